@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/sandbox"
+)
+
+func newCleanCmd() *cobra.Command {
+	var (
+		all       bool
+		olderThan string
+		dryRun    bool
+		force     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove a sandbox directory to reclaim disk space",
+		Long: `Remove the current project's sandbox directory, freeing the disk space
+used by ~/.local/share/devsandbox/<project>.
+
+Refuses to remove a sandbox with an active session (live lock). Use --all to
+prune every sandbox whose lock is free instead of just the current project's.`,
+		Example: `  devsandbox clean
+  devsandbox clean --dry-run
+  devsandbox clean --all --older-than 30d
+  devsandbox clean --all --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			baseDir := sandbox.SandboxBasePath(homeDir)
+
+			var duration time.Duration
+			if olderThan != "" {
+				duration, err = parseDuration(olderThan)
+				if err != nil {
+					return fmt.Errorf("invalid duration %q: %w", olderThan, err)
+				}
+			}
+
+			if all {
+				return cleanAllSandboxes(baseDir, duration, dryRun, force)
+			}
+			return cleanCurrentProject(baseDir, duration, dryRun, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Remove every sandbox whose lock is free, not just the current project's")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Only remove sandboxes not used in duration (e.g. 30d, 2w)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// cleanCurrentProject removes the sandbox for the current working
+// directory's project, if one exists and isn't in use.
+func cleanCurrentProject(baseDir string, olderThan time.Duration, dryRun, force bool) error {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	m, err := sandbox.FindExistingSandbox(projectDir, baseDir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		fmt.Println("No sandbox found for this project.")
+		return nil
+	}
+
+	if sandbox.IsSessionActive(m.SandboxRoot) {
+		return fmt.Errorf("sandbox %q has an active session; stop it before cleaning", m.Name)
+	}
+
+	if olderThan > 0 && time.Since(m.LastUsed) < olderThan {
+		fmt.Printf("Sandbox %s was used within %s, skipping.\n", m.Name, olderThan)
+		return nil
+	}
+
+	size, err := sandbox.GetSandboxSize(m.SandboxRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to calculate size for %s: %v\n", m.Name, err)
+	}
+
+	fmt.Printf("Sandbox: %s\n", m.Name)
+	fmt.Printf("  Project: %s\n", m.ProjectDir)
+	fmt.Printf("  Last used: %s\n", m.LastUsed.Format("2006-01-02 15:04"))
+	fmt.Printf("  Size: %s\n\n", sandbox.FormatSize(size))
+
+	if dryRun {
+		fmt.Println("Dry run - sandbox was not removed.")
+		return nil
+	}
+
+	if !force {
+		ok, err := confirm("Remove this sandbox?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	if err := sandbox.RemoveSandboxByType(m, false); err != nil {
+		return fmt.Errorf("failed to remove sandbox: %w", err)
+	}
+
+	fmt.Printf("Removed sandbox, freed %s.\n", sandbox.FormatSize(size))
+	return nil
+}
+
+// cleanAllSandboxes removes every sandbox whose lock is free, optionally
+// restricted to ones not used within olderThan.
+func cleanAllSandboxes(baseDir string, olderThan time.Duration, dryRun, force bool) error {
+	sandboxes, err := sandbox.ListAllSandboxes(baseDir)
+	if err != nil {
+		return err
+	}
+	if len(sandboxes) == 0 {
+		fmt.Println("No sandboxes found.")
+		return nil
+	}
+
+	for _, s := range sandboxes {
+		s.Active = sandbox.IsSessionActive(s.SandboxRoot)
+	}
+
+	toClean := sandbox.SelectForPruning(sandboxes, sandbox.PruneOptions{
+		All:       true,
+		OlderThan: olderThan,
+		DryRun:    dryRun,
+	})
+
+	if len(toClean) == 0 {
+		fmt.Println("No sandboxes to clean.")
+		return nil
+	}
+
+	var totalSize int64
+	fmt.Printf("Sandboxes to remove (%d):\n\n", len(toClean))
+	for _, s := range toClean {
+		size, err := sandbox.GetSandboxSize(s.SandboxRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to calculate size for %s: %v\n", s.Name, err)
+		}
+		s.SizeBytes = size
+		totalSize += size
+		fmt.Printf("  %s (%s)\n", s.Name, s.ProjectDir)
+		fmt.Printf("    Last used: %s\n", s.LastUsed.Format("2006-01-02 15:04"))
+		fmt.Printf("    Size: %s\n", sandbox.FormatSize(size))
+	}
+	fmt.Printf("\nTotal: %s\n\n", sandbox.FormatSize(totalSize))
+
+	if dryRun {
+		fmt.Println("Dry run - no sandboxes were removed.")
+		return nil
+	}
+
+	if !force {
+		ok, err := confirm("Remove these sandboxes?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
+	var removed, failed int
+	for _, s := range toClean {
+		if err := sandbox.RemoveSandboxByType(s, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove %s: %v\n", s.Name, err)
+			failed++
+		} else {
+			removed++
+		}
+	}
+
+	fmt.Printf("Removed %d sandbox(es)", removed)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// confirm prompts the user with a yes/no question on stdin.
+func confirm(prompt string) (bool, error) {
+	fmt.Printf("%s [y/N] ", prompt)
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/sandbox"
+)
+
+// httpMethods lists the methods completed for flags like "logs proxy
+// --method" - the common verbs, not an exhaustive RFC list.
+var httpMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions, http.MethodConnect,
+}
+
+// gitModes lists the valid `--git-mode`/`[tools.git] mode` values (see
+// tools.ValidGitMode).
+var gitModes = []string{"readonly", "readwrite", "disabled"}
+
+// proxyStatsGroupBy lists the valid "logs proxy --group-by" dimensions.
+var proxyStatsGroupBy = []string{"host", "method", "status"}
+
+// completeFromList returns a completion function offering a fixed set of
+// values, for flags whose choices are a short enum rather than something
+// that needs to be looked up (see completeSandboxNames for the latter).
+func completeFromList(values []string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeSandboxNames completes against the names of existing sandboxes
+// under SandboxBasePath, for commands/flags that take a sandbox name (e.g.
+// "logs proxy [sandbox-name]", "config env set --sandbox").
+func completeSandboxNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	sandboxes, err := sandbox.ListAllSandboxes(sandbox.SandboxBasePath(homeDir))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(sandboxes))
+	for _, s := range sandboxes {
+		names = append(names, s.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteFromList(t *testing.T) {
+	f := completeFromList(gitModes)
+
+	values, directive := f(nil, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("expected ShellCompDirectiveNoFileComp, got %v", directive)
+	}
+	if len(values) != len(gitModes) {
+		t.Fatalf("expected %d values, got %d", len(gitModes), len(values))
+	}
+	for i, v := range gitModes {
+		if values[i] != v {
+			t.Errorf("values[%d] = %q, want %q", i, values[i], v)
+		}
+	}
+}
+
+func TestCompleteSandboxNames_NoSandboxes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	names, directive := completeSandboxNames(nil, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("expected ShellCompDirectiveNoFileComp, got %v", directive)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no sandboxes, got %v", names)
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"devsandbox/internal/config"
+	"devsandbox/internal/sandbox"
 )
 
 func newConfigCmd() *cobra.Command {
@@ -23,10 +27,184 @@ Configuration file location: ~/.config/devsandbox/config.toml
 	cmd.AddCommand(newConfigShowCmd())
 	cmd.AddCommand(newConfigPathCmd())
 	cmd.AddCommand(newConfigInitCmd())
+	cmd.AddCommand(newConfigValidateCmd())
+	cmd.AddCommand(newConfigSchemaCmd())
+	cmd.AddCommand(newConfigEnvCmd())
 
 	return cmd
 }
 
+// resolveSandboxRoot resolves the sandbox a config env command should act
+// on: an explicit --sandbox name, or the current project directory's
+// sandbox otherwise. Mirrors the resolution `logs proxy` uses.
+func resolveSandboxRoot(sandboxName string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := sandboxName
+	if name == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		name = sandbox.GenerateSandboxName(cwd)
+	}
+
+	return filepath.Join(sandbox.SandboxBasePath(homeDir), name), nil
+}
+
+func newConfigEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage a sandbox's persistent environment file",
+		Long: `Manage the per-sandbox environment file (SandboxRoot/env), which is
+loaded automatically on every launch of that sandbox and injected into it
+via SetEnv - unlike --env-file, which only applies for one invocation.
+
+Keys whose name looks like a secret (TOKEN, SECRET, KEY, PASSWORD, ...)
+are never loaded without an explicit --allow-secret opt-in.`,
+	}
+
+	cmd.AddCommand(newConfigEnvSetCmd())
+	cmd.AddCommand(newConfigEnvListCmd())
+	cmd.AddCommand(newConfigEnvUnsetCmd())
+
+	return cmd
+}
+
+func newConfigEnvSetCmd() *cobra.Command {
+	var (
+		sandboxName string
+		allowSecret bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set KEY=VALUE",
+		Short: "Set a variable in the sandbox's persistent environment file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value, ok := strings.Cut(args[0], "=")
+			if !ok {
+				return fmt.Errorf("expected KEY=VALUE, got %q", args[0])
+			}
+			key = strings.TrimSpace(key)
+
+			sandboxRoot, err := resolveSandboxRoot(sandboxName)
+			if err != nil {
+				return err
+			}
+
+			if err := sandbox.SetSandboxEnvVar(sandboxRoot, key, value, allowSecret); err != nil {
+				return err
+			}
+
+			fmt.Printf("Set %s in %s\n", key, sandbox.SandboxEnvPath(sandboxRoot))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sandboxName, "sandbox", "", "Sandbox name (default: current directory's sandbox)")
+	cmd.Flags().BoolVar(&allowSecret, "allow-secret", false, "Allow a secret-looking key name to be loaded automatically")
+
+	_ = cmd.RegisterFlagCompletionFunc("sandbox", completeSandboxNames)
+
+	return cmd
+}
+
+func newConfigEnvListCmd() *cobra.Command {
+	var sandboxName string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List variables in the sandbox's persistent environment file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sandboxRoot, err := resolveSandboxRoot(sandboxName)
+			if err != nil {
+				return err
+			}
+
+			loaded, skipped, err := sandbox.LoadSandboxEnv(sandboxRoot)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(loaded))
+			for name := range loaded {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s=%s\n", name, loaded[name])
+			}
+
+			if len(skipped) > 0 {
+				sort.Strings(skipped)
+				fmt.Fprintf(os.Stderr, "skipped (secret-looking, not loaded): %s\n", strings.Join(skipped, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sandboxName, "sandbox", "", "Sandbox name (default: current directory's sandbox)")
+
+	_ = cmd.RegisterFlagCompletionFunc("sandbox", completeSandboxNames)
+
+	return cmd
+}
+
+func newConfigEnvUnsetCmd() *cobra.Command {
+	var sandboxName string
+
+	cmd := &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Remove a variable from the sandbox's persistent environment file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sandboxRoot, err := resolveSandboxRoot(sandboxName)
+			if err != nil {
+				return err
+			}
+
+			found, err := sandbox.UnsetSandboxEnvVar(sandboxRoot, args[0])
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("%q not found in %s", args[0], sandbox.SandboxEnvPath(sandboxRoot))
+			}
+
+			fmt.Printf("Removed %s from %s\n", args[0], sandbox.SandboxEnvPath(sandboxRoot))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sandboxName, "sandbox", "", "Sandbox name (default: current directory's sandbox)")
+
+	_ = cmd.RegisterFlagCompletionFunc("sandbox", completeSandboxNames)
+
+	return cmd
+}
+
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Emit a JSON Schema for the configuration file",
+		Long: `Emit a JSON Schema describing config.toml/.devsandbox.toml, for editors
+with TOML/JSON schema support to validate and autocomplete against.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal schema: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
+
 func newConfigShowCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "show",
@@ -38,106 +216,114 @@ func newConfigShowCmd() *cobra.Command {
 			}
 
 			fmt.Printf("Config file: %s\n\n", config.ConfigPath())
+			printConfig(cfg)
+			return nil
+		},
+	}
+}
 
-			fmt.Println("[proxy]")
-			fmt.Printf("  enabled = %v\n", cfg.Proxy.Enabled)
-			fmt.Printf("  port = %d\n", cfg.Proxy.Port)
-			fmt.Println()
+// printConfig prints cfg in the same human-readable form `config show` uses,
+// so `config validate` can echo back the effective configuration it just
+// checked without duplicating the formatting.
+func printConfig(cfg *config.Config) {
+	fmt.Println("[proxy]")
+	fmt.Printf("  enabled = %v\n", cfg.Proxy.Enabled)
+	fmt.Printf("  port = %d\n", cfg.Proxy.Port)
+	fmt.Println()
 
-			// Show filter config if set
-			if cfg.Proxy.Filter.DefaultAction != "" {
-				fmt.Println("[proxy.filter]")
-				fmt.Printf("  default_action = %s\n", cfg.Proxy.Filter.DefaultAction)
-				if cfg.Proxy.Filter.AskTimeout > 0 {
-					fmt.Printf("  ask_timeout = %d\n", cfg.Proxy.Filter.AskTimeout)
+	// Show filter config if set
+	if cfg.Proxy.Filter.DefaultAction != "" {
+		fmt.Println("[proxy.filter]")
+		fmt.Printf("  default_action = %s\n", cfg.Proxy.Filter.DefaultAction)
+		if cfg.Proxy.Filter.AskTimeout > 0 {
+			fmt.Printf("  ask_timeout = %d\n", cfg.Proxy.Filter.AskTimeout)
+		}
+		if cfg.Proxy.Filter.CacheDecisions != nil {
+			fmt.Printf("  cache_decisions = %v\n", *cfg.Proxy.Filter.CacheDecisions)
+		}
+		if cfg.Proxy.Filter.CacheTTL != "" {
+			fmt.Printf("  cache_ttl = %q\n", cfg.Proxy.Filter.CacheTTL)
+		}
+		fmt.Println()
+
+		if len(cfg.Proxy.Filter.Rules) > 0 {
+			for i, rule := range cfg.Proxy.Filter.Rules {
+				fmt.Printf("[[proxy.filter.rules]] #%d\n", i+1)
+				fmt.Printf("  pattern = %q\n", rule.Pattern)
+				fmt.Printf("  action = %s\n", rule.Action)
+				if rule.Scope != "" {
+					fmt.Printf("  scope = %s\n", rule.Scope)
 				}
-				if cfg.Proxy.Filter.CacheDecisions != nil {
-					fmt.Printf("  cache_decisions = %v\n", *cfg.Proxy.Filter.CacheDecisions)
+				if rule.Type != "" {
+					fmt.Printf("  type = %s\n", rule.Type)
 				}
-				fmt.Println()
-
-				if len(cfg.Proxy.Filter.Rules) > 0 {
-					for i, rule := range cfg.Proxy.Filter.Rules {
-						fmt.Printf("[[proxy.filter.rules]] #%d\n", i+1)
-						fmt.Printf("  pattern = %q\n", rule.Pattern)
-						fmt.Printf("  action = %s\n", rule.Action)
-						if rule.Scope != "" {
-							fmt.Printf("  scope = %s\n", rule.Scope)
-						}
-						if rule.Type != "" {
-							fmt.Printf("  type = %s\n", rule.Type)
-						}
-						if rule.Reason != "" {
-							fmt.Printf("  reason = %q\n", rule.Reason)
-						}
-					}
-					fmt.Println()
+				if rule.Reason != "" {
+					fmt.Printf("  reason = %q\n", rule.Reason)
 				}
 			}
-
-			fmt.Println("[sandbox]")
-			basePath := cfg.Sandbox.BasePath
-			if basePath == "" {
-				basePath = "(default)"
-			}
-			fmt.Printf("  base_path = %s\n", basePath)
 			fmt.Println()
+		}
+	}
 
-			// Show custom mounts config
-			fmt.Println("[sandbox.mounts]")
-			if len(cfg.Sandbox.Mounts.Rules) == 0 {
-				fmt.Println("  # No custom mount rules configured")
-			} else {
-				fmt.Println("  rules:")
-				for i, rule := range cfg.Sandbox.Mounts.Rules {
-					mode := rule.Mode
-					if mode == "" {
-						mode = "readonly"
-					}
-					fmt.Printf("    %d. %s (%s)\n", i+1, rule.Pattern, mode)
-				}
+	fmt.Println("[sandbox]")
+	basePath := cfg.Sandbox.BasePath
+	if basePath == "" {
+		basePath = "(default)"
+	}
+	fmt.Printf("  base_path = %s\n", basePath)
+	fmt.Println()
+
+	// Show custom mounts config
+	fmt.Println("[sandbox.mounts]")
+	if len(cfg.Sandbox.Mounts.Rules) == 0 {
+		fmt.Println("  # No custom mount rules configured")
+	} else {
+		fmt.Println("  rules:")
+		for i, rule := range cfg.Sandbox.Mounts.Rules {
+			mode := rule.Mode
+			if mode == "" {
+				mode = "readonly"
 			}
-			fmt.Println()
+			fmt.Printf("    %d. %s (%s)\n", i+1, rule.Pattern, mode)
+		}
+	}
+	fmt.Println()
 
-			fmt.Println("[overlay]")
-			fmt.Printf("  enabled = %v\n", cfg.Overlay.IsEnabled())
-			fmt.Println()
+	fmt.Println("[overlay]")
+	fmt.Printf("  enabled = %v\n", cfg.Overlay.IsEnabled())
+	fmt.Println()
 
-			// Print tool configurations dynamically
-			for toolName, toolCfg := range cfg.Tools {
-				fmt.Printf("[tools.%s]\n", toolName)
-				if m, ok := toolCfg.(map[string]any); ok {
-					for k, v := range m {
-						fmt.Printf("  %s = %v\n", k, v)
-					}
-				}
-				fmt.Println()
-			}
-
-			fmt.Println("[logging]")
-			if len(cfg.Logging.Receivers) == 0 {
-				fmt.Println("  receivers = (none)")
-			} else {
-				for i, r := range cfg.Logging.Receivers {
-					fmt.Printf("  [[receivers]] #%d\n", i+1)
-					fmt.Printf("    type = %s\n", r.Type)
-					if r.Address != "" {
-						fmt.Printf("    address = %s\n", r.Address)
-					}
-					if r.Endpoint != "" {
-						fmt.Printf("    endpoint = %s\n", r.Endpoint)
-					}
-					if r.Facility != "" {
-						fmt.Printf("    facility = %s\n", r.Facility)
-					}
-					if r.Tag != "" {
-						fmt.Printf("    tag = %s\n", r.Tag)
-					}
-				}
+	// Print tool configurations dynamically
+	for toolName, toolCfg := range cfg.Tools {
+		fmt.Printf("[tools.%s]\n", toolName)
+		if m, ok := toolCfg.(map[string]any); ok {
+			for k, v := range m {
+				fmt.Printf("  %s = %v\n", k, v)
 			}
+		}
+		fmt.Println()
+	}
 
-			return nil
-		},
+	fmt.Println("[logging]")
+	if len(cfg.Logging.Receivers) == 0 {
+		fmt.Println("  receivers = (none)")
+	} else {
+		for i, r := range cfg.Logging.Receivers {
+			fmt.Printf("  [[receivers]] #%d\n", i+1)
+			fmt.Printf("    type = %s\n", r.Type)
+			if r.Address != "" {
+				fmt.Printf("    address = %s\n", r.Address)
+			}
+			if r.Endpoint != "" {
+				fmt.Printf("    endpoint = %s\n", r.Endpoint)
+			}
+			if r.Facility != "" {
+				fmt.Printf("    facility = %s\n", r.Facility)
+			}
+			if r.Tag != "" {
+				fmt.Printf("    tag = %s\n", r.Tag)
+			}
+		}
 	}
 }
 
@@ -182,3 +368,36 @@ func newConfigInitCmd() *cobra.Command {
 
 	return cmd
 }
+
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [path]",
+		Short: "Validate a configuration file",
+		Long: `Load a config.toml/.devsandbox.toml file and check it the same way
+devsandbox does on startup: TOML syntax, then Config.Validate() (port ranges,
+filter/mount/bind rule shapes, path safety, and friends).
+
+Defaults to the global config path if no path is given. Syntax errors from
+a malformed file include the offending line.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := config.ConfigPath()
+			if len(args) == 1 {
+				path = args[0]
+			}
+
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("config file %s: %w", path, err)
+			}
+
+			cfg, err := config.LoadFrom(path)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			fmt.Printf("%s is valid\n\n", path)
+			printConfig(cfg)
+			return nil
+		},
+	}
+}
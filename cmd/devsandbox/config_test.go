@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewConfigValidateCmd(t *testing.T) {
+	cmd := newConfigValidateCmd()
+	if cmd.Use != "validate [path]" {
+		t.Errorf("expected Use='validate [path]', got %q", cmd.Use)
+	}
+}
+
+func TestConfigValidate_MissingFile(t *testing.T) {
+	cmd := newConfigValidateCmd()
+	cmd.SetArgs([]string{filepath.Join(t.TempDir(), "does-not-exist.toml")})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a missing config file")
+	}
+}
+
+func TestConfigValidate_ValidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[proxy]\nport = 8080\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := newConfigValidateCmd()
+	cmd.SetArgs([]string{path})
+
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("expected no error for a valid config, got: %v", err)
+	}
+}
+
+func TestConfigValidate_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[proxy]\nport = 999999\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := newConfigValidateCmd()
+	cmd.SetArgs([]string{path})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an out-of-range proxy.port")
+	}
+}
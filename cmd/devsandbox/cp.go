@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/sandbox"
+)
+
+// sandboxPathPrefix marks which side of `devsandbox cp` refers to the
+// sandbox home rather than a plain host path.
+const sandboxPathPrefix = "sandbox:"
+
+func newCpCmd() *cobra.Command {
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "cp <src> <dst>",
+		Short: "Copy a file between the host and the current project's sandbox home",
+		Long: `Copy a file or directory between the host and the sandbox home
+(~/.local/share/devsandbox/<project>/home), which isn't otherwise reachable
+except from inside a running sandbox.
+
+Prefix whichever of <src>/<dst> refers to the sandbox with "sandbox:" -
+exactly one side may have it, and the path is resolved relative to the
+sandbox home:
+
+  devsandbox cp ./token.txt sandbox:.config/mytool/token
+  devsandbox cp sandbox:.cache/build/out.tar.gz ./out.tar.gz
+
+The project directory is already bind-mounted into the sandbox read-write,
+so a sandbox: path that resolves inside it is almost always a mistake -
+this warns rather than silently writing somewhere the sandbox can't see.`,
+		Example: `  devsandbox cp ./credential.stub sandbox:.config/tool/credential
+  devsandbox cp -r sandbox:.cache/build ./build`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCp(args[0], args[1], recursive)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Copy directories recursively")
+
+	return cmd
+}
+
+func runCp(srcArg, dstArg string, recursive bool) error {
+	srcIsSandbox := strings.HasPrefix(srcArg, sandboxPathPrefix)
+	dstIsSandbox := strings.HasPrefix(dstArg, sandboxPathPrefix)
+
+	if srcIsSandbox == dstIsSandbox {
+		return fmt.Errorf(`exactly one of <src>/<dst> must have a "sandbox:" prefix`)
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	m, err := sandbox.FindExistingSandbox(projectDir, sandbox.SandboxBasePath(homeDir))
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("no sandbox found for this project; run devsandbox here at least once first")
+	}
+	sandboxHome := filepath.Join(m.SandboxRoot, "home")
+
+	src, dst := srcArg, dstArg
+	if srcIsSandbox {
+		src = resolveSandboxCpPath(sandboxHome, homeDir, projectDir, srcArg)
+	} else {
+		dst = resolveSandboxCpPath(sandboxHome, homeDir, projectDir, dstArg)
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if !recursive {
+			return fmt.Errorf("%s is a directory; pass -r to copy directories", src)
+		}
+		return copyCpDir(src, dst)
+	}
+
+	return copyCpFile(src, dst, info.Mode())
+}
+
+// resolveSandboxCpPath turns a "sandbox:<path>" argument into an absolute
+// path under sandboxHome, warning on stderr (not failing) if it resolves
+// inside projectDir - the project directory is already bind-mounted into
+// the sandbox at that same absolute path, which takes precedence over
+// whatever lives underneath it in the sandbox home, so a copy there
+// wouldn't be visible to the sandboxed process.
+func resolveSandboxCpPath(sandboxHome, homeDir, projectDir, arg string) string {
+	rel := strings.TrimPrefix(arg, sandboxPathPrefix)
+	resolved := filepath.Join(sandboxHome, rel)
+
+	asSeenInSandbox := filepath.Join(homeDir, rel)
+	projectDir = filepath.Clean(projectDir)
+	if asSeenInSandbox == projectDir || strings.HasPrefix(asSeenInSandbox, projectDir+string(filepath.Separator)) {
+		fmt.Fprintf(os.Stderr, "warning: %s resolves to %s inside the project directory, which the sandbox "+
+			"already sees directly (bind-mounted read-write) - this copy won't be visible there; did you mean "+
+			"a plain host path instead of sandbox:?\n", arg, asSeenInSandbox)
+	}
+
+	return resolved
+}
+
+func copyCpFile(src, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("cp: mkdir %s: %w", filepath.Dir(dst), err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("cp: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return fmt.Errorf("cp: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("cp: copying %s to %s: %w", src, dst, err)
+	}
+
+	return nil
+}
+
+func copyCpDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(destPath, 0o755)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			// Mirrors copyDotfile: don't follow symlinks into unknown territory.
+			return nil
+		}
+
+		return copyCpFile(path, destPath, info.Mode())
+	})
+}
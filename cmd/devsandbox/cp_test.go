@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCpCmd(t *testing.T) {
+	cmd := newCpCmd()
+	if cmd.Use != "cp <src> <dst>" {
+		t.Errorf("expected Use='cp <src> <dst>', got %q", cmd.Use)
+	}
+	if cmd.RunE == nil {
+		t.Fatal("expected cp to have a RunE")
+	}
+}
+
+func TestRunCp_RequiresExactlyOneSandboxSide(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		dst  string
+	}{
+		{"neither side prefixed", "./a", "./b"},
+		{"both sides prefixed", "sandbox:a", "sandbox:b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runCp(tt.src, tt.dst, false); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestResolveSandboxCpPath(t *testing.T) {
+	sandboxHome := "/sandboxes/proj/home"
+	homeDir := "/home/user"
+	projectDir := "/home/user/myproject"
+
+	got := resolveSandboxCpPath(sandboxHome, homeDir, projectDir, "sandbox:.config/tool/token")
+	want := filepath.Join(sandboxHome, ".config/tool/token")
+	if got != want {
+		t.Errorf("resolveSandboxCpPath() = %q, want %q", got, want)
+	}
+}
+
+func TestCopyCpFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	if err := os.WriteFile(src, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "nested", "dst.txt")
+	if err := copyCpFile(src, dst, 0o644); err != nil {
+		t.Fatalf("copyCpFile() error: %v", err)
+	}
+
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("expected copied content %q, got %q", "hello", string(data))
+	}
+}
+
+func TestCopyCpDir(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := copyCpDir(src, dst); err != nil {
+		t.Fatalf("copyCpDir() error: %v", err)
+	}
+
+	for _, rel := range []string{"a.txt", "sub/b.txt"} {
+		if _, err := os.Stat(filepath.Join(dst, rel)); err != nil {
+			t.Errorf("expected %s to exist: %v", rel, err)
+		}
+	}
+}
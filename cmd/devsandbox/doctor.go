@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
+	"devsandbox/internal/bwrap"
 	"devsandbox/internal/config"
 	"devsandbox/internal/embed"
 	"devsandbox/internal/sandbox"
@@ -26,6 +28,8 @@ type checkResult struct {
 }
 
 func newDoctorCmd() *cobra.Command {
+	var jsonOutput bool
+
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Check installation and dependencies",
@@ -37,6 +41,7 @@ Checks (all platforms):
   - Configuration file
   - Recent error logs
   - Docker and Docker image availability
+  - Tool availability (binaries, config, install hints)
 
 Checks (Linux only):
   - Required binaries (bwrap)
@@ -44,15 +49,26 @@ Checks (Linux only):
   - User namespace support
   - Kernel version
   - Overlayfs support`,
+		Example: `  devsandbox doctor          # Run all checks and print a report
+  devsandbox doctor --json   # Emit tool check results as JSON for scripting`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDoctor()
+			return runDoctor(jsonOutput)
 		},
 	}
 
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Emit tool check results as JSON instead of a table")
+
 	return cmd
 }
 
-func runDoctor() error {
+// toolDoctorResult is the JSON shape for `devsandbox doctor --json`: a tool
+// name alongside its detailed tools.CheckResult.
+type toolDoctorResult struct {
+	Name string `json:"name"`
+	tools.CheckResult
+}
+
+func runDoctor(jsonOutput bool) error {
 	// Load config to check use_embedded setting
 	appCfg, _, _, err := config.LoadConfig()
 	if err != nil {
@@ -60,6 +76,18 @@ func runDoctor() error {
 		appCfg = config.DefaultConfig()
 	}
 
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+
+	if jsonOutput {
+		toolResults, _ := checkTools(homeDir, appCfg)
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(toolResults)
+	}
+
 	if !appCfg.Sandbox.IsUseEmbeddedEnabled() {
 		embed.Disabled = true
 	}
@@ -73,6 +101,7 @@ func runDoctor() error {
 		results = append(results, checkUserNamespaces())
 		results = append(results, checkKernelVersion())
 		results = append(results, checkOverlayfs())
+		results = append(results, checkBwrapFeatures())
 	}
 
 	// Universal checks
@@ -88,7 +117,8 @@ func runDoctor() error {
 	printDoctorResults(results)
 
 	// Print detected tools
-	printDetectedTools()
+	toolResults, missingEnabled := checkTools(homeDir, appCfg)
+	printToolDoctorResults(toolResults)
 
 	hasError := false
 	for _, r := range results {
@@ -98,6 +128,11 @@ func runDoctor() error {
 		}
 	}
 
+	if len(missingEnabled) > 0 {
+		fmt.Printf("\nTools enabled in config but unavailable: %s\n", strings.Join(missingEnabled, ", "))
+		hasError = true
+	}
+
 	if hasError {
 		fmt.Println("\nSome checks failed. Please install missing dependencies.")
 		return fmt.Errorf("doctor found issues")
@@ -107,6 +142,42 @@ func runDoctor() error {
 	return nil
 }
 
+// checkTools runs Check() (falling back to Available()) for every
+// registered tool and returns the results alongside the names of any tools
+// explicitly enabled via [tools.<name>] enabled = true in config that are
+// unavailable.
+func checkTools(homeDir string, appCfg *config.Config) ([]toolDoctorResult, []string) {
+	allTools := tools.All()
+	results := make([]toolDoctorResult, 0, len(allTools))
+	var missingEnabled []string
+
+	for _, t := range allTools {
+		result := toolDoctorResult{Name: t.Name()}
+
+		if checker, ok := t.(tools.ToolWithCheck); ok {
+			result.CheckResult = checker.Check(homeDir)
+		} else {
+			result.CheckResult = tools.CheckResult{Available: t.Available(homeDir)}
+		}
+
+		results = append(results, result)
+
+		if !result.Available && toolExplicitlyEnabled(appCfg, t.Name()) {
+			missingEnabled = append(missingEnabled, t.Name())
+		}
+	}
+
+	return results, missingEnabled
+}
+
+// toolExplicitlyEnabled reports whether [tools.<name>] enabled = true is set
+// in config, the convention tools like docker use to opt in explicitly.
+func toolExplicitlyEnabled(appCfg *config.Config, name string) bool {
+	toolCfg := appCfg.GetToolConfig(name)
+	enabled, ok := toolCfg["enabled"].(bool)
+	return ok && enabled
+}
+
 func getBinaryVersion(name string) string {
 	var cmd *exec.Cmd
 	switch name {
@@ -122,6 +193,8 @@ func getBinaryVersion(name string) string {
 		cmd = exec.Command(name, "--version")
 	case "zsh":
 		cmd = exec.Command(name, "--version")
+	case "nu":
+		cmd = exec.Command(name, "--version")
 	case "docker":
 		cmd = exec.Command(name, "--version")
 	default:
@@ -353,38 +426,43 @@ func printDoctorResults(results []checkResult) {
 	_ = table.Render()
 }
 
-func printDetectedTools() {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return
-	}
-
-	allTools := tools.All()
-	availableTools := tools.Available(homeDir)
-
-	// Build a set of available tool names for quick lookup
-	availableSet := make(map[string]bool)
-	for _, t := range availableTools {
-		availableSet[t.Name()] = true
-	}
-
+func printToolDoctorResults(results []toolDoctorResult) {
 	fmt.Println("\nDetected Tools:")
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("TOOL", "STATUS", "DESCRIPTION")
+	table.Header("TOOL", "STATUS", "CONFIG", "INSTALL HINT")
 
-	for _, t := range allTools {
-		status := "✗ not found"
-		if availableSet[t.Name()] {
-			status = "✓ available"
+	available := 0
+	for _, r := range results {
+		status := "\033[31m✗ not found\033[0m"
+		installHint := ""
+		if r.Available {
+			status = "\033[32m✓ available\033[0m"
+			available++
+		} else {
+			installHint = r.InstallHint
+		}
+		if r.BinaryPath != "" {
+			status += fmt.Sprintf(" (%s)", r.BinaryPath)
+		}
+
+		configInfo := "-"
+		if len(r.ConfigPaths) > 0 {
+			configInfo = strings.Join(r.ConfigPaths, ", ")
 		}
 
-		_ = table.Append(t.Name(), status, t.Description())
+		_ = table.Append(r.Name, status, configInfo, installHint)
 	}
 
 	_ = table.Render()
 
-	fmt.Printf("\n%d of %d tools available\n", len(availableTools), len(allTools))
+	for _, r := range results {
+		for _, issue := range r.Issues {
+			fmt.Printf("  \033[33m!\033[0m %s: %s\n", r.Name, issue)
+		}
+	}
+
+	fmt.Printf("\n%d of %d tools available\n", available, len(results))
 }
 
 func checkEmbeddedBinary(name string, version string, required bool) checkResult {
@@ -432,6 +510,34 @@ func checkEmbeddedBinary(name string, version string, required bool) checkResult
 	}
 }
 
+// checkBwrapFeatures reports which optional bwrap flags are supported by
+// the effective bwrap binary, so version-gated features (like --overlay)
+// fail with a clear message instead of a cryptic bwrap error.
+func checkBwrapFeatures() checkResult {
+	features, err := bwrap.DetectFeatures()
+	if err != nil {
+		return checkResult{
+			name:    "bwrap features",
+			status:  "warn",
+			message: fmt.Sprintf("could not detect: %v", err),
+		}
+	}
+
+	return checkResult{
+		name:   "bwrap features",
+		status: "ok",
+		message: fmt.Sprintf("version %s, overlay=%v, tmpfs-size=%v, bind-fd=%v",
+			versionOrUnknownDoctor(features.Version), features.SupportsOverlay, features.SupportsTmpfsSize, features.SupportsBindFD),
+	}
+}
+
+func versionOrUnknownDoctor(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
+
 // checkOverlayfs tests if bwrap's overlayfs support works.
 // This is needed for tool overlay features (e.g., mise with writable layers).
 func checkOverlayfs() checkResult {
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"devsandbox/internal/config"
+)
+
+func TestNewDoctorCmd(t *testing.T) {
+	cmd := newDoctorCmd()
+	if cmd.Use != "doctor" {
+		t.Errorf("expected Use='doctor', got %q", cmd.Use)
+	}
+	if cmd.Flags().Lookup("json") == nil {
+		t.Error("expected --json flag to be registered")
+	}
+}
+
+func TestToolExplicitlyEnabled(t *testing.T) {
+	appCfg := config.DefaultConfig()
+	appCfg.Tools = map[string]any{
+		"docker": map[string]any{"enabled": true},
+		"git":    map[string]any{"enabled": false},
+	}
+
+	if !toolExplicitlyEnabled(appCfg, "docker") {
+		t.Error("expected docker to be explicitly enabled")
+	}
+	if toolExplicitlyEnabled(appCfg, "git") {
+		t.Error("expected git to not be explicitly enabled")
+	}
+	if toolExplicitlyEnabled(appCfg, "mise") {
+		t.Error("expected mise (unset) to not be explicitly enabled")
+	}
+}
+
+func TestCheckTools(t *testing.T) {
+	appCfg := config.DefaultConfig()
+
+	results, missingEnabled := checkTools("/nonexistent-home-dir", appCfg)
+	if len(results) == 0 {
+		t.Fatal("expected at least one tool result")
+	}
+	if len(missingEnabled) != 0 {
+		t.Errorf("expected no tools explicitly enabled by default, got %v", missingEnabled)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newExecCmd returns the "exec" subcommand: a thin, explicit alias for the
+// default "devsandbox <command...>" invocation, for scripts and CI that
+// want to spell out intent rather than rely on flag-parsing to tell a
+// devsandbox flag from the sandboxed command's own flags. It shares
+// runSandbox with the root command, so every root flag (--proxy, --rm,
+// --isolation, etc.) works here too.
+//
+// The sandboxed command's exit code is always propagated faithfully (see
+// main's unwrapping of *exec.ExitError), and --quiet suppresses the
+// proxy/filter startup banner for clean CI logs.
+func newExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec [command...]",
+		Short: "Run a command in the sandbox (explicit alias of the default invocation, for scripts/CI)",
+		Long: `devsandbox exec runs a command in the sandbox exactly like the default
+"devsandbox <command...>" invocation, and accepts the same flags.
+
+It exists for scripts and CI pipelines that want to say what they mean
+("devsandbox exec -- npm test") instead of leaning on devsandbox's flag
+parsing to tell its own flags apart from the sandboxed command's. The
+sandboxed command's exit code is always propagated faithfully, and
+--quiet suppresses the proxy/filter startup banner for clean CI output.`,
+		Example:               `  devsandbox exec -- npm test`,
+		Args:                  cobra.ArbitraryArgs,
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		RunE:                  runSandbox,
+	}
+	cmd.Flags().SetInterspersed(false)
+	return cmd
+}
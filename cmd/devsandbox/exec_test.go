@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func TestNewExecCmd(t *testing.T) {
+	cmd := newExecCmd()
+	if cmd.Use != "exec [command...]" {
+		t.Errorf("expected Use='exec [command...]', got %q", cmd.Use)
+	}
+	if cmd.RunE == nil {
+		t.Fatal("expected exec to run the sandbox command")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -27,10 +28,161 @@ func newFilterCmd() *cobra.Command {
 
 	cmd.AddCommand(newFilterGenerateCmd())
 	cmd.AddCommand(newFilterShowCmd())
+	cmd.AddCommand(newFilterResetCmd())
+	cmd.AddCommand(newFilterDecisionsCmd())
 
 	return cmd
 }
 
+// newFilterDecisionsCmd groups the ask-mode decision cache inspection
+// commands. "filter reset" predates this and remains as a shorthand for
+// "filter decisions clear" with no host argument.
+func newFilterDecisionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decisions",
+		Short: "List or clear cached ask-mode decisions",
+		Long: `Cached ask-mode decisions are "remember for session" choices from
+the ask-mode prompt, persisted to filter-decisions.json so they survive a
+sandbox restart. These subcommands make that accumulated state visible
+and revocable without digging through the file or waiting for it to
+expire on its own.
+
+Clearing a decision here only updates the file on disk - a sandbox
+session that already loaded that host's decision into memory keeps using
+it until the session restarts.`,
+	}
+
+	cmd.AddCommand(newFilterDecisionsListCmd())
+	cmd.AddCommand(newFilterDecisionsClearCmd())
+
+	return cmd
+}
+
+func newFilterDecisionsListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached ask-mode decisions for the current project",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sandboxBase, err := resolveSandboxBase()
+			if err != nil {
+				return err
+			}
+
+			entries, err := proxy.LoadDecisionCache(sandboxBase)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No cached filter decisions.")
+				return nil
+			}
+
+			now := time.Now()
+			fmt.Printf("%-40s %-8s %s\n", "HOST", "ACTION", "EXPIRES")
+			for _, e := range entries {
+				expires := e.ExpiresAt.Format(time.RFC3339)
+				if e.ExpiresAt.Before(now) {
+					expires += " (expired)"
+				}
+				fmt.Printf("%-40s %-8s %s\n", e.Host, e.Action, expires)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newFilterDecisionsClearCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "clear [host]",
+		Short: "Clear cached ask-mode decisions",
+		Long: `Clears the persisted ask-mode decision cache. With a host argument,
+removes only that host's cached decision. Without one, clears everything
+(equivalent to "devsandbox filter reset").`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sandboxBase, err := resolveSandboxBase()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				path := proxy.DecisionCachePath(sandboxBase)
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to clear filter decision cache: %w", err)
+				}
+				if jsonOutput {
+					return json.NewEncoder(os.Stdout).Encode(map[string]any{"cleared": "all"})
+				}
+				fmt.Println("Filter decision cache cleared.")
+				return nil
+			}
+
+			host := proxy.NormalizeHost(args[0])
+			removed, err := proxy.RemoveDecision(sandboxBase, host)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return json.NewEncoder(os.Stdout).Encode(map[string]any{"host": host, "removed": removed})
+			}
+			if removed {
+				fmt.Printf("Cleared cached decision for %s.\n", host)
+			} else {
+				fmt.Printf("No cached decision for %s.\n", host)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func newFilterResetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset",
+		Short: "Clear cached ask-mode decisions",
+		Long: `Deletes the persisted ask-mode decision cache (filter-decisions.json),
+so the next sandbox run prompts again for every host instead of reusing
+previously remembered allow/block decisions.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sandboxBase, err := resolveSandboxBase()
+			if err != nil {
+				return err
+			}
+
+			path := proxy.DecisionCachePath(sandboxBase)
+			if err := os.Remove(path); err != nil {
+				if os.IsNotExist(err) {
+					fmt.Println("No cached filter decisions to reset.")
+					return nil
+				}
+				return fmt.Errorf("failed to reset filter decision cache: %w", err)
+			}
+
+			fmt.Println("Filter decision cache reset.")
+			return nil
+		},
+	}
+}
+
 func newFilterGenerateCmd() *cobra.Command {
 	var (
 		fromLogs      string
@@ -91,6 +243,9 @@ func newFilterShowCmd() *cobra.Command {
 			if cfg.Proxy.Filter.CacheDecisions != nil {
 				fmt.Printf("Cache Decisions: %v\n", *cfg.Proxy.Filter.CacheDecisions)
 			}
+			if cfg.Proxy.Filter.CacheTTL != "" {
+				fmt.Printf("Cache TTL: %s\n", cfg.Proxy.Filter.CacheTTL)
+			}
 			fmt.Println()
 			fmt.Printf("Rules (%d):\n", len(cfg.Proxy.Filter.Rules))
 			for i, rule := range cfg.Proxy.Filter.Rules {
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"unicode/utf8"
+
+	"devsandbox/internal/proxy"
+	"devsandbox/internal/version"
+)
+
+// harVersion is the HAR format version produced by exportProxyLogsHAR.
+// See http://www.softwareishard.com/blog/har-12-spec/.
+const harVersion = "1.2"
+
+// harDocument is the top-level HAR document.
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           harCache    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harCache is always empty: the proxy doesn't capture cache metadata.
+type harCache struct{}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// exportProxyLogsHAR writes the filtered proxy logs for logDir to stdout as
+// a HAR 1.2 document, for import into browser devtools or other HAR-aware
+// tooling.
+func exportProxyLogsHAR(logDir string, filter *ProxyLogFilter) error {
+	entries, err := collectProxyLogEntries(logDir, filter)
+	if err != nil {
+		return err
+	}
+
+	doc := harDocument{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "devsandbox", Version: version.Version},
+			Entries: make([]harEntry, 0, len(entries)),
+		},
+	}
+
+	for _, e := range entries {
+		doc.Log.Entries = append(doc.Log.Entries, requestLogToHAREntry(&e))
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// requestLogToHAREntry converts a single RequestLog into a HAR entry.
+// RequestLog tracks only one aggregate Duration rather than separate
+// connect/send/wait/receive phases, so the full duration is attributed to
+// Timings.Wait and Send/Receive are left at zero.
+func requestLogToHAREntry(e *proxy.RequestLog) harEntry {
+	entry := harEntry{
+		StartedDateTime: e.Timestamp.Format(harTimeFormat),
+		Time:            float64(e.Duration.Milliseconds()),
+		Request: harRequest{
+			Method:      e.Method,
+			URL:         e.URL,
+			HTTPVersion: "HTTP/1.1",
+			Headers:     harHeaders(e.RequestHeaders),
+			QueryString: harQueryString(e.URL),
+			PostData:    harPostDataFromBody(e.RequestBody, e.RequestHeaders),
+			HeadersSize: -1,
+			BodySize:    len(e.RequestBody),
+		},
+		Cache: harCache{},
+		Timings: harTimings{
+			Wait: float64(e.Duration.Milliseconds()),
+		},
+	}
+
+	if e.Error != "" {
+		entry.Comment = e.Error
+		entry.Response = harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: ""},
+			HeadersSize: -1,
+			BodySize:    -1,
+		}
+		return entry
+	}
+
+	entry.Response = harResponse{
+		Status:      e.StatusCode,
+		StatusText:  http.StatusText(e.StatusCode),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     harHeaders(e.ResponseHeaders),
+		Content:     harContentFromBody(e.ResponseBody, e.ResponseHeaders),
+		HeadersSize: -1,
+		BodySize:    len(e.ResponseBody),
+	}
+
+	return entry
+}
+
+// harTimeFormat matches HAR's ISO 8601 startedDateTime field.
+const harTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+func harHeaders(headers map[string][]string) []harNameValue {
+	out := make([]harNameValue, 0, len(headers))
+	for name, values := range headers {
+		for _, v := range values {
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func harQueryString(rawURL string) []harNameValue {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return []harNameValue{}
+	}
+	out := make([]harNameValue, 0, len(u.Query()))
+	for name, values := range u.Query() {
+		for _, v := range values {
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func harContentType(headers map[string][]string) string {
+	if headers == nil {
+		return ""
+	}
+	if v, ok := headers[http.CanonicalHeaderKey("Content-Type")]; ok && len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// harBodyText returns the body as text along with the HAR encoding to use:
+// empty for valid UTF-8 text, "base64" for binary bodies.
+func harBodyText(body []byte) (text, encoding string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func harPostDataFromBody(body []byte, headers map[string][]string) *harPostData {
+	if len(body) == 0 {
+		return nil
+	}
+	text, encoding := harBodyText(body)
+	return &harPostData{
+		MimeType: harContentType(headers),
+		Text:     text,
+		Encoding: encoding,
+	}
+}
+
+func harContentFromBody(body []byte, headers map[string][]string) harContent {
+	text, encoding := harBodyText(body)
+	return harContent{
+		Size:     len(body),
+		MimeType: harContentType(headers),
+		Text:     text,
+		Encoding: encoding,
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"devsandbox/internal/proxy"
+)
+
+func TestRequestLogToHAREntry_TextBody(t *testing.T) {
+	e := &proxy.RequestLog{
+		Timestamp:       time.Now(),
+		Method:          "POST",
+		URL:             "https://example.com/api?q=1",
+		RequestHeaders:  map[string][]string{"Content-Type": {"application/json"}},
+		RequestBody:     []byte(`{"a":1}`),
+		StatusCode:      200,
+		ResponseHeaders: map[string][]string{"Content-Type": {"application/json"}},
+		ResponseBody:    []byte(`{"ok":true}`),
+		Duration:        250 * time.Millisecond,
+	}
+
+	entry := requestLogToHAREntry(e)
+
+	if entry.Request.Method != "POST" || entry.Request.URL != e.URL {
+		t.Errorf("unexpected request: %+v", entry.Request)
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.Encoding != "" || entry.Request.PostData.Text != `{"a":1}` {
+		t.Errorf("unexpected postData: %+v", entry.Request.PostData)
+	}
+	if len(entry.Request.QueryString) != 1 || entry.Request.QueryString[0].Name != "q" {
+		t.Errorf("unexpected queryString: %+v", entry.Request.QueryString)
+	}
+	if entry.Response.Status != 200 || entry.Response.Content.Text != `{"ok":true}` {
+		t.Errorf("unexpected response: %+v", entry.Response)
+	}
+	if entry.Time != 250 {
+		t.Errorf("Time = %v, want 250", entry.Time)
+	}
+}
+
+func TestRequestLogToHAREntry_BinaryBodyBase64(t *testing.T) {
+	e := &proxy.RequestLog{
+		Timestamp:    time.Now(),
+		Method:       "GET",
+		URL:          "https://example.com/img",
+		StatusCode:   200,
+		ResponseBody: []byte{0xff, 0xd8, 0xff, 0x00},
+	}
+
+	entry := requestLogToHAREntry(e)
+
+	if entry.Response.Content.Encoding != "base64" {
+		t.Errorf("Encoding = %q, want base64", entry.Response.Content.Encoding)
+	}
+}
+
+func TestRequestLogToHAREntry_Error(t *testing.T) {
+	e := &proxy.RequestLog{
+		Timestamp: time.Now(),
+		Method:    "GET",
+		URL:       "https://example.com/down",
+		Error:     "dial tcp: connection refused",
+	}
+
+	entry := requestLogToHAREntry(e)
+
+	if entry.Comment != e.Error {
+		t.Errorf("Comment = %q, want %q", entry.Comment, e.Error)
+	}
+	if entry.Response.Status != 0 {
+		t.Errorf("Status = %d, want 0 for errored request", entry.Response.Status)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/sandbox"
+)
+
+func newHistoryCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the recorded command history for the current project's sandbox",
+		Long: `Each devsandbox invocation against a sandbox appends an entry (argv,
+timestamp, exit status, whether the proxy was on) to that sandbox's
+history.jsonl, for audit and reproducibility.`,
+		Example: `  devsandbox history
+  devsandbox history --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			baseDir := sandbox.SandboxBasePath(homeDir)
+
+			projectDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			m, err := sandbox.FindExistingSandbox(projectDir, baseDir)
+			if err != nil {
+				return err
+			}
+			if m == nil {
+				fmt.Println("No sandbox found for this project.")
+				return nil
+			}
+
+			entries, err := sandbox.LoadHistory(m.SandboxRoot)
+			if err != nil {
+				return fmt.Errorf("failed to load history: %w", err)
+			}
+
+			if jsonOutput {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No history recorded yet.")
+				return nil
+			}
+
+			for _, e := range entries {
+				status := "?"
+				if e.ExitCode != nil {
+					status = fmt.Sprintf("%d", *e.ExitCode)
+				}
+				proxy := ""
+				if e.Proxy {
+					proxy = " [proxy]"
+				}
+				fmt.Printf("%s  exit=%s%s  %s\n", e.Timestamp.Format("2006-01-02 15:04:05"), status, proxy, strings.Join(e.Argv, " "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"devsandbox/internal/config"
+	"devsandbox/internal/sandbox"
+)
+
+func TestGitModeForInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		tools map[string]any
+		want  string
+	}{
+		{"unset defaults to readonly", nil, "readonly"},
+		{"explicit readwrite", map[string]any{"git": map[string]any{"mode": "readwrite"}}, "readwrite"},
+		{"invalid mode falls back to default", map[string]any{"git": map[string]any{"mode": "bogus"}}, "readonly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCfg := &config.Config{Tools: tt.tools}
+			if got := gitModeForInfo(appCfg); got != tt.want {
+				t.Errorf("gitModeForInfo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintInfoJSON(t *testing.T) {
+	cfg := &sandbox.Config{
+		ProjectName:  "myproject",
+		ProjectDir:   "/home/user/myproject",
+		SandboxHome:  "/home/user/.local/share/devsandbox/myproject/home",
+		SandboxRoot:  t.TempDir(),
+		BlockedPaths: []string{"/home/user/.ssh"},
+		ProxyEnabled: true,
+		ProxyPort:    8080,
+		ProxyCAPath:  "/tmp/ca.pem",
+		GatewayIP:    "10.0.0.1",
+	}
+	appCfg := &config.Config{}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout := os.Stdout
+	os.Stdout = w
+	err = printInfoJSON(cfg, appCfg)
+	w.Close()
+	os.Stdout = origStdout
+	if err != nil {
+		t.Fatalf("printInfoJSON() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+
+	var got InfoJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	if got.Project != cfg.ProjectName {
+		t.Errorf("Project = %q, want %q", got.Project, cfg.ProjectName)
+	}
+	if got.GitMode != "readonly" {
+		t.Errorf("GitMode = %q, want readonly", got.GitMode)
+	}
+	if !got.Proxy.Enabled || got.Proxy.Port != 8080 || got.Proxy.Gateway != "10.0.0.1" {
+		t.Errorf("Proxy = %+v, unexpected", got.Proxy)
+	}
+	if got.SessionActive {
+		t.Errorf("SessionActive = true, want false (no lock held)")
+	}
+}
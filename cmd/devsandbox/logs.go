@@ -1,6 +1,7 @@
 package main
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"compress/gzip"
@@ -8,6 +9,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -20,7 +23,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/andybalholm/brotli"
 	"github.com/olekukonko/tablewriter"
+	"golang.org/x/term"
 
 	"devsandbox/internal/proxy"
 	"devsandbox/internal/sandbox"
@@ -99,6 +104,34 @@ func (f *ProxyLogFilter) Match(entry *proxy.RequestLog) bool {
 	return true
 }
 
+// String returns a human-readable summary of the active filter criteria,
+// or "" if no filters are set.
+func (f *ProxyLogFilter) String() string {
+	var parts []string
+	if f.URL != "" {
+		parts = append(parts, fmt.Sprintf("url=%q", f.URL))
+	}
+	if f.Method != "" {
+		parts = append(parts, fmt.Sprintf("method=%s", f.Method))
+	}
+	if f.StatusCode > 0 {
+		parts = append(parts, fmt.Sprintf("status=%d", f.StatusCode))
+	}
+	if f.StatusMin > 0 || f.StatusMax > 0 {
+		parts = append(parts, fmt.Sprintf("status=%d-%d", f.StatusMin, f.StatusMax))
+	}
+	if !f.Since.IsZero() {
+		parts = append(parts, fmt.Sprintf("since=%s", f.Since.UTC().Format(time.RFC3339)))
+	}
+	if !f.Until.IsZero() {
+		parts = append(parts, fmt.Sprintf("until=%s", f.Until.UTC().Format(time.RFC3339)))
+	}
+	if f.ErrorsOnly {
+		parts = append(parts, "errors_only")
+	}
+	return strings.Join(parts, " ")
+}
+
 // ParseTimeFilter parses various time formats into a time.Time.
 // Supported formats:
 // - RFC3339: 2024-01-15T10:30:00Z
@@ -224,6 +257,7 @@ func newLogsProxyCmd() *cobra.Command {
 		follow       bool
 		jsonOutput   bool
 		showBody     bool
+		rawBody      bool
 		filterURL    string
 		filterMethod string
 		filterStatus string
@@ -233,6 +267,12 @@ func newLogsProxyCmd() *cobra.Command {
 		noColor      bool
 		compact      bool
 		stats        bool
+		groupBy      string
+		bundlePath   string
+		exportFormat string
+		dedup        bool
+		dedupWindow  string
+		useSocket    bool
 	)
 
 	cmd := &cobra.Command{
@@ -256,6 +296,7 @@ Status filters support:
   devsandbox logs proxy myproject            # Logs for specific sandbox
   devsandbox logs proxy --last 50            # Show last 50 requests
   devsandbox logs proxy -f                   # Follow/tail logs
+  devsandbox logs proxy -f --socket          # Follow via the live event socket (lower latency)
   devsandbox logs proxy --since 1h           # Logs from last hour
   devsandbox logs proxy --since today        # Logs from today
   devsandbox logs proxy --errors             # Show only errors
@@ -263,7 +304,10 @@ Status filters support:
   devsandbox logs proxy --url /api --method POST  # Filter by URL and method
   devsandbox logs proxy --json               # JSON output
   devsandbox logs proxy --compact            # Compact one-line format
-  devsandbox logs proxy --stats              # Show statistics summary`,
+  devsandbox logs proxy --stats              # Show statistics summary
+  devsandbox logs proxy --export har > session.har  # Export as HAR
+  devsandbox logs proxy --dedup              # Collapse repeated identical requests
+  devsandbox logs proxy --dedup --dedup-window 5s  # ...but only within a 5s window`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
@@ -304,6 +348,25 @@ Status filters support:
 				filter.StatusMax = max
 			}
 
+			// Parse dedup window
+			var dedupWindowDur time.Duration
+			if dedupWindow != "" {
+				d, err := time.ParseDuration(dedupWindow)
+				if err != nil {
+					return fmt.Errorf("invalid --dedup-window %q: %w", dedupWindow, err)
+				}
+				dedupWindowDur = d
+			}
+			if dedupWindow != "" && !dedup {
+				return fmt.Errorf("--dedup-window requires --dedup")
+			}
+			if dedup && follow {
+				return fmt.Errorf("--dedup is not supported with --follow")
+			}
+			if useSocket && !follow {
+				return fmt.Errorf("--socket requires --follow")
+			}
+
 			// Determine sandbox name
 			name := sandboxName
 			if len(args) > 0 {
@@ -326,19 +389,46 @@ Status filters support:
 				return fmt.Errorf("no logs found for sandbox %q (run with --proxy to capture logs)", name)
 			}
 
+			if bundlePath != "" {
+				return writeProxyLogBundle(logDir, sandboxRoot, bundlePath, filter)
+			}
+
+			if exportFormat != "" {
+				switch exportFormat {
+				case "har":
+					return exportProxyLogsHAR(logDir, filter)
+				default:
+					return fmt.Errorf("unsupported export format %q (supported: har)", exportFormat)
+				}
+			}
+
+			if groupBy != "" {
+				switch groupBy {
+				case "host", "method", "status":
+				default:
+					return fmt.Errorf("unsupported --group-by %q (supported: host, method, status)", groupBy)
+				}
+			}
+
+			if follow && useSocket {
+				return followProxyLogsSocket(sandboxRoot, filter, jsonOutput, showBody, rawBody, compact, noColor)
+			}
+
 			if follow {
-				return followProxyLogs(logDir, filter, jsonOutput, showBody, compact, noColor)
+				return followProxyLogs(logDir, filter, jsonOutput, showBody, rawBody, compact, noColor)
 			}
 
-			return viewProxyLogs(logDir, filter, last, jsonOutput, showBody, compact, noColor, stats)
+			return viewProxyLogs(logDir, filter, last, jsonOutput, showBody, rawBody, compact, noColor, stats, groupBy, dedup, dedupWindowDur)
 		},
 	}
 
 	cmd.Flags().StringVarP(&sandboxName, "sandbox", "s", "", "Sandbox name (default: current directory)")
 	cmd.Flags().IntVarP(&last, "last", "n", 0, "Show only last N entries (default: 100)")
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow/tail log output")
+	cmd.Flags().BoolVar(&useSocket, "socket", false, "With --follow, connect to the live event socket instead of tailing files (requires proxy.stream_socket = true)")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	cmd.Flags().BoolVar(&showBody, "body", false, "Include request/response bodies")
+	cmd.Flags().BoolVar(&rawBody, "raw", false, "Show bodies as stored, without decoding Content-Encoding or pretty-printing JSON")
 	cmd.Flags().StringVar(&filterURL, "url", "", "Filter by URL (substring match)")
 	cmd.Flags().StringVar(&filterMethod, "method", "", "Filter by HTTP method")
 	cmd.Flags().StringVar(&filterStatus, "status", "", "Filter by status code (e.g., 200, 400-599, >=400)")
@@ -348,11 +438,171 @@ Status filters support:
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.Flags().BoolVar(&compact, "compact", false, "Compact one-line output format")
 	cmd.Flags().BoolVar(&stats, "stats", false, "Show summary statistics")
+	cmd.Flags().StringVar(&groupBy, "group-by", "host", "Dimension for the --stats breakdown: host, method, or status")
+	cmd.Flags().StringVar(&bundlePath, "bundle", "", "Export filtered logs, CA cert, and a manifest as a replay bundle (tarball) instead of printing")
+	cmd.Flags().StringVar(&exportFormat, "export", "", "Export filtered logs to stdout in the given format: har")
+	cmd.Flags().BoolVar(&dedup, "dedup", false, "Collapse consecutive identical method+url+status entries into one line with a count and time span")
+	cmd.Flags().StringVar(&dedupWindow, "dedup-window", "", "Only collapse entries into a run if they fall within this duration of the run's start (e.g. 5s); unset means no limit")
+
+	cmd.ValidArgsFunction = completeSandboxNames
+	_ = cmd.RegisterFlagCompletionFunc("sandbox", completeSandboxNames)
+	_ = cmd.RegisterFlagCompletionFunc("method", completeFromList(httpMethods))
+	_ = cmd.RegisterFlagCompletionFunc("group-by", completeFromList(proxyStatsGroupBy))
 
 	return cmd
 }
 
-func viewProxyLogs(logDir string, filter *ProxyLogFilter, last int, jsonOutput, showBody, compact, noColor, showStats bool) error {
+// proxyBundleManifest describes the contents of a replay bundle exported via --bundle.
+type proxyBundleManifest struct {
+	Sandbox      string `json:"sandbox"`
+	GeneratedAt  string `json:"generated_at"`
+	EntryCount   int    `json:"entry_count"`
+	OldestEntry  string `json:"oldest_entry,omitempty"`
+	NewestEntry  string `json:"newest_entry,omitempty"`
+	Filter       string `json:"filter,omitempty"`
+	IncludesCert bool   `json:"includes_cert"`
+}
+
+// collectProxyLogEntries reads every active and archived proxy log file in
+// logDir, applies filter, and returns the matching entries in chronological
+// order. It reads whole files (no --last truncation), so it's meant for
+// bulk-export paths like --bundle and --export rather than interactive
+// viewing.
+func collectProxyLogEntries(logDir string, filter *ProxyLogFilter) ([]proxy.RequestLog, error) {
+	activePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogSuffix)
+	archivePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogArchiveSuffix)
+
+	activeFiles, err := filepath.Glob(activePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log pattern: %w", err)
+	}
+	archiveFiles, err := filepath.Glob(archivePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive pattern: %w", err)
+	}
+
+	files := append(archiveFiles, activeFiles...)
+	sort.Strings(files)
+
+	var entries []proxy.RequestLog
+	for _, file := range files {
+		fileEntries, err := readProxyLogFileWithLimit(file, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", filepath.Base(file), err)
+			continue
+		}
+		for _, e := range fileEntries {
+			if filter.Match(&e) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// writeProxyLogBundle packages the filtered proxy logs for a sandbox, its CA
+// certificate, and a small manifest into a self-contained tarball that can be
+// handed to a teammate to inspect or replay offline.
+func writeProxyLogBundle(logDir, sandboxRoot, bundlePath string, filter *ProxyLogFilter) error {
+	entries, err := collectProxyLogEntries(logDir, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no matching log entries to bundle")
+	}
+
+	entriesJSON, err := marshalProxyLogsNDJSON(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode log entries: %w", err)
+	}
+
+	manifest := proxyBundleManifest{
+		Sandbox:     filepath.Base(sandboxRoot),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		EntryCount:  len(entries),
+		OldestEntry: entries[0].Timestamp.UTC().Format(time.RFC3339),
+		NewestEntry: entries[len(entries)-1].Timestamp.UTC().Format(time.RFC3339),
+		Filter:      filter.String(),
+	}
+
+	caCertPath := proxy.NewConfig(sandboxRoot, 0).CACertPath
+	caCert, err := os.ReadFile(caCertPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		caCert = nil
+	}
+	manifest.IncludesCert = caCert != nil
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	if err := addBundleFile(tw, "manifest.json", manifestJSON); err != nil {
+		return err
+	}
+	if err := addBundleFile(tw, "requests.jsonl", entriesJSON); err != nil {
+		return err
+	}
+	if caCert != nil {
+		if err := addBundleFile(tw, "ca.crt", caCert); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+
+	fmt.Printf("Wrote replay bundle with %d entries to %s\n", len(entries), bundlePath)
+	return nil
+}
+
+func addBundleFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name: name,
+		Mode: 0o600,
+		Size: int64(len(data)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func marshalProxyLogsNDJSON(entries []proxy.RequestLog) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func viewProxyLogs(logDir string, filter *ProxyLogFilter, last int, jsonOutput, showBody, rawBody, compact, noColor, showStats bool, groupBy string, dedup bool, dedupWindow time.Duration) error {
 	// Find both compressed and uncompressed log files
 	activePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogSuffix)
 	archivePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogArchiveSuffix)
@@ -427,26 +677,52 @@ func viewProxyLogs(logDir string, filter *ProxyLogFilter, last int, jsonOutput,
 	}
 
 	// Output
+	if dedup {
+		deduped := dedupRequestLogs(entries, dedupWindow)
+
+		if jsonOutput {
+			return printProxyLogsJSONDeduped(deduped, showBody, rawBody)
+		}
+		if compact {
+			return printProxyLogsCompactDeduped(deduped, noColor)
+		}
+
+		err = printProxyLogsTableDeduped(deduped, showBody, rawBody, noColor)
+		if err != nil {
+			return err
+		}
+
+		if showStats {
+			if err := printProxyLogStats(logDir, filter, groupBy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	if jsonOutput {
-		return printProxyLogsJSON(entries, showBody)
+		return printProxyLogsJSON(entries, showBody, rawBody)
 	}
 	if compact {
 		return printProxyLogsCompact(entries, noColor)
 	}
 
-	err = printProxyLogsTable(entries, showBody, noColor)
+	err = printProxyLogsTable(entries, showBody, rawBody, noColor)
 	if err != nil {
 		return err
 	}
 
 	if showStats {
-		printProxyStats(entries)
+		if err := printProxyLogStats(logDir, filter, groupBy); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func followProxyLogs(logDir string, filter *ProxyLogFilter, jsonOutput, showBody, compact, noColor bool) error {
+func followProxyLogs(logDir string, filter *ProxyLogFilter, jsonOutput, showBody, rawBody, compact, noColor bool) error {
 	// Set up signal handling for graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -454,28 +730,8 @@ func followProxyLogs(logDir string, filter *ProxyLogFilter, jsonOutput, showBody
 	// Pattern for uncompressed active files
 	activePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogSuffix)
 
-	// Helper to print an entry
 	printEntry := func(e *proxy.RequestLog) {
-		if !filter.Match(e) {
-			return
-		}
-		if jsonOutput {
-			out := *e
-			if !showBody {
-				out.RequestBody = nil
-				out.ResponseBody = nil
-			}
-			data, err := json.Marshal(out)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to marshal log entry: %v\n", err)
-				return
-			}
-			fmt.Println(string(data))
-		} else if compact {
-			printProxyLogCompactLine(e, noColor)
-		} else {
-			printProxyLogLine(e, showBody, noColor)
-		}
+		printProxyLogEntry(e, filter, jsonOutput, showBody, rawBody, compact, noColor)
 	}
 
 	// Find current active log file
@@ -527,8 +783,17 @@ func followProxyLogs(logDir string, filter *ProxyLogFilter, jsonOutput, showBody
 				continue
 			}
 
-			// If file changed (rotation), start from beginning of new file
+			// If file changed (rotation), drain any entries written to the
+			// old file between the last tick and the rotation before
+			// switching, so a burst right at rotation isn't lost.
 			if currentFile != lastFile {
+				if lastFile != "" {
+					if entries, _, err := tailProxyLogFile(lastFile, lastOffset); err == nil {
+						for i := range entries {
+							printEntry(&entries[i])
+						}
+					}
+				}
 				lastFile = currentFile
 				lastOffset = 0
 			}
@@ -547,6 +812,76 @@ func followProxyLogs(logDir string, filter *ProxyLogFilter, jsonOutput, showBody
 	}
 }
 
+// printProxyLogEntry renders a single RequestLog according to the same
+// filter/format flags accepted by `devsandbox logs proxy`, shared by the
+// file-tailing and socket-streaming follow modes.
+func printProxyLogEntry(e *proxy.RequestLog, filter *ProxyLogFilter, jsonOutput, showBody, rawBody, compact, noColor bool) {
+	if !filter.Match(e) {
+		return
+	}
+	if jsonOutput {
+		out := *e
+		if !showBody {
+			out.RequestBody = nil
+			out.ResponseBody = nil
+		} else if !rawBody {
+			out.RequestBody = decodeLogBody(out.RequestBody, out.RequestHeaders)
+			out.ResponseBody = decodeLogBody(out.ResponseBody, out.ResponseHeaders)
+		}
+		data, err := json.Marshal(out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to marshal log entry: %v\n", err)
+			return
+		}
+		fmt.Println(string(data))
+	} else if compact {
+		printProxyLogCompactLine(e, noColor)
+	} else {
+		printProxyLogLine(e, showBody, rawBody, noColor)
+	}
+}
+
+// followProxyLogsSocket connects to the live event socket (see
+// proxy.StreamServer, enabled by proxy.stream_socket) and prints each
+// RequestLog as it arrives, instead of polling the persisted log files -
+// lower latency, at the cost of missing anything published before it
+// connects.
+func followProxyLogsSocket(sandboxRoot string, filter *ProxyLogFilter, jsonOutput, showBody, rawBody, compact, noColor bool) error {
+	socketPath := proxy.EventSocketPath(sandboxRoot)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to connect to event socket %s (is proxy.stream_socket enabled and the sandbox running?): %w", socketPath, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Following live proxy events via %s (Ctrl+C to stop)...\n", socketPath)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry proxy.RequestLog
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to decode event line: %v\n", err)
+			continue
+		}
+		printProxyLogEntry(&entry, filter, jsonOutput, showBody, rawBody, compact, noColor)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
 // tailProxyLogFile reads new entries from an uncompressed JSONL file starting at offset.
 // It returns only complete lines and tracks the position after the last complete line,
 // so partial lines (from in-progress writes) are not lost.
@@ -707,7 +1042,7 @@ func readCompressedProxyLogFile(path string, limit int) ([]proxy.RequestLog, err
 	return entries, nil
 }
 
-func printProxyLogsJSON(entries []proxy.RequestLog, showBody bool) error {
+func printProxyLogsJSON(entries []proxy.RequestLog, showBody, rawBody bool) error {
 	output := entries
 	if !showBody {
 		output = make([]proxy.RequestLog, len(entries))
@@ -716,6 +1051,13 @@ func printProxyLogsJSON(entries []proxy.RequestLog, showBody bool) error {
 			output[i].RequestBody = nil
 			output[i].ResponseBody = nil
 		}
+	} else if !rawBody {
+		output = make([]proxy.RequestLog, len(entries))
+		for i, e := range entries {
+			output[i] = e
+			output[i].RequestBody = decodeLogBody(e.RequestBody, e.RequestHeaders)
+			output[i].ResponseBody = decodeLogBody(e.ResponseBody, e.ResponseHeaders)
+		}
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -723,7 +1065,7 @@ func printProxyLogsJSON(entries []proxy.RequestLog, showBody bool) error {
 	return encoder.Encode(output)
 }
 
-func printProxyLogsTable(entries []proxy.RequestLog, showBody, noColor bool) error {
+func printProxyLogsTable(entries []proxy.RequestLog, showBody, rawBody, noColor bool) error {
 	table := tablewriter.NewWriter(os.Stdout)
 
 	if showBody {
@@ -754,13 +1096,18 @@ func printProxyLogsTable(entries []proxy.RequestLog, showBody, noColor bool) err
 		}
 
 		if showBody {
-			reqBody := truncateLogBody(e.RequestBody, 80)
-			respBody := truncateLogBody(e.ResponseBody, 80)
-			if reqBody == "" {
-				reqBody = "-"
+			reqBody, respBody := e.RequestBody, e.ResponseBody
+			if !rawBody {
+				reqBody = decodeLogBody(reqBody, e.RequestHeaders)
+				respBody = decodeLogBody(respBody, e.ResponseHeaders)
 			}
-			if respBody == "" {
-				respBody = "-"
+			reqStr := truncateLogBody(reqBody, 80)
+			respStr := truncateLogBody(respBody, 80)
+			if reqStr == "" {
+				reqStr = "-"
+			}
+			if respStr == "" {
+				respStr = "-"
 			}
 			_ = table.Append(
 				e.Timestamp.Format("15:04:05"),
@@ -768,8 +1115,8 @@ func printProxyLogsTable(entries []proxy.RequestLog, showBody, noColor bool) err
 				status,
 				duration,
 				url,
-				reqBody,
-				respBody,
+				reqStr,
+				respStr,
 			)
 		} else {
 			_ = table.Append(
@@ -816,7 +1163,7 @@ func printProxyLogCompactLine(e *proxy.RequestLog, noColor bool) {
 	)
 }
 
-func printProxyLogLine(e *proxy.RequestLog, showBody, noColor bool) {
+func printProxyLogLine(e *proxy.RequestLog, showBody, rawBody, noColor bool) {
 	status := fmt.Sprintf("%d", e.StatusCode)
 	if e.Error != "" {
 		status = "ERR"
@@ -840,13 +1187,190 @@ func printProxyLogLine(e *proxy.RequestLog, showBody, noColor bool) {
 	)
 
 	if showBody {
-		if len(e.RequestBody) > 0 {
-			fmt.Printf("  → REQ: %s\n", truncateLogBody(e.RequestBody, 200))
+		reqBody, respBody := e.RequestBody, e.ResponseBody
+		if !rawBody {
+			reqBody = decodeLogBody(reqBody, e.RequestHeaders)
+			respBody = decodeLogBody(respBody, e.ResponseHeaders)
+		}
+		if len(reqBody) > 0 {
+			fmt.Printf("  → REQ: %s\n", truncateLogBody(reqBody, 200))
 		}
-		if len(e.ResponseBody) > 0 {
-			fmt.Printf("  ← RSP: %s\n", truncateLogBody(e.ResponseBody, 200))
+		if len(respBody) > 0 {
+			fmt.Printf("  ← RSP: %s\n", truncateLogBody(respBody, 200))
+		}
+	}
+}
+
+// dedupedLogEntry is a run of one or more consecutive RequestLog entries
+// that share the same method, URL, and status code, collapsed into a
+// single line for display. Timestamp is the run's first occurrence; Span
+// is the gap between its first and last occurrence.
+type dedupedLogEntry struct {
+	proxy.RequestLog
+	Count int           `json:"dedup_count"`
+	Span  time.Duration `json:"dedup_span"`
+}
+
+// dedupRequestLogs collapses consecutive entries that share the same
+// method, URL, and status code into a single dedupedLogEntry. It's meant
+// to be applied at display time, after filtering, so it never touches the
+// underlying log files. If window is non-zero, a run stops growing once
+// including the next matching entry would make its span exceed window,
+// even though the entries are otherwise identical.
+func dedupRequestLogs(entries []proxy.RequestLog, window time.Duration) []dedupedLogEntry {
+	var result []dedupedLogEntry
+
+	for _, e := range entries {
+		if n := len(result); n > 0 {
+			last := &result[n-1]
+			sameKey := last.Method == e.Method && last.URL == e.URL && last.StatusCode == e.StatusCode
+			withinWindow := window <= 0 || e.Timestamp.Sub(last.Timestamp) <= window
+			if sameKey && withinWindow {
+				last.Count++
+				last.Span = e.Timestamp.Sub(last.Timestamp)
+				continue
+			}
 		}
+		result = append(result, dedupedLogEntry{RequestLog: e, Count: 1})
 	}
+
+	return result
+}
+
+func printProxyLogsJSONDeduped(entries []dedupedLogEntry, showBody, rawBody bool) error {
+	output := entries
+	if !showBody {
+		output = make([]dedupedLogEntry, len(entries))
+		for i, e := range entries {
+			output[i] = e
+			output[i].RequestBody = nil
+			output[i].ResponseBody = nil
+		}
+	} else if !rawBody {
+		output = make([]dedupedLogEntry, len(entries))
+		for i, e := range entries {
+			output[i] = e
+			output[i].RequestBody = decodeLogBody(e.RequestBody, e.RequestHeaders)
+			output[i].ResponseBody = decodeLogBody(e.ResponseBody, e.ResponseHeaders)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func printProxyLogsTableDeduped(entries []dedupedLogEntry, showBody, rawBody, noColor bool) error {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	if showBody {
+		table.Header("TIME", "METHOD", "STATUS", "DURATION", "URL", "COUNT", "SPAN", "REQ BODY", "RESP BODY")
+	} else {
+		table.Header("TIME", "METHOD", "STATUS", "DURATION", "URL", "COUNT", "SPAN")
+	}
+
+	for _, e := range entries {
+		status := fmt.Sprintf("%d", e.StatusCode)
+		if e.Error != "" {
+			status = "ERR"
+		}
+
+		if !noColor {
+			status = colorizeStatus(status, e.StatusCode, e.Error)
+		}
+
+		duration := "-"
+		if e.Duration > 0 {
+			duration = e.Duration.Round(time.Millisecond).String()
+		}
+
+		url := e.URL
+		if len(url) > 60 {
+			url = url[:57] + "..."
+		}
+
+		count := fmt.Sprintf("%d", e.Count)
+		span := "-"
+		if e.Count > 1 {
+			span = e.Span.Round(time.Millisecond).String()
+		}
+
+		if showBody {
+			reqBody, respBody := e.RequestBody, e.ResponseBody
+			if !rawBody {
+				reqBody = decodeLogBody(reqBody, e.RequestHeaders)
+				respBody = decodeLogBody(respBody, e.ResponseHeaders)
+			}
+			reqStr := truncateLogBody(reqBody, 80)
+			respStr := truncateLogBody(respBody, 80)
+			if reqStr == "" {
+				reqStr = "-"
+			}
+			if respStr == "" {
+				respStr = "-"
+			}
+			_ = table.Append(
+				e.Timestamp.Format("15:04:05"),
+				e.Method,
+				status,
+				duration,
+				url,
+				count,
+				span,
+				reqStr,
+				respStr,
+			)
+		} else {
+			_ = table.Append(
+				e.Timestamp.Format("15:04:05"),
+				e.Method,
+				status,
+				duration,
+				url,
+				count,
+				span,
+			)
+		}
+	}
+
+	return table.Render()
+}
+
+func printProxyLogsCompactDeduped(entries []dedupedLogEntry, noColor bool) error {
+	for _, e := range entries {
+		printProxyDedupedCompactLine(&e, noColor)
+	}
+	return nil
+}
+
+func printProxyDedupedCompactLine(e *dedupedLogEntry, noColor bool) {
+	status := fmt.Sprintf("%d", e.StatusCode)
+	if e.Error != "" {
+		status = "ERR"
+	}
+
+	if !noColor {
+		status = colorizeStatus(status, e.StatusCode, e.Error)
+	}
+
+	duration := "-"
+	if e.Duration > 0 {
+		duration = fmt.Sprintf("%dms", e.Duration.Milliseconds())
+	}
+
+	suffix := ""
+	if e.Count > 1 {
+		suffix = fmt.Sprintf(" (x%d over %s)", e.Count, e.Span.Round(time.Millisecond))
+	}
+
+	fmt.Printf("%s %s %s %s %s%s\n",
+		e.Timestamp.Format("15:04:05"),
+		e.Method,
+		status,
+		duration,
+		e.URL,
+		suffix,
+	)
 }
 
 func colorizeStatus(status string, code int, errMsg string) string {
@@ -868,74 +1392,344 @@ func colorizeStatus(status string, code int, errMsg string) string {
 	}
 }
 
-func printProxyStats(entries []proxy.RequestLog) {
-	if len(entries) == 0 {
-		return
+// proxyStatsSlowestN is how many of the slowest requests `--stats` reports.
+const proxyStatsSlowestN = 5
+
+// proxyLogStatsGroup accumulates counts and bytes for one --group-by key.
+type proxyLogStatsGroup struct {
+	Count    int
+	BytesIn  int64
+	BytesOut int64
+}
+
+// proxyLogStats accumulates `logs proxy --stats` aggregates one entry at a
+// time via add(), so a full session's logs can be summarized without ever
+// holding more than proxyStatsSlowestN decoded entries in memory (see
+// forEachProxyLogEntry, which streams the files entry-by-entry).
+type proxyLogStats struct {
+	GroupBy string
+
+	Total     int
+	Success   int // 2xx
+	Redirect  int // 3xx
+	ClientErr int // 4xx
+	ServerErr int // 5xx
+	Errors    int // error field set
+	BytesIn   int64
+	BytesOut  int64
+	TotalDur  time.Duration
+	DurCount  int
+	MinTime   time.Time
+	MaxTime   time.Time
+
+	groups     map[string]*proxyLogStatsGroup
+	groupOrder []string
+	slowest    []proxy.RequestLog
+}
+
+func newProxyLogStats(groupBy string) *proxyLogStats {
+	return &proxyLogStats{GroupBy: groupBy, groups: make(map[string]*proxyLogStatsGroup)}
+}
+
+func (s *proxyLogStats) add(e *proxy.RequestLog) {
+	if s.Total == 0 || e.Timestamp.Before(s.MinTime) {
+		s.MinTime = e.Timestamp
+	}
+	if s.Total == 0 || e.Timestamp.After(s.MaxTime) {
+		s.MaxTime = e.Timestamp
 	}
+	s.Total++
 
-	var (
-		total     = len(entries)
-		success   int // 2xx
-		redirect  int // 3xx
-		clientErr int // 4xx
-		serverErr int // 5xx
-		errors    int // error field set
-		totalDur  time.Duration
-		durCount  int
-		minTime   = entries[0].Timestamp
-		maxTime   = entries[0].Timestamp
-	)
+	switch {
+	case e.Error != "":
+		s.Errors++
+	case e.StatusCode >= 500:
+		s.ServerErr++
+	case e.StatusCode >= 400:
+		s.ClientErr++
+	case e.StatusCode >= 300:
+		s.Redirect++
+	case e.StatusCode >= 200:
+		s.Success++
+	}
 
-	for _, e := range entries {
-		switch {
-		case e.Error != "":
-			errors++
-		case e.StatusCode >= 500:
-			serverErr++
-		case e.StatusCode >= 400:
-			clientErr++
-		case e.StatusCode >= 300:
-			redirect++
-		case e.StatusCode >= 200:
-			success++
+	if e.Duration > 0 {
+		s.TotalDur += e.Duration
+		s.DurCount++
+	}
+
+	bytesIn := int64(len(e.RequestBody))
+	bytesOut := int64(len(e.ResponseBody))
+	s.BytesIn += bytesIn
+	s.BytesOut += bytesOut
+
+	if s.GroupBy != "" {
+		key := proxyStatsGroupKey(e, s.GroupBy)
+		g, ok := s.groups[key]
+		if !ok {
+			g = &proxyLogStatsGroup{}
+			s.groups[key] = g
+			s.groupOrder = append(s.groupOrder, key)
 		}
+		g.Count++
+		g.BytesIn += bytesIn
+		g.BytesOut += bytesOut
+	}
 
-		if e.Duration > 0 {
-			totalDur += e.Duration
-			durCount++
-		}
+	s.recordSlowest(e)
+}
+
+// recordSlowest keeps the top proxyStatsSlowestN entries by Duration,
+// re-sorting the small slice in place rather than collecting every entry.
+func (s *proxyLogStats) recordSlowest(e *proxy.RequestLog) {
+	if e.Duration <= 0 {
+		return
+	}
+	if len(s.slowest) < proxyStatsSlowestN {
+		s.slowest = append(s.slowest, *e)
+	} else if e.Duration > s.slowest[len(s.slowest)-1].Duration {
+		s.slowest[len(s.slowest)-1] = *e
+	} else {
+		return
+	}
+	sort.Slice(s.slowest, func(i, j int) bool { return s.slowest[i].Duration > s.slowest[j].Duration })
+}
 
-		if e.Timestamp.Before(minTime) {
-			minTime = e.Timestamp
+// proxyStatsGroupKey extracts the --group-by key for an entry.
+func proxyStatsGroupKey(e *proxy.RequestLog, groupBy string) string {
+	switch groupBy {
+	case "method":
+		return e.Method
+	case "status":
+		if e.Error != "" {
+			return "ERR"
 		}
-		if e.Timestamp.After(maxTime) {
-			maxTime = e.Timestamp
+		return fmt.Sprintf("%d", e.StatusCode)
+	default: // "host"
+		if u, err := url.Parse(e.URL); err == nil && u.Host != "" {
+			return u.Host
 		}
+		return e.URL
+	}
+}
+
+// printProxyLogStats streams every entry matching filter across logDir and
+// prints the aggregated `--stats` summary.
+func printProxyLogStats(logDir string, filter *ProxyLogFilter, groupBy string) error {
+	stats := newProxyLogStats(groupBy)
+	if err := forEachProxyLogEntry(logDir, filter, stats.add); err != nil {
+		return err
+	}
+	stats.print()
+	return nil
+}
+
+func (s *proxyLogStats) print() {
+	if s.Total == 0 {
+		return
 	}
 
 	fmt.Println()
 	fmt.Println("Summary:")
-	fmt.Printf("  Total requests: %d\n", total)
-	if success > 0 {
-		fmt.Printf("  Success (2xx):  %d (%.0f%%)\n", success, float64(success)/float64(total)*100)
+	fmt.Printf("  Total requests: %d\n", s.Total)
+	if s.Success > 0 {
+		fmt.Printf("  Success (2xx):  %d (%.0f%%)\n", s.Success, float64(s.Success)/float64(s.Total)*100)
 	}
-	if redirect > 0 {
-		fmt.Printf("  Redirect (3xx): %d (%.0f%%)\n", redirect, float64(redirect)/float64(total)*100)
+	if s.Redirect > 0 {
+		fmt.Printf("  Redirect (3xx): %d (%.0f%%)\n", s.Redirect, float64(s.Redirect)/float64(s.Total)*100)
 	}
-	if clientErr > 0 {
-		fmt.Printf("  Client err (4xx): %d (%.0f%%)\n", clientErr, float64(clientErr)/float64(total)*100)
+	if s.ClientErr > 0 {
+		fmt.Printf("  Client err (4xx): %d (%.0f%%)\n", s.ClientErr, float64(s.ClientErr)/float64(s.Total)*100)
 	}
-	if serverErr > 0 {
-		fmt.Printf("  Server err (5xx): %d (%.0f%%)\n", serverErr, float64(serverErr)/float64(total)*100)
+	if s.ServerErr > 0 {
+		fmt.Printf("  Server err (5xx): %d (%.0f%%)\n", s.ServerErr, float64(s.ServerErr)/float64(s.Total)*100)
 	}
-	if errors > 0 {
-		fmt.Printf("  Errors: %d (%.0f%%)\n", errors, float64(errors)/float64(total)*100)
+	if s.Errors > 0 {
+		fmt.Printf("  Errors: %d (%.0f%%)\n", s.Errors, float64(s.Errors)/float64(s.Total)*100)
 	}
-	if durCount > 0 {
-		avgDur := totalDur / time.Duration(durCount)
+	if s.DurCount > 0 {
+		avgDur := s.TotalDur / time.Duration(s.DurCount)
 		fmt.Printf("  Avg duration: %s\n", avgDur.Round(time.Millisecond))
 	}
-	fmt.Printf("  Time range: %s - %s\n", minTime.Format("2006-01-02 15:04"), maxTime.Format("15:04"))
+	fmt.Printf("  Bytes in/out: %s / %s\n", sandbox.FormatSize(s.BytesIn), sandbox.FormatSize(s.BytesOut))
+	fmt.Printf("  Time range: %s - %s\n", s.MinTime.Format("2006-01-02 15:04"), s.MaxTime.Format("15:04"))
+
+	if s.GroupBy != "" && len(s.groupOrder) > 0 {
+		sort.Slice(s.groupOrder, func(i, j int) bool {
+			return s.groups[s.groupOrder[i]].Count > s.groups[s.groupOrder[j]].Count
+		})
+		fmt.Printf("\nBy %s:\n", s.GroupBy)
+		for _, key := range s.groupOrder {
+			g := s.groups[key]
+			fmt.Printf("  %-30s %6d  (in %s / out %s)\n", key, g.Count, sandbox.FormatSize(g.BytesIn), sandbox.FormatSize(g.BytesOut))
+		}
+	}
+
+	if len(s.slowest) > 0 {
+		fmt.Printf("\nSlowest %d:\n", len(s.slowest))
+		for _, e := range s.slowest {
+			status := fmt.Sprintf("%d", e.StatusCode)
+			if e.Error != "" {
+				status = "ERR"
+			}
+			fmt.Printf("  %-8s %-6s %-4s %s\n", e.Duration.Round(time.Millisecond), e.Method, status, e.URL)
+		}
+	}
+}
+
+// forEachProxyLogEntry streams every entry matching filter across logDir's
+// active and archived log files, in chronological order. Unlike
+// readProxyLogFileWithLimit, it never holds more than one decoded entry in
+// memory at a time, so --stats stays cheap even over sessions with
+// hundreds of MB of rotated logs.
+func forEachProxyLogEntry(logDir string, filter *ProxyLogFilter, fn func(*proxy.RequestLog)) error {
+	activePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogSuffix)
+	archivePattern := filepath.Join(logDir, proxy.RequestLogPrefix+"*"+proxy.RequestLogArchiveSuffix)
+
+	activeFiles, err := filepath.Glob(activePattern)
+	if err != nil {
+		return fmt.Errorf("invalid log pattern: %w", err)
+	}
+	archiveFiles, err := filepath.Glob(archivePattern)
+	if err != nil {
+		return fmt.Errorf("invalid archive pattern: %w", err)
+	}
+
+	files := append(archiveFiles, activeFiles...)
+	sort.Strings(files)
+
+	for _, file := range files {
+		err := streamProxyLogFile(file, func(e *proxy.RequestLog) {
+			if filter.Match(e) {
+				fn(e)
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// streamProxyLogFile decodes path's entries one at a time, calling fn for
+// each rather than collecting them, so callers can aggregate over files far
+// larger than they'd want to hold in memory at once.
+func streamProxyLogFile(path string, fn func(*proxy.RequestLog)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if !strings.HasSuffix(path, ".gz") {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			var entry proxy.RequestLog
+			if err := json.Unmarshal(line, &entry); err != nil {
+				continue
+			}
+			fn(&entry)
+		}
+		return scanner.Err()
+	}
+
+	// Handle concatenated gzip streams, same as readCompressedProxyLogFile.
+	for {
+		gz, err := gzip.NewReader(f)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		decoder := json.NewDecoder(gz)
+		for {
+			var entry proxy.RequestLog
+			if err := decoder.Decode(&entry); err != nil {
+				if err == io.EOF || err == io.ErrUnexpectedEOF || strings.Contains(err.Error(), "unexpected EOF") {
+					break
+				}
+				continue
+			}
+			fn(&entry)
+		}
+		_ = gz.Close()
+	}
+	return nil
+}
+
+// decodeLogBody transparently gunzips or brotli-decodes body according to
+// the Content-Encoding recorded in headers, then pretty-prints it if it's
+// JSON and stdout is a terminal. It's display-only: it never modifies body,
+// so the stored RequestLog bytes stay exactly as captured off the wire (see
+// RequestLog.RequestBody/ResponseBody).
+func decodeLogBody(body []byte, headers map[string][]string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	decoded := decodeContentEncoding(body, logHeaderValue(headers, "Content-Encoding"))
+	return prettyPrintJSONBody(decoded, logHeaderValue(headers, "Content-Type"))
+}
+
+// logHeaderValue returns the first value of the header matching key,
+// case-insensitively, since RequestLog.RequestHeaders/ResponseHeaders store
+// header names as captured off the wire rather than canonicalized.
+func logHeaderValue(headers map[string][]string, key string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, key) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// decodeContentEncoding decodes body per the given Content-Encoding value.
+// Unrecognized or empty encodings, and bodies that fail to decode (e.g.
+// truncated capture), are returned unchanged.
+func decodeContentEncoding(body []byte, encoding string) []byte {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return body
+		}
+		defer func() { _ = r.Close() }()
+		out, err := io.ReadAll(r)
+		if err != nil {
+			return body
+		}
+		return out
+	case "br":
+		out, err := io.ReadAll(brotli.NewReader(bytes.NewReader(body)))
+		if err != nil {
+			return body
+		}
+		return out
+	default:
+		return body
+	}
+}
+
+// prettyPrintJSONBody re-indents body when contentType looks like JSON and
+// stdout is a terminal. Piped/redirected output is left compact, matching
+// how the rest of logs proxy treats --json output for scripting.
+func prettyPrintJSONBody(body []byte, contentType string) []byte {
+	if !strings.Contains(strings.ToLower(contentType), "json") {
+		return body
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return body
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return body
+	}
+	return buf.Bytes()
 }
 
 func truncateLogBody(body []byte, maxLen int) string {
@@ -964,15 +1758,28 @@ func newLogsInternalCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "internal [sandbox-name]",
 		Short: "View internal logs",
-		Long: `View internal logs including proxy server errors and logging failures.
+		Long: `View internal logs including proxy server errors, logging failures, and
+structured sandbox lifecycle events.
 
 Log types:
-  proxy    - Proxy server internal logs (warnings, errors from goproxy)
+  proxy    - Proxy server internal logs (warnings/errors from goproxy, plus
+             structured proxy start/port-selection events)
   logging  - Remote logging failures (OTLP, syslog errors)
+  sandbox  - Sandbox lifecycle events (start/stop)
+  network  - Network provider selection and firewall rule events
+  tool     - Per-tool setup results
+  mitm     - TLS handshake failures (e.g. a client rejecting our certificate
+             because it pins its own - see proxy.no_mitm_hosts)
+  builder  - Sandbox builder warnings
+  mounts   - Mount/binding warnings
+  resources - Resource limit warnings
+  docker   - Docker backend warnings
   all      - All internal logs (default)`,
 		Example: `  devsandbox logs internal                   # All internal logs
   devsandbox logs internal --type logging    # Logging errors only
   devsandbox logs internal --type proxy      # Proxy server logs only
+  devsandbox logs internal --type network    # Network/firewall setup events
+  devsandbox logs internal --type mitm       # TLS handshake/pinning failures
   devsandbox logs internal -f                # Follow internal logs
   devsandbox logs internal --last 100        # Last 100 lines`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -1022,11 +1829,14 @@ Log types:
 	}
 
 	cmd.Flags().StringVarP(&sandboxName, "sandbox", "s", "", "Sandbox name (default: current directory)")
-	cmd.Flags().StringVar(&logType, "type", "all", "Log type: proxy, logging, or all")
+	cmd.Flags().StringVar(&logType, "type", "all", "Log type: proxy, logging, sandbox, network, tool, mitm, builder, mounts, resources, docker, or all")
 	cmd.Flags().IntVarP(&last, "last", "n", 0, "Show only last N lines")
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow/tail log output")
 	cmd.Flags().StringVar(&since, "since", "", "Show logs since time")
 
+	cmd.ValidArgsFunction = completeSandboxNames
+	_ = cmd.RegisterFlagCompletionFunc("sandbox", completeSandboxNames)
+
 	return cmd
 }
 
@@ -1049,7 +1859,14 @@ func viewInternalLogs(logDir, logType string, last int, since time.Time) error {
 		}
 		lines = append(lines, l...)
 
-	default: // "all"
+		el, err := readEventLogLines(logDir, "proxy", since)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, el...)
+		sort.Strings(lines)
+
+	case "all":
 		// Read logging errors
 		l1, err := readLoggingErrorsLog(filepath.Join(logDir, "logging-errors.log"), since)
 		if err != nil && !os.IsNotExist(err) {
@@ -1064,8 +1881,24 @@ func viewInternalLogs(logDir, logType string, last int, since time.Time) error {
 		}
 		lines = append(lines, l2...)
 
+		// Read structured lifecycle events (sandbox, proxy, network, tool, ...)
+		l3, err := readEventLogLines(logDir, "all", since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		lines = append(lines, l3...)
+
 		// Sort by timestamp (lines start with timestamp)
 		sort.Strings(lines)
+
+	default:
+		// Any other --type is treated as a structured event type
+		// (sandbox, network, tool, builder, mounts, resources, docker, ...).
+		el, err := readEventLogLines(logDir, logType, since)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, el...)
 	}
 
 	if len(lines) == 0 {
@@ -1171,6 +2004,26 @@ func readGzipLogFile(path string, since time.Time) ([]string, error) {
 	return lines, nil
 }
 
+// readEventLogLines reads the structured lifecycle event log (see
+// proxy.EventLogger) and formats each entry as a display line matching
+// readLoggingErrorsLog's "<ts> [<type>] <LEVEL> <message>" style.
+func readEventLogLines(logDir, eventType string, since time.Time) ([]string, error) {
+	events, err := proxy.ReadEvents(logDir, eventType, since)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, len(events))
+	for i, e := range events {
+		lines[i] = formatEvent(e)
+	}
+	return lines, nil
+}
+
+func formatEvent(e proxy.Event) string {
+	return fmt.Sprintf("%s [%s] %s %s", e.Timestamp.Format(time.RFC3339), e.Type, strings.ToUpper(e.Level), e.Message)
+}
+
 func followInternalLogs(logDir, logType string, since time.Time) error {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -1180,6 +2033,13 @@ func followInternalLogs(logDir, logType string, since time.Time) error {
 	loggingErrorsPath := filepath.Join(logDir, "logging-errors.log")
 	var lastLoggingPos int64
 
+	eventsWanted := logType != "logging"
+	eventsFilter := logType
+	if logType == "" || logType == "logging" {
+		eventsFilter = "all"
+	}
+	lastEventCheck := time.Now()
+
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -1198,6 +2058,18 @@ func followInternalLogs(logDir, logType string, since time.Time) error {
 					}
 				}
 			}
+
+			// Follow structured lifecycle events (sandbox, proxy, network, ...)
+			if eventsWanted {
+				checkedAt := time.Now()
+				events, err := proxy.ReadEvents(logDir, eventsFilter, lastEventCheck)
+				if err == nil {
+					for _, e := range events {
+						fmt.Println(formatEvent(e))
+					}
+					lastEventCheck = checkedAt
+				}
+			}
 		}
 	}
 }
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+
+	"devsandbox/internal/proxy"
+)
+
+func TestTailProxyLogFile_NewEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "requests_20240101_0000.jsonl")
+
+	writeLine := func(e proxy.RequestLog) {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeLine(proxy.RequestLog{Method: "GET", URL: "https://example.com/one"})
+
+	entries, offset, err := tailProxyLogFile(path, 0)
+	if err != nil {
+		t.Fatalf("tailProxyLogFile failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].URL != "https://example.com/one" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	// No new data yet - should return nothing and the same offset.
+	entries, offset2, err := tailProxyLogFile(path, offset)
+	if err != nil {
+		t.Fatalf("tailProxyLogFile failed: %v", err)
+	}
+	if len(entries) != 0 || offset2 != offset {
+		t.Fatalf("expected no new entries, got %+v at offset %d", entries, offset2)
+	}
+
+	writeLine(proxy.RequestLog{Method: "GET", URL: "https://example.com/two"})
+
+	entries, _, err = tailProxyLogFile(path, offset2)
+	if err != nil {
+		t.Fatalf("tailProxyLogFile failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].URL != "https://example.com/two" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestTailProxyLogFile_PartialLineNotConsumed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "requests_20240101_0000.jsonl")
+
+	data, err := json.Marshal(proxy.RequestLog{Method: "GET", URL: "https://example.com/one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One complete line plus a partial line with no trailing newline.
+	content := append(data, '\n')
+	content = append(content, []byte(`{"method":"GET","url":"https://exam`)...)
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, offset, err := tailProxyLogFile(path, 0)
+	if err != nil {
+		t.Fatalf("tailProxyLogFile failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the complete line, got %+v", entries)
+	}
+	if int(offset) != len(data)+1 {
+		t.Errorf("offset = %d, want %d (partial line left unconsumed)", offset, len(data)+1)
+	}
+}
+
+func TestDedupRequestLogs_CollapsesRun(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []proxy.RequestLog{
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base},
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base.Add(1 * time.Second)},
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base.Add(2 * time.Second)},
+		{Method: "POST", URL: "/api/orders", StatusCode: 201, Timestamp: base.Add(3 * time.Second)},
+	}
+
+	got := dedupRequestLogs(entries, 0)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 runs, got %d: %+v", len(got), got)
+	}
+	if got[0].Count != 3 {
+		t.Errorf("first run count = %d, want 3", got[0].Count)
+	}
+	if got[0].Span != 2*time.Second {
+		t.Errorf("first run span = %v, want 2s", got[0].Span)
+	}
+	if got[0].Timestamp != base {
+		t.Errorf("first run timestamp = %v, want %v (first occurrence)", got[0].Timestamp, base)
+	}
+	if got[1].Count != 1 || got[1].Method != "POST" {
+		t.Errorf("second run = %+v, want a lone POST entry", got[1])
+	}
+}
+
+func TestDedupRequestLogs_NoMatchesNotCollapsed(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []proxy.RequestLog{
+		{Method: "GET", URL: "/a", StatusCode: 200, Timestamp: base},
+		{Method: "GET", URL: "/b", StatusCode: 200, Timestamp: base.Add(1 * time.Second)},
+		{Method: "GET", URL: "/a", StatusCode: 404, Timestamp: base.Add(2 * time.Second)},
+	}
+
+	got := dedupRequestLogs(entries, 0)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 runs (nothing collapsible), got %d: %+v", len(got), got)
+	}
+	for _, e := range got {
+		if e.Count != 1 {
+			t.Errorf("entry %+v should not be collapsed", e)
+		}
+	}
+}
+
+func TestDedupRequestLogs_WindowBreaksRun(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []proxy.RequestLog{
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base},
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base.Add(1 * time.Second)},
+		// Gap from the run's start exceeds the 1s window, so this starts a new run.
+		{Method: "GET", URL: "/health", StatusCode: 200, Timestamp: base.Add(5 * time.Second)},
+	}
+
+	got := dedupRequestLogs(entries, 1*time.Second)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 runs, got %d: %+v", len(got), got)
+	}
+	if got[0].Count != 2 {
+		t.Errorf("first run count = %d, want 2", got[0].Count)
+	}
+	if got[1].Count != 1 {
+		t.Errorf("second run count = %d, want 1", got[1].Count)
+	}
+}
+
+func TestProxyLogStats_Aggregates(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []proxy.RequestLog{
+		{Method: "GET", URL: "https://api.example.com/a", StatusCode: 200, Duration: 10 * time.Millisecond, RequestBody: []byte("x"), ResponseBody: []byte("abcd"), Timestamp: base},
+		{Method: "GET", URL: "https://api.example.com/b", StatusCode: 404, Duration: 30 * time.Millisecond, Timestamp: base.Add(1 * time.Second)},
+		{Method: "POST", URL: "https://other.example.com/c", StatusCode: 500, Duration: 20 * time.Millisecond, Timestamp: base.Add(2 * time.Second)},
+		{Method: "POST", URL: "https://other.example.com/d", Error: "connection reset", Timestamp: base.Add(3 * time.Second)},
+	}
+
+	stats := newProxyLogStats("host")
+	for i := range entries {
+		stats.add(&entries[i])
+	}
+
+	if stats.Total != 4 {
+		t.Fatalf("Total = %d, want 4", stats.Total)
+	}
+	if stats.Success != 1 || stats.ClientErr != 1 || stats.ServerErr != 1 || stats.Errors != 1 {
+		t.Errorf("status class counts = %+v, want 1 each", stats)
+	}
+	if stats.BytesIn != 1 || stats.BytesOut != 4 {
+		t.Errorf("BytesIn/BytesOut = %d/%d, want 1/4", stats.BytesIn, stats.BytesOut)
+	}
+	if got := stats.groups["api.example.com"]; got == nil || got.Count != 2 {
+		t.Errorf("group api.example.com = %+v, want Count 2", got)
+	}
+	if got := stats.groups["other.example.com"]; got == nil || got.Count != 2 {
+		t.Errorf("group other.example.com = %+v, want Count 2", got)
+	}
+	if len(stats.slowest) != 3 {
+		t.Fatalf("slowest has %d entries, want 3 (one entry had no duration)", len(stats.slowest))
+	}
+	if stats.slowest[0].Duration != 30*time.Millisecond {
+		t.Errorf("slowest[0].Duration = %v, want 30ms", stats.slowest[0].Duration)
+	}
+}
+
+func TestProxyLogStats_SlowestCapped(t *testing.T) {
+	stats := newProxyLogStats("")
+	for i := 0; i < proxyStatsSlowestN+3; i++ {
+		e := proxy.RequestLog{URL: "/x", Duration: time.Duration(i+1) * time.Millisecond}
+		stats.add(&e)
+	}
+
+	if len(stats.slowest) != proxyStatsSlowestN {
+		t.Fatalf("slowest has %d entries, want %d", len(stats.slowest), proxyStatsSlowestN)
+	}
+	// Durations were added in increasing order, so the slowest N are the
+	// last N added; slowest[0] should be the single largest duration.
+	want := time.Duration(proxyStatsSlowestN+3) * time.Millisecond
+	if stats.slowest[0].Duration != want {
+		t.Errorf("slowest[0].Duration = %v, want %v", stats.slowest[0].Duration, want)
+	}
+}
+
+func TestProxyStatsGroupKey(t *testing.T) {
+	e := &proxy.RequestLog{Method: "POST", URL: "https://api.example.com/v1/things", StatusCode: 404}
+
+	if got := proxyStatsGroupKey(e, "host"); got != "api.example.com" {
+		t.Errorf("host key = %q, want api.example.com", got)
+	}
+	if got := proxyStatsGroupKey(e, "method"); got != "POST" {
+		t.Errorf("method key = %q, want POST", got)
+	}
+	if got := proxyStatsGroupKey(e, "status"); got != "404" {
+		t.Errorf("status key = %q, want 404", got)
+	}
+
+	errEntry := &proxy.RequestLog{Error: "timeout"}
+	if got := proxyStatsGroupKey(errEntry, "status"); got != "ERR" {
+		t.Errorf("status key for errored entry = %q, want ERR", got)
+	}
+}
+
+func TestForEachProxyLogEntry_StreamsAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeLine := func(path string, e proxy.RequestLog) {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeLine(filepath.Join(dir, "requests_20240101_0000.jsonl"), proxy.RequestLog{Method: "GET", URL: "/a", StatusCode: 200, Timestamp: base})
+	writeLine(filepath.Join(dir, "requests_20240101_0001.jsonl"), proxy.RequestLog{Method: "GET", URL: "/b", StatusCode: 200, Timestamp: base.Add(time.Second)})
+
+	var got []string
+	err := forEachProxyLogEntry(dir, &ProxyLogFilter{}, func(e *proxy.RequestLog) {
+		got = append(got, e.URL)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "/a" || got[1] != "/b" {
+		t.Errorf("forEachProxyLogEntry visited %v, want [/a /b] in file order", got)
+	}
+}
+
+func TestDecodeContentEncoding_Gzip(t *testing.T) {
+	want := []byte(`{"hello":"world"}`)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := decodeContentEncoding(buf.Bytes(), "gzip")
+	if string(got) != string(want) {
+		t.Errorf("decodeContentEncoding(gzip) = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeContentEncoding_Brotli(t *testing.T) {
+	want := []byte(`{"hello":"world"}`)
+	var buf bytes.Buffer
+	bw := brotli.NewWriter(&buf)
+	if _, err := bw.Write(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := decodeContentEncoding(buf.Bytes(), "br")
+	if string(got) != string(want) {
+		t.Errorf("decodeContentEncoding(br) = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeContentEncoding_UnknownOrInvalidPassesThrough(t *testing.T) {
+	body := []byte("not actually gzip")
+
+	if got := decodeContentEncoding(body, ""); string(got) != string(body) {
+		t.Errorf("empty encoding should pass through unchanged, got %q", got)
+	}
+	if got := decodeContentEncoding(body, "identity"); string(got) != string(body) {
+		t.Errorf("unrecognized encoding should pass through unchanged, got %q", got)
+	}
+	if got := decodeContentEncoding(body, "gzip"); string(got) != string(body) {
+		t.Errorf("malformed gzip should pass through unchanged, got %q", got)
+	}
+}
+
+func TestLogHeaderValue_CaseInsensitive(t *testing.T) {
+	headers := map[string][]string{"Content-Encoding": {"gzip"}}
+
+	if got := logHeaderValue(headers, "content-encoding"); got != "gzip" {
+		t.Errorf("logHeaderValue case-insensitive lookup = %q, want %q", got, "gzip")
+	}
+	if got := logHeaderValue(headers, "Content-Type"); got != "" {
+		t.Errorf("logHeaderValue for missing header = %q, want empty", got)
+	}
+}
+
+func TestDecodeLogBody_StoredBytesUntouched(t *testing.T) {
+	want := []byte(`{"hello":"world"}`)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	stored := buf.Bytes()
+	original := append([]byte(nil), stored...)
+
+	_ = decodeLogBody(stored, map[string][]string{"Content-Encoding": {"gzip"}})
+
+	if !bytes.Equal(stored, original) {
+		t.Errorf("decodeLogBody mutated the stored bytes; display-only decoding must not do that")
+	}
+}
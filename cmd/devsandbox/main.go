@@ -2,18 +2,25 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
+	"devsandbox/internal/bwrap"
 	"devsandbox/internal/config"
 	"devsandbox/internal/embed"
 	"devsandbox/internal/isolator"
@@ -62,23 +69,50 @@ Proxy Mode (--proxy):
 
 	rootCmd.Flags().SetInterspersed(false)
 
-	rootCmd.Flags().Bool("info", false, "Show sandbox configuration")
-	rootCmd.Flags().Bool("proxy", false, "Enable proxy mode (route traffic through MITM proxy)")
-	rootCmd.Flags().Int("proxy-port", proxy.DefaultProxyPort, "Proxy server port")
+	rootCmd.PersistentFlags().Bool("info", false, "Show sandbox configuration")
+	rootCmd.PersistentFlags().Bool("json", false, "With --info, emit machine-readable JSON instead of the text summary")
+	rootCmd.PersistentFlags().Bool("verbose", false, "With --info, also list the final resolved tool bindings and which tool contributed each one")
+	rootCmd.PersistentFlags().Bool("explain", false, "Explain why each major isolation decision was made, for the resolved configuration")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print the bwrap command that would run (and the pasta/slirp4netns wrapper around it with --proxy) without running it")
+	rootCmd.PersistentFlags().Bool("proxy", false, "Enable proxy mode (route traffic through MITM proxy)")
+	rootCmd.PersistentFlags().Bool("no-network", false, "Disable all network access: own network namespace with only loopback, no pasta/proxy (mutually exclusive with --proxy)")
+	rootCmd.PersistentFlags().Int("proxy-port", proxy.DefaultProxyPort, "Proxy server port")
+	rootCmd.PersistentFlags().Bool("socks", false, "Also start a SOCKS5 listener alongside the HTTP proxy (requires --proxy)")
+	rootCmd.PersistentFlags().Bool("trace-http", false, "Print a live one-line summary of each proxied request to stderr (requires --proxy)")
+	rootCmd.PersistentFlags().String("on-block", "continue", "What to do when the proxy filter blocks a request mid-command: continue, or fail-fast (SIGTERM the sandboxed process and exit non-zero, requires --proxy)")
+	rootCmd.PersistentFlags().Bool("quiet", false, "Suppress non-essential stderr output (the proxy/filter startup banner, --trace-http)")
+	rootCmd.PersistentFlags().Bool("print-env-diff", false, "Print how the sandbox environment differs from the host environment to stderr")
+	rootCmd.PersistentFlags().Bool("trust-host-cas", false, "Bind the host's SSL_CERT_FILE/SSL_CERT_DIR into the sandbox (for custom/internal CA setups outside the usual distro paths)")
+	rootCmd.PersistentFlags().Bool("read-only-home", false, "Mount the sandbox home read-only, so a compromised tool can't persist anything outside the project dir and /tmp (shell history won't persist either)")
+	rootCmd.PersistentFlags().String("memory", "", "Cap the sandboxed process's memory usage, e.g. 2G, 512M (bwrap backend only; no-op if neither systemd nor cgroup v2 is usable)")
+	rootCmd.PersistentFlags().Float64("cpus", 0, "Cap the sandboxed process's CPU usage in number of cores, e.g. 1.5 (bwrap backend only; no-op if neither systemd nor cgroup v2 is usable)")
 
 	// Tool flags
-	rootCmd.Flags().String("git-mode", "", "Override git tool mode for this session (readonly, readwrite, disabled)")
+	rootCmd.PersistentFlags().String("git-mode", "", "Override git tool mode for this session (readonly, readwrite, disabled)")
 
 	// Filter flags
-	rootCmd.Flags().String("filter-default", "", "Default filter action for unmatched requests: allow, block, or ask")
-	rootCmd.Flags().StringSlice("allow-domain", nil, "Allow domain pattern (can be repeated)")
-	rootCmd.Flags().StringSlice("block-domain", nil, "Block domain pattern (can be repeated)")
+	rootCmd.PersistentFlags().String("filter-default", "", "Default filter action for unmatched requests: allow, block, or ask")
+	rootCmd.PersistentFlags().StringSlice("env", nil, "Pass through a host environment variable name or glob pattern, e.g. FOO_* (can be repeated)")
+	rootCmd.PersistentFlags().StringSlice("allow-domain", nil, "Allow domain pattern (can be repeated)")
+	rootCmd.PersistentFlags().StringSlice("block-domain", nil, "Block domain pattern (can be repeated)")
+	rootCmd.PersistentFlags().String("filter-file", "", "Load filter rules from a standalone policy file (.toml, .yaml, or .yml)")
 
 	// Isolation backend flag
-	rootCmd.Flags().String("isolation", "", "Isolation backend: auto, bwrap, docker")
+	rootCmd.PersistentFlags().String("isolation", "", "Isolation backend: auto, bwrap, docker")
+
+	// Seccomp flag
+	rootCmd.PersistentFlags().String("seccomp", "", "Syscall filter profile: default, strict, none")
 
 	// Sandbox lifecycle flag
-	rootCmd.Flags().Bool("rm", false, "Remove sandbox state after exit (ephemeral mode)")
+	rootCmd.PersistentFlags().Bool("rm", false, "Remove sandbox state after exit (ephemeral mode)")
+
+	// Dotfiles bootstrap flag
+	rootCmd.PersistentFlags().String("dotfiles", "", "Git URL or path to a dotfiles repo to bootstrap the sandbox home with (applied once)")
+
+	// Shell init flag
+	rootCmd.PersistentFlags().String("shell-init", "", "Command to run at shell startup, after mise activation (e.g. 'source ./.venv/bin/activate')")
+	rootCmd.PersistentFlags().String("shell", "", "Force the sandbox shell instead of using $SHELL: bash, zsh, fish, or nu")
+	rootCmd.PersistentFlags().Bool("fast", false, "Skip shell/mise/tool init for a one-shot command, exec'ing it directly (no effect on interactive shells; tool-managed versions may not be on PATH)")
 
 	// Add subcommands
 	rootCmd.AddCommand(newSandboxesCmd())
@@ -89,6 +123,15 @@ Proxy Mode (--proxy):
 	rootCmd.AddCommand(newProxyCmd())
 	rootCmd.AddCommand(newTrustCmd())
 	rootCmd.AddCommand(newImageCmd())
+	rootCmd.AddCommand(newCleanCmd())
+	rootCmd.AddCommand(newRenameCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newTopLevelListCmd())
+	rootCmd.AddCommand(newExecCmd())
+	rootCmd.AddCommand(newSelfTestCmd())
+	rootCmd.AddCommand(newCpCmd())
+
+	_ = rootCmd.RegisterFlagCompletionFunc("git-mode", completeFromList(gitModes))
 
 	versionTpl := fmt.Sprintf("devsandbox %s (built: %s)\n", version.FullVersion(), version.Date)
 	if runtime.GOOS == "linux" {
@@ -97,6 +140,14 @@ Proxy Mode (--proxy):
 	rootCmd.SetVersionTemplate(versionTpl)
 
 	if err := rootCmd.Execute(); err != nil {
+		// When the sandboxed command itself failed (as opposed to devsandbox
+		// failing to run it), propagate its real exit code instead of
+		// flattening everything to 1 - scripts and CI depend on the actual
+		// status, and the command already wrote its own error output.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -113,11 +164,26 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	}()
 
 	showInfo, _ := cmd.Flags().GetBool("info")
+	infoJSON, _ := cmd.Flags().GetBool("json")
+	infoVerbose, _ := cmd.Flags().GetBool("verbose")
+	showExplain, _ := cmd.Flags().GetBool("explain")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	proxyEnabled, _ := cmd.Flags().GetBool("proxy")
 	proxyPort, _ := cmd.Flags().GetInt("proxy-port")
+	noNetwork, _ := cmd.Flags().GetBool("no-network")
+	socksEnabled, _ := cmd.Flags().GetBool("socks")
+	traceHTTP, _ := cmd.Flags().GetBool("trace-http")
+	onBlock, _ := cmd.Flags().GetString("on-block")
+	if onBlock != "continue" && onBlock != "fail-fast" {
+		return fmt.Errorf("invalid --on-block value %q: must be continue or fail-fast", onBlock)
+	}
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	printEnvDiff, _ := cmd.Flags().GetBool("print-env-diff")
+	envPassthrough, _ := cmd.Flags().GetStringSlice("env")
 	filterDefault, _ := cmd.Flags().GetString("filter-default")
 	allowDomains, _ := cmd.Flags().GetStringSlice("allow-domain")
 	blockDomains, _ := cmd.Flags().GetStringSlice("block-domain")
+	filterFile, _ := cmd.Flags().GetString("filter-file")
 
 	// Load configuration file with project-specific overrides
 	appCfg, _, _, err := config.LoadConfig()
@@ -171,12 +237,25 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	if appCfg.Proxy.Port != 0 {
 		cfg.ProxyPort = appCfg.Proxy.Port
 	}
+	if appCfg.Proxy.IsSocksEnabled() {
+		cfg.SocksEnabled = true
+	}
+	if appCfg.Proxy.SocksPort != 0 {
+		cfg.SocksPort = appCfg.Proxy.SocksPort
+	}
 	if cmd.Flags().Changed("proxy") {
 		cfg.ProxyEnabled = proxyEnabled
 	}
 	if cmd.Flags().Changed("proxy-port") {
 		cfg.ProxyPort = proxyPort
 	}
+	if cmd.Flags().Changed("socks") {
+		cfg.SocksEnabled = socksEnabled
+	}
+	cfg.NetworkDisabled = noNetwork
+	if cfg.NetworkDisabled && cfg.ProxyEnabled {
+		return fmt.Errorf("--no-network and --proxy are mutually exclusive")
+	}
 
 	// CLI override for git mode
 	if cmd.Flags().Changed("git-mode") {
@@ -196,20 +275,107 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	}
 
 	cfg.OverlayEnabled = appCfg.Overlay.IsEnabled()
+	cfg.TrustHostCAs = appCfg.Sandbox.IsTrustHostCAsEnabled()
+	if cmd.Flags().Changed("trust-host-cas") {
+		cfg.TrustHostCAs, _ = cmd.Flags().GetBool("trust-host-cas")
+	}
+	cfg.ReadOnlyHome = appCfg.Sandbox.IsReadOnlyHomeEnabled()
+	if cmd.Flags().Changed("read-only-home") {
+		cfg.ReadOnlyHome, _ = cmd.Flags().GetBool("read-only-home")
+	}
+	cfg.ResourceLimits = bwrap.ResourceLimits{
+		Memory: appCfg.Sandbox.Memory,
+		CPUs:   appCfg.Sandbox.CPUs,
+	}
+	if cmd.Flags().Changed("memory") {
+		cfg.ResourceLimits.Memory, _ = cmd.Flags().GetString("memory")
+	}
+	if cmd.Flags().Changed("cpus") {
+		cfg.ResourceLimits.CPUs, _ = cmd.Flags().GetFloat64("cpus")
+	}
 	cfg.ToolsConfig = appCfg.Tools
+	cfg.Locale = appCfg.Env.Locale
+	cfg.EnvPassthrough = append(envPassthrough, appCfg.Env.Passthrough...)
+	cfg.SourceDateEpoch = appCfg.Sandbox.SourceDateEpoch
+	cfg.BlockedPaths = appCfg.Security.EffectiveBlockedPaths()
+	cfg.AllowedCommands = appCfg.Sandbox.AllowedCommands
+	cfg.TmpMode = sandbox.TmpMode(appCfg.Sandbox.GetTmpMode())
 	cfg.ConfigVisibility = string(appCfg.Sandbox.GetConfigVisibility())
 	cfg.MountsConfig = mounts.NewEngine(appCfg.Sandbox.Mounts, cfg.HomeDir)
 	cfg.Isolation = iso.IsolationType()
 
+	cfg.SeccompProfile = sandbox.SeccompProfile(appCfg.Sandbox.GetSeccompProfile())
+	if cmd.Flags().Changed("seccomp") {
+		seccompFlag, _ := cmd.Flags().GetString("seccomp")
+		switch seccompFlag {
+		case "default", "strict", "none":
+			cfg.SeccompProfile = sandbox.SeccompProfile(seccompFlag)
+		default:
+			return fmt.Errorf("invalid --seccomp value %q: must be default, strict, or none", seccompFlag)
+		}
+	}
+
+	if cmd.Flags().Changed("shell-init") {
+		cfg.ShellInit, _ = cmd.Flags().GetString("shell-init")
+	}
+
+	if cmd.Flags().Changed("shell") {
+		shellFlag, _ := cmd.Flags().GetString("shell")
+		shell, shellPath, err := sandbox.ResolveShellOverride(shellFlag)
+		if err != nil {
+			return err
+		}
+		cfg.Shell = shell
+		cfg.ShellPath = shellPath
+	}
+
+	cfg.Fast, _ = cmd.Flags().GetBool("fast")
+
 	if showInfo {
-		printInfo(cfg)
+		if infoVerbose {
+			resolveToolBindingsForInfo(cmd.Context(), iso, cfg, appCfg)
+		}
+		if infoJSON {
+			return printInfoJSON(cfg, appCfg)
+		}
+		printInfo(cfg, appCfg)
+		return nil
+	}
+
+	if showExplain {
+		printExplain(cfg, appCfg)
 		return nil
 	}
 
+	if dryRun {
+		return runDryRun(cmd.Context(), iso, cfg, appCfg, args, proxyPort)
+	}
+
 	if err := cfg.EnsureSandboxDirs(); err != nil {
 		return err
 	}
 
+	if appCfg.Sandbox.WarnHomeSize != "" {
+		if threshold, err := sandbox.ParseSize(appCfg.Sandbox.WarnHomeSize); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: invalid warn_home_size %q: %v\n", appCfg.Sandbox.WarnHomeSize, err)
+		} else if size, warn, err := sandbox.CheckHomeSize(cfg, threshold); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to check sandbox home size: %v\n", err)
+		} else if warn {
+			fmt.Fprintf(os.Stderr, "warning: sandbox home is %s (exceeds warn_home_size %s) — review caches or run 'devsandbox sandboxes prune'\n",
+				sandbox.FormatSize(size), appCfg.Sandbox.WarnHomeSize)
+		}
+	}
+
+	dotfilesRepo := appCfg.Sandbox.DotfilesRepo
+	if cmd.Flags().Changed("dotfiles") {
+		dotfilesRepo, _ = cmd.Flags().GetString("dotfiles")
+	}
+	if dotfilesRepo != "" {
+		if err := sandbox.ApplyDotfiles(cfg, dotfilesRepo); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to apply dotfiles: %v\n", err)
+		}
+	}
+
 	// When --rm is set, remove sandbox state after exit (both backends).
 	if rmFlag {
 		defer func() {
@@ -229,6 +395,13 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	}
 	defer func() { _ = iso.Cleanup() }()
 
+	// runCtx governs the sandboxed process itself. It's cancelled by blockMon
+	// below when --on-block=fail-fast sees a blocked request, which delivers
+	// SIGTERM instead of letting the command run to completion unaware.
+	runCtx, cancelRun := context.WithCancel(cmd.Context())
+	defer cancelRun()
+	var blockMon *blockMonitor
+
 	// Set up logging infrastructure (shared between proxy and sandbox)
 	logDir := filepath.Join(cfg.SandboxHome, proxy.LogBaseDirName, proxy.InternalLogDirName)
 	sandboxLogger, err := logging.NewErrorLogger(filepath.Join(logDir, "sandbox.log"))
@@ -236,6 +409,19 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 		sandboxLogger = nil
 	}
 
+	// eventLogger records structured, `--type`-filterable lifecycle events
+	// (see `devsandbox logs internal`) independent of sandboxLogger's
+	// plain-text component warnings/errors.
+	eventLogger, err := proxy.NewEventLogger(logDir)
+	if err != nil {
+		eventLogger = nil
+	}
+	if eventLogger != nil {
+		defer func() { _ = eventLogger.Close() }()
+	}
+	eventLogger.Infof("sandbox", "starting sandbox for %s", cfg.ProjectDir)
+	defer eventLogger.Infof("sandbox", "sandbox stopped")
+
 	var logDispatcher *logging.Dispatcher
 	if len(appCfg.Logging.Receivers) > 0 {
 		logDispatcher, err = logging.NewDispatcherFromConfig(
@@ -250,12 +436,54 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	// Start proxy if enabled
 	var proxyServer *proxy.Server
 	if cfg.ProxyEnabled {
-		pCfg := proxy.NewConfig(cfg.SandboxRoot, proxyPort)
+		// Consult the per-project port registry so concurrent sessions
+		// against this same sandbox pick distinct starting ports instead of
+		// racing each other through the listener's own retry loop.
+		startPort, err := sandbox.NextAvailablePort(cfg.SandboxRoot, proxyPort)
+		if err != nil {
+			return fmt.Errorf("failed to pick proxy port: %w", err)
+		}
+
+		pCfg := proxy.NewConfig(cfg.SandboxRoot, startPort)
+		if appCfg.Proxy.CA.Shared {
+			pCfg.UseSharedCA(cfg.SandboxBase)
+		}
 		pCfg.Dispatcher = logDispatcher
 		pCfg.LogReceivers = appCfg.Logging.Receivers
 		pCfg.LogAttributes = appCfg.Logging.Attributes
 		pCfg.CredentialInjectors = proxy.BuildCredentialInjectors(appCfg.Proxy.Credentials)
-		pCfg.Filter = buildFilterConfig(appCfg, cmd, filterDefault, allowDomains, blockDomains)
+		pCfg.Redact = &proxy.RedactConfig{
+			Headers:      appCfg.Proxy.Redact.Headers,
+			BodyPatterns: appCfg.Proxy.Redact.BodyPatterns,
+		}
+		if appCfg.Proxy.GRPC.Descriptors != "" {
+			pCfg.GRPC = &proxy.GRPCConfig{DescriptorSetPath: appCfg.Proxy.GRPC.Descriptors}
+		}
+		if appCfg.Proxy.Logs.MaxAge != "" || appCfg.Proxy.Logs.MaxTotalSize != "" || appCfg.Proxy.Logs.MaxFiles != 0 {
+			pCfg.LogRetention = &proxy.LogRetentionConfig{
+				MaxAge:       appCfg.Proxy.Logs.MaxAge,
+				MaxTotalSize: appCfg.Proxy.Logs.MaxTotalSize,
+				MaxFiles:     appCfg.Proxy.Logs.MaxFiles,
+			}
+		}
+		for _, rule := range appCfg.Proxy.ResponseRewrite {
+			pCfg.ResponseRewrite = append(pCfg.ResponseRewrite, proxy.ResponseRewriteRule{
+				Host:        rule.Host,
+				Pattern:     rule.Pattern,
+				Replacement: rule.Replacement,
+			})
+		}
+		pCfg.Filter, err = buildFilterConfig(appCfg, cmd, filterDefault, allowDomains, blockDomains, filterFile)
+		if err != nil {
+			return err
+		}
+		pCfg.UpstreamProxy = appCfg.Proxy.Upstream
+		pCfg.SocksEnabled = cfg.SocksEnabled
+		pCfg.SocksPort = cfg.SocksPort
+		pCfg.MetricsAddr = appCfg.Proxy.MetricsAddr
+		pCfg.NoMITMHosts = appCfg.Proxy.NoMITMHosts
+		pCfg.EventLogger = eventLogger
+		pCfg.StreamSocket = appCfg.Proxy.StreamSocket
 
 		if netInfo != nil {
 			pCfg.BindAddress = netInfo.BindAddress
@@ -270,13 +498,54 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 		cfg.ProxyPort = proxyRes.port
 		proxyServer = proxyRes.server
 
-		fmt.Fprintf(os.Stderr, "Proxy server started on %s:%d\n", pCfg.GetBindAddress(), proxyRes.port)
+		eventLogger.Infof("proxy", "proxy server started on %s:%d", pCfg.GetBindAddress(), proxyRes.port)
 		if proxyRes.port != proxyPort {
-			fmt.Fprintf(os.Stderr, "Note: Using port %d (requested port %d was busy)\n", proxyRes.port, proxyPort)
+			eventLogger.Infof("proxy", "using port %d (requested port %d was busy)", proxyRes.port, proxyPort)
+		}
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Proxy server started on %s:%d\n", pCfg.GetBindAddress(), proxyRes.port)
+			if proxyRes.port != proxyPort {
+				fmt.Fprintf(os.Stderr, "Note: Using port %d (requested port %d was busy)\n", proxyRes.port, proxyPort)
+			}
+			fmt.Fprintf(os.Stderr, "CA certificate: %s\n", proxyRes.caPath)
 		}
-		fmt.Fprintf(os.Stderr, "CA certificate: %s\n", proxyRes.caPath)
 
-		if pCfg.Filter != nil && pCfg.Filter.IsEnabled() {
+		if pCfg.SocksEnabled {
+			cfg.SocksPort = proxyRes.server.SocksPort()
+			eventLogger.Infof("proxy", "socks5 listener started on %s:%d", pCfg.GetBindAddress(), cfg.SocksPort)
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "SOCKS5 listener started on %s:%d\n", pCfg.GetBindAddress(), cfg.SocksPort)
+			}
+		}
+
+		if pCfg.StreamSocket && !quiet {
+			fmt.Fprintf(os.Stderr, "Live proxy event socket: %s\n", proxy.EventSocketPath(pCfg.SandboxBase))
+		}
+
+		if pCfg.MetricsAddr != "" && !quiet {
+			fmt.Fprintf(os.Stderr, "Metrics listening on %s (/metrics, /healthz)\n", pCfg.MetricsAddr)
+		}
+
+		if err := sandbox.RegisterPort(cfg.SandboxRoot, cfg.ProxyPort, cfg.SocksPort); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to register proxy port: %v\n", err)
+		}
+
+		if onBlock == "fail-fast" {
+			blockMon = newBlockMonitor(cancelRun)
+		}
+		if traceHTTP && !quiet || blockMon != nil {
+			proxyRes.server.SetTraceHook(func(e *proxy.RequestLog) {
+				if traceHTTP && !quiet {
+					printTraceLine(e)
+				}
+				if blockMon != nil {
+					blockMon.record(e)
+				}
+			})
+		}
+
+		if !quiet && pCfg.Filter != nil && pCfg.Filter.IsEnabled() {
 			if pCfg.Filter.DefaultAction == proxy.FilterActionAsk {
 				fmt.Fprintf(os.Stderr, "Filter: ask mode (default action for unmatched requests)\n")
 				fmt.Fprintf(os.Stderr, "\nRun in another terminal to approve/deny requests:\n")
@@ -301,7 +570,14 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 	if err != nil {
 		return fmt.Errorf("failed to acquire session lock: %w", err)
 	}
-	defer func() { _ = lockFile.Close() }()
+	defer func() {
+		_ = lockFile.Close()
+		if cfg.ProxyEnabled {
+			if err := sandbox.ReleasePort(cfg.SandboxRoot); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to release proxy port registration: %v\n", err)
+			}
+		}
+	}()
 
 	// Build RunConfig and delegate to the isolator
 	var proxyCAPath string
@@ -316,29 +592,301 @@ func runSandbox(cmd *cobra.Command, args []string) (retErr error) {
 		Interactive:    term.IsTerminal(int(os.Stdin.Fd())),
 		RemoveOnExit:   rmFlag,
 		HasActiveTools: hasActiveTools,
+		PrintEnvDiff:   printEnvDiff,
 		ProxyServer:    proxyServer,
 		ProxyCAPath:    proxyCAPath,
 		ProxyPort:      cfg.ProxyPort,
+		SocksPort:      cfg.SocksPort,
 		SandboxLogger:  sandboxLogger,
 		LogDispatcher:  logDispatcher,
+		EventLogger:    eventLogger,
+	}
+
+	// The fast bwrap path (no proxy, no active tools, no --rm) execs the
+	// sandboxed command via syscall.Exec, replacing this process - control
+	// never returns, so the history entry has to be written before the
+	// call with the exit code left unknown. Every other path returns
+	// normally, so it's recorded after with the real exit code.
+	usesReplaceExec := cfg.Isolation == sandbox.IsolationBwrap && !cfg.ProxyEnabled && !hasActiveTools && !rmFlag
+	histEntry := sandbox.HistoryEntry{
+		Timestamp: time.Now(),
+		Argv:      append([]string{"devsandbox"}, os.Args[1:]...),
+		Proxy:     cfg.ProxyEnabled,
+	}
+	if usesReplaceExec {
+		if err := sandbox.AppendHistory(cfg.SandboxRoot, histEntry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record sandbox history: %v\n", err)
+		}
+	}
+
+	runErr := iso.Run(runCtx, runCfg)
+
+	if !usesReplaceExec {
+		histEntry.ExitCode = exitCodePtr(runErr)
+		if err := sandbox.AppendHistory(cfg.SandboxRoot, histEntry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record sandbox history: %v\n", err)
+		}
+	}
+	if blockMon != nil {
+		if blocked := blockMon.blocked(); len(blocked) > 0 {
+			fmt.Fprintln(os.Stderr, "\nSandbox stopped (--on-block=fail-fast): request(s) blocked by the proxy filter:")
+			for _, reason := range blocked {
+				fmt.Fprintf(os.Stderr, "  - %s\n", reason)
+			}
+			return fmt.Errorf("command terminated: %d request(s) blocked by proxy filter", len(blocked))
+		}
+	}
+	return runErr
+}
+
+// exitCodePtr extracts the sandboxed command's exit status from the error
+// iso.Run returned, for recording in the sandbox history. A nil err means
+// success (0); an error that isn't an *exec.ExitError (e.g. devsandbox
+// itself failed to launch the command) has no real exit code to report.
+func exitCodePtr(err error) *int {
+	if err == nil {
+		code := 0
+		return &code
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		return &code
+	}
+	return nil
+}
+
+// printConfigSources shows which config file(s) contributed the effective
+// configuration, and which of a few commonly-overridden settings came from
+// the local .devsandbox.toml (found by walking up from projectDir) versus
+// the global config.
+func printConfigSources(projectDir string, appCfg *config.Config) {
+	fmt.Println("Config Sources:")
+	fmt.Printf("  Global: %s\n", config.ConfigPath())
+
+	localDir, found := config.FindLocalConfigDir(projectDir)
+	if !found {
+		fmt.Println("  Local:  (none found)")
+		fmt.Println()
+		return
+	}
+	localPath := filepath.Join(localDir, config.LocalConfigFile)
+	fmt.Printf("  Local:  %s (overrides win)\n", localPath)
+
+	globalOnly, err := config.LoadWithProjectDir(config.ConfigPath(), projectDir, &config.LoadOptions{SkipLocalConfig: true})
+	if err != nil {
+		fmt.Println()
+		return
+	}
+
+	type overridden struct {
+		name          string
+		global, local string
+	}
+	var diffs []overridden
+	if globalOnly.Proxy.IsEnabled() != appCfg.Proxy.IsEnabled() {
+		diffs = append(diffs, overridden{"proxy.enabled", fmt.Sprint(globalOnly.Proxy.IsEnabled()), fmt.Sprint(appCfg.Proxy.IsEnabled())})
+	}
+	if globalOnly.Proxy.Filter.DefaultAction != appCfg.Proxy.Filter.DefaultAction {
+		diffs = append(diffs, overridden{"proxy.filter.default_action", globalOnly.Proxy.Filter.DefaultAction, appCfg.Proxy.Filter.DefaultAction})
+	}
+	if len(globalOnly.Proxy.Filter.Rules) != len(appCfg.Proxy.Filter.Rules) {
+		diffs = append(diffs, overridden{"proxy.filter.rules", fmt.Sprintf("%d rule(s)", len(globalOnly.Proxy.Filter.Rules)), fmt.Sprintf("%d rule(s)", len(appCfg.Proxy.Filter.Rules))})
+	}
+	if globalOnly.Sandbox.GetIsolation() != appCfg.Sandbox.GetIsolation() {
+		diffs = append(diffs, overridden{"sandbox.isolation", string(globalOnly.Sandbox.GetIsolation()), string(appCfg.Sandbox.GetIsolation())})
+	}
+	if fmt.Sprint(globalOnly.Tools["git"]) != fmt.Sprint(appCfg.Tools["git"]) {
+		diffs = append(diffs, overridden{"tools.git", fmt.Sprint(globalOnly.Tools["git"]), fmt.Sprint(appCfg.Tools["git"])})
+	}
+
+	if len(diffs) > 0 {
+		fmt.Println("  Overridden by local config:")
+		for _, d := range diffs {
+			fmt.Printf("    %-28s global=%s  local=%s\n", d.name, d.global, d.local)
+		}
+	}
+	fmt.Println()
+}
+
+// formatResourceLimits renders the configured memory/CPU caps for --info and
+// --explain, e.g. "memory=2G, cpus=1.5" or just whichever of the two is set.
+func formatResourceLimits(limits bwrap.ResourceLimits) string {
+	var parts []string
+	if limits.Memory != "" {
+		parts = append(parts, fmt.Sprintf("memory=%s", limits.Memory))
+	}
+	if limits.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("cpus=%g", limits.CPUs))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// InfoJSON is the stable, explicit shape emitted by `--info --json`. It's
+// hand-maintained rather than a marshalled sandbox.Config so that unexported
+// field churn in Config never silently changes the output scripts pin
+// against.
+type InfoJSON struct {
+	Project          string            `json:"project"`
+	ProjectDir       string            `json:"project_dir"`
+	SandboxHome      string            `json:"sandbox_home"`
+	GitMode          string            `json:"git_mode"`
+	BlockedPaths     []string          `json:"blocked_paths"`
+	EnvPassthrough   []string          `json:"env_passthrough,omitempty"`
+	Network          string            `json:"network"`
+	Proxy            InfoJSONProxy     `json:"proxy"`
+	EnabledTools     []string          `json:"enabled_tools"`
+	SessionActive    bool              `json:"session_active"`
+	ResolvedBindings []InfoJSONBinding `json:"resolved_bindings,omitempty"`
+}
+
+// InfoJSONBinding is one entry of the "resolved_bindings" array, only
+// populated with --verbose (see resolveToolBindingsForInfo).
+type InfoJSONBinding struct {
+	Tool     string `json:"tool"`
+	Source   string `json:"source"`
+	Dest     string `json:"dest"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+// bindingDestForInfo returns a binding's effective destination for display,
+// applying the same "defaults to Source" rule the builder uses when
+// actually mounting it.
+func bindingDestForInfo(b tools.Binding) string {
+	if b.Dest != "" {
+		return b.Dest
+	}
+	return b.Source
+}
+
+// resolveToolBindingsForInfo runs the same builder pipeline --dry-run uses,
+// far enough to populate cfg.ResolvedToolBindings with the final
+// conflict-resolved binding set and its tool attribution, without
+// creating any sandbox directories or running the sandboxed command. The
+// bwrap command line it produces is discarded - callers only want the
+// side effect on cfg. Best-effort: a failure here just means --verbose
+// can't show bindings, so it's reported but doesn't block the rest of
+// --info.
+func resolveToolBindingsForInfo(ctx context.Context, iso isolator.Isolator, cfg *sandbox.Config, appCfg *config.Config) {
+	dr, ok := iso.(isolator.DryRunner)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "warning: --verbose binding resolution is not supported with the %s backend\n", iso.Name())
+		return
+	}
+
+	runCfg := &isolator.RunConfig{SandboxCfg: cfg, AppCfg: appCfg}
+	if _, err := dr.DryRun(ctx, runCfg); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to resolve tool bindings for --verbose: %v\n", err)
+	}
+}
+
+// networkSummary is the one-line "Network:" status shown by --info and
+// --info --json, in order of precedence: --no-network fully isolates the
+// sandbox; --proxy routes and filters traffic; otherwise the host network
+// is shared unfiltered.
+func networkSummary(cfg *sandbox.Config) string {
+	switch {
+	case cfg.NetworkDisabled:
+		return "ISOLATED (no egress)"
+	case cfg.ProxyEnabled:
+		return "PROXY (filtered)"
+	default:
+		return "shared with host (default)"
+	}
+}
+
+// InfoJSONProxy is the "proxy" section of InfoJSON.
+type InfoJSONProxy struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port,omitempty"`
+	CAPath  string `json:"ca_path,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// gitModeForInfo resolves the effective git mode the same way the git tool
+// itself does (see tools.Git.Configure), without requiring a built Builder:
+// the configured tools.git.mode if set and valid, else the default.
+func gitModeForInfo(appCfg *config.Config) string {
+	if gitCfg, ok := appCfg.Tools["git"].(map[string]any); ok {
+		if mode, ok := gitCfg["mode"].(string); ok && tools.ValidGitMode(mode) {
+			return mode
+		}
 	}
+	return string(tools.GitModeReadOnly)
+}
 
-	return iso.Run(cmd.Context(), runCfg)
+// printInfoJSON emits the InfoJSON for --info --json.
+func printInfoJSON(cfg *sandbox.Config, appCfg *config.Config) error {
+	active := tools.Enabled(tools.Available(cfg.HomeDir), appCfg.Tools)
+	enabledTools := make([]string, 0, len(active))
+	for _, t := range active {
+		enabledTools = append(enabledTools, t.Name())
+	}
+
+	var resolvedBindings []InfoJSONBinding
+	for _, rb := range cfg.ResolvedToolBindings {
+		resolvedBindings = append(resolvedBindings, InfoJSONBinding{
+			Tool:     rb.ToolName,
+			Source:   rb.Binding.Source,
+			Dest:     bindingDestForInfo(rb.Binding),
+			ReadOnly: rb.Binding.ReadOnly,
+		})
+	}
+
+	info := InfoJSON{
+		Project:        cfg.ProjectName,
+		ProjectDir:     cfg.ProjectDir,
+		SandboxHome:    cfg.SandboxHome,
+		GitMode:        gitModeForInfo(appCfg),
+		BlockedPaths:   cfg.BlockedPaths,
+		EnvPassthrough: sandbox.MatchedEnvPassthroughNames(cfg.EnvPassthrough),
+		Network:        networkSummary(cfg),
+		Proxy: InfoJSONProxy{
+			Enabled: cfg.ProxyEnabled,
+			Port:    cfg.ProxyPort,
+			CAPath:  cfg.ProxyCAPath,
+			Gateway: cfg.GatewayIP,
+		},
+		EnabledTools:     enabledTools,
+		SessionActive:    sandbox.IsSessionActive(cfg.SandboxRoot),
+		ResolvedBindings: resolvedBindings,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(info)
 }
 
-func printInfo(cfg *sandbox.Config) {
+func printInfo(cfg *sandbox.Config, appCfg *config.Config) {
 	// Extract mise config from ToolsConfig
 	miseWritable, misePersistent := getMiseConfig(cfg)
 
+	printConfigSources(cfg.ProjectDir, appCfg)
+
 	fmt.Println("Sandbox Configuration:")
 	fmt.Printf("  Project:      %s\n", cfg.ProjectName)
 	fmt.Printf("  Project Dir:  %s\n", cfg.ProjectDir)
-	fmt.Printf("  Sandbox Home: %s\n", cfg.SandboxHome)
+	if cfg.ReadOnlyHome {
+		fmt.Printf("  Sandbox Home: %s (read-only, --read-only-home; shell history won't persist)\n", cfg.SandboxHome)
+	} else {
+		fmt.Printf("  Sandbox Home: %s\n", cfg.SandboxHome)
+	}
 	fmt.Printf("  Shell:        %s (%s)\n", cfg.Shell, cfg.ShellPath)
+	if cfg.ShellInit != "" {
+		fmt.Printf("  Shell Init:   %s\n", cfg.ShellInit)
+	}
+	fmt.Printf("  Seccomp:      %s\n", cfg.SeccompProfile)
+	fmt.Printf("  Network:      %s\n", networkSummary(cfg))
+	if !cfg.ResourceLimits.IsZero() {
+		fmt.Printf("  Limits:       %s\n", formatResourceLimits(cfg.ResourceLimits))
+	}
 	fmt.Println()
 	fmt.Println("Mounted Paths:")
 	fmt.Println("  /usr, /lib, /lib64, /bin (read-only system)")
 	fmt.Printf("  %s (read-write)\n", cfg.ProjectDir)
+	if cfg.ReadOnlyHome {
+		fmt.Println("  ~/.config, ~/.cache, ~/.local/share, ~/.local/state, ~/.local/bin (tmpfs, writable but discarded on exit)")
+	}
 	if cfg.OverlayEnabled && miseWritable {
 		mode := "tmpoverlay"
 		if misePersistent {
@@ -350,10 +898,53 @@ func printInfo(cfg *sandbox.Config) {
 	}
 	fmt.Printf("  Shell config for %s (read-only)\n", cfg.Shell)
 	fmt.Println("  ~/.config/nvim, ~/.local/share/nvim (read-only editor)")
+	if cfg.TrustHostCAs {
+		fmt.Println("  $SSL_CERT_FILE, $SSL_CERT_DIR (read-only, --trust-host-cas)")
+	}
+	fmt.Println()
+
+	active := tools.Enabled(tools.Available(cfg.HomeDir), appCfg.Tools)
+	activeNames := make([]string, 0, len(active))
+	for _, t := range active {
+		activeNames = append(activeNames, t.Name())
+	}
+	fmt.Println("Tools:")
+	fmt.Printf("  %s\n", strings.Join(activeNames, ", "))
+	if disabled := tools.DisabledByConfig(tools.Available(cfg.HomeDir), appCfg.Tools); len(disabled) > 0 {
+		fmt.Printf("  Disabled by config: %s\n", strings.Join(disabled, ", "))
+	}
 	fmt.Println()
+
+	if loaded, skipped, err := sandbox.LoadSandboxEnv(cfg.SandboxRoot); err == nil && (len(loaded) > 0 || len(skipped) > 0) {
+		names := make([]string, 0, len(loaded))
+		for name := range loaded {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Println("Sandbox Env (SandboxRoot/env):")
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+		if len(skipped) > 0 {
+			sort.Strings(skipped)
+			fmt.Printf("  (skipped, secret-looking: %s)\n", strings.Join(skipped, ", "))
+		}
+		fmt.Println()
+	}
+	if len(cfg.EnvPassthrough) > 0 {
+		fmt.Println("Env Passthrough (--env / [env].passthrough):")
+		for _, name := range sandbox.MatchedEnvPassthroughNames(cfg.EnvPassthrough) {
+			fmt.Printf("  %s\n", name)
+		}
+		fmt.Println()
+	}
 	fmt.Println("Blocked Paths:")
-	fmt.Println("  ~/.ssh, ~/.aws, ~/.azure, ~/.gcloud (not mounted)")
-	fmt.Println("  .env, .env.* files (hidden, project secrets)")
+	for _, path := range cfg.BlockedPaths {
+		fmt.Printf("  %s\n", path)
+	}
+	if !sandbox.AllowEnvrcConfigured(cfg.ToolsConfig) {
+		fmt.Println("  .envrc (hidden; set tools.direnv.allow_envrc to allow)")
+	}
 
 	if cfg.MountsConfig != nil && len(cfg.MountsConfig.Rules()) > 0 {
 		fmt.Println()
@@ -372,6 +963,22 @@ func printInfo(cfg *sandbox.Config) {
 		fmt.Printf("  Gateway:  %s\n", cfg.GatewayIP)
 	}
 
+	if len(appCfg.Network.ExtraRules) > 0 {
+		fmt.Println()
+		fmt.Println("Network Extra Rules (WARNING: can weaken isolation):")
+		for _, rule := range appCfg.Network.ExtraRules {
+			fmt.Printf("  %s\n", rule)
+		}
+	}
+
+	if len(appCfg.Network.AllowDirect) > 0 {
+		fmt.Println()
+		fmt.Println("Network Allow Direct (bypasses proxy, not captured in proxy logs):")
+		for _, entry := range appCfg.Network.AllowDirect {
+			fmt.Printf("  %s\n", entry)
+		}
+	}
+
 	if cfg.OverlayEnabled && miseWritable {
 		fmt.Println()
 		fmt.Println("Overlay Mode:")
@@ -381,6 +988,171 @@ func printInfo(cfg *sandbox.Config) {
 			fmt.Printf("  Overlay Dir:     %s/overlay/\n", cfg.SandboxHome)
 		}
 	}
+
+	if len(cfg.ResolvedToolBindings) > 0 {
+		fmt.Println()
+		fmt.Println("Resolved Tool Bindings (--verbose):")
+		for _, rb := range cfg.ResolvedToolBindings {
+			mode := "rw"
+			if rb.Binding.ReadOnly {
+				mode = "ro"
+			}
+			fmt.Printf("  %-30s %s (%s, from %s)\n", bindingDestForInfo(rb.Binding), rb.Binding.Source, mode, rb.ToolName)
+		}
+	}
+}
+
+// printExplain prints a human-readable rationale for each major isolation
+// decision in the resolved configuration, for onboarding - --info shows
+// what is configured, --explain shows why it's safe (or how to change it).
+func printExplain(cfg *sandbox.Config, appCfg *config.Config) {
+	fmt.Println("Why devsandbox is isolating things this way:")
+	fmt.Println()
+
+	blocksSSH := false
+	for _, path := range cfg.BlockedPaths {
+		if path == "~/.ssh" {
+			blocksSSH = true
+			break
+		}
+	}
+	if blocksSSH {
+		fmt.Println("- SSH keys blocked (security default): a compromised or untrusted tool")
+		fmt.Println("  running in the sandbox can't read or use your host SSH keys.")
+		fmt.Println("  Override: remove \"~/.ssh\" from [security].blocked_paths (not recommended).")
+	}
+	if len(cfg.BlockedPaths) > 0 {
+		fmt.Println("- .env files and other secret-looking paths blocked (security default):")
+		fmt.Println("  overlaid with /dev/null so tools see an empty/missing file instead of")
+		fmt.Println("  host credentials. Override: [security].blocked_paths / replace_defaults.")
+	}
+	fmt.Println()
+
+	if gitTool, ok := tools.Get("git").(tools.ToolWithConfig); ok {
+		var gitCfg map[string]any
+		if cfg.ToolsConfig != nil {
+			gitCfg, _ = cfg.ToolsConfig["git"].(map[string]any)
+		}
+		gitTool.Configure(tools.GlobalConfig{
+			OverlayEnabled: cfg.OverlayEnabled,
+			ProjectDir:     cfg.ProjectDir,
+			HomeDir:        cfg.HomeDir,
+		}, gitCfg)
+		fmt.Printf("- git: %s\n", tools.Get("git").Description())
+		switch {
+		case gitCfg != nil && gitCfg["mode"] == "readwrite":
+			fmt.Println("  readwrite mode: commits, pushes, and credentials are available.")
+			fmt.Println("  Change: set [tools.git] mode=\"readonly\" or mode=\"disabled\".")
+		case gitCfg != nil && gitCfg["mode"] == "disabled":
+			fmt.Println("  disabled: git commands run without any user configuration.")
+			fmt.Println("  Change: set [tools.git] mode=\"readonly\" or mode=\"readwrite\".")
+		default:
+			fmt.Println("  readonly mode (default): commits are blocked, and only your name/email")
+			fmt.Println("  leave the host gitconfig - no credentials, signing keys, or includes.")
+			fmt.Println("  Change: set [tools.git] mode=\"readwrite\" for full access, or --git-mode.")
+		}
+		fmt.Println()
+	}
+
+	if cfg.NetworkDisabled {
+		fmt.Println("- network fully disabled (--no-network): own network namespace with only")
+		fmt.Println("  loopback - no pasta/slirp4netns, no proxy, no route anywhere else.")
+		fmt.Println("  Change: drop --no-network for shared access, or use --proxy to filter instead.")
+	} else if cfg.ProxyEnabled {
+		fmt.Println("- network routed through the local proxy (--proxy): all HTTP/HTTPS traffic")
+		fmt.Println("  is MITM-inspected and filtered against proxy.filter before it leaves the")
+		fmt.Println("  sandbox.")
+		if cfg.NetworkIsolated {
+			fmt.Println("  Direct connections (bypassing the proxy) are blocked: the sandbox's")
+			fmt.Println("  network namespace only has a route to the proxy's gateway, over both")
+			fmt.Println("  IPv4 and IPv6.")
+		} else {
+			fmt.Println("  Direct connections are NOT blocked (no pasta/slirp4netns available):")
+			fmt.Println("  only traffic that honors HTTP_PROXY/HTTPS_PROXY is inspected.")
+		}
+	} else {
+		fmt.Println("- network shared with host (default): the sandbox has the same network")
+		fmt.Println("  access as your host, unfiltered and unlogged.")
+		fmt.Println("  Change: use --proxy to route and filter traffic through a local MITM proxy.")
+	}
+	fmt.Println()
+
+	switch cfg.TmpMode {
+	case sandbox.TmpModeDisk:
+		fmt.Println("- /tmp persists on disk across sandbox runs (sandbox.tmp_mode=\"disk\"):")
+		fmt.Println("  useful for tools that cache large files in /tmp, at the cost of disk usage.")
+	default:
+		fmt.Println("- /tmp is a private tmpfs (default): wiped when the sandbox exits.")
+		fmt.Println("  Change: set sandbox.tmp_mode=\"disk\" for a persistent /tmp.")
+	}
+	fmt.Println()
+
+	if cfg.ReadOnlyHome {
+		fmt.Println("- sandbox home is read-only (--read-only-home): a compromised tool can't")
+		fmt.Println("  persist anything outside the project dir and /tmp. ~/.config, ~/.cache,")
+		fmt.Println("  and similar dirs are backed by tmpfs instead, so shell history and other")
+		fmt.Println("  writes there don't survive the session.")
+	} else {
+		fmt.Println("- sandbox home is read-write (default): tools can persist config, caches,")
+		fmt.Println("  and shell history across runs, isolated from your real home directory.")
+		fmt.Println("  Change: use --read-only-home for untrusted analysis sessions.")
+	}
+	fmt.Println()
+
+	switch cfg.ConfigVisibility {
+	case "readwrite":
+		fmt.Println("- .devsandbox.toml is writable inside the sandbox (sandbox.config_visibility=\"readwrite\").")
+	case "hidden":
+		fmt.Println("- .devsandbox.toml is hidden from the sandbox (sandbox.config_visibility=\"hidden\").")
+	default:
+		fmt.Println("- .devsandbox.toml is read-only inside the sandbox (default): sandboxed")
+		fmt.Println("  processes can see the config that shaped them, but can't change it.")
+		fmt.Println("  Change: set sandbox.config_visibility=\"readwrite\" or \"hidden\".")
+	}
+	fmt.Println()
+
+	fmt.Printf("- syscall filter: %s\n", cfg.SeccompProfile)
+	fmt.Println("  Change: --seccomp default|strict|none, or sandbox.seccomp_profile.")
+	fmt.Println()
+
+	if !cfg.ResourceLimits.IsZero() {
+		fmt.Printf("- resource limits (%s): bwrap has no limiting of its own, so the sandboxed\n", formatResourceLimits(cfg.ResourceLimits))
+		fmt.Println("  process is placed in a cgroup enforcing this - a transient systemd --user")
+		fmt.Println("  scope if available, falling back to writing a cgroup v2 slice directly.")
+		fmt.Println("  No-op (with a warning) on hosts where neither is usable.")
+	} else {
+		fmt.Println("- memory/CPU unlimited (default): the sandboxed process can use as much of")
+		fmt.Println("  either as the host allows.")
+		fmt.Println("  Change: use --memory/--cpus, or sandbox.memory/sandbox.cpus.")
+	}
+
+	fmt.Println()
+	fmt.Println("Run --info to see the concrete resolved configuration (paths, ports, mounts).")
+}
+
+// printTraceLine writes a compact, color-coded summary of a single proxied
+// request to stderr. Registered as the proxy server's trace hook by
+// --trace-http, it's the live equivalent of `logs proxy --follow --compact`
+// without needing a second terminal, and never touches stdout.
+func printTraceLine(e *proxy.RequestLog) {
+	status := fmt.Sprintf("%d", e.StatusCode)
+	if e.Error != "" {
+		status = "ERR"
+	}
+	status = colorizeStatus(status, e.StatusCode, e.Error)
+
+	duration := "-"
+	if e.Duration > 0 {
+		duration = fmt.Sprintf("%dms", e.Duration.Milliseconds())
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s %s %s %s\n",
+		e.Timestamp.Format("15:04:05"),
+		e.Method,
+		status,
+		duration,
+		e.URL,
+	)
 }
 
 // getMiseConfig extracts mise configuration from ToolsConfig.
@@ -405,9 +1177,10 @@ func getMiseConfig(cfg *sandbox.Config) (writable, persistent bool) {
 	return
 }
 
-// buildFilterConfig builds filter configuration from config file and CLI flags.
-// CLI flags override config file settings.
-func buildFilterConfig(appCfg *config.Config, cmd *cobra.Command, filterDefault string, allowDomains, blockDomains []string) *proxy.FilterConfig {
+// buildFilterConfig builds filter configuration from config file, an
+// optional standalone --filter-file, and CLI flags. Later sources override
+// or extend earlier ones: config file -> --filter-file -> --filter-*.
+func buildFilterConfig(appCfg *config.Config, cmd *cobra.Command, filterDefault string, allowDomains, blockDomains []string, filterFile string) (*proxy.FilterConfig, error) {
 	filterCfg := proxy.DefaultFilterConfig()
 
 	// Apply config file settings
@@ -417,7 +1190,11 @@ func buildFilterConfig(appCfg *config.Config, cmd *cobra.Command, filterDefault
 	if appCfg.Proxy.Filter.AskTimeout > 0 {
 		filterCfg.AskTimeout = appCfg.Proxy.Filter.AskTimeout
 	}
+	if appCfg.Proxy.Filter.AskVia != "" {
+		filterCfg.AskVia = proxy.AskVia(appCfg.Proxy.Filter.AskVia)
+	}
 	filterCfg.CacheDecisions = appCfg.Proxy.Filter.CacheDecisions
+	filterCfg.CacheTTL = appCfg.Proxy.Filter.CacheTTL
 
 	// Convert config file rules
 	for _, r := range appCfg.Proxy.Filter.Rules {
@@ -427,9 +1204,29 @@ func buildFilterConfig(appCfg *config.Config, cmd *cobra.Command, filterDefault
 			Scope:   proxy.FilterScope(r.Scope),
 			Type:    proxy.PatternType(r.Type),
 			Reason:  r.Reason,
+			Mock:    r.Mock,
 		})
 	}
 
+	for _, r := range appCfg.Proxy.Filter.BodyRules {
+		filterCfg.BodyRules = append(filterCfg.BodyRules, proxy.BodyFilterRule{
+			ContentType: r.ContentType,
+			MaxBodySize: r.MaxBodySize,
+			Reason:      r.Reason,
+		})
+	}
+
+	// A standalone filter file (TOML or YAML) replaces the config file's
+	// filter settings wholesale, so teams can manage policy as its own
+	// reusable document.
+	if filterFile != "" {
+		fileCfg, err := proxy.LoadFilterConfigFile(filterFile)
+		if err != nil {
+			return nil, err
+		}
+		filterCfg = fileCfg
+	}
+
 	// CLI override for default action
 	if cmd.Flags().Changed("filter-default") && filterDefault != "" {
 		filterCfg.DefaultAction = proxy.FilterAction(filterDefault)
@@ -467,7 +1264,95 @@ func buildFilterConfig(appCfg *config.Config, cmd *cobra.Command, filterDefault
 		}
 	}
 
-	return filterCfg
+	return filterCfg, nil
+}
+
+// blockMonitor implements --on-block=fail-fast: it's fed every proxied
+// request via the proxy server's trace hook, and on the first blocked one it
+// cancels the sandbox's run context (see runSandbox's runCtx) so the
+// sandboxed process gets a SIGTERM instead of continuing unaware that its
+// request was denied.
+type blockMonitor struct {
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	reasons []string
+}
+
+func newBlockMonitor(cancel context.CancelFunc) *blockMonitor {
+	return &blockMonitor{cancel: cancel}
+}
+
+// record notes e if it was blocked by the proxy filter and cancels the run
+// context. Safe to call from the proxy's trace hook goroutine.
+func (m *blockMonitor) record(e *proxy.RequestLog) {
+	if e.FilterAction != string(proxy.FilterActionBlock) {
+		return
+	}
+	m.mu.Lock()
+	reason := e.FilterReason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	m.reasons = append(m.reasons, fmt.Sprintf("%s %s (%s)", e.Method, e.URL, reason))
+	m.mu.Unlock()
+	m.cancel()
+}
+
+// blocked returns a summary line per blocked request seen so far.
+func (m *blockMonitor) blocked() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.reasons...)
+}
+
+// runDryRun prints the full command that would be run for this invocation -
+// the bwrap argv, and in proxy mode the pasta/slirp4netns wrapper around it -
+// without creating sandbox directories, starting the proxy, or running
+// anything. Only the bwrap backend implements isolator.DryRunner.
+func runDryRun(ctx context.Context, iso isolator.Isolator, cfg *sandbox.Config, appCfg *config.Config, command []string, proxyPort int) error {
+	dr, ok := iso.(isolator.DryRunner)
+	if !ok {
+		return fmt.Errorf("--dry-run is not supported with the %s backend", iso.Name())
+	}
+
+	var proxyCAPath string
+	socksPort := cfg.SocksPort
+	if cfg.ProxyEnabled {
+		port, err := sandbox.NextAvailablePort(cfg.SandboxRoot, proxyPort)
+		if err != nil {
+			return fmt.Errorf("failed to pick proxy port: %w", err)
+		}
+		cfg.ProxyPort = port
+
+		pCfg := proxy.NewConfig(cfg.SandboxRoot, port)
+		if appCfg.Proxy.CA.Shared {
+			pCfg.UseSharedCA(cfg.SandboxBase)
+		}
+		proxyCAPath = pCfg.CACertPath
+		if cfg.SocksEnabled {
+			socksPort = pCfg.GetSocksPort()
+		}
+	}
+
+	runCfg := &isolator.RunConfig{
+		SandboxCfg:  cfg,
+		AppCfg:      appCfg,
+		Command:     command,
+		ProxyCAPath: proxyCAPath,
+		ProxyPort:   cfg.ProxyPort,
+		SocksPort:   socksPort,
+	}
+
+	argv, err := dr.DryRun(ctx, runCfg)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range argv {
+		fmt.Println(sandbox.ShellQuote(arg))
+	}
+	return nil
 }
 
 // proxyResult holds the running proxy server and its cleanup/signal handling.
@@ -497,9 +1382,14 @@ func startProxyServer(pCfg *proxy.Config) (*proxyResult, error) {
 		caPath: pCfg.CACertPath,
 	}
 
+	pauseChan := make(chan os.Signal, 1)
+	signal.Notify(pauseChan, syscall.SIGUSR1)
+
 	var cleanupOnce sync.Once
 	result.cleanup = func() {
 		cleanupOnce.Do(func() {
+			signal.Stop(pauseChan)
+			close(pauseChan)
 			_ = server.Stop()
 		})
 	}
@@ -513,6 +1403,14 @@ func startProxyServer(pCfg *proxy.Config) (*proxyResult, error) {
 		result.cleanup()
 	}()
 
+	// SIGUSR1 toggles request log persistence on/off without stopping the
+	// proxy, so a sensitive command can be run without it being recorded.
+	go func() {
+		for range pauseChan {
+			server.TogglePauseLogging()
+		}
+	}()
+
 	return result, nil
 }
 
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"devsandbox/internal/proxy"
+)
+
+func TestBlockMonitor_RecordsBlockedRequestsAndCancels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	canceled := false
+	mon := newBlockMonitor(func() {
+		canceled = true
+		cancel()
+	})
+
+	mon.record(&proxy.RequestLog{Method: "GET", URL: "https://allowed.example.com", FilterAction: string(proxy.FilterActionAllow)})
+	if len(mon.blocked()) != 0 {
+		t.Fatalf("expected no blocked requests recorded for an allowed one, got %v", mon.blocked())
+	}
+	if canceled {
+		t.Fatal("expected an allowed request not to cancel the run context")
+	}
+
+	mon.record(&proxy.RequestLog{Method: "GET", URL: "https://blocked.example.com", FilterAction: string(proxy.FilterActionBlock), FilterReason: "blocked by policy"})
+	blocked := mon.blocked()
+	if len(blocked) != 1 || blocked[0] != "GET https://blocked.example.com (blocked by policy)" {
+		t.Fatalf("unexpected blocked summary: %v", blocked)
+	}
+	if !canceled {
+		t.Fatal("expected a blocked request to cancel the run context")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected run context to be canceled")
+	}
+}
+
+func TestBlockMonitor_DefaultsMissingReason(t *testing.T) {
+	mon := newBlockMonitor(func() {})
+	mon.record(&proxy.RequestLog{Method: "POST", URL: "https://blocked.example.com", FilterAction: string(proxy.FilterActionBlock)})
+
+	blocked := mon.blocked()
+	if len(blocked) != 1 || blocked[0] != "POST https://blocked.example.com (no reason given)" {
+		t.Fatalf("unexpected blocked summary: %v", blocked)
+	}
+}
@@ -28,10 +28,81 @@ func newProxyCmd() *cobra.Command {
 
 	cmd.AddCommand(newProxyMonitorCmd())
 	cmd.AddCommand(newFilterCmd())
+	cmd.AddCommand(newProxyCACmd())
 
 	return cmd
 }
 
+func newProxyCACmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ca",
+		Short: "Manage the proxy's TLS interception CA",
+		Long:  `Commands for inspecting and rotating the CA certificate used for HTTPS interception.`,
+	}
+
+	cmd.AddCommand(newProxyCARotateCmd())
+
+	return cmd
+}
+
+func newProxyCARotateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "Regenerate the proxy CA certificate and key",
+		Long: `Deletes the current CA certificate and key and generates a new pair in
+their place. Use this when the CA is nearing expiry (the proxy logs a
+warning when it is) or if the key may have been compromised.
+
+Every certificate the proxy previously signed for MITM interception becomes
+invalid, so any already-running sandbox will need to be restarted to pick
+up the new CA, and any process outside the sandbox that was pointed at the
+old certificate (e.g. via SSL_CERT_FILE) needs to be updated too.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCfg, _, _, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			sandboxBase, err := resolveSandboxBase()
+			if err != nil {
+				return err
+			}
+
+			pCfg := proxy.NewConfig(sandboxBase, 0)
+			if appCfg.Proxy.CA.Shared {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return err
+				}
+				basePath := appCfg.Sandbox.BasePath
+				if basePath == "" {
+					basePath = sandbox.SandboxBasePath(home)
+				}
+				pCfg.UseSharedCA(basePath)
+			}
+
+			if pCfg.CAExists() {
+				if err := os.Remove(pCfg.CACertPath); err != nil {
+					return fmt.Errorf("failed to remove old CA certificate: %w", err)
+				}
+				if err := os.Remove(pCfg.CAKeyPath); err != nil {
+					return fmt.Errorf("failed to remove old CA key: %w", err)
+				}
+			}
+
+			ca, err := proxy.CreateCA(pCfg)
+			if err != nil {
+				return fmt.Errorf("failed to generate new CA: %w", err)
+			}
+
+			fmt.Printf("New CA generated, valid until %s.\n", ca.Certificate.NotAfter.Format("2006-01-02"))
+			fmt.Printf("Certificate: %s\n", pCfg.CACertPath)
+			fmt.Println("Restart any running sandbox for this project to pick it up.")
+			return nil
+		},
+	}
+}
+
 func newProxyMonitorCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "monitor [socket-path]",
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/sandbox"
+)
+
+func newRenameCmd() *cobra.Command {
+	var (
+		to    string
+		adopt bool
+		from  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rename",
+		Short: "Re-point a sandbox at a moved or renamed project directory",
+		Long: `Sandbox directories are named <basename>-<hash>, where the hash is derived
+from the project's full path. Moving or renaming a project directory means
+FindExistingSandbox no longer matches, so the next run creates a fresh,
+empty sandbox - losing caches and shell history.
+
+"devsandbox rename --to <newpath>" updates the current project's sandbox to
+track newpath instead, moving its directory to match. Run it from the
+project's current (pre-move) location.
+
+"devsandbox rename --adopt" does the reverse: run it from the project's new
+location, and it looks for an orphaned sandbox (project directory no longer
+exists) with the same basename, and re-points that one here instead. Use
+--from to pick a specific old path if more than one orphaned sandbox
+matches.`,
+		Example: `  devsandbox rename --to ~/code/new-project-name
+  devsandbox rename --adopt
+  devsandbox rename --adopt --from ~/code/old-project-name`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			baseDir := sandbox.SandboxBasePath(homeDir)
+
+			projectDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			if adopt {
+				return adoptSandbox(baseDir, projectDir, from)
+			}
+
+			if to == "" {
+				return fmt.Errorf("--to <newpath> is required (or use --adopt from the new location)")
+			}
+			return renameSandboxTo(baseDir, projectDir, to)
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "New project directory path to re-point this sandbox at")
+	cmd.Flags().BoolVar(&adopt, "adopt", false, "Run from the moved project; find and adopt the orphaned sandbox for its old location")
+	cmd.Flags().StringVar(&from, "from", "", "With --adopt, the specific old project path to adopt instead of auto-detecting by basename")
+
+	return cmd
+}
+
+// renameSandboxTo re-points the sandbox for the current project (cwd) at
+// newProjectDir.
+func renameSandboxTo(baseDir, projectDir, newProjectDir string) error {
+	m, err := sandbox.FindExistingSandbox(projectDir, baseDir)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return fmt.Errorf("no sandbox found for %s", projectDir)
+	}
+
+	renamed, err := sandbox.RenameSandbox(m, newProjectDir, baseDir)
+	if err != nil {
+		return fmt.Errorf("failed to rename sandbox: %w", err)
+	}
+
+	fmt.Printf("Renamed sandbox %s -> %s\n", m.Name, renamed.Name)
+	fmt.Printf("  Project: %s -> %s\n", projectDir, newProjectDir)
+	return nil
+}
+
+// adoptSandbox re-points an orphaned sandbox (explicitly identified by
+// fromProjectDir, or auto-detected by basename match) at the current
+// project directory (cwd).
+func adoptSandbox(baseDir, projectDir, fromProjectDir string) error {
+	if existing, err := sandbox.FindExistingSandbox(projectDir, baseDir); err != nil {
+		return err
+	} else if existing != nil && !existing.Orphaned {
+		return fmt.Errorf("sandbox %q already exists for this project; nothing to adopt", existing.Name)
+	}
+
+	var m *sandbox.Metadata
+	if fromProjectDir != "" {
+		found, err := sandbox.FindExistingSandbox(fromProjectDir, baseDir)
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return fmt.Errorf("no sandbox found for %s", fromProjectDir)
+		}
+		m = found
+	} else {
+		found, err := sandbox.FindOrphanedSandboxByBasename(baseDir, projectDir)
+		if err != nil {
+			return err
+		}
+		m = found
+	}
+
+	oldProjectDir := m.ProjectDir
+	renamed, err := sandbox.RenameSandbox(m, projectDir, baseDir)
+	if err != nil {
+		return fmt.Errorf("failed to adopt sandbox: %w", err)
+	}
+
+	fmt.Printf("Adopted sandbox %s -> %s\n", m.Name, renamed.Name)
+	fmt.Printf("  Project: %s -> %s\n", oldProjectDir, projectDir)
+	return nil
+}
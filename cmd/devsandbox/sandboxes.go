@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -21,17 +20,22 @@ func newSandboxesCmd() *cobra.Command {
 		Long:  "List, inspect, and prune sandbox instances stored in ~/.local/share/devsandbox/",
 	}
 
-	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newListCmd("name"))
 	cmd.AddCommand(newPruneCmd())
 
 	return cmd
 }
 
-func newListCmd() *cobra.Command {
+// newListCmd builds the sandbox listing command. defaultSort sets the
+// --sort flag's default, so the top-level `devsandbox list` alias (see
+// newTopLevelListCmd) can default to "used" without changing
+// `devsandbox sandboxes list`'s long-standing default.
+func newListCmd(defaultSort string) *cobra.Command {
 	var (
 		jsonOutput bool
 		sortBy     string
 		noSize     bool
+		active     bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,7 +45,8 @@ func newListCmd() *cobra.Command {
 		Example: `  devsandbox sandboxes list
   devsandbox sandboxes list --json
   devsandbox sandboxes list --sort used
-  devsandbox sandboxes list --no-size`,
+  devsandbox sandboxes list --no-size
+  devsandbox sandboxes list --active`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
@@ -62,6 +67,30 @@ func newListCmd() *cobra.Command {
 			// Check active status for each sandbox
 			for _, s := range sandboxes {
 				s.Active = sandbox.IsSessionActive(s.SandboxRoot)
+				if s.Active {
+					if ports, err := sandbox.ActivePorts(s.SandboxRoot); err == nil {
+						s.ActivePorts = ports
+					}
+				}
+			}
+
+			if active {
+				var activeOnly []*sandbox.Metadata
+				for _, s := range sandboxes {
+					if s.Active {
+						activeOnly = append(activeOnly, s)
+					}
+				}
+				sandboxes = activeOnly
+			}
+
+			if len(sandboxes) == 0 {
+				if active {
+					fmt.Println("No active sandboxes.")
+					return nil
+				}
+				fmt.Println("No sandboxes found.")
+				return nil
 			}
 
 			// Calculate sizes (default: on)
@@ -110,9 +139,19 @@ func newListCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
-	cmd.Flags().StringVar(&sortBy, "sort", "name", "Sort by: name, created, used, size")
+	cmd.Flags().StringVar(&sortBy, "sort", defaultSort, "Sort by: name, created, used, size")
 	cmd.Flags().BoolVar(&noSize, "no-size", false, "Skip size calculation (faster)")
+	cmd.Flags().BoolVar(&active, "active", false, "Only show sandboxes with a live session")
+
+	return cmd
+}
 
+// newTopLevelListCmd is a `devsandbox list` shortcut for `devsandbox
+// sandboxes list`, sorted by last used by default so the sandboxes most
+// worth cleaning up show up first.
+func newTopLevelListCmd() *cobra.Command {
+	cmd := newListCmd("used")
+	cmd.Long = "List all sandbox instances with their metadata, sorted by last used.\n\nShortcut for `devsandbox sandboxes list --sort used`."
 	return cmd
 }
 
@@ -249,14 +288,11 @@ directory no longer exists) are removed.`,
 
 			// Confirm unless --force
 			if !force {
-				fmt.Print("Remove these sandboxes? [y/N] ")
-				reader := bufio.NewReader(os.Stdin)
-				response, err := reader.ReadString('\n')
+				ok, err := confirm("Remove these sandboxes?")
 				if err != nil {
 					return err
 				}
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
+				if !ok {
 					fmt.Println("Aborted.")
 					return nil
 				}
@@ -303,9 +339,9 @@ func printTable(sandboxes []*sandbox.Metadata, showSize bool) error {
 	table := tablewriter.NewWriter(os.Stdout)
 
 	if showSize {
-		table.Header("NAME", "TYPE", "PROJECT DIR", "CREATED", "LAST USED", "SIZE", "STATUS")
+		table.Header("NAME", "TYPE", "PROJECT DIR", "CREATED", "LAST USED", "SIZE", "STATUS", "PORTS")
 	} else {
-		table.Header("NAME", "TYPE", "PROJECT DIR", "CREATED", "LAST USED", "STATUS")
+		table.Header("NAME", "TYPE", "PROJECT DIR", "CREATED", "LAST USED", "STATUS", "PORTS")
 	}
 
 	for _, s := range sandboxes {
@@ -344,6 +380,8 @@ func printTable(sandboxes []*sandbox.Metadata, showSize bool) error {
 			sizeStr = "-"
 		}
 
+		ports := formatActivePorts(s.ActivePorts)
+
 		if showSize {
 			_ = table.Append(
 				s.Name,
@@ -353,6 +391,7 @@ func printTable(sandboxes []*sandbox.Metadata, showSize bool) error {
 				s.LastUsed.Format("2006-01-02"),
 				sizeStr,
 				status,
+				ports,
 			)
 		} else {
 			_ = table.Append(
@@ -362,6 +401,7 @@ func printTable(sandboxes []*sandbox.Metadata, showSize bool) error {
 				s.CreatedAt.Format("2006-01-02"),
 				s.LastUsed.Format("2006-01-02"),
 				status,
+				ports,
 			)
 		}
 	}
@@ -369,6 +409,19 @@ func printTable(sandboxes []*sandbox.Metadata, showSize bool) error {
 	return table.Render()
 }
 
+// formatActivePorts renders a sandbox's port registry entries as a short
+// comma-separated list for table display, e.g. "8080, 8081".
+func formatActivePorts(entries []sandbox.PortRegistryEntry) string {
+	if len(entries) == 0 {
+		return "-"
+	}
+	ports := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ports = append(ports, fmt.Sprintf("%d", e.ProxyPort))
+	}
+	return strings.Join(ports, ", ")
+}
+
 // parseDuration parses a human-friendly duration like "30d", "2w", "1h"
 func parseDuration(s string) (time.Duration, error) {
 	if len(s) < 2 {
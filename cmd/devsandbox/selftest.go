@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"devsandbox/internal/embed"
+	"devsandbox/internal/version"
+)
+
+// selfTestResult is one row of `devsandbox self-test`'s report.
+type selfTestResult struct {
+	name    string
+	status  string // "ok", "fail", "skip"
+	message string
+}
+
+func newSelfTestCmd() *cobra.Command {
+	var proxyTest, noNetworkTest bool
+
+	cmd := &cobra.Command{
+		Use:   "self-test",
+		Short: "Run end-to-end checks of the sandbox's security model",
+		Long: `self-test runs devsandbox against itself, in a disposable throwaway project
+directory, to verify the security model actually holds on this machine:
+.env is blocked, ~/.ssh is hidden, the project directory is writable, and
+network/DNS access works. With --proxy, it additionally checks that the
+proxy's CA is trusted (HTTPS requests succeed) and that a direct,
+non-proxied connection is blocked. With --no-network, it checks that
+--no-network blocks DNS resolution and outbound connections entirely.
+
+This covers the same guarantees as the project's own e2e test suite,
+packaged as a diagnostic end users can run and attach to a bug report.`,
+		Example: `  devsandbox self-test
+  devsandbox self-test --proxy
+  devsandbox self-test --no-network`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSelfTest(proxyTest, noNetworkTest)
+		},
+	}
+
+	cmd.Flags().BoolVar(&proxyTest, "proxy", false, "Also verify proxy mode: CA trust and direct-connection blocking")
+	cmd.Flags().BoolVar(&noNetworkTest, "no-network", false, "Also verify --no-network: DNS resolution and outbound connections are blocked")
+
+	return cmd
+}
+
+func runSelfTest(proxyTest, noNetworkTest bool) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("self-test is only supported on Linux (bwrap isolation)")
+	}
+
+	selfPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the devsandbox binary: %w", err)
+	}
+
+	projectDir, err := os.MkdirTemp("", "devsandbox-self-test-*")
+	if err != nil {
+		return fmt.Errorf("failed to create throwaway project directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(projectDir) }()
+
+	fmt.Printf("devsandbox %s\n", version.Version)
+	fmt.Printf("bwrap: %s  pasta: %s\n\n", embed.BwrapVersion, embed.PastaVersion)
+
+	results := []selfTestResult{
+		checkEnvFileBlocked(selfPath, projectDir),
+		checkSSHHidden(selfPath, projectDir),
+		checkProjectDirWritable(selfPath, projectDir),
+		checkNetworkAvailable(selfPath, projectDir),
+	}
+
+	if proxyTest {
+		results = append(results,
+			checkProxyCATrusted(selfPath, projectDir),
+			checkProxyBlocksDirectConnections(selfPath, projectDir),
+		)
+	}
+
+	if noNetworkTest {
+		results = append(results,
+			checkNoNetworkBlocksDNS(selfPath, projectDir),
+			checkNoNetworkBlocksConnections(selfPath, projectDir),
+		)
+	}
+
+	printSelfTestResults(results)
+
+	for _, r := range results {
+		if r.status == "fail" {
+			return fmt.Errorf("self-test found issues")
+		}
+	}
+
+	fmt.Println("\nAll checks passed!")
+	return nil
+}
+
+// runInThrowawaySandbox runs command inside a --rm sandbox rooted at
+// projectDir, using the devsandbox binary at selfPath, and returns its
+// combined output.
+func runInThrowawaySandbox(selfPath, projectDir string, extraArgs []string, command ...string) (string, error) {
+	args := append([]string{"--rm"}, extraArgs...)
+	args = append(args, command...)
+	cmd := exec.Command(selfPath, args...) //nolint:gosec // selfPath is os.Executable(), command is fixed per check
+	cmd.Dir = projectDir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func checkEnvFileBlocked(selfPath, projectDir string) selfTestResult {
+	envFile := filepath.Join(projectDir, ".env")
+	if err := os.WriteFile(envFile, []byte("SECRET=devsandbox-self-test-canary\n"), 0o600); err != nil {
+		return selfTestResult{name: ".env blocked", status: "fail", message: fmt.Sprintf("failed to write test .env: %v", err)}
+	}
+	defer func() { _ = os.Remove(envFile) }()
+
+	out, _ := runInThrowawaySandbox(selfPath, projectDir, nil, "cat", ".env")
+	if strings.Contains(out, "devsandbox-self-test-canary") {
+		return selfTestResult{name: ".env blocked", status: "fail", message: ".env contents were readable inside the sandbox"}
+	}
+	return selfTestResult{name: ".env blocked", status: "ok", message: ".env is hidden from the sandbox"}
+}
+
+func checkSSHHidden(selfPath, projectDir string) selfTestResult {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return selfTestResult{name: "~/.ssh hidden", status: "skip", message: "cannot determine home directory"}
+	}
+	sshDir := filepath.Join(homeDir, ".ssh")
+	if _, err := os.Stat(sshDir); os.IsNotExist(err) {
+		return selfTestResult{name: "~/.ssh hidden", status: "skip", message: "no ~/.ssh on this machine to test against"}
+	}
+
+	out, err := runInThrowawaySandbox(selfPath, projectDir, nil, "ls", sshDir)
+	if err == nil && strings.TrimSpace(out) != "" {
+		return selfTestResult{name: "~/.ssh hidden", status: "fail", message: "~/.ssh appears accessible inside the sandbox"}
+	}
+	return selfTestResult{name: "~/.ssh hidden", status: "ok", message: "~/.ssh is not accessible inside the sandbox"}
+}
+
+func checkProjectDirWritable(selfPath, projectDir string) selfTestResult {
+	marker := "self-test-marker.txt"
+	if _, err := runInThrowawaySandbox(selfPath, projectDir, nil, "touch", marker); err != nil {
+		return selfTestResult{name: "project dir writable", status: "fail", message: fmt.Sprintf("touch failed inside the sandbox: %v", err)}
+	}
+	defer func() { _ = os.Remove(filepath.Join(projectDir, marker)) }()
+
+	if _, err := os.Stat(filepath.Join(projectDir, marker)); err != nil {
+		return selfTestResult{name: "project dir writable", status: "fail", message: "file created in the sandbox did not appear on the host"}
+	}
+	return selfTestResult{name: "project dir writable", status: "ok", message: "files created in the sandbox persist on the host"}
+}
+
+func checkNetworkAvailable(selfPath, projectDir string) selfTestResult {
+	out, err := runInThrowawaySandbox(selfPath, projectDir, nil, "cat", "/etc/resolv.conf")
+	if err != nil || !strings.Contains(out, "nameserver") {
+		return selfTestResult{name: "network/DNS", status: "fail", message: "resolv.conf is not available inside the sandbox"}
+	}
+	return selfTestResult{name: "network/DNS", status: "ok", message: "DNS configuration is available inside the sandbox"}
+}
+
+func checkProxyCATrusted(selfPath, projectDir string) selfTestResult {
+	if _, err := exec.LookPath("curl"); err != nil {
+		return selfTestResult{name: "proxy CA trusted", status: "skip", message: "curl not installed on this machine"}
+	}
+
+	out, err := runInThrowawaySandbox(selfPath, projectDir, []string{"--proxy", "--quiet"},
+		"curl", "-s", "-o", "/dev/null", "-w", "%{http_code}", "--max-time", "10", "https://httpbin.org/get")
+	code := lastLine(out)
+	if code == "000" {
+		return selfTestResult{name: "proxy CA trusted", status: "skip", message: "no network access in this environment"}
+	}
+	if err != nil || code != "200" {
+		return selfTestResult{name: "proxy CA trusted", status: "fail", message: fmt.Sprintf("HTTPS request through the proxy returned %q instead of 200", code)}
+	}
+	return selfTestResult{name: "proxy CA trusted", status: "ok", message: "HTTPS requests through the proxy succeed without certificate errors"}
+}
+
+func checkProxyBlocksDirectConnections(selfPath, projectDir string) selfTestResult {
+	if _, err := exec.LookPath("nc"); err != nil {
+		return selfTestResult{name: "direct connections blocked", status: "skip", message: "nc (netcat) not installed on this machine"}
+	}
+
+	_, err := runInThrowawaySandbox(selfPath, projectDir, []string{"--proxy", "--quiet"}, "nc", "-vv", "-w", "2", "1.1.1.1", "443")
+	if err == nil {
+		return selfTestResult{name: "direct connections blocked", status: "fail", message: "a direct (non-proxied) connection succeeded despite --proxy"}
+	}
+	return selfTestResult{name: "direct connections blocked", status: "ok", message: "direct (non-proxied) connections are blocked, as expected"}
+}
+
+func checkNoNetworkBlocksDNS(selfPath, projectDir string) selfTestResult {
+	out, err := runInThrowawaySandbox(selfPath, projectDir, []string{"--no-network", "--quiet"}, "getent", "hosts", "example.com")
+	if err == nil && strings.TrimSpace(out) != "" {
+		return selfTestResult{name: "no-network blocks DNS", status: "fail", message: "DNS resolution succeeded despite --no-network"}
+	}
+	return selfTestResult{name: "no-network blocks DNS", status: "ok", message: "DNS resolution fails, as expected"}
+}
+
+func checkNoNetworkBlocksConnections(selfPath, projectDir string) selfTestResult {
+	if _, err := exec.LookPath("nc"); err != nil {
+		return selfTestResult{name: "no-network blocks connections", status: "skip", message: "nc (netcat) not installed on this machine"}
+	}
+
+	_, err := runInThrowawaySandbox(selfPath, projectDir, []string{"--no-network", "--quiet"}, "nc", "-vv", "-w", "2", "1.1.1.1", "443")
+	if err == nil {
+		return selfTestResult{name: "no-network blocks connections", status: "fail", message: "an outbound connection succeeded despite --no-network"}
+	}
+	return selfTestResult{name: "no-network blocks connections", status: "ok", message: "outbound connections are blocked, as expected"}
+}
+
+// lastLine returns the last non-empty line of s, for pulling curl's -w
+// output out of combined output that may also contain proxy startup text.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func printSelfTestResults(results []selfTestResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("CHECK", "STATUS", "DETAILS")
+
+	for _, r := range results {
+		status := r.status
+		switch r.status {
+		case "ok":
+			status = "✓ ok"
+		case "fail":
+			status = "✗ fail"
+		case "skip":
+			status = "- skip"
+		}
+		_ = table.Append(r.name, status, r.message)
+	}
+
+	_ = table.Render()
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestLastLine(t *testing.T) {
+	cases := map[string]string{
+		"200":                       "200",
+		"Proxy server started\n200": "200",
+		"one\ntwo\nthree\n":         "three",
+		"":                          "",
+	}
+
+	for input, want := range cases {
+		if got := lastLine(input); got != want {
+			t.Errorf("lastLine(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNewSelfTestCmd(t *testing.T) {
+	cmd := newSelfTestCmd()
+	if cmd.Use != "self-test" {
+		t.Errorf("expected Use='self-test', got %q", cmd.Use)
+	}
+	if cmd.Flags().Lookup("proxy") == nil {
+		t.Error("expected --proxy flag to be registered")
+	}
+}
@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -144,6 +145,71 @@ func TestSandbox_EnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestSandbox_LocaleConfigured(t *testing.T) {
+	if !bwrapAvailable() {
+		t.Skip("bwrap not available")
+	}
+
+	tmpConfigDir, err := os.MkdirTemp("", "devsandbox-e2e-locale-*")
+	if err != nil {
+		t.Fatalf("failed to create temp config dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpConfigDir) }()
+
+	configPath := filepath.Join(tmpConfigDir, "devsandbox", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("[env]\nlocale = \"de_DE.UTF-8\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "sh", "-c", "echo LANG=$LANG LC_ALL=$LC_ALL")
+	cmd.Env = append(os.Environ(), "XDG_CONFIG_HOME="+tmpConfigDir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "LANG=de_DE.UTF-8 LC_ALL=de_DE.UTF-8") {
+		t.Errorf("expected configured locale to be reflected in LANG/LC_ALL, got: %s", output)
+	}
+}
+
+func TestSandbox_LocaleDefaultsToCUTF8(t *testing.T) {
+	if !bwrapAvailable() {
+		t.Skip("bwrap not available")
+	}
+
+	tmpConfigDir, err := os.MkdirTemp("", "devsandbox-e2e-locale-default-*")
+	if err != nil {
+		t.Fatalf("failed to create temp config dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpConfigDir) }()
+
+	// No [env] section and no host LANG/LC_ALL: should fall back to C.UTF-8
+	// for deterministic output rather than inheriting whatever's ambient.
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "LANG=") || strings.HasPrefix(kv, "LC_ALL=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env, "XDG_CONFIG_HOME="+tmpConfigDir)
+
+	cmd := exec.Command(binaryPath, "sh", "-c", "echo LANG=$LANG LC_ALL=$LC_ALL")
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "LANG=C.UTF-8 LC_ALL=C.UTF-8") {
+		t.Errorf("expected default locale of C.UTF-8, got: %s", output)
+	}
+}
+
 func TestSandbox_MiseAvailable(t *testing.T) {
 	if !bwrapAvailable() {
 		t.Skip("bwrap not available")
@@ -446,6 +512,32 @@ func TestSandbox_ProxyEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestSandbox_ProxyExitCodePropagated(t *testing.T) {
+	if !bwrapAvailable() {
+		t.Skip("bwrap not available")
+	}
+
+	if !networkProviderAvailable() {
+		t.Skip("pasta not available")
+	}
+
+	// Proxy mode runs the sandboxed command via exec.Command rather than
+	// syscall.Exec (the parent process has to stay alive for the proxy
+	// goroutine), so the child's exit code has to be propagated explicitly -
+	// verify it actually is, rather than devsandbox exiting 1 for every
+	// failure.
+	cmd := exec.Command(binaryPath, "--proxy", "sh", "-c", "exit 42")
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected an *exec.ExitError, got %v", err)
+	}
+	if exitErr.ExitCode() != 42 {
+		t.Errorf("expected exit code 42, got %d", exitErr.ExitCode())
+	}
+}
+
 func TestSandbox_ProxyCACertificateAccessible(t *testing.T) {
 	if !bwrapAvailable() {
 		t.Skip("bwrap not available")
@@ -605,6 +697,62 @@ func TestSandbox_ProxyBlocksDirectConnections(t *testing.T) {
 	}
 }
 
+func TestSandbox_ProxyBlocksDirectIPv6Connections(t *testing.T) {
+	if !bwrapAvailable() {
+		t.Skip("bwrap not available")
+	}
+
+	if !networkProviderAvailable() {
+		t.Skip("pasta not available")
+	}
+
+	ncPath, err := exec.LookPath("nc")
+	if err != nil {
+		t.Skip("nc (netcat) not installed on host")
+	}
+
+	// Not every nc build supports -6 (e.g. some BusyBox variants) - skip
+	// rather than fail if the flag itself is rejected.
+	if out, err := exec.Command(ncPath, "-6", "-w", "1", "::1", "0").CombinedOutput(); err != nil &&
+		strings.Contains(string(out), "invalid option") {
+		t.Skip("nc on this host does not support -6")
+	}
+
+	// Try to connect directly to an external IPv6 address - should fail the
+	// same way the IPv4 case does, since the wrapper script tears down both
+	// default routes. Using Cloudflare's 2606:4700:4700::1111 as a reliable
+	// external IPv6 endpoint.
+	cmd := exec.Command(binaryPath, "--proxy",
+		"nc", "-6", "-vv", "-w", "2", "2606:4700:4700::1111", "443")
+
+	output, err := cmd.CombinedOutput()
+	outputStr := string(output)
+
+	if err == nil {
+		t.Error("Direct IPv6 connection to external address should be blocked in proxy mode")
+	}
+
+	networkErrors := []string{
+		"Network is unreachable",
+		"No route to host",
+		"network is unreachable",
+		"no route to host",
+		"Connection timed out",
+	}
+
+	foundNetworkError := false
+	for _, errMsg := range networkErrors {
+		if strings.Contains(outputStr, errMsg) {
+			foundNetworkError = true
+			break
+		}
+	}
+
+	if !foundNetworkError {
+		t.Logf("Expected network error, got: %s", outputStr)
+	}
+}
+
 func TestSandbox_ProxyAllowsHTTPTraffic(t *testing.T) {
 	if !bwrapAvailable() {
 		t.Skip("bwrap not available")
@@ -1,11 +1,16 @@
 package bwrap
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"devsandbox/internal/embed"
 	"devsandbox/internal/network"
@@ -30,84 +35,317 @@ func pastaSupportsMapHostLoopback(pastaPath string) bool {
 	return strings.Contains(string(output), "--map-host-loopback")
 }
 
-func Exec(bwrapArgs []string, shellCmd []string) error {
+// PreviewCommand returns the full bwrap invocation (binary path, bwrap's own
+// arguments, then the sandboxed shell command) that Exec/ExecRun would run,
+// without running it. Used by --dry-run to print the command instead of
+// executing it.
+func PreviewCommand(bwrapArgs []string, shellCmd []string) ([]string, error) {
 	bwrapPath, err := embed.BwrapPath()
 	if err != nil {
-		return fmt.Errorf("bwrap not available: %w", err)
+		return nil, fmt.Errorf("bwrap not available: %w", err)
 	}
 
 	args := make([]string, 0, len(bwrapArgs)+len(shellCmd)+2)
-	args = append(args, "bwrap")
+	args = append(args, bwrapPath)
 	args = append(args, bwrapArgs...)
 	args = append(args, "--")
 	args = append(args, shellCmd...)
+	return args, nil
+}
+
+// warnFunc reports a non-fatal problem applying resource limits (see
+// ResourceLimits.Wrap). Matches the signature of logging.ComponentLogger.Warnf,
+// so callers can pass that directly; nil is a valid no-op logger.
+type warnFunc func(format string, args ...any)
+
+func warn(warnf warnFunc, message string) {
+	if warnf == nil || message == "" {
+		return
+	}
+	warnf("%s", message)
+}
+
+// Exec replaces the current process with bwrap via syscall.Exec. seccompFile,
+// if non-nil, is the read end of a compiled seccomp BPF program that the
+// bwrap args reference via "--seccomp 3" (see sandbox.Builder.Seccomp) -
+// passing it requires keeping the calling process alive to hand off the fd,
+// so Exec delegates to ExecRun in that case instead of replacing itself.
+//
+// limits caps the resulting process's memory/CPU usage (see
+// ResourceLimits.Wrap); warnf receives a message if limits are configured
+// but couldn't be applied (nil is a valid no-op).
+func Exec(bwrapArgs []string, shellCmd []string, seccompFile *os.File, limits ResourceLimits, warnf warnFunc) error {
+	if seccompFile != nil {
+		return ExecRun(bwrapArgs, shellCmd, seccompFile, limits, warnf)
+	}
+
+	args, err := PreviewCommand(bwrapArgs, shellCmd)
+	if err != nil {
+		return err
+	}
+	args, warning, err := limits.Wrap(args)
+	if err != nil {
+		return err
+	}
+	warn(warnf, warning)
+
+	execPath := args[0]
+	args[0] = filepath.Base(execPath) // argv[0] for syscall.Exec, conventionally the program name rather than its full path
 
-	return syscall.Exec(bwrapPath, args, os.Environ())
+	return syscall.Exec(execPath, args, os.Environ())
 }
 
 // ExecRun runs bwrap using exec.Command instead of syscall.Exec.
 // Unlike Exec, this keeps the parent process alive, which is necessary
-// when background goroutines (like ActiveTool proxies) need to keep running.
-func ExecRun(bwrapArgs []string, shellCmd []string) error {
+// when background goroutines (like ActiveTool proxies) need to keep running,
+// or when seccompFile needs to be inherited as an open file descriptor.
+//
+// limits and warnf behave as in Exec.
+func ExecRun(bwrapArgs []string, shellCmd []string, seccompFile *os.File, limits ResourceLimits, warnf warnFunc) error {
+	full, err := PreviewCommand(bwrapArgs, shellCmd)
+	if err != nil {
+		return err
+	}
+	full, applyLimits := limits.WrapForChild(full)
+
+	bwrapPath, args := full[0], full[1:]
+
+	cmd := exec.Command(bwrapPath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if seccompFile != nil {
+		cmd.ExtraFiles = []*os.File{seccompFile}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Apply the direct-cgroup fallback (if in play) to the child we just
+	// started, not this process - see WrapForChild.
+	warn(warnf, applyLimits(cmd.Process.Pid))
+
+	return cmd.Wait()
+}
+
+// PastaNamespaceError indicates that pasta failed to set up its network
+// namespace, typically because the host environment can't grant it the
+// capabilities it needs (nested containers, missing /dev/net/tun, etc.).
+// Callers can match on this type to decide whether to fall back to a
+// non-isolated network provider instead of failing outright.
+type PastaNamespaceError struct {
+	// Reason is a short, actionable explanation of the likely cause.
+	Reason string
+	// Err is the underlying error returned by pasta.
+	Err error
+}
+
+func (e *PastaNamespaceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+}
+
+func (e *PastaNamespaceError) Unwrap() error {
+	return e.Err
+}
+
+// pastaFailureSignatures maps substrings commonly found in pasta's stderr
+// output to a clearer, actionable explanation of the underlying cause.
+var pastaFailureSignatures = []struct {
+	substring string
+	reason    string
+}{
+	{"/dev/net/tun", "proxy mode requires /dev/net/tun, which is unavailable here; try running outside a container, or add --device /dev/net/tun to the outer container"},
+	{"No such device", "proxy mode requires a tun device, which is unavailable here; try running outside a container, or add --device /dev/net/tun to the outer container"},
+	{"Operation not permitted", "proxy mode requires CAP_NET_ADMIN or a tun device; try running outside a container, or add --cap-add NET_ADMIN to the outer container"},
+	{"Permission denied", "proxy mode requires CAP_NET_ADMIN or a tun device; try running outside a container, or add --cap-add NET_ADMIN to the outer container"},
+	{"unshare(CLONE_NEWNET)", "proxy mode requires creating a network namespace, which this environment doesn't allow (often the case in nested containers); try running outside a container"},
+	{"iptables", "proxy mode requires iptables/nft support inside the namespace, which is unavailable here; try running outside a container"},
+}
+
+// terminateOnCancel makes cmd, when run via exec.CommandContext, respond to
+// context cancellation with SIGTERM instead of exec.Cmd's default SIGKILL -
+// giving the sandboxed process (and whatever it's wrapping here: pasta or the
+// slirp4netns unshare script) a chance to exit cleanly, e.g. for
+// --on-block=fail-fast. WaitDelay bounds how long Go waits for that before
+// escalating to a kill on its own.
+func terminateOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 5 * time.Second
+}
+
+// translatePastaError inspects pasta's stderr output for known failure
+// signatures and, if one matches, wraps err in a PastaNamespaceError with an
+// actionable message. If nothing matches, err is returned unchanged.
+func translatePastaError(err error, stderr string) error {
+	if err == nil {
+		return nil
+	}
+	for _, sig := range pastaFailureSignatures {
+		if strings.Contains(stderr, sig.substring) {
+			return &PastaNamespaceError{Reason: sig.reason, Err: err}
+		}
+	}
+	return err
+}
+
+// PreviewSlirpCommand returns the full unshare-wrapped bwrap invocation that
+// ExecWithSlirp would run, without running it. Used by --dry-run to print
+// the command instead of executing it.
+func PreviewSlirpCommand(bwrapArgs []string, shellCmd []string, extraRules []string) ([]string, error) {
 	bwrapPath, err := embed.BwrapPath()
 	if err != nil {
-		return fmt.Errorf("bwrap not available: %w", err)
+		return nil, fmt.Errorf("bwrap not available: %w", err)
 	}
 
-	args := make([]string, 0, len(bwrapArgs)+len(shellCmd)+2)
+	unsharePath, err := exec.LookPath("unshare")
+	if err != nil {
+		return nil, fmt.Errorf("unshare not available (required for slirp4netns fallback): %w", err)
+	}
+
+	slirpPath, err := exec.LookPath("slirp4netns")
+	if err != nil {
+		return nil, fmt.Errorf("slirp4netns not available: %w", err)
+	}
+
+	var extraRulesScript string
+	for _, rule := range extraRules {
+		extraRulesScript += rule + " 2>/dev/null\n\t\t"
+	}
+	wrapperScript := fmt.Sprintf(`
+		%s --configure --mtu=65520 "$$" tap0 >/dev/null 2>&1 &
+		slirp_pid=$!
+		for i in $(seq 1 50); do
+			ip link show tap0 >/dev/null 2>&1 && break
+			sleep 0.1
+		done
+		ip route add %s/32 dev tap0 2>/dev/null
+		%sip route del default 2>/dev/null
+		"$@"
+		status=$?
+		kill "$slirp_pid" 2>/dev/null
+		exit $status
+	`, slirpPath, network.SlirpGatewayIP, extraRulesScript)
+
+	args := make([]string, 0, len(bwrapArgs)+len(shellCmd)+9)
+	args = append(args, unsharePath)
+	args = append(args, "--net", "--")
+	args = append(args, "sh", "-c", wrapperScript, "_")
+	args = append(args, bwrapPath)
 	args = append(args, bwrapArgs...)
 	args = append(args, "--")
 	args = append(args, shellCmd...)
 
-	cmd := exec.Command(bwrapPath, args...)
+	return args, nil
+}
+
+// ExecWithSlirp wraps bwrap execution inside slirp4netns for network namespace
+// isolation, as a fallback for distros that don't package pasta. Like
+// ExecWithPasta, it restricts the namespace to proxy-only access: traffic can
+// reach the gateway IP (which maps to the host's loopback) but the default
+// route is removed so direct connections elsewhere fail.
+//
+// Unlike pasta, slirp4netns doesn't create the network namespace itself or
+// supervise a child process inside it - it attaches to an already-running
+// process's namespace by PID. So the wrapper script here creates the
+// namespace with unshare, backgrounds slirp4netns pointed at its own PID
+// ($$), waits for the tap device to come up, then runs bwrap as a foreground
+// child (rather than exec'ing it, as ExecWithPasta's wrapper does) so the
+// script can kill slirp4netns once bwrap exits instead of leaking it.
+//
+// The extraRules and seccompFile parameters behave the same as in
+// ExecWithPasta. There is no portForwardArgs equivalent: slirp4netns's port
+// forwarding mechanism doesn't map onto pasta's -t/-u/-T/-U flags, so port
+// forwarding isn't supported under this fallback.
+//
+// ctx cancellation sends SIGTERM to the wrapper script (see terminateOnCancel),
+// e.g. for --on-block=fail-fast.
+//
+// limits and warnf behave as in Exec.
+func ExecWithSlirp(ctx context.Context, bwrapArgs []string, shellCmd []string, extraRules []string, seccompFile *os.File, limits ResourceLimits, warnf warnFunc) error {
+	full, err := PreviewSlirpCommand(bwrapArgs, shellCmd, extraRules)
+	if err != nil {
+		return err
+	}
+	full, applyLimits := limits.WrapForChild(full)
+
+	unsharePath, args := full[0], full[1:]
+
+	cmd := exec.CommandContext(ctx, unsharePath, args...)
+	terminateOnCancel(cmd)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Env = os.Environ()
+	if seccompFile != nil {
+		cmd.ExtraFiles = []*os.File{seccompFile}
+	}
 
-	return cmd.Run()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Apply the direct-cgroup fallback (if in play) to the child we just
+	// started, not this process - see WrapForChild.
+	warn(warnf, applyLimits(cmd.Process.Pid))
+
+	return cmd.Wait()
 }
 
-// ExecWithPasta wraps bwrap execution inside pasta for network namespace isolation.
-// This creates an isolated network namespace where all traffic must go through
-// pasta's gateway, which we configure to route through our proxy.
+// PreviewPastaCommand returns the full pasta-wrapped bwrap invocation that
+// ExecWithPasta would run, without running it. Used by --dry-run to print
+// the command instead of executing it.
 //
-// The portForwardArgs parameter accepts pasta port forwarding arguments (e.g., -t, -u, -T, -U).
-// Pass nil if no port forwarding is needed.
+// pasta --config-net [--map-host-loopback <gatewayIP>] -f -- sh -c '...' _ bwrap [args] -- shell
 //
-// Unlike the regular Exec function, this uses exec.Command instead of syscall.Exec
-// so that the calling process (and its proxy server goroutine) stays alive.
-func ExecWithPasta(bwrapArgs []string, shellCmd []string, portForwardArgs []string) error {
+// --config-net: Configure tap interface in namespace (required for network to work)
+// --map-host-loopback <gatewayIP>: Map gatewayIP to host's 127.0.0.1 (for proxy access)
+//
+//	Note: This option is not available in older pasta versions (pre-2023)
+//
+// -f: Run in foreground (pasta exits when child exits)
+//
+// gatewayIP is the address the proxy is reachable at from inside the
+// namespace; callers pass network.Provider.GatewayIP() (network.PastaGatewayIP
+// if empty, for callers that haven't resolved a provider).
+//
+// The wrapper script restricts network to proxy-only:
+// 1. Add a host route to gateway via the tap device
+// 2. Run any operator-configured extra rules (network.extra_rules)
+// 3. Delete the default IPv4 and IPv6 routes to block direct internet access
+// This forces all traffic through our proxy - direct connections to external IPs (v4 or v6)
+// will fail, except for whatever the extra rules above carved out. The proxy itself is only
+// ever reached over the IPv4 gateway, but it CONNECTs/forwards to IPv6 destinations just fine.
+func PreviewPastaCommand(bwrapArgs []string, shellCmd []string, portForwardArgs []string, extraRules []string, gatewayIP string) ([]string, error) {
+	if gatewayIP == "" {
+		gatewayIP = network.PastaGatewayIP
+	}
+
 	pastaPath, err := embed.PastaPath()
 	if err != nil {
-		return fmt.Errorf("pasta not available (required for proxy mode): %w\nRun 'devsandbox doctor' for details", err)
+		return nil, fmt.Errorf("pasta not available (required for proxy mode): %w\nRun 'devsandbox doctor' for details", err)
 	}
 
 	bwrapPath, err := embed.BwrapPath()
 	if err != nil {
-		return fmt.Errorf("bwrap not available: %w", err)
-	}
-
-	// Build pasta command with network isolation:
-	// pasta --config-net [--map-host-loopback 10.0.2.2] -f -- sh -c '...' _ bwrap [args] -- shell
-	//
-	// --config-net: Configure tap interface in namespace (required for network to work)
-	// --map-host-loopback 10.0.2.2: Map 10.0.2.2 to host's 127.0.0.1 (for proxy access)
-	//   Note: This option is not available in older pasta versions (pre-2023)
-	// -f: Run in foreground (pasta exits when child exits)
-	//
-	// The wrapper script restricts network to proxy-only:
-	// 1. Add a host route to gateway via the tap device
-	// 2. Delete the default route to block direct internet access
-	// This forces all traffic through our proxy - direct connections to external IPs will fail.
+		return nil, fmt.Errorf("bwrap not available: %w", err)
+	}
+
+	var extraRulesScript string
+	for _, rule := range extraRules {
+		extraRulesScript += rule + " 2>/dev/null\n\t\t"
+	}
 	wrapperScript := fmt.Sprintf(`
 		dev=$(ip -o route show default | awk '{print $5}')
 		ip route add %s/32 dev "$dev" 2>/dev/null
-		ip route del default 2>/dev/null
+		%sip route del default 2>/dev/null
+		ip -6 route del default 2>/dev/null
 		exec "$@"
-	`, network.PastaGatewayIP)
+	`, gatewayIP, extraRulesScript)
 
-	args := make([]string, 0, len(bwrapArgs)+len(shellCmd)+len(portForwardArgs)+16)
+	args := make([]string, 0, len(bwrapArgs)+len(shellCmd)+len(portForwardArgs)+17)
+	args = append(args, pastaPath)
 	args = append(args, "--config-net") // Configure network interface
 
 	// Use --map-host-loopback if supported.
@@ -118,7 +356,7 @@ func ExecWithPasta(bwrapArgs []string, shellCmd []string, portForwardArgs []stri
 		supportsMapHostLoopback = pastaSupportsMapHostLoopback(pastaPath)
 	}
 	if supportsMapHostLoopback {
-		args = append(args, "--map-host-loopback", network.PastaGatewayIP)
+		args = append(args, "--map-host-loopback", gatewayIP)
 	}
 
 	// Add port forwarding arguments
@@ -132,13 +370,73 @@ func ExecWithPasta(bwrapArgs []string, shellCmd []string, portForwardArgs []stri
 	args = append(args, "--")
 	args = append(args, shellCmd...)
 
+	return args, nil
+}
+
+// ExecWithPasta wraps bwrap execution inside pasta for network namespace isolation.
+// This creates an isolated network namespace where all traffic must go through
+// pasta's gateway, which we configure to route through our proxy.
+//
+// The portForwardArgs parameter accepts pasta port forwarding arguments (e.g., -t, -u, -T, -U).
+// Pass nil if no port forwarding is needed.
+//
+// The extraRules parameter accepts additional "ip" subcommands (see
+// config.NetworkConfig.ExtraRules) that run before the default route is torn
+// down, for setups that need to reach something besides the proxy directly.
+// Callers must validate these against config.validateNetwork's allowlist
+// before passing them in - ExecWithPasta interpolates them into a shell
+// script as-is.
+//
+// seccompFile, if non-nil, is the read end of a compiled seccomp BPF program
+// that the bwrap args reference via "--seccomp 3" (see
+// sandbox.Builder.Seccomp) and is passed to bwrap as its sole extra file
+// descriptor.
+//
+// Unlike the regular Exec function, this uses exec.Command instead of syscall.Exec
+// so that the calling process (and its proxy server goroutine) stays alive.
+//
+// ctx cancellation sends SIGTERM to pasta (see terminateOnCancel), e.g. for
+// --on-block=fail-fast.
+//
+// gatewayIP is forwarded to PreviewPastaCommand; see its doc comment.
+//
+// limits and warnf behave as in Exec.
+func ExecWithPasta(ctx context.Context, bwrapArgs []string, shellCmd []string, portForwardArgs []string, extraRules []string, gatewayIP string, seccompFile *os.File, limits ResourceLimits, warnf warnFunc) error {
+	full, err := PreviewPastaCommand(bwrapArgs, shellCmd, portForwardArgs, extraRules, gatewayIP)
+	if err != nil {
+		return err
+	}
+	full, applyLimits := limits.WrapForChild(full)
+
+	pastaPath, args := full[0], full[1:]
+
 	// Use exec.Command instead of syscall.Exec so the parent process stays alive
 	// This is necessary because we have a proxy server goroutine running
-	cmd := exec.Command(pastaPath, args...)
+	cmd := exec.CommandContext(ctx, pastaPath, args...)
+	terminateOnCancel(cmd)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+
+	// Tee stderr to the terminal (so the user still sees pasta's own output)
+	// while also capturing it, so a failure can be matched against known
+	// namespace-setup failure signatures.
+	var stderr bytes.Buffer
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 	cmd.Env = os.Environ()
+	if seccompFile != nil {
+		cmd.ExtraFiles = []*os.File{seccompFile}
+	}
 
-	return cmd.Run()
+	if err := cmd.Start(); err != nil {
+		return translatePastaError(err, stderr.String())
+	}
+	// Apply the direct-cgroup fallback (if in play) to pasta's own PID, not
+	// this process - see WrapForChild. pasta's child bwrap inherits the
+	// same cgroup at fork, same as it would inherit any other cgroup.
+	warn(warnf, applyLimits(cmd.Process.Pid))
+
+	if err := cmd.Wait(); err != nil {
+		return translatePastaError(err, stderr.String())
+	}
+	return nil
 }
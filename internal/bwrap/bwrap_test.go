@@ -1,6 +1,7 @@
 package bwrap
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -22,3 +23,54 @@ func TestPastaSupportsMapHostLoopback(t *testing.T) {
 		t.Error("pastaSupportsMapHostLoopback should return false for nonexistent path")
 	}
 }
+
+func TestTranslatePastaError_NilError(t *testing.T) {
+	if err := translatePastaError(nil, "anything"); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestTranslatePastaError_UnknownSignature(t *testing.T) {
+	base := errors.New("exit status 1")
+	err := translatePastaError(base, "some unrelated failure output")
+
+	if !errors.Is(err, base) {
+		t.Errorf("expected original error to be preserved, got %v", err)
+	}
+	var nsErr *PastaNamespaceError
+	if errors.As(err, &nsErr) {
+		t.Error("expected no PastaNamespaceError for an unrecognized signature")
+	}
+}
+
+func TestTranslatePastaError_KnownSignatures(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+	}{
+		{"missing tun device", "Failed to open /dev/net/tun: No such file or directory"},
+		{"no such device", "tun: No such device"},
+		{"permission denied", "unshare: Permission denied"},
+		{"operation not permitted", "unshare(CLONE_NEWNET): Operation not permitted"},
+		{"unshare failure", "Failed to unshare(CLONE_NEWNET): Operation not permitted"},
+		{"iptables unavailable", "Failed to set up iptables rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := errors.New("exit status 1")
+			err := translatePastaError(base, tt.stderr)
+
+			var nsErr *PastaNamespaceError
+			if !errors.As(err, &nsErr) {
+				t.Fatalf("expected PastaNamespaceError for stderr %q, got %v", tt.stderr, err)
+			}
+			if nsErr.Reason == "" {
+				t.Error("expected a non-empty Reason")
+			}
+			if !errors.Is(err, base) {
+				t.Error("expected the underlying error to be preserved via Unwrap")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package bwrap
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"devsandbox/internal/embed"
+)
+
+// Features describes the capabilities of the effective bwrap binary
+// (embedded or system), as detected by parsing `bwrap --version` and
+// probing `bwrap --help` for flag support. Different distros ship
+// different bwrap versions, and some flags (like --overlay) are only
+// available in newer releases.
+type Features struct {
+	// Version is the bwrap version string (e.g. "0.8.0"), or empty if
+	// it could not be determined.
+	Version string
+
+	// SupportsOverlay is true if --overlay/--ro-overlay/--tmp-overlay
+	// are available.
+	SupportsOverlay bool
+
+	// SupportsTmpfsSize is true if --size is available for --tmpfs.
+	SupportsTmpfsSize bool
+
+	// SupportsBindFD is true if --bind-fd is available.
+	SupportsBindFD bool
+}
+
+var (
+	featuresOnce  sync.Once
+	featuresCache Features
+	featuresErr   error
+)
+
+// DetectFeatures inspects the effective bwrap binary and returns its
+// supported features. The result is cached for the lifetime of the
+// process, since the effective bwrap binary cannot change mid-run.
+func DetectFeatures() (Features, error) {
+	featuresOnce.Do(func() {
+		featuresCache, featuresErr = detectFeatures()
+	})
+	return featuresCache, featuresErr
+}
+
+func detectFeatures() (Features, error) {
+	bwrapPath, err := embed.BwrapPath()
+	if err != nil {
+		return Features{}, fmt.Errorf("bwrap not available: %w", err)
+	}
+
+	help, err := exec.Command(bwrapPath, "--help").CombinedOutput()
+	if err != nil {
+		return Features{}, fmt.Errorf("bwrap --help failed: %w", err)
+	}
+	helpText := string(help)
+
+	return Features{
+		Version:           bwrapVersion(bwrapPath),
+		SupportsOverlay:   strings.Contains(helpText, "--overlay"),
+		SupportsTmpfsSize: strings.Contains(helpText, "--size"),
+		SupportsBindFD:    strings.Contains(helpText, "--bind-fd"),
+	}, nil
+}
+
+// bwrapVersion parses the version reported by `bwrap --version`, which
+// prints a single line like "bubblewrap 0.8.0". Returns "" if it cannot
+// be determined.
+func bwrapVersion(bwrapPath string) string {
+	out, err := exec.Command(bwrapPath, "--version").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimSpace(string(out)))
+	if len(fields) != 2 {
+		return ""
+	}
+	return fields[1]
+}
@@ -0,0 +1,25 @@
+package bwrap
+
+import "testing"
+
+func TestDetectFeatures(t *testing.T) {
+	// Don't assert specific feature values -- depends on the bwrap version
+	// installed in the test environment. Just verify it doesn't panic and
+	// is cached across calls.
+	f1, err1 := DetectFeatures()
+	f2, err2 := DetectFeatures()
+
+	if err1 != err2 {
+		t.Errorf("DetectFeatures() error not stable across calls: %v, %v", err1, err2)
+	}
+	if f1 != f2 {
+		t.Errorf("DetectFeatures() result not stable across calls: %+v, %+v", f1, f2)
+	}
+	t.Logf("DetectFeatures() = %+v, err = %v", f1, err1)
+}
+
+func TestBwrapVersion_NonexistentPath(t *testing.T) {
+	if v := bwrapVersion("/nonexistent/bwrap"); v != "" {
+		t.Errorf("bwrapVersion(nonexistent) = %q, want empty", v)
+	}
+}
@@ -0,0 +1,260 @@
+package bwrap
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is the standard cgroup v2 mount point. Overridable in tests.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// ResourceLimits caps the sandboxed process's memory and CPU usage.
+// Bubblewrap has no resource-limiting of its own, so these are enforced by
+// placing the process in a cgroup v2 slice before exec: a transient systemd
+// "--user --scope" when systemd is available (see Wrap), falling back to
+// writing a cgroup directly otherwise (see applyToSelf). Neither mechanism
+// is guaranteed to exist - older kernels, systems without cgroup v2, or a
+// missing user session - so a host that can't enforce either is left
+// unlimited rather than failing the sandbox run.
+type ResourceLimits struct {
+	// Memory is a systemd MemoryMax-style value, e.g. "2G", "512M". Empty
+	// means unlimited.
+	Memory string
+	// CPUs caps CPU usage in number of cores, e.g. 1.5 for one and a half
+	// cores. Converted to a CPUQuota percentage (cgroup cpu.max) under the
+	// hood. Zero or negative means unlimited.
+	CPUs float64
+}
+
+// IsZero reports whether no limits are configured.
+func (r ResourceLimits) IsZero() bool {
+	return r.Memory == "" && r.CPUs <= 0
+}
+
+// Wrap prepends a transient "systemd-run --user --scope" wrapper enforcing
+// these limits to args (the full exec argv this process is about to run -
+// bwrap directly, or one of the pasta/slirp4netns wrapper scripts around
+// it), when systemd-run and a usable user session are available. Otherwise
+// it falls back to placing the calling process directly into a cgroup with
+// the same limits via applyToSelf and returns args unchanged: a process
+// inherits its parent's cgroup across both syscall.Exec and exec.Command,
+// so this covers every Exec* variant in this package without needing to be
+// wrapped itself.
+//
+// If limits are configured but neither mechanism is usable, args is
+// returned unchanged along with a non-empty warning describing why - the
+// caller is expected to log it, since running unlimited is a silent
+// downgrade from what was asked for.
+func (r ResourceLimits) Wrap(args []string) (wrapped []string, warning string, err error) {
+	if r.IsZero() {
+		return args, "", nil
+	}
+
+	if prefix, ok := r.systemdRunArgs(); ok {
+		return append(prefix, args...), "", nil
+	}
+
+	if applyErr := r.applyToSelf(); applyErr != nil {
+		return args, fmt.Sprintf("resource limits: %v; running without memory/CPU limits", applyErr), nil
+	}
+
+	return args, "", nil
+}
+
+// WrapForChild is like Wrap, but for callers that run the limited process
+// as a separate child (ExecRun, ExecWithSlirp, ExecWithPasta) rather than
+// replacing themselves via syscall.Exec. Applying the direct-cgroup
+// fallback to os.Getpid() before that child exists would put the calling
+// process itself - the long-lived devsandbox orchestrator, proxy server
+// and all - under the limit, not just the child being sandboxed. So when
+// the fallback is in play, it's deferred to the returned applyLimits
+// function, which the caller must invoke with the child's PID once it's
+// running (e.g. right after cmd.Start()) instead of before starting it.
+//
+// If systemd-run is usable, it already scopes the limits to the wrapped
+// child only, so applyLimits is a no-op in that case; same if no limits
+// are configured at all.
+func (r ResourceLimits) WrapForChild(args []string) (wrapped []string, applyLimits func(pid int) (warning string)) {
+	noop := func(int) string { return "" }
+
+	if r.IsZero() {
+		return args, noop
+	}
+
+	if prefix, ok := r.systemdRunArgs(); ok {
+		return append(prefix, args...), noop
+	}
+
+	apply := func(pid int) string {
+		if applyErr := r.applyToPID(pid); applyErr != nil {
+			return fmt.Sprintf("resource limits: %v; running without memory/CPU limits", applyErr)
+		}
+		return ""
+	}
+	return args, apply
+}
+
+// Preview returns args with the systemd-run wrapper prepended, if one would
+// be used to enforce r (see Wrap) - without the direct-cgroup fallback's
+// side effects (creating cgroups, moving the calling process). Used by
+// --dry-run, which must not mutate host state just to print a command.
+func (r ResourceLimits) Preview(args []string) []string {
+	if r.IsZero() {
+		return args
+	}
+	if prefix, ok := r.systemdRunArgs(); ok {
+		return append(prefix, args...)
+	}
+	return args
+}
+
+// systemdRunArgs returns the "systemd-run --user --scope ..." argv prefix
+// enforcing r, and false if systemd-run isn't usable here (not installed,
+// or no user session to run a transient scope in).
+func (r ResourceLimits) systemdRunArgs() ([]string, bool) {
+	systemdRunPath, err := exec.LookPath("systemd-run")
+	if err != nil {
+		return nil, false
+	}
+	if os.Getenv("XDG_RUNTIME_DIR") == "" {
+		// No user session for --user to attach to (e.g. a minimal container
+		// or a login that never went through systemd-logind).
+		return nil, false
+	}
+
+	args := []string{systemdRunPath, "--user", "--scope", "--quiet"}
+	if r.Memory != "" {
+		args = append(args, "-p", "MemoryMax="+r.Memory)
+	}
+	if r.CPUs > 0 {
+		args = append(args, "-p", fmt.Sprintf("CPUQuota=%d%%", int(r.CPUs*100)))
+	}
+	return append(args, "--"), true
+}
+
+// applyToSelf enforces r by moving the calling process into a cgroup (see
+// applyToPID) - so that whatever it execs next (via syscall.Exec) inherits
+// the same cgroup and is bound by the same limits, without needing any
+// special handling at exec time. Used by Exec, which replaces the calling
+// process outright, so self is the right (and only) target.
+func (r ResourceLimits) applyToSelf() error {
+	return r.applyToPID(os.Getpid())
+}
+
+// applyToPID enforces r by creating a child cgroup under the calling
+// process's current cgroup, enabling the memory/cpu controllers, writing
+// memory.max/cpu.max, and moving pid into it. pid is typically either the
+// calling process's own (applyToSelf, for the syscall.Exec path) or an
+// already-started child's (WrapForChild, for callers that keep running
+// themselves alongside the process being limited). Used when systemd-run
+// isn't usable (see Wrap/WrapForChild).
+//
+// Returns an error describing why the limits couldn't be applied (no
+// cgroup v2, not enough permission, ...); callers should treat this as a
+// warning to surface, not a reason to fail the sandbox run.
+func (r ResourceLimits) applyToPID(pid int) error {
+	parent, err := currentCgroupPath()
+	if err != nil {
+		return fmt.Errorf("cgroup v2 not available: %w", err)
+	}
+
+	controllers, err := os.ReadFile(parent + "/cgroup.controllers")
+	if err != nil {
+		return fmt.Errorf("reading cgroup.controllers: %w", err)
+	}
+	for _, want := range []string{"memory", "cpu"} {
+		if !strings.Contains(string(controllers), want) {
+			return fmt.Errorf("cgroup controller %q not available under %s", want, parent)
+		}
+	}
+
+	if err := os.WriteFile(parent+"/cgroup.subtree_control", []byte("+memory +cpu"), 0o644); err != nil {
+		return fmt.Errorf("enabling memory/cpu controllers: %w", err)
+	}
+
+	child := parent + "/devsandbox-" + strconv.Itoa(pid)
+	if err := os.Mkdir(child, 0o755); err != nil {
+		return fmt.Errorf("creating cgroup: %w", err)
+	}
+
+	if r.Memory != "" {
+		max, err := parseMemoryMax(r.Memory)
+		if err != nil {
+			return fmt.Errorf("parsing memory limit %q: %w", r.Memory, err)
+		}
+		if err := os.WriteFile(child+"/memory.max", []byte(strconv.FormatInt(max, 10)), 0o644); err != nil {
+			return fmt.Errorf("writing memory.max: %w", err)
+		}
+	}
+
+	if r.CPUs > 0 {
+		// cpu.max is "<quota> <period>", both in microseconds: the cgroup
+		// may use up to quota/period cores over each period.
+		const period = 100000
+		quota := int(r.CPUs * period)
+		cpuMax := fmt.Sprintf("%d %d", quota, period)
+		if err := os.WriteFile(child+"/cpu.max", []byte(cpuMax), 0o644); err != nil {
+			return fmt.Errorf("writing cpu.max: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(child+"/cgroup.procs", []byte(strconv.Itoa(pid)), 0o644); err != nil {
+		return fmt.Errorf("moving process into cgroup: %w", err)
+	}
+
+	return nil
+}
+
+// currentCgroupPath returns the absolute path under cgroupRoot of the
+// calling process's own cgroup v2 membership, by reading /proc/self/cgroup.
+// Fails if the host isn't using the unified (v2) cgroup hierarchy, where
+// that file has exactly one "0::<path>" line.
+func currentCgroupPath() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(data))
+	prefix, path, ok := strings.Cut(line, "::")
+	if !ok || prefix != "0" || strings.Contains(line, "\n") {
+		return "", fmt.Errorf("host is not using the cgroup v2 unified hierarchy")
+	}
+
+	return cgroupRoot + path, nil
+}
+
+// parseMemoryMax converts a systemd MemoryMax-style value ("2G", "512M",
+// "1024K", or a bare byte count) into a byte count for cgroup v2's
+// memory.max.
+func parseMemoryMax(value string) (int64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("empty value")
+	}
+
+	multiplier := int64(1)
+	numeric := value
+	switch last := value[len(value)-1]; last {
+	case 'K', 'k':
+		multiplier = 1 << 10
+		numeric = value[:len(value)-1]
+	case 'M', 'm':
+		multiplier = 1 << 20
+		numeric = value[:len(value)-1]
+	case 'G', 'g':
+		multiplier = 1 << 30
+		numeric = value[:len(value)-1]
+	case 'T', 't':
+		multiplier = 1 << 40
+		numeric = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(numeric, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number optionally suffixed with K/M/G/T, got %q", value)
+	}
+	return n * multiplier, nil
+}
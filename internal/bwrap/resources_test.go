@@ -0,0 +1,79 @@
+package bwrap
+
+import "testing"
+
+func TestResourceLimits_IsZero(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits ResourceLimits
+		want   bool
+	}{
+		{"empty", ResourceLimits{}, true},
+		{"memory only", ResourceLimits{Memory: "2G"}, false},
+		{"cpus only", ResourceLimits{CPUs: 1.5}, false},
+		{"negative cpus still zero", ResourceLimits{CPUs: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.IsZero(); got != tt.want {
+				t.Errorf("IsZero() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceLimits_Wrap_NoLimits(t *testing.T) {
+	args := []string{"bwrap", "--ro-bind", "/", "/"}
+	wrapped, warning, err := ResourceLimits{}.Wrap(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if warning != "" {
+		t.Errorf("expected no warning, got %q", warning)
+	}
+	if len(wrapped) != len(args) || wrapped[0] != args[0] {
+		t.Errorf("expected args unchanged, got %v", wrapped)
+	}
+}
+
+func TestResourceLimits_Preview_NoLimits(t *testing.T) {
+	args := []string{"bwrap", "--ro-bind", "/", "/"}
+	if got := (ResourceLimits{}).Preview(args); len(got) != len(args) {
+		t.Errorf("expected args unchanged, got %v", got)
+	}
+}
+
+func TestParseMemoryMax(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"1K", 1 << 10, false},
+		{"2M", 2 << 20, false},
+		{"2G", 2 << 30, false},
+		{"1T", 1 << 40, false},
+		{"", 0, true},
+		{"nope", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseMemoryMax(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseMemoryMax(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -325,6 +325,36 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid loopback metrics_addr",
+			cfg: &Config{
+				Proxy: ProxyConfig{MetricsAddr: "127.0.0.1:9090"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid localhost metrics_addr",
+			cfg: &Config{
+				Proxy: ProxyConfig{MetricsAddr: "localhost:9090"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "metrics_addr rejects non-loopback host",
+			cfg: &Config{
+				Proxy: ProxyConfig{MetricsAddr: "0.0.0.0:9090"},
+			},
+			wantErr: true,
+			errMsg:  "must bind to loopback",
+		},
+		{
+			name: "metrics_addr rejects missing port",
+			cfg: &Config{
+				Proxy: ProxyConfig{MetricsAddr: "127.0.0.1"},
+			},
+			wantErr: true,
+			errMsg:  "must be a \"host:port\" address",
+		},
 		{
 			name: "negative ask timeout",
 			cfg: &Config{
@@ -421,6 +451,87 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "action must be",
 		},
+		{
+			name: "invalid redact body pattern",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					Redact: ProxyRedactConfig{BodyPatterns: []string{"("}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid regex",
+		},
+		{
+			name: "valid redact config",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					Redact: ProxyRedactConfig{
+						Headers:      []string{"X-Internal-Token"},
+						BodyPatterns: []string{`"password"\s*:\s*"[^"]*"`},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty response rewrite host",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					ResponseRewrite: []ProxyResponseRewriteRule{
+						{Host: "", Pattern: "x", Replacement: "y"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "host cannot be empty",
+		},
+		{
+			name: "invalid response rewrite host pattern",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					ResponseRewrite: []ProxyResponseRewriteRule{
+						{Host: "[", Pattern: "x", Replacement: "y"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid pattern",
+		},
+		{
+			name: "empty response rewrite pattern",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					ResponseRewrite: []ProxyResponseRewriteRule{
+						{Host: "*.example.com", Pattern: "", Replacement: "y"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "pattern cannot be empty",
+		},
+		{
+			name: "invalid response rewrite regex",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					ResponseRewrite: []ProxyResponseRewriteRule{
+						{Host: "*.example.com", Pattern: "(", Replacement: "y"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid regex",
+		},
+		{
+			name: "valid response rewrite rule",
+			cfg: &Config{
+				Proxy: ProxyConfig{
+					ResponseRewrite: []ProxyResponseRewriteRule{
+						{Host: "api.example.com", Pattern: `https://api\.example\.com`, Replacement: "http://localhost:8080"},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid isolation backend",
 			cfg: &Config{
@@ -494,6 +605,174 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid docker cpu limit",
 		},
+		{
+			name: "valid seccomp profile strict",
+			cfg: &Config{
+				Sandbox: SandboxConfig{SeccompProfile: "strict"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid seccomp profile",
+			cfg: &Config{
+				Sandbox: SandboxConfig{SeccompProfile: "paranoid"},
+			},
+			wantErr: true,
+			errMsg:  "invalid sandbox.seccomp_profile",
+		},
+		{
+			name: "empty blocked path",
+			cfg: &Config{
+				Security: SecurityConfig{BlockedPaths: []string{""}},
+			},
+			wantErr: true,
+			errMsg:  "cannot be empty",
+		},
+		{
+			name: "valid blocked paths",
+			cfg: &Config{
+				Security: SecurityConfig{BlockedPaths: []string{"~/.kube", ".env.local", "secrets.yaml"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid source date epoch literal",
+			cfg: &Config{
+				Sandbox: SandboxConfig{SourceDateEpoch: "1700000000"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid source date epoch git",
+			cfg: &Config{
+				Sandbox: SandboxConfig{SourceDateEpoch: "git"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid source date epoch",
+			cfg: &Config{
+				Sandbox: SandboxConfig{SourceDateEpoch: "not-a-timestamp"},
+			},
+			wantErr: true,
+			errMsg:  "invalid sandbox.source_date_epoch",
+		},
+		{
+			name: "valid network extra rule",
+			cfg: &Config{
+				Network: NetworkConfig{
+					ExtraRules: []string{"ip route add 10.1.2.0/24 via 10.0.2.2"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "network extra rule with shell metacharacters rejected",
+			cfg: &Config{
+				Network: NetworkConfig{
+					ExtraRules: []string{"ip route add 10.1.2.0/24 via 10.0.2.2; rm -rf /"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "network.extra_rules[0]",
+		},
+		{
+			name: "network extra rule not starting with ip rejected",
+			cfg: &Config{
+				Network: NetworkConfig{
+					ExtraRules: []string{"iptables -A INPUT -j DROP"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "network.extra_rules[0]",
+		},
+		{
+			name: "valid network allow direct",
+			cfg: &Config{
+				Network: NetworkConfig{
+					AllowDirect: []string{"db.internal:5432"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "network allow direct missing port",
+			cfg: &Config{
+				Network: NetworkConfig{
+					AllowDirect: []string{"db.internal"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "network.allow_direct[0]",
+		},
+		{
+			name: "network allow direct empty host",
+			cfg: &Config{
+				Network: NetworkConfig{
+					AllowDirect: []string{":5432"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "network.allow_direct[0]: host cannot be empty",
+		},
+		{
+			name: "network allow direct invalid port",
+			cfg: &Config{
+				Network: NetworkConfig{
+					AllowDirect: []string{"db.internal:notaport"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "network.allow_direct[0]: invalid port",
+		},
+		{
+			name: "empty bind source",
+			cfg: &Config{
+				Sandbox: SandboxConfig{
+					Mounts: MountsConfig{Binds: []MountBind{{Source: ""}}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "sandbox.mounts.binds[0].source cannot be empty",
+		},
+		{
+			name: "bind source does not exist",
+			cfg: &Config{
+				Sandbox: SandboxConfig{
+					Mounts: MountsConfig{Binds: []MountBind{{Source: "/no/such/devsandbox/test/path"}}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "not found",
+		},
+		{
+			name: "optional bind source does not exist",
+			cfg: &Config{
+				Sandbox: SandboxConfig{
+					Mounts: MountsConfig{Binds: []MountBind{{Source: "/no/such/devsandbox/test/path", Optional: true}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid bind source",
+			cfg: &Config{
+				Sandbox: SandboxConfig{
+					Mounts: MountsConfig{Binds: []MountBind{{Source: "/tmp", Dest: "/data", ReadOnly: true}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bind dest clobbers HOME",
+			cfg: &Config{
+				Sandbox: SandboxConfig{
+					Mounts: MountsConfig{Binds: []MountBind{{Source: "/tmp", Dest: mustUserHomeDir(t)}}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "would clobber $HOME",
+		},
 	}
 
 	for _, tt := range tests {
@@ -514,6 +793,35 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestSecurityConfig_EffectiveBlockedPaths(t *testing.T) {
+	t.Run("appends to defaults", func(t *testing.T) {
+		s := SecurityConfig{BlockedPaths: []string{"secrets.yaml"}}
+		got := s.EffectiveBlockedPaths()
+		if len(got) != len(defaultBlockedPaths)+1 {
+			t.Fatalf("got %d paths, want %d", len(got), len(defaultBlockedPaths)+1)
+		}
+		if got[len(got)-1] != "secrets.yaml" {
+			t.Errorf("last path = %q, want %q", got[len(got)-1], "secrets.yaml")
+		}
+	})
+
+	t.Run("replace_defaults uses only configured paths", func(t *testing.T) {
+		s := SecurityConfig{BlockedPaths: []string{"secrets.yaml"}, ReplaceDefaults: true}
+		got := s.EffectiveBlockedPaths()
+		if len(got) != 1 || got[0] != "secrets.yaml" {
+			t.Errorf("got %v, want [secrets.yaml]", got)
+		}
+	})
+
+	t.Run("empty config returns only defaults", func(t *testing.T) {
+		var s SecurityConfig
+		got := s.EffectiveBlockedPaths()
+		if len(got) != len(defaultBlockedPaths) {
+			t.Errorf("got %v, want %v", got, defaultBlockedPaths)
+		}
+	})
+}
+
 func TestValidatePath(t *testing.T) {
 	tests := []struct {
 		path    string
@@ -552,6 +860,14 @@ func containsAt(s, substr string, start int) bool {
 	return false
 }
 
+func mustUserHomeDir(t *testing.T) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("cannot determine home directory: %v", err)
+	}
+	return home
+}
+
 func TestLoadWithProjectDir_IncludeMatch(t *testing.T) {
 	tmpDir := t.TempDir()
 	configDir := filepath.Join(tmpDir, "config")
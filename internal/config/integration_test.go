@@ -120,6 +120,94 @@ action = "allow"
 	}
 }
 
+func TestLoadWithProjectDir_LocalConfigWalksUp_Integration(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+	subDir := filepath.Join(repoDir, "sub", "deeper")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("failed to create sub dir: %v", err)
+	}
+
+	globalPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(globalPath, []byte("[proxy]\nport = 8080\n"), 0644); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+
+	localPath := filepath.Join(repoDir, ".devsandbox.toml")
+	if err := os.WriteFile(localPath, []byte("[proxy]\nport = 7070\n"), 0644); err != nil {
+		t.Fatalf("failed to write local config: %v", err)
+	}
+
+	trustStore := &TrustStore{}
+	hash, err := HashFile(localPath)
+	if err != nil {
+		t.Fatalf("failed to hash local config: %v", err)
+	}
+	trustStore.AddTrust(repoDir, hash)
+
+	cfg, err := LoadWithProjectDir(globalPath, subDir, &LoadOptions{TrustStore: trustStore})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Proxy.Port != 7070 {
+		t.Errorf("expected port 7070 from local config found by walking up from %s, got %d", subDir, cfg.Proxy.Port)
+	}
+
+	if dir, found := FindLocalConfigDir(subDir); !found || dir != repoDir {
+		t.Errorf("FindLocalConfigDir(%s) = (%q, %v), want (%q, true)", subDir, dir, found, repoDir)
+	}
+	if _, found := FindLocalConfigDir(tmpDir); found {
+		t.Error("FindLocalConfigDir should not find a config above the directory that has one")
+	}
+}
+
+func TestMergeConfigs_FilterRulesMode_Integration(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+
+	globalPath := filepath.Join(tmpDir, "config.toml")
+	globalConfig := `
+[[proxy.filter.rules]]
+pattern = "*.global.example.com"
+action = "allow"
+`
+	if err := os.WriteFile(globalPath, []byte(globalConfig), 0644); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+
+	localPath := filepath.Join(projectDir, ".devsandbox.toml")
+	localConfig := `
+[proxy.filter]
+rules_mode = "replace"
+
+[[proxy.filter.rules]]
+pattern = "*.local.example.com"
+action = "allow"
+`
+	if err := os.WriteFile(localPath, []byte(localConfig), 0644); err != nil {
+		t.Fatalf("failed to write local config: %v", err)
+	}
+
+	trustStore := &TrustStore{}
+	hash, err := HashFile(localPath)
+	if err != nil {
+		t.Fatalf("failed to hash local config: %v", err)
+	}
+	trustStore.AddTrust(projectDir, hash)
+
+	cfg, err := LoadWithProjectDir(globalPath, projectDir, &LoadOptions{TrustStore: trustStore})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if len(cfg.Proxy.Filter.Rules) != 1 || cfg.Proxy.Filter.Rules[0].Pattern != "*.local.example.com" {
+		t.Errorf("rules_mode=replace should replace global rules entirely, got %+v", cfg.Proxy.Filter.Rules)
+	}
+}
+
 func TestUntrustedLocalConfig_Integration(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project")
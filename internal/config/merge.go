@@ -30,12 +30,23 @@ func mergeConfigs(base, overlay *Config) *Config {
 	if overlay.Proxy.Filter.AskTimeout != 0 {
 		result.Proxy.Filter.AskTimeout = overlay.Proxy.Filter.AskTimeout
 	}
+	if overlay.Proxy.Filter.AskVia != "" {
+		result.Proxy.Filter.AskVia = overlay.Proxy.Filter.AskVia
+	}
 	if overlay.Proxy.Filter.CacheDecisions != nil {
 		result.Proxy.Filter.CacheDecisions = overlay.Proxy.Filter.CacheDecisions
 	}
+	if overlay.Proxy.Filter.CacheTTL != "" {
+		result.Proxy.Filter.CacheTTL = overlay.Proxy.Filter.CacheTTL
+	}
 
-	// Rules: prepend overlay rules (higher priority)
-	if len(overlay.Proxy.Filter.Rules) > 0 {
+	// Rules: prepended (higher priority) by default, or replace the
+	// underlying rules entirely when overlay.Proxy.Filter.rules_mode is
+	// "replace" (e.g. a project wants a filter whitelist that isn't
+	// layered on top of the global one).
+	if overlay.Proxy.Filter.GetRulesMode() == "replace" {
+		result.Proxy.Filter.Rules = overlay.Proxy.Filter.Rules
+	} else if len(overlay.Proxy.Filter.Rules) > 0 {
 		result.Proxy.Filter.Rules = append(
 			overlay.Proxy.Filter.Rules,
 			result.Proxy.Filter.Rules...,
@@ -52,6 +63,9 @@ func mergeConfigs(base, overlay *Config) *Config {
 	if overlay.Sandbox.Isolation != "" {
 		result.Sandbox.Isolation = overlay.Sandbox.Isolation
 	}
+	if overlay.Sandbox.TrustHostCAs != nil {
+		result.Sandbox.TrustHostCAs = overlay.Sandbox.TrustHostCAs
+	}
 
 	// Sandbox Docker settings
 	if overlay.Sandbox.Docker.Dockerfile != "" {
@@ -75,6 +89,14 @@ func mergeConfigs(base, overlay *Config) *Config {
 		)
 	}
 
+	// Sandbox extra bind mounts: prepend overlay binds (higher priority)
+	if len(overlay.Sandbox.Mounts.Binds) > 0 {
+		result.Sandbox.Mounts.Binds = append(
+			overlay.Sandbox.Mounts.Binds,
+			result.Sandbox.Mounts.Binds...,
+		)
+	}
+
 	// Port forwarding settings
 	if overlay.PortForwarding.Enabled != nil {
 		result.PortForwarding.Enabled = overlay.PortForwarding.Enabled
@@ -91,6 +113,14 @@ func mergeConfigs(base, overlay *Config) *Config {
 		result.Overlay.Enabled = overlay.Overlay.Enabled
 	}
 
+	// Env passthrough: prepend overlay patterns (higher priority)
+	if len(overlay.Env.Passthrough) > 0 {
+		result.Env.Passthrough = append(
+			overlay.Env.Passthrough,
+			result.Env.Passthrough...,
+		)
+	}
+
 	// Proxy credentials: deep merge (same pattern as tools)
 	result.Proxy.Credentials = mergeToolsConfig(base.Proxy.Credentials, overlay.Proxy.Credentials)
 
@@ -250,3 +250,21 @@ func Test_mergeConfigs_DockerDockerfileNotOverriddenByEmpty(t *testing.T) {
 		t.Errorf("expected dockerfile preserved from base, got %q", result.Sandbox.Docker.Dockerfile)
 	}
 }
+
+func Test_mergeConfigs_EnvPassthroughConcat(t *testing.T) {
+	base := &Config{Env: EnvConfig{Passthrough: []string{"TERM"}}}
+	overlay := &Config{Env: EnvConfig{Passthrough: []string{"FOO_*"}}}
+
+	result := mergeConfigs(base, overlay)
+
+	if len(result.Env.Passthrough) != 2 {
+		t.Fatalf("expected 2 passthrough patterns, got %d", len(result.Env.Passthrough))
+	}
+	// Overlay patterns come first (higher priority)
+	if result.Env.Passthrough[0] != "FOO_*" {
+		t.Error("expected overlay pattern first")
+	}
+	if result.Env.Passthrough[1] != "TERM" {
+		t.Error("expected base pattern second")
+	}
+}
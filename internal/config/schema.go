@@ -0,0 +1,239 @@
+package config
+
+// JSONSchema returns a JSON Schema (draft-07) describing the devsandbox
+// config file, for editor autocompletion/validation of config.toml and
+// .devsandbox.toml. It's maintained by hand alongside Config and Validate -
+// there's no struct tag rich enough to derive enums like "host, path, url,
+// query, status, location_host" from reflection, so new fields and their
+// constraints need a matching addition here.
+func JSONSchema() map[string]any {
+	return map[string]any{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "devsandbox config",
+		"description": "Configuration for devsandbox (config.toml / .devsandbox.toml)",
+		"type":        "object",
+		"properties": map[string]any{
+			"proxy":           proxySchema(),
+			"sandbox":         sandboxSchema(),
+			"overlay":         map[string]any{"type": "object", "properties": map[string]any{"enabled": boolSchema("Master switch for overlay filesystem support")}},
+			"tools":           map[string]any{"type": "object", "description": "Per-tool configuration, keyed by tool name (e.g. tools.git, tools.mise)"},
+			"logging":         loggingSchema(),
+			"port_forwarding": portForwardingSchema(),
+			"network":         networkSchema(),
+			"include":         includeSchema(),
+			"env":             envSchema(),
+			"security": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"blocked_paths":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Glob patterns masked inside the sandbox, appended to the built-in credential paths"},
+					"replace_defaults": boolSchema("Use blocked_paths as the complete list instead of appending to the built-in defaults"),
+				},
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+func boolSchema(desc string) map[string]any {
+	return map[string]any{"type": "boolean", "description": desc}
+}
+
+func enumSchema(desc string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": desc, "enum": values}
+}
+
+func proxySchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"enabled":    boolSchema("Enable proxy mode by default"),
+			"port":       map[string]any{"type": "integer", "minimum": MinPort, "maximum": MaxPort, "description": "Default proxy server port"},
+			"socks":      boolSchema("Enable a SOCKS5 listener alongside the HTTP proxy"),
+			"socks_port": map[string]any{"type": "integer", "minimum": MinPort, "maximum": MaxPort, "description": "SOCKS5 listener port (default: port+1000)"},
+			"filter":     filterSchema(),
+			"redact": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"headers":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Additional header names to redact (case-insensitive)"},
+					"body_patterns": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Regexes; matches in logged bodies are replaced with ***REDACTED***"},
+				},
+			},
+			"ca": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"shared": boolSchema("Share a single CA across all projects instead of one per project")},
+			},
+			"grpc": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"descriptors": map[string]any{"type": "string", "description": "Path to a compiled FileDescriptorSet used to decode gRPC bodies to JSON"}},
+			},
+			"credentials": map[string]any{"type": "object", "description": "Per-injector credential settings, keyed by injector name (e.g. proxy.credentials.github)"},
+		},
+	}
+}
+
+func filterSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"default_action":  enumSchema("Action for requests that don't match any rule; setting this enables filtering", "allow", "block", "ask"),
+			"ask_timeout":     map[string]any{"type": "integer", "minimum": 0, "maximum": MaxAskTimeout, "description": "Timeout in seconds for ask mode decisions (default: 30)"},
+			"ask_via":         enumSchema("How ask mode decisions are collected (default: tty)", "tty", "notify"),
+			"cache_decisions": boolSchema("Cache ask mode decisions for the session (default: true)"),
+			"rules_mode":      enumSchema("How this config's rules combine with rules merged from underneath (default: append)", "append", "replace"),
+			"rules":           map[string]any{"type": "array", "items": filterRuleSchema()},
+		},
+	}
+}
+
+func filterRuleSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern":     map[string]any{"type": "string", "description": "Pattern to match (exact, glob, or regex)"},
+			"action":      enumSchema("What to do when the rule matches", "allow", "block", "ask", "mock"),
+			"scope":       enumSchema("What to match against (default: host)", "host", "path", "url", "query", "status", "location_host"),
+			"type":        enumSchema("Pattern matching type (default: glob; auto-detected as regex if the pattern has regex metacharacters)", "exact", "glob", "regex"),
+			"query_param": map[string]any{"type": "string", "description": "Query parameter name to match; required when scope is \"query\""},
+			"reason":      map[string]any{"type": "string", "description": "Shown when the rule blocks a request"},
+			"mock":        map[string]any{"type": "string", "description": "Path to a JSON file (status, headers, body) served as the response; required when action is \"mock\""},
+		},
+		"required": []string{"pattern", "action"},
+	}
+}
+
+func sandboxSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"base_path":         map[string]any{"type": "string", "description": "Directory where sandbox homes are stored (default: ~/.local/share/devsandbox)"},
+			"mounts":            map[string]any{"type": "object", "properties": map[string]any{"rules": map[string]any{"type": "array", "items": mountRuleSchema()}}},
+			"config_visibility": enumSchema("How .devsandbox.toml is exposed to the sandbox (default: hidden)", "hidden", "readonly", "readwrite"),
+			"isolation":         enumSchema("Isolation backend (default: auto)", "auto", "bwrap", "docker"),
+			"use_embedded":      boolSchema("Use embedded bwrap/pasta binaries instead of only system-installed ones (default: true)"),
+			"docker": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"dockerfile":     map[string]any{"type": "string", "description": "Path to the Dockerfile used to build the sandbox image"},
+					"keep_container": boolSchema("Keep the container after exit for fast restarts (default: true)"),
+					"resources": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"memory": map[string]any{"type": "string", "description": "Memory limit, e.g. \"512m\", \"2g\""},
+							"cpus":   map[string]any{"type": "string", "description": "CPU limit, e.g. \"0.5\", \"2\""},
+						},
+					},
+				},
+			},
+			"dotfiles_repo":   map[string]any{"type": "string", "description": "Git URL or local path to a dotfiles repository to bootstrap new sandbox homes with"},
+			"warn_home_size":  map[string]any{"type": "string", "description": "Size threshold (e.g. \"10G\") above which a startup warning is printed for a bloated sandbox home"},
+			"tmp_mode":        enumSchema("How /tmp is provided inside the sandbox (default: tmpfs)", "tmpfs", "disk"),
+			"seccomp_profile": enumSchema("Syscall filter applied to sandboxed processes (default: default)", "default", "strict", "none"),
+		},
+	}
+}
+
+func mountRuleSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{"type": "string", "description": "Glob pattern to match paths (supports ~ and **)"},
+			"mode":    enumSchema("How matching paths are handled (default: readonly)", "hidden", "readonly", "readwrite", "overlay", "tmpoverlay"),
+		},
+		"required": []string{"pattern"},
+	}
+}
+
+func loggingSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"attributes": map[string]any{"type": "object", "description": "Custom key-value pairs added to all log entries", "additionalProperties": map[string]any{"type": "string"}},
+			"receivers":  map[string]any{"type": "array", "items": receiverSchema()},
+		},
+	}
+}
+
+func receiverSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type":           enumSchema("Receiver type", "syslog", "syslog-remote", "otlp"),
+			"address":        map[string]any{"type": "string", "description": "Remote server address (syslog-remote, otlp)"},
+			"endpoint":       map[string]any{"type": "string", "description": "OTLP endpoint URL (alias for address)"},
+			"protocol":       map[string]any{"type": "string", "description": "Transport protocol: udp/tcp (syslog-remote) or http/grpc (otlp)"},
+			"facility":       map[string]any{"type": "string", "description": "Syslog facility, e.g. \"local0\""},
+			"tag":            map[string]any{"type": "string", "description": "Syslog program tag"},
+			"headers":        map[string]any{"type": "object", "description": "Custom HTTP headers for OTLP", "additionalProperties": map[string]any{"type": "string"}},
+			"batch_size":     map[string]any{"type": "integer", "description": "OTLP batch size before flush"},
+			"flush_interval": map[string]any{"type": "string", "description": "OTLP flush interval, e.g. \"5s\""},
+			"insecure":       boolSchema("Disable TLS verification for gRPC connections"),
+		},
+		"required": []string{"type"},
+	}
+}
+
+func portForwardingSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"enabled": boolSchema("Enable port forwarding"),
+			"rules":   map[string]any{"type": "array", "items": portForwardingRuleSchema()},
+		},
+	}
+}
+
+func portForwardingRuleSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":         map[string]any{"type": "string", "description": "Identifier for this rule (auto-generated if empty)"},
+			"direction":    enumSchema("Direction of the forward", "inbound", "outbound"),
+			"protocol":     enumSchema("Transport protocol (default: tcp)", "tcp", "udp"),
+			"host_port":    map[string]any{"type": "integer", "minimum": MinPort, "maximum": MaxPort},
+			"sandbox_port": map[string]any{"type": "integer", "minimum": MinPort, "maximum": MaxPort},
+		},
+		"required": []string{"direction", "host_port", "sandbox_port"},
+	}
+}
+
+func networkSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"extra_rules": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Additional \"ip route|rule|addr ...\" commands run in the sandbox's network namespace before the default route is removed. Misconfigured rules can weaken network isolation.",
+			},
+		},
+	}
+}
+
+func envSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"locale": map[string]any{"type": "string", "description": "LANG/LC_ALL inside the sandbox (default: the host's LC_ALL/LANG, falling back to C.UTF-8)"},
+			"passthrough": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Host environment variable names (or glob patterns like \"FOO_*\") to copy into the sandbox. Names that look like secrets (*_TOKEN, *_KEY, *SECRET*, ...) are refused even if matched.",
+			},
+		},
+	}
+}
+
+func includeSchema() map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": "Conditional config includes, merged over the base config when their condition matches",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"if":   map[string]any{"type": "string", "description": "Condition under which to merge path, e.g. \"dir:~/work/**\" (only \"dir:\" is currently supported)"},
+				"path": map[string]any{"type": "string", "description": "Path to the config file to merge in when the condition matches"},
+			},
+			"required": []string{"if", "path"},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema_Marshals(t *testing.T) {
+	schema := JSONSchema()
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	if decoded["type"] != "object" {
+		t.Errorf("expected top-level type \"object\", got %v", decoded["type"])
+	}
+
+	props, ok := decoded["properties"].(map[string]any)
+	if !ok {
+		t.Fatal("expected top-level properties")
+	}
+
+	for _, key := range []string{"proxy", "sandbox", "overlay", "tools", "logging", "port_forwarding", "network", "include"} {
+		if _, ok := props[key]; !ok {
+			t.Errorf("expected top-level property %q in schema", key)
+		}
+	}
+}
+
+func TestJSONSchema_FilterRuleScopesIncludeResponseScopes(t *testing.T) {
+	schema := JSONSchema()
+
+	props := schema["properties"].(map[string]any)
+	proxy := props["proxy"].(map[string]any)["properties"].(map[string]any)
+	filter := proxy["filter"].(map[string]any)["properties"].(map[string]any)
+	rule := filter["rules"].(map[string]any)["items"].(map[string]any)
+	scope := rule["properties"].(map[string]any)["scope"].(map[string]any)
+
+	enum, ok := scope["enum"].([]string)
+	if !ok {
+		t.Fatal("expected scope enum to be a []string")
+	}
+
+	for _, want := range []string{"host", "path", "url", "query", "status", "location_host"} {
+		found := false
+		for _, v := range enum {
+			if v == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected scope enum to include %q, got %v", want, enum)
+		}
+	}
+}
@@ -11,6 +11,10 @@
 //
 // The proxy blocks container creation, deletion, image manipulation, and other
 // write operations. But exec into existing containers is intentionally allowed.
+//
+// Proxy.ReadOnly tightens this further to GET/HEAD only, for callers that
+// want Docker state visible (e.g. `docker ps`, `docker inspect`) without
+// granting exec access into other containers.
 package dockerproxy
 
 import (
@@ -47,6 +51,14 @@ func IsAllowed(method, path string) bool {
 	return false
 }
 
+// IsAllowedReadOnly checks if a Docker API request should be allowed under
+// the strict read-only filter: GET/HEAD only, no exec/attach and no writes
+// of any kind. Used for DockerModeReadOnlyInfo, where even exec into an
+// existing container is considered too much privilege to hand out.
+func IsAllowedReadOnly(method, _ string) bool {
+	return method == "GET" || method == "HEAD"
+}
+
 // DenyReason returns a human-readable reason why a request was denied.
 // Returns empty string if the request is allowed.
 func DenyReason(method, path string) string {
@@ -56,3 +68,12 @@ func DenyReason(method, path string) string {
 
 	return fmt.Sprintf("docker proxy: %s %s blocked (write operations not allowed)", method, path)
 }
+
+// DenyReasonReadOnly is DenyReason's counterpart for IsAllowedReadOnly.
+func DenyReasonReadOnly(method, path string) string {
+	if IsAllowedReadOnly(method, path) {
+		return ""
+	}
+
+	return fmt.Sprintf("docker proxy: %s %s blocked (read-only mode allows GET/HEAD only)", method, path)
+}
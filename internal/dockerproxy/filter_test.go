@@ -100,6 +100,36 @@ func TestIsAllowed_Denied(t *testing.T) {
 	}
 }
 
+func TestIsAllowedReadOnly(t *testing.T) {
+	if !IsAllowedReadOnly("GET", "/containers/json") {
+		t.Error("GET should be allowed in read-only mode")
+	}
+	if !IsAllowedReadOnly("HEAD", "/_ping") {
+		t.Error("HEAD should be allowed in read-only mode")
+	}
+	if IsAllowedReadOnly("POST", "/containers/abc123/exec") {
+		t.Error("exec should be denied in read-only mode")
+	}
+	if IsAllowedReadOnly("POST", "/containers/abc123/attach") {
+		t.Error("attach should be denied in read-only mode")
+	}
+	if IsAllowedReadOnly("POST", "/containers/create") {
+		t.Error("create should be denied in read-only mode")
+	}
+}
+
+func TestDenyReasonReadOnly(t *testing.T) {
+	reason := DenyReasonReadOnly("POST", "/containers/abc123/exec")
+	if reason == "" {
+		t.Error("expected non-empty deny reason for exec in read-only mode")
+	}
+
+	reason = DenyReasonReadOnly("GET", "/containers/json")
+	if reason != "" {
+		t.Errorf("expected empty deny reason for GET, got %q", reason)
+	}
+}
+
 func TestDenyReason(t *testing.T) {
 	reason := DenyReason("POST", "/containers/create")
 	if reason == "" {
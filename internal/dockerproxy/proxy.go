@@ -26,11 +26,14 @@ type Logger interface {
 type Proxy struct {
 	hostSocket string
 	listenPath string
-	listener   net.Listener
-	ctx        context.Context
-	cancel     context.CancelFunc
-	wg         sync.WaitGroup
-	logger     Logger
+	// ReadOnly restricts the proxy to GET/HEAD requests only (no
+	// exec/attach), via IsAllowedReadOnly instead of IsAllowed.
+	ReadOnly bool
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	logger   Logger
 }
 
 // New creates a new Docker socket proxy.
@@ -146,8 +149,11 @@ func (p *Proxy) handleConnection(conn net.Conn) {
 	}
 
 	// Check if allowed
-	if !IsAllowed(req.Method, req.URL.Path) {
-		reason := DenyReason(req.Method, req.URL.Path)
+	allowed, reason := IsAllowed(req.Method, req.URL.Path), DenyReason(req.Method, req.URL.Path)
+	if p.ReadOnly {
+		allowed, reason = IsAllowedReadOnly(req.Method, req.URL.Path), DenyReasonReadOnly(req.Method, req.URL.Path)
+	}
+	if !allowed {
 		p.logInfo("request denied: %s %s - %s", req.Method, req.URL.Path, reason)
 		p.sendError(conn, http.StatusForbidden, reason)
 		return
@@ -139,3 +139,94 @@ func TestProxy_BlocksPOST(t *testing.T) {
 		t.Errorf("expected 403, got %d", resp.StatusCode)
 	}
 }
+
+func TestProxy_ReadOnly_BlocksExec(t *testing.T) {
+	tmpDir := t.TempDir()
+	listenPath := filepath.Join(tmpDir, "docker.sock")
+	hostPath := filepath.Join(tmpDir, "host.sock")
+
+	// Create a fake Docker daemon (should not receive request)
+	hostListener, err := net.Listen("unix", hostPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = hostListener.Close() }()
+
+	p := New(hostPath, listenPath)
+	p.ReadOnly = true
+	ctx := context.Background()
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = p.Stop() }()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial("unix", listenPath)
+			},
+		},
+	}
+
+	// Exec is normally allowed, but ReadOnly mode blocks it too.
+	resp, err := client.Post("http://localhost/containers/abc123/exec", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestProxy_ReadOnly_AllowsGET(t *testing.T) {
+	tmpDir := t.TempDir()
+	listenPath := filepath.Join(tmpDir, "docker.sock")
+	hostPath := filepath.Join(tmpDir, "host.sock")
+
+	hostListener, err := net.Listen("unix", hostPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = hostListener.Close() }()
+
+	go func() {
+		conn, err := hostListener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		buf := make([]byte, 1024)
+		_, _ = conn.Read(buf)
+		response := "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\n[]"
+		_, _ = conn.Write([]byte(response))
+	}()
+
+	p := New(hostPath, listenPath)
+	p.ReadOnly = true
+	ctx := context.Background()
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = p.Stop() }()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial("unix", listenPath)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://localhost/containers/json")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"devsandbox/internal/bwrap"
 	"devsandbox/internal/embed"
@@ -61,6 +63,17 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 
 	sandboxCfg := cfg.SandboxCfg
 
+	if sandboxCfg.OverlayEnabled {
+		features, err := bwrap.DetectFeatures()
+		if err != nil {
+			return fmt.Errorf("failed to detect bwrap features: %w", err)
+		}
+		if !features.SupportsOverlay {
+			return fmt.Errorf("overlay mode requires a bwrap version with --overlay support "+
+				"(detected %s); disable overlay or run 'devsandbox doctor' for details", versionOrUnknown(features.Version))
+		}
+	}
+
 	// Set up structured logging
 	logDir := filepath.Join(sandboxCfg.SandboxHome, proxy.LogBaseDirName, proxy.InternalLogDirName)
 	sandboxLogger := cfg.SandboxLogger
@@ -68,24 +81,33 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 		sandboxLogger, _ = logging.NewErrorLogger(filepath.Join(logDir, "sandbox.log"))
 	}
 
-	sandboxCfg.Logger = logging.NewComponentLogger("builder", sandboxLogger, cfg.LogDispatcher)
+	sandboxCfg.Logger = logging.NewComponentLogger("builder", sandboxLogger, cfg.LogDispatcher, cfg.EventLogger)
+	sandboxCfg.ToolLogger = logging.NewComponentLogger("tool", sandboxLogger, cfg.LogDispatcher, cfg.EventLogger)
 	if sandboxCfg.MountsConfig != nil {
-		sandboxCfg.MountsConfig.SetLogger(logging.NewComponentLogger("mounts", sandboxLogger, cfg.LogDispatcher))
+		sandboxCfg.MountsConfig.SetLogger(logging.NewComponentLogger("mounts", sandboxLogger, cfg.LogDispatcher, cfg.EventLogger))
 	}
 
 	// Handle proxy mode — detect pasta for network isolation
 	var netProvider network.Provider
+	var networkRules []string
 	if sandboxCfg.ProxyEnabled {
 		var err error
 		netProvider, err = network.SelectProvider()
 		if err != nil {
-			return fmt.Errorf("proxy mode requires pasta: %w\nRun 'devsandbox doctor' for installation instructions", err)
+			return fmt.Errorf("proxy mode requires pasta or slirp4netns: %w\nRun 'devsandbox doctor' for installation instructions", err)
 		}
 
 		sandboxCfg.NetworkIsolated = netProvider.NetworkIsolated()
 		sandboxCfg.ProxyPort = cfg.ProxyPort
 		sandboxCfg.GatewayIP = netProvider.GatewayIP()
 		sandboxCfg.ProxyCAPath = cfg.ProxyCAPath
+		sandboxCfg.SocksEnabled = cfg.SocksPort != 0
+		sandboxCfg.SocksPort = cfg.SocksPort
+
+		networkLogger := logging.NewComponentLogger("network", sandboxLogger, cfg.LogDispatcher, cfg.EventLogger)
+		networkLogger.Infof("using %s network provider (isolated=%v, gateway=%s)", netProvider.Name(), sandboxCfg.NetworkIsolated, sandboxCfg.GatewayIP)
+		directRules := resolveAllowDirectRoutes(cfg.AppCfg.Network.AllowDirect, sandboxCfg.GatewayIP, networkLogger)
+		networkRules = append(directRules, cfg.AppCfg.Network.ExtraRules...)
 	}
 
 	// Build sandbox arguments
@@ -95,21 +117,33 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 	builder.AddNetworkBindings()
 	builder.AddLocaleBindings()
 	builder.AddCABindings()
+	if sandboxCfg.TrustHostCAs {
+		builder.AddHostCABindings()
+	}
 	builder.AddCustomMounts()
 	builder.AddSandboxHome()
 	builder.AddProjectBindings()
 	builder.AddTools()
 	builder.SuppressSSHAgent()
 	builder.AddProxyCACertificate()
+	builder.AddBlockedPaths()
 	builder.AddEnvironment()
+	builder.Seccomp(sandboxCfg.SeccompProfile)
 
 	if err := builder.Err(); err != nil {
 		return fmt.Errorf("failed to build sandbox: %w", err)
 	}
+	if f := builder.SeccompFile(); f != nil {
+		defer f.Close()
+	}
 
 	bwrapArgs := builder.Build()
 	shellCmd := sandbox.BuildShellCommand(sandboxCfg, cfg.Command)
 
+	if cfg.PrintEnvDiff {
+		sandbox.PrintEnvDiff(os.Stderr, hostEnviron(), builder.Env())
+	}
+
 	// Debug output
 	if os.Getenv("DEVSANDBOX_DEBUG") != "" {
 		fmt.Fprintln(os.Stderr, "=== Sandbox Debug ===")
@@ -128,6 +162,10 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 				"without network isolation, the sandbox already has direct network access to the host; " +
 				"either enable proxy mode (--proxy) or remove port_forwarding configuration")
 		}
+		if netProvider != nil && netProvider.Name() == "slirp4netns" {
+			return fmt.Errorf("port forwarding is not supported with the slirp4netns fallback provider; " +
+				"install pasta (passt package) or remove port_forwarding configuration")
+		}
 	}
 
 	// Build port forwarding args for pasta
@@ -137,15 +175,120 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 	}
 
 	// Execute the sandbox
+	seccompFile := builder.SeccompFile()
+	limits := sandboxCfg.ResourceLimits
+	resourcesLogger := logging.NewComponentLogger("resources", sandboxLogger, cfg.LogDispatcher, cfg.EventLogger)
+
 	if sandboxCfg.ProxyEnabled {
-		return bwrap.ExecWithPasta(bwrapArgs, shellCmd, portForwardArgs)
+		if netProvider.Name() == "slirp4netns" {
+			return bwrap.ExecWithSlirp(ctx, bwrapArgs, shellCmd, networkRules, seccompFile, limits, resourcesLogger.Warnf)
+		}
+		return bwrap.ExecWithPasta(ctx, bwrapArgs, shellCmd, portForwardArgs, networkRules, sandboxCfg.GatewayIP, seccompFile, limits, resourcesLogger.Warnf)
 	}
 
 	if cfg.HasActiveTools || cfg.RemoveOnExit {
-		return bwrap.ExecRun(bwrapArgs, shellCmd)
+		return bwrap.ExecRun(bwrapArgs, shellCmd, seccompFile, limits, resourcesLogger.Warnf)
+	}
+
+	return bwrap.Exec(bwrapArgs, shellCmd, seccompFile, limits, resourcesLogger.Warnf)
+}
+
+// DryRun builds the full bwrap command line (and, in proxy mode, the pasta
+// or slirp4netns wrapper command around it) without executing anything. It
+// mirrors Run() through Builder.Build()/BuildShellCommand, but never starts
+// the proxy or execs bwrap - callers are expected to have skipped
+// Config.EnsureSandboxDirs and proxy startup before calling this, since
+// this only covers what happens inside Run() itself.
+//
+// It is not perfectly side-effect-free: a couple of Builder steps write
+// small marker files into SandboxHome as part of computing the args they
+// add (e.g. SuppressSSHAgent's no-op ssh-agent wrapper), the same as a real
+// run would. Nothing outside SandboxHome is touched, and no proxy, lock, or
+// active tool process is started.
+func (b *BwrapIsolator) DryRun(_ context.Context, cfg *RunConfig) ([]string, error) {
+	sandboxCfg := cfg.SandboxCfg
+
+	sandboxCfg.Logger = logging.NewComponentLogger("builder", nil, nil, nil)
+	sandboxCfg.ToolLogger = logging.NewComponentLogger("tool", nil, nil, nil)
+	if sandboxCfg.MountsConfig != nil {
+		sandboxCfg.MountsConfig.SetLogger(logging.NewComponentLogger("mounts", nil, nil, nil))
+	}
+
+	var netProvider network.Provider
+	var networkRules []string
+	if sandboxCfg.ProxyEnabled {
+		var err error
+		netProvider, err = network.SelectProvider()
+		if err != nil {
+			return nil, fmt.Errorf("proxy mode requires pasta or slirp4netns: %w\nRun 'devsandbox doctor' for installation instructions", err)
+		}
+
+		sandboxCfg.NetworkIsolated = netProvider.NetworkIsolated()
+		sandboxCfg.ProxyPort = cfg.ProxyPort
+		sandboxCfg.GatewayIP = netProvider.GatewayIP()
+		sandboxCfg.ProxyCAPath = cfg.ProxyCAPath
+		sandboxCfg.SocksEnabled = cfg.SocksPort != 0
+		sandboxCfg.SocksPort = cfg.SocksPort
+
+		networkLogger := logging.NewComponentLogger("network", nil, nil, nil)
+		directRules := resolveAllowDirectRoutes(cfg.AppCfg.Network.AllowDirect, sandboxCfg.GatewayIP, networkLogger)
+		networkRules = append(directRules, cfg.AppCfg.Network.ExtraRules...)
+	}
+
+	builder := sandbox.NewBuilder(sandboxCfg)
+	builder.AddBaseArgs()
+	builder.AddSystemBindings()
+	builder.AddNetworkBindings()
+	builder.AddLocaleBindings()
+	builder.AddCABindings()
+	if sandboxCfg.TrustHostCAs {
+		builder.AddHostCABindings()
+	}
+	builder.AddCustomMounts()
+	builder.AddSandboxHome()
+	builder.AddProjectBindings()
+	builder.AddTools()
+	builder.SuppressSSHAgent()
+	builder.AddProxyCACertificate()
+	builder.AddBlockedPaths()
+	builder.AddEnvironment()
+	builder.Seccomp(sandboxCfg.SeccompProfile)
+
+	if err := builder.Err(); err != nil {
+		return nil, fmt.Errorf("failed to build sandbox: %w", err)
+	}
+	if f := builder.SeccompFile(); f != nil {
+		defer f.Close()
+	}
+
+	bwrapArgs := builder.Build()
+	shellCmd := sandbox.BuildShellCommand(sandboxCfg, cfg.Command)
+
+	if sandboxCfg.ProxyEnabled {
+		if netProvider.Name() == "slirp4netns" {
+			full, err := bwrap.PreviewSlirpCommand(bwrapArgs, shellCmd, networkRules)
+			if err != nil {
+				return nil, err
+			}
+			return sandboxCfg.ResourceLimits.Preview(full), nil
+		}
+
+		var portForwardArgs []string
+		if cfg.AppCfg.PortForwarding.IsEnabled() {
+			portForwardArgs = sandbox.BuildPastaPortArgs(cfg.AppCfg.PortForwarding.Rules)
+		}
+		full, err := bwrap.PreviewPastaCommand(bwrapArgs, shellCmd, portForwardArgs, networkRules, sandboxCfg.GatewayIP)
+		if err != nil {
+			return nil, err
+		}
+		return sandboxCfg.ResourceLimits.Preview(full), nil
 	}
 
-	return bwrap.Exec(bwrapArgs, shellCmd)
+	full, err := bwrap.PreviewCommand(bwrapArgs, shellCmd)
+	if err != nil {
+		return nil, err
+	}
+	return sandboxCfg.ResourceLimits.Preview(full), nil
 }
 
 // Cleanup performs any post-sandbox cleanup.
@@ -153,3 +296,66 @@ func (b *BwrapIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 func (b *BwrapIsolator) Cleanup() error {
 	return nil
 }
+
+// resolveAllowDirectRoutes resolves each "host:port" entry in
+// network.allow_direct to a host route that bypasses the proxy, in the
+// same "ip route add <ip>/32 via <gatewayIP>" form documented for
+// network.extra_rules - they're fed into the same extraRules pipeline and
+// run before the default route is torn down. The port is intentionally
+// dropped here: this isolation can only allow or block a destination
+// host, not a specific port on it (see config.NetworkConfig.AllowDirect).
+//
+// Routing is IPv4-only, matching the IPv4-only gateway pasta/slirp4netns
+// map: an IPv6-only resolution is skipped with a warning rather than
+// generating a route with no usable nexthop. A host that fails to resolve
+// at all is also skipped with a warning rather than failing the whole
+// sandbox - the alternative is a hard failure every time a DNS-backed
+// allowlist entry's target is briefly unreachable.
+func resolveAllowDirectRoutes(entries []string, gatewayIP string, logger *logging.ComponentLogger) []string {
+	var rules []string
+	for _, entry := range entries {
+		host, _, err := net.SplitHostPort(entry)
+		if err != nil {
+			logger.Warnf("network.allow_direct: skipping invalid entry %q: %v", entry, err)
+			continue
+		}
+
+		ips, err := net.LookupHost(host)
+		if err != nil {
+			logger.Warnf("network.allow_direct: failed to resolve %q: %v", host, err)
+			continue
+		}
+
+		var resolvedV4 bool
+		for _, ip := range ips {
+			if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+				continue
+			}
+			rules = append(rules, fmt.Sprintf("ip route add %s/32 via %s", ip, gatewayIP))
+			resolvedV4 = true
+		}
+		if !resolvedV4 {
+			logger.Warnf("network.allow_direct: %q has no IPv4 address, skipping (direct routing is IPv4-only)", host)
+		}
+	}
+	return rules
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "unknown version"
+	}
+	return "v" + version
+}
+
+// hostEnviron returns the host process environment as a map, for comparison
+// against the sandbox environment in --print-env-diff.
+func hostEnviron() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
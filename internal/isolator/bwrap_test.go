@@ -2,8 +2,14 @@ package isolator
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+
+	"devsandbox/internal/config"
+	"devsandbox/internal/logging"
+	"devsandbox/internal/sandbox"
 )
 
 func TestBwrapIsolator_Name(t *testing.T) {
@@ -52,4 +58,114 @@ func TestBwrapIsolator_Cleanup(t *testing.T) {
 
 func TestBwrapIsolator_ImplementsInterface(t *testing.T) {
 	var _ Isolator = (*BwrapIsolator)(nil)
+	var _ DryRunner = (*BwrapIsolator)(nil)
+}
+
+func TestBwrapIsolator_DryRun(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Linux-only test")
+	}
+
+	sandboxHome := t.TempDir()
+	cfg := &sandbox.Config{
+		HomeDir:     "/home/test",
+		ProjectDir:  t.TempDir(),
+		ProjectName: "myproject",
+		SandboxHome: sandboxHome,
+		XDGRuntime:  "/run/user/1000",
+	}
+
+	iso := NewBwrapIsolator()
+	argv, err := iso.DryRun(context.Background(), &RunConfig{
+		SandboxCfg: cfg,
+		AppCfg:     &config.Config{},
+		Command:    []string{"echo", "hi"},
+	})
+	if err != nil {
+		t.Fatalf("DryRun() error: %v", err)
+	}
+
+	if len(argv) == 0 {
+		t.Fatal("DryRun() returned empty argv")
+	}
+	if argv[0] == "" {
+		t.Error("DryRun()[0] should be the bwrap binary path")
+	}
+
+	var foundSeparator bool
+	for _, a := range argv {
+		if a == "--" {
+			foundSeparator = true
+			break
+		}
+	}
+	if !foundSeparator {
+		t.Errorf("DryRun() argv missing '--' separator before the shell command: %v", argv)
+	}
+}
+
+func TestBwrapIsolator_DryRun_DoesNotCreateSandboxDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Linux-only test")
+	}
+
+	base := t.TempDir()
+	sandboxHome := filepath.Join(base, "home")
+	cfg := &sandbox.Config{
+		HomeDir:     "/home/test",
+		ProjectDir:  t.TempDir(),
+		ProjectName: "myproject",
+		SandboxHome: sandboxHome,
+		SandboxRoot: filepath.Join(base, "root"),
+		XDGRuntime:  "/run/user/1000",
+	}
+
+	iso := NewBwrapIsolator()
+	if _, err := iso.DryRun(context.Background(), &RunConfig{
+		SandboxCfg: cfg,
+		AppCfg:     &config.Config{},
+		Command:    []string{"echo", "hi"},
+	}); err != nil {
+		t.Fatalf("DryRun() error: %v", err)
+	}
+
+	if _, err := os.Stat(cfg.SandboxRoot); err == nil {
+		t.Error("DryRun() should not create SandboxRoot")
+	}
+}
+
+func TestResolveAllowDirectRoutes(t *testing.T) {
+	logger := logging.NewComponentLogger("network", nil, nil, nil)
+
+	t.Run("resolves a host to a via-gateway route", func(t *testing.T) {
+		rules := resolveAllowDirectRoutes([]string{"localhost:5432"}, "10.0.2.2", logger)
+		if len(rules) != 1 {
+			t.Fatalf("resolveAllowDirectRoutes() = %v, want 1 rule", rules)
+		}
+		want := "ip route add 127.0.0.1/32 via 10.0.2.2"
+		if rules[0] != want {
+			t.Errorf("resolveAllowDirectRoutes()[0] = %q, want %q", rules[0], want)
+		}
+	})
+
+	t.Run("skips an entry with no port", func(t *testing.T) {
+		rules := resolveAllowDirectRoutes([]string{"localhost"}, "10.0.2.2", logger)
+		if len(rules) != 0 {
+			t.Errorf("resolveAllowDirectRoutes() = %v, want no rules for an invalid entry", rules)
+		}
+	})
+
+	t.Run("skips a host that fails to resolve", func(t *testing.T) {
+		rules := resolveAllowDirectRoutes([]string{"this-host-does-not-resolve.invalid:5432"}, "10.0.2.2", logger)
+		if len(rules) != 0 {
+			t.Errorf("resolveAllowDirectRoutes() = %v, want no rules for an unresolvable host", rules)
+		}
+	})
+
+	t.Run("empty list produces no rules", func(t *testing.T) {
+		rules := resolveAllowDirectRoutes(nil, "10.0.2.2", logger)
+		if len(rules) != 0 {
+			t.Errorf("resolveAllowDirectRoutes() = %v, want no rules for an empty list", rules)
+		}
+	})
 }
@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"devsandbox/internal/logging"
@@ -250,7 +251,7 @@ func (d *DockerIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 	// Set up logger for Docker isolator
 	logDir := filepath.Join(sandboxCfg.SandboxHome, proxy.LogBaseDirName, proxy.InternalLogDirName)
 	dockerLogger, _ := logging.NewErrorLogger(filepath.Join(logDir, "docker.log"))
-	d.SetLogger(logging.NewComponentLogger("docker", dockerLogger, cfg.LogDispatcher))
+	d.SetLogger(logging.NewComponentLogger("docker", dockerLogger, cfg.LogDispatcher, cfg.EventLogger))
 
 	// Build isolator config from RunConfig
 	isoCfg := &Config{
@@ -317,7 +318,7 @@ func (d *DockerIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 			fmt.Fprintf(os.Stderr, "Warning: failed to install tools: %v\n", err)
 		}
 
-		return d.execIntoContainer(result.BinaryPath, result.ContainerName, isoCfg.Interactive, isoCfg.Shell, cfg.Command)
+		return d.execIntoContainer(ctx, result.BinaryPath, result.ContainerName, isoCfg.Interactive, isoCfg.Shell, cfg.Command)
 
 	case DockerActionExec:
 		if result.ContainerJustStarted {
@@ -333,7 +334,7 @@ func (d *DockerIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 			fmt.Fprintf(os.Stderr, "Warning: failed to install tools: %v\n", err)
 		}
 
-		return d.execIntoContainer(result.BinaryPath, result.ContainerName, isoCfg.Interactive, isoCfg.Shell, cfg.Command)
+		return d.execIntoContainer(ctx, result.BinaryPath, result.ContainerName, isoCfg.Interactive, isoCfg.Shell, cfg.Command)
 
 	default:
 		return fmt.Errorf("unexpected docker action: %d", result.Action)
@@ -341,7 +342,10 @@ func (d *DockerIsolator) Run(ctx context.Context, cfg *RunConfig) error {
 }
 
 // execIntoContainer runs docker exec into a container with the given command.
-func (d *DockerIsolator) execIntoContainer(dockerBinary, containerName string, interactive bool, shell string, userArgs []string) error {
+// ctx cancellation sends SIGTERM to the docker exec process (not the
+// containerized command itself - docker has no API for that over a plain
+// exec session), e.g. for --on-block=fail-fast.
+func (d *DockerIsolator) execIntoContainer(ctx context.Context, dockerBinary, containerName string, interactive bool, shell string, userArgs []string) error {
 	execArgs := []string{"exec"}
 	if interactive {
 		execArgs = append(execArgs, "-it")
@@ -355,7 +359,11 @@ func (d *DockerIsolator) execIntoContainer(dockerBinary, containerName string, i
 	} else {
 		execArgs = append(execArgs, shell)
 	}
-	cmd := exec.Command(dockerBinary, execArgs...)
+	cmd := exec.CommandContext(ctx, dockerBinary, execArgs...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 5 * time.Second
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -750,13 +758,29 @@ func (d *DockerIsolator) buildCommonArgs(cfg *Config) ([]string, error) {
 		if cfg.ProxyCAPath != "" {
 			caDest := "/etc/ssl/certs/devsandbox-ca.crt"
 			args = append(args, "-v", fmt.Sprintf("%s:%s:ro", cfg.ProxyCAPath, caDest))
-			args = append(args, "-e", fmt.Sprintf("SSL_CERT_FILE=%s", caDest))
 			// Also set for Node.js which uses its own env var
 			args = append(args, "-e", fmt.Sprintf("NODE_EXTRA_CA_CERTS=%s", caDest))
 			// Match bwrap backend's proxy env vars for consistency
 			args = append(args, "-e", fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", caDest))
 			args = append(args, "-e", fmt.Sprintf("CURL_CA_BUNDLE=%s", caDest))
 			args = append(args, "-e", fmt.Sprintf("GIT_SSL_CAINFO=%s", caDest))
+
+			// Some JVMs treat an unrecognized SSL_CERT_FILE as a fatal startup
+			// error, so tools.java.ca_env can opt Java out of it (see
+			// sandbox.Builder.addJavaTruststore for the bwrap equivalent).
+			switch sandbox.JavaCAEnv(getToolConfig(cfg.ToolsConfig, "java")) {
+			case "javatruststore":
+				if dest, opts, err := d.javaTruststoreMount(cfg.ProxyCAPath, cfg.SandboxHome); err != nil {
+					d.logWarn("failed to generate Java truststore, HTTPS through the proxy will fail for Java tools: %v", err)
+				} else {
+					args = append(args, "-v", dest)
+					args = append(args, "-e", opts)
+				}
+			case "none":
+				// Leave SSL_CERT_FILE unset for everyone in this sandbox.
+			default:
+				args = append(args, "-e", fmt.Sprintf("SSL_CERT_FILE=%s", caDest))
+			}
 		}
 	}
 
@@ -942,6 +966,26 @@ func getToolConfig(toolsConfig map[string]any, toolName string) map[string]any {
 	return nil
 }
 
+// javaTruststoreMount generates a PKCS12 truststore for the proxy CA under
+// sandboxHome and returns the "-v" and "-e" arguments needed to mount it
+// into the container and point the JVM at it.
+func (d *DockerIsolator) javaTruststoreMount(caCertPath, sandboxHome string) (volumeArg, envArg string, err error) {
+	const truststorePassword = "changeit" // not a secret: only trusts the proxy's own CA
+	truststoreHostPath := filepath.Join(sandboxHome, "devsandbox-java-truststore.p12")
+
+	if err := sandbox.GenerateJavaTruststore(caCertPath, truststoreHostPath, truststorePassword); err != nil {
+		return "", "", err
+	}
+
+	truststoreDest := "/etc/ssl/certs/devsandbox-java-truststore.p12"
+	volumeArg = fmt.Sprintf("%s:%s:ro", truststoreHostPath, truststoreDest)
+	envArg = fmt.Sprintf(
+		"JAVA_TOOL_OPTIONS=-Djavax.net.ssl.trustStore=%s -Djavax.net.ssl.trustStoreType=PKCS12 -Djavax.net.ssl.trustStorePassword=%s",
+		truststoreDest, truststorePassword,
+	)
+	return volumeArg, envArg, nil
+}
+
 // containerName generates a Docker container name for the sandbox.
 // Format: devsandbox-<project>-<hash>
 func (d *DockerIsolator) containerName(projectDir string) string {
@@ -38,15 +38,28 @@ type RunConfig struct {
 	Interactive    bool
 	RemoveOnExit   bool
 	HasActiveTools bool
+	PrintEnvDiff   bool
 
 	// Proxy state (started by main.go before Run)
 	ProxyServer *proxy.Server // nil if proxy disabled
 	ProxyCAPath string
 	ProxyPort   int // actual port after binding
+	SocksPort   int // actual SOCKS5 port after binding, 0 if disabled
 
 	// Logging
 	SandboxLogger *logging.ErrorLogger
 	LogDispatcher *logging.Dispatcher
+	EventLogger   *proxy.EventLogger
+}
+
+// DryRunner is implemented by isolators that can build the full command
+// line they would execute without actually running it. Used by --dry-run.
+// Not all backends support this (e.g. Docker's equivalent is the container
+// runtime's own command line, which isn't an argv in the same sense).
+type DryRunner interface {
+	// DryRun builds the command that Run would execute, without running it,
+	// creating any sandbox directories, or starting the proxy.
+	DryRun(ctx context.Context, cfg *RunConfig) ([]string, error)
 }
 
 // Isolator is the interface for sandbox backends.
@@ -5,22 +5,35 @@ import (
 	"time"
 )
 
+// EventRecorder receives structured lifecycle events from a ComponentLogger,
+// tagged with its component name as the event's type. Satisfied by
+// *proxy.EventLogger; declared here as an interface (rather than imported
+// directly) because internal/proxy already imports this package.
+type EventRecorder interface {
+	Infof(eventType, format string, args ...any)
+	Warnf(eventType, format string, args ...any)
+	Errorf(eventType, format string, args ...any)
+}
+
 // ComponentLogger provides scoped logging for a specific component.
-// It writes to both a local ErrorLogger (file) and a remote Dispatcher
-// (syslog, OTLP) when configured. Nil-safe: if both are nil, calls are no-ops.
+// It writes to a local ErrorLogger (file), a remote Dispatcher (syslog,
+// OTLP), and a structured EventRecorder, each independently optional.
+// Nil-safe: if all three are nil, calls are no-ops.
 type ComponentLogger struct {
 	component   string
 	errorLogger *ErrorLogger
 	dispatcher  *Dispatcher
+	events      EventRecorder
 }
 
 // NewComponentLogger creates a logger for the given component.
-// Either errorLogger or dispatcher (or both) may be nil.
-func NewComponentLogger(component string, errorLogger *ErrorLogger, dispatcher *Dispatcher) *ComponentLogger {
+// errorLogger, dispatcher, and events may each be nil independently.
+func NewComponentLogger(component string, errorLogger *ErrorLogger, dispatcher *Dispatcher, events EventRecorder) *ComponentLogger {
 	return &ComponentLogger{
 		component:   component,
 		errorLogger: errorLogger,
 		dispatcher:  dispatcher,
+		events:      events,
 	}
 }
 
@@ -42,6 +55,7 @@ func (l *ComponentLogger) Warnf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	l.writeLocal(LevelWarn, msg)
 	l.dispatch(LevelWarn, msg)
+	l.recordEvent(LevelWarn, msg)
 }
 
 // Infof logs an informational message.
@@ -52,6 +66,7 @@ func (l *ComponentLogger) Infof(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	l.writeLocal(LevelInfo, msg)
 	l.dispatch(LevelInfo, msg)
+	l.recordEvent(LevelInfo, msg)
 }
 
 // Errorf logs an error message.
@@ -62,6 +77,23 @@ func (l *ComponentLogger) Errorf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	l.writeLocal(LevelError, msg)
 	l.dispatch(LevelError, msg)
+	l.recordEvent(LevelError, msg)
+}
+
+// recordEvent forwards the message to the EventRecorder, if configured,
+// tagged with this logger's component name as the event type.
+func (l *ComponentLogger) recordEvent(level Level, msg string) {
+	if l.events == nil {
+		return
+	}
+	switch level {
+	case LevelError:
+		l.events.Errorf(l.component, "%s", msg)
+	case LevelWarn:
+		l.events.Warnf(l.component, "%s", msg)
+	default:
+		l.events.Infof(l.component, "%s", msg)
+	}
 }
 
 // writeLocal writes to the local ErrorLogger file.
@@ -25,7 +25,7 @@ func TestComponentLogger_LocalOnly(t *testing.T) {
 	}
 	defer func() { _ = el.Close() }()
 
-	l := NewComponentLogger("builder", el, nil)
+	l := NewComponentLogger("builder", el, nil, nil)
 	l.Warnf("mount conflict: %s", "/home/test")
 	l.Infof("setup complete")
 	l.Errorf("fatal: %v", "disk full")
@@ -92,7 +92,7 @@ func TestComponentLogger_WithDispatcher(t *testing.T) {
 
 func TestComponentLogger_NilBoth(t *testing.T) {
 	// Both nil: should not panic, just no-op
-	l := NewComponentLogger("test", nil, nil)
+	l := NewComponentLogger("test", nil, nil, nil)
 	l.Warnf("test")
 	l.Infof("test")
 	l.Errorf("test")
@@ -15,8 +15,8 @@ func TestPastaAvailable(t *testing.T) {
 func TestSelectProvider(t *testing.T) {
 	provider, err := SelectProvider()
 
-	if err == ErrNoPastaProvider {
-		t.Skip("pasta not available")
+	if err == ErrNoNetworkProvider {
+		t.Skip("neither pasta nor slirp4netns available")
 	}
 
 	if err != nil {
@@ -61,3 +61,36 @@ func TestPastaNetworkIsolated(t *testing.T) {
 func TestPastaImplementsProvider(t *testing.T) {
 	var _ Provider = (*Pasta)(nil)
 }
+
+func TestSlirp4netnsAvailable(t *testing.T) {
+	s := NewSlirp4netns()
+
+	// Just test that it doesn't panic
+	available := s.Available()
+	t.Logf("slirp4netns available: %v", available)
+}
+
+func TestSlirp4netnsGatewayIP(t *testing.T) {
+	s := NewSlirp4netns()
+	if ip := s.GatewayIP(); ip != "10.0.2.2" {
+		t.Errorf("unexpected gateway IP: %s", ip)
+	}
+}
+
+func TestSlirp4netnsName(t *testing.T) {
+	s := NewSlirp4netns()
+	if s.Name() != "slirp4netns" {
+		t.Errorf("unexpected name: %s", s.Name())
+	}
+}
+
+func TestSlirp4netnsNetworkIsolated(t *testing.T) {
+	s := NewSlirp4netns()
+	if !s.NetworkIsolated() {
+		t.Error("slirp4netns should report network isolated")
+	}
+}
+
+func TestSlirp4netnsImplementsProvider(t *testing.T) {
+	var _ Provider = (*Slirp4netns)(nil)
+}
@@ -4,6 +4,10 @@ import "errors"
 
 var ErrNoPastaProvider = errors.New("pasta not available (install passt package for proxy mode)")
 
+// ErrNoNetworkProvider is returned by SelectProvider when neither pasta nor
+// its slirp4netns fallback is available.
+var ErrNoNetworkProvider = errors.New("no network provider available (install passt or slirp4netns for proxy mode)")
+
 // Provider defines the interface for user-mode network providers.
 // This interface is intentionally minimal - it only includes methods
 // that are actually used in the proxy flow.
@@ -21,16 +25,22 @@ type Provider interface {
 	NetworkIsolated() bool
 }
 
-// SelectProvider returns the pasta network provider if available.
-// Proxy mode requires pasta for proper network isolation and traffic enforcement.
-// Returns an error if pasta is not installed.
+// SelectProvider returns the best available network provider. It tries
+// pasta first, falling back to slirp4netns for distros that don't package
+// pasta. Proxy mode requires one of the two for proper network isolation
+// and traffic enforcement. Returns an error if neither is installed.
 func SelectProvider() (Provider, error) {
 	pasta := NewPasta()
 	if pasta.Available() {
 		return pasta, nil
 	}
 
-	return nil, ErrNoPastaProvider
+	slirp := NewSlirp4netns()
+	if slirp.Available() {
+		return slirp, nil
+	}
+
+	return nil, ErrNoNetworkProvider
 }
 
 // CheckPastaAvailable returns true if pasta is available
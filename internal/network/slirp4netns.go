@@ -0,0 +1,47 @@
+package network
+
+import "os/exec"
+
+const (
+	// SlirpGatewayIP is the gateway IP slirp4netns assigns inside the
+	// namespace by default. Like pasta's gateway, it also answers for the
+	// host's loopback, but the route restricting traffic to it is set up
+	// differently - see bwrap.ExecWithSlirp.
+	SlirpGatewayIP = "10.0.2.2"
+)
+
+// Slirp4netns implements the Provider interface using slirp4netns, a
+// fallback for distros that don't package pasta. Unlike pasta, it isn't
+// embedded in the binary, so Available only checks the system PATH.
+type Slirp4netns struct{}
+
+// NewSlirp4netns creates a new slirp4netns provider.
+func NewSlirp4netns() *Slirp4netns {
+	return &Slirp4netns{}
+}
+
+// Name returns the provider name.
+func (s *Slirp4netns) Name() string {
+	return "slirp4netns"
+}
+
+// Available checks if slirp4netns and the unshare helper it relies on to
+// create a network namespace are both present on the system PATH.
+func (s *Slirp4netns) Available() bool {
+	if _, err := exec.LookPath("slirp4netns"); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("unshare")
+	return err == nil
+}
+
+// GatewayIP returns the gateway IP for slirp4netns.
+func (s *Slirp4netns) GatewayIP() string {
+	return SlirpGatewayIP
+}
+
+// NetworkIsolated returns true as slirp4netns provides full network
+// namespace isolation, same as pasta.
+func (s *Slirp4netns) NetworkIsolated() bool {
+	return true
+}
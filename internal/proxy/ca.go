@@ -15,6 +15,13 @@ import (
 const (
 	rsaKeyBits     = 4096
 	caValidityDays = 3650 // 10 years
+
+	// caExpiryWarningWindow is how far ahead of the CA's actual expiry the
+	// server starts warning in its logs. 10 years is a long time, but
+	// sandboxes can be left running or reused across a long-lived project,
+	// so this gives plenty of notice to rotate before leaf certs signed by
+	// an expired CA start failing TLS verification.
+	caExpiryWarningWindow = 30 * 24 * time.Hour
 )
 
 type CA struct {
@@ -135,6 +142,18 @@ func CreateCA(cfg *Config) (*CA, error) {
 	}, nil
 }
 
+// NearingExpiry reports whether the CA certificate will expire within
+// caExpiryWarningWindow of now.
+func (ca *CA) NearingExpiry() bool {
+	return time.Until(ca.Certificate.NotAfter) < caExpiryWarningWindow
+}
+
+// DaysUntilExpiry returns the number of whole days remaining before the CA
+// certificate expires. It is negative once the certificate has expired.
+func (ca *CA) DaysUntilExpiry() int {
+	return int(time.Until(ca.Certificate.NotAfter).Hours() / 24)
+}
+
 func (ca *CA) SignCertificate(host string) ([]byte, []byte, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestCreateCA(t *testing.T) {
@@ -221,6 +222,41 @@ func TestCAExists(t *testing.T) {
 	}
 }
 
+func TestLoadOrCreateCA_Shared(t *testing.T) {
+	baseDir, err := os.MkdirTemp("", "ca-shared-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(baseDir) }()
+
+	cfgA := NewConfig(filepath.Join(baseDir, "project-a"), DefaultProxyPort)
+	cfgA.UseSharedCA(baseDir)
+	cfgB := NewConfig(filepath.Join(baseDir, "project-b"), DefaultProxyPort)
+	cfgB.UseSharedCA(baseDir)
+
+	caA, err := LoadOrCreateCA(cfgA)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA for project-a failed: %v", err)
+	}
+
+	caB, err := LoadOrCreateCA(cfgB)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA for project-b failed: %v", err)
+	}
+
+	if caA.Certificate.SerialNumber.Cmp(caB.Certificate.SerialNumber) != 0 {
+		t.Error("projects sharing a CA should get the same certificate, got different serial numbers")
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, "project-a", ".ca")); !os.IsNotExist(err) {
+		t.Error("shared CA mode should not create a per-project .ca directory")
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, SharedCADirName, CACertFile)); err != nil {
+		t.Errorf("expected shared CA certificate at %s: %v", filepath.Join(baseDir, SharedCADirName, CACertFile), err)
+	}
+}
+
 func TestEnsureCADir(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "ca-test-*")
 	if err != nil {
@@ -250,3 +286,29 @@ func TestEnsureCADir(t *testing.T) {
 		t.Errorf("CA dir has wrong permissions: %o", info.Mode().Perm())
 	}
 }
+
+func TestCA_NearingExpiry(t *testing.T) {
+	nearExpiry := &CA{Certificate: &x509.Certificate{NotAfter: time.Now().Add(5 * 24 * time.Hour)}}
+	if !nearExpiry.NearingExpiry() {
+		t.Error("expected a CA expiring in 5 days to be reported as nearing expiry")
+	}
+
+	freshlyMinted := &CA{Certificate: &x509.Certificate{NotAfter: time.Now().AddDate(0, 0, caValidityDays)}}
+	if freshlyMinted.NearingExpiry() {
+		t.Error("expected a freshly created CA to not be reported as nearing expiry")
+	}
+}
+
+func TestCA_DaysUntilExpiry(t *testing.T) {
+	ca := &CA{Certificate: &x509.Certificate{NotAfter: time.Now().Add(10 * 24 * time.Hour)}}
+
+	days := ca.DaysUntilExpiry()
+	if days < 9 || days > 10 {
+		t.Errorf("DaysUntilExpiry() = %d, want ~10", days)
+	}
+
+	expired := &CA{Certificate: &x509.Certificate{NotAfter: time.Now().Add(-24 * time.Hour)}}
+	if expired.DaysUntilExpiry() >= 0 {
+		t.Errorf("DaysUntilExpiry() = %d, want negative for an expired CA", expired.DaysUntilExpiry())
+	}
+}
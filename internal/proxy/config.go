@@ -17,21 +17,37 @@ const (
 )
 
 const (
-	DefaultProxyPort   = 8080 // Standard proxy port, matches config.toml default
-	MaxPortRetries     = 50   // Number of ports to try if default is busy
-	CADirName          = ".ca"
-	CACertFile         = "ca.crt"
-	CAKeyFile          = "ca.key"
+	DefaultProxyPort = 8080 // Standard proxy port, matches config.toml default
+	MaxPortRetries   = 50   // Number of ports to try if default is busy
+	CADirName        = ".ca"
+	CACertFile       = "ca.crt"
+	CAKeyFile        = "ca.key"
+	// SharedCADirName is the subdirectory of the sandbox base path (as
+	// opposed to a per-project sandbox root) holding the shared CA used
+	// when proxy.ca.shared is enabled.
+	SharedCADirName    = "_shared"
 	LogBaseDirName     = "logs"
 	ProxyLogDirName    = "proxy"
 	InternalLogDirName = "internal"
+
+	// DefaultSocksPortOffset is added to the HTTP proxy port to derive the
+	// default SOCKS5 listener port when SocksPort is not explicitly set.
+	DefaultSocksPortOffset = 1000
 )
 
 type Config struct {
-	Enabled        bool
-	Port           int
-	BindAddress    string // IP to bind to (default "127.0.0.1"). For Docker, use DockerBridgeIP().
-	SandboxBase    string // Root directory for this sandbox instance
+	Enabled     bool
+	Port        int
+	BindAddress string // IP to bind to (default "127.0.0.1"). For Docker, use DockerBridgeIP().
+	SandboxBase string // Root directory for this sandbox instance
+
+	// SocksEnabled brings up a SOCKS5 listener alongside the HTTP proxy,
+	// for tools that only honor ALL_PROXY with a SOCKS endpoint. SOCKS
+	// connections go through the same RequestLogger and FilterConfig host
+	// rules as the HTTP proxy.
+	SocksEnabled bool
+	// SocksPort is the SOCKS5 listener port. If zero, Port+DefaultSocksPortOffset is used.
+	SocksPort      int
 	CADir          string
 	CACertPath     string
 	CAKeyPath      string
@@ -47,6 +63,19 @@ type Config struct {
 	// Filter contains HTTP request filtering configuration.
 	Filter *FilterConfig
 
+	// Redact contains request/response logging redaction settings. If nil,
+	// only the default secret-header set is redacted.
+	Redact *RedactConfig
+
+	// GRPC contains gRPC message decoding settings for the request log. If
+	// nil, gRPC calls are logged (method, status, message sizes) but
+	// bodies are not decoded to JSON.
+	GRPC *GRPCConfig
+
+	// LogRetention controls pruning of rotated request logs. If nil, only
+	// RotatingFileWriter's default file-count cap applies.
+	LogRetention *LogRetentionConfig
+
 	// CredentialInjectors add authentication to requests for specific domains.
 	// Built by BuildCredentialInjectors() from [proxy.credentials] config.
 	// If nil/empty, no credential injection is performed.
@@ -55,6 +84,47 @@ type Config struct {
 	// Dispatcher is an optional shared log dispatcher for remote forwarding.
 	// If set, the server uses it instead of creating its own from LogReceivers.
 	Dispatcher *logging.Dispatcher
+
+	// ResponseRewrite rewrites text response bodies from matching hosts,
+	// applied in the OnResponse hook before logging. If empty, no rewriting
+	// is performed.
+	ResponseRewrite []ResponseRewriteRule
+
+	// UpstreamProxy chains the server's own outbound connections (both
+	// plain HTTP and MITM'd CONNECT tunnels) through another HTTP(S) proxy,
+	// e.g. a corporate egress proxy. Supports "user:pass@host:port" for
+	// proxies requiring Basic auth. If empty, GetUpstreamProxy() falls back
+	// to the host's HTTPS_PROXY/https_proxy environment variable. This only
+	// affects where the server dials out to - the proxy address exported
+	// into the sandboxed process (HTTP_PROXY/HTTPS_PROXY pointing at this
+	// server) is unrelated and untouched.
+	UpstreamProxy string
+
+	// MetricsAddr, if set, starts an HTTP server on this "host:port"
+	// exposing Prometheus metrics at /metrics and a liveness check at
+	// /healthz. Off by default. Only loopback addresses are accepted (see
+	// config.ProxyConfig's validation) since these endpoints have no
+	// authentication of their own.
+	MetricsAddr string
+
+	// NoMITMHosts lists glob patterns (doublestar syntax) of hosts whose
+	// CONNECT tunnels are passed through untouched instead of intercepted -
+	// for hosts that pin their TLS certificate and reject our generated
+	// leaf cert. Host-level filtering (FilterConfig) still applies before
+	// the tunnel is opened; only interception is skipped.
+	NoMITMHosts []string
+
+	// EventLogger records structured lifecycle events (see events.go),
+	// e.g. a CONNECT handshake rejected because the client pinned its TLS
+	// certificate. Nil-safe: if nil, these events are simply not recorded.
+	EventLogger *EventLogger
+
+	// StreamSocket, when true, starts a StreamServer (see eventstream.go)
+	// publishing every completed RequestLog as a JSON line over a Unix
+	// domain socket at EventSocketPath(SandboxBase), for dashboards that
+	// want lower latency than tailing the persisted log files. Off by
+	// default.
+	StreamSocket bool
 }
 
 func NewConfig(sandboxBase string, port int) *Config {
@@ -87,6 +157,27 @@ func AskLockPath(sandboxBase string) string {
 	return filepath.Join(AskSocketDir(sandboxBase), "ask.lock")
 }
 
+// DecisionCacheFileName is the name of the file persisted decisions from ask
+// mode are stored in, under a sandbox's SandboxBase.
+const DecisionCacheFileName = "filter-decisions.json"
+
+// DecisionCachePath returns the path to the persisted ask-mode decision
+// cache for a sandbox.
+func DecisionCachePath(sandboxBase string) string {
+	return filepath.Join(sandboxBase, DecisionCacheFileName)
+}
+
+// UseSharedCA repoints the CA paths at the shared CA directory under
+// baseDir (the devsandbox base path, e.g. ~/.local/share/devsandbox),
+// replacing the per-project CADir set by NewConfig. Call after NewConfig
+// when proxy.ca.shared is enabled, so all projects reuse one CA instead of
+// each generating and needing its own trusted separately.
+func (c *Config) UseSharedCA(baseDir string) {
+	c.CADir = filepath.Join(baseDir, SharedCADirName)
+	c.CACertPath = filepath.Join(c.CADir, CACertFile)
+	c.CAKeyPath = filepath.Join(c.CADir, CAKeyFile)
+}
+
 func (c *Config) EnsureCADir() error {
 	return os.MkdirAll(c.CADir, 0o700)
 }
@@ -97,6 +188,15 @@ func (c *Config) CAExists() bool {
 	return certErr == nil && keyErr == nil
 }
 
+// GetSocksPort returns the configured SOCKS5 port, defaulting to
+// Port+DefaultSocksPortOffset when SocksPort is not explicitly set.
+func (c *Config) GetSocksPort() int {
+	if c.SocksPort != 0 {
+		return c.SocksPort
+	}
+	return c.Port + DefaultSocksPortOffset
+}
+
 // GetBindAddress returns the bind address, defaulting to 127.0.0.1.
 func (c *Config) GetBindAddress() string {
 	if c.BindAddress != "" {
@@ -105,6 +205,18 @@ func (c *Config) GetBindAddress() string {
 	return DefaultBindAddress
 }
 
+// GetUpstreamProxy returns the configured UpstreamProxy, falling back to the
+// host's HTTPS_PROXY/https_proxy environment variable when unset.
+func (c *Config) GetUpstreamProxy() string {
+	if c.UpstreamProxy != "" {
+		return c.UpstreamProxy
+	}
+	if v := os.Getenv("HTTPS_PROXY"); v != "" {
+		return v
+	}
+	return os.Getenv("https_proxy")
+}
+
 // DockerBridgeIP returns the IP address of the Docker bridge interface (docker0).
 // This is used when running in Docker mode so containers can reach the proxy.
 // Returns empty string if the interface doesn't exist or has no IP.
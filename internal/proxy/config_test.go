@@ -25,3 +25,37 @@ func TestAskLockPath(t *testing.T) {
 		t.Errorf("AskLockPath = %q, want %q", path, expected)
 	}
 }
+
+func TestConfig_GetSocksPort_Default(t *testing.T) {
+	cfg := NewConfig("/tmp/sandbox-test", 8080)
+	if got := cfg.GetSocksPort(); got != 9080 {
+		t.Errorf("GetSocksPort() = %d, want %d", got, 9080)
+	}
+}
+
+func TestConfig_GetSocksPort_Explicit(t *testing.T) {
+	cfg := NewConfig("/tmp/sandbox-test", 8080)
+	cfg.SocksPort = 1080
+	if got := cfg.GetSocksPort(); got != 1080 {
+		t.Errorf("GetSocksPort() = %d, want %d", got, 1080)
+	}
+}
+
+func TestConfig_PerProjectCA_Default(t *testing.T) {
+	cfg := NewConfig("/tmp/sandbox-test/myproject", 8080)
+	if want := "/tmp/sandbox-test/myproject/.ca/ca.crt"; cfg.CACertPath != want {
+		t.Errorf("CACertPath = %q, want %q", cfg.CACertPath, want)
+	}
+}
+
+func TestConfig_UseSharedCA(t *testing.T) {
+	cfg := NewConfig("/tmp/sandbox-test/myproject", 8080)
+	cfg.UseSharedCA("/tmp/sandbox-test")
+
+	if want := "/tmp/sandbox-test/_shared/ca.crt"; cfg.CACertPath != want {
+		t.Errorf("CACertPath = %q, want %q", cfg.CACertPath, want)
+	}
+	if want := "/tmp/sandbox-test/_shared/ca.key"; cfg.CAKeyPath != want {
+		t.Errorf("CAKeyPath = %q, want %q", cfg.CAKeyPath, want)
+	}
+}
@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	EventLogPrefix        = "events"
+	EventLogSuffix        = ".jsonl"
+	EventLogArchiveSuffix = ".jsonl.gz"
+)
+
+// Event is one structured lifecycle record written by EventLogger: a
+// sandbox start/stop, a tool setup result, proxy start/port selection,
+// pasta/firewall setup, or an error, each tagged with Type so `devsandbox
+// logs internal --type <type>` can filter to just one subsystem without
+// scraping goproxy's plain-text warnings (see ProxyLogPrefix).
+type Event struct {
+	Timestamp time.Time `json:"ts"`
+	Type      string    `json:"type"`
+	Level     string    `json:"level"` // "info", "warn", or "error"
+	Message   string    `json:"message"`
+}
+
+// EventLogger records structured lifecycle events to a rotating,
+// gzip-compressed file under the sandbox's internal log directory,
+// independent of the request log and the proxy's own goproxy warning log.
+type EventLogger struct {
+	writer *RotatingFileWriter
+}
+
+// NewEventLogger creates an event logger writing into logDir (typically
+// cfg.InternalLogDir, i.e. logs/internal under the sandbox root).
+func NewEventLogger(logDir string) (*EventLogger, error) {
+	writer, err := NewRotatingFileWriter(RotatingFileWriterConfig{
+		Dir:           logDir,
+		Prefix:        EventLogPrefix,
+		Suffix:        EventLogSuffix,
+		ArchiveSuffix: EventLogArchiveSuffix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event logger: %w", err)
+	}
+	return &EventLogger{writer: writer}, nil
+}
+
+// Infof records an info-level event of the given type (e.g. "sandbox",
+// "proxy", "tool", "network").
+func (l *EventLogger) Infof(eventType, format string, args ...any) {
+	l.record("info", eventType, fmt.Sprintf(format, args...))
+}
+
+// Warnf records a warn-level event of the given type.
+func (l *EventLogger) Warnf(eventType, format string, args ...any) {
+	l.record("warn", eventType, fmt.Sprintf(format, args...))
+}
+
+// Errorf records an error-level event of the given type.
+func (l *EventLogger) Errorf(eventType, format string, args ...any) {
+	l.record("error", eventType, fmt.Sprintf(format, args...))
+}
+
+func (l *EventLogger) record(level, eventType, message string) {
+	if l == nil || l.writer == nil {
+		return
+	}
+	data, err := json.Marshal(Event{
+		Timestamp: time.Now(),
+		Type:      eventType,
+		Level:     level,
+		Message:   message,
+	})
+	if err != nil {
+		return
+	}
+	_, _ = l.writer.Write(append(data, '\n'))
+}
+
+// Close closes the underlying log file.
+func (l *EventLogger) Close() error {
+	if l == nil || l.writer == nil {
+		return nil
+	}
+	return l.writer.Close()
+}
+
+// ReadEvents reads every event logged to logDir (active and rotated,
+// gzip-compressed files), oldest first. eventType restricts the results to
+// a single Type; "" or "all" matches everything. since restricts results to
+// entries at or after that time; a zero Time matches everything.
+func ReadEvents(logDir, eventType string, since time.Time) ([]Event, error) {
+	activePattern := filepath.Join(logDir, EventLogPrefix+"*"+EventLogSuffix)
+	archivePattern := filepath.Join(logDir, EventLogPrefix+"*"+EventLogArchiveSuffix)
+
+	activeFiles, err := filepath.Glob(activePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event log pattern: %w", err)
+	}
+	archiveFiles, err := filepath.Glob(archivePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event log archive pattern: %w", err)
+	}
+
+	files := append(archiveFiles, activeFiles...)
+	sort.Strings(files)
+
+	var events []Event
+	for _, file := range files {
+		fileEvents, err := readEventFile(file)
+		if err != nil {
+			continue
+		}
+		for _, e := range fileEvents {
+			if eventType != "" && eventType != "all" && e.Type != eventType {
+				continue
+			}
+			if !since.IsZero() && e.Timestamp.Before(since) {
+				continue
+			}
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func readEventFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEventLogger_WriteAndRead(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "events-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	logger, err := NewEventLogger(tmpDir)
+	if err != nil {
+		t.Fatalf("NewEventLogger failed: %v", err)
+	}
+
+	logger.Infof("sandbox", "sandbox %s starting", "myproject")
+	logger.Infof("proxy", "proxy listening on port %d", 8080)
+	logger.Errorf("tool", "setup failed for %s", "cargo")
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	events, err := ReadEvents(tmpDir, "all", time.Time{})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+
+	if events[0].Type != "sandbox" || events[0].Level != "info" || events[0].Message != "sandbox myproject starting" {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[2].Type != "tool" || events[2].Level != "error" || events[2].Message != "setup failed for cargo" {
+		t.Errorf("unexpected third event: %+v", events[2])
+	}
+}
+
+func TestReadEvents_FiltersByTypeAndSince(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "events-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	logger, err := NewEventLogger(tmpDir)
+	if err != nil {
+		t.Fatalf("NewEventLogger failed: %v", err)
+	}
+	logger.Infof("sandbox", "start")
+	cutoff := time.Now()
+	logger.Infof("proxy", "port selected")
+	logger.Infof("proxy", "metrics listening")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	proxyEvents, err := ReadEvents(tmpDir, "proxy", time.Time{})
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(proxyEvents) != 2 {
+		t.Fatalf("got %d proxy events, want 2", len(proxyEvents))
+	}
+
+	sinceEvents, err := ReadEvents(tmpDir, "all", cutoff)
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(sinceEvents) != 2 {
+		t.Fatalf("got %d events since cutoff, want 2", len(sinceEvents))
+	}
+}
+
+func TestEventLogger_NilIsNoop(t *testing.T) {
+	var logger *EventLogger
+	logger.Infof("sandbox", "should not panic")
+	logger.Warnf("sandbox", "should not panic")
+	logger.Errorf("sandbox", "should not panic")
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close on nil logger returned error: %v", err)
+	}
+}
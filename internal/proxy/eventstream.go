@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// EventSocketName is the Unix domain socket a StreamServer listens on for
+// live RequestLog subscribers (see `devsandbox logs proxy --follow --socket`).
+const EventSocketName = "proxy-events.sock"
+
+// EventSocketPath returns the path to the live request-event streaming
+// socket under a sandbox's root directory.
+func EventSocketPath(sandboxBase string) string {
+	return filepath.Join(sandboxBase, EventSocketName)
+}
+
+// streamSubscriberBuffer bounds how many pending lines a subscriber can
+// fall behind by before lines start being dropped for it.
+const streamSubscriberBuffer = 64
+
+// StreamServer fans out completed RequestLog entries, as JSON lines, to any
+// number of connected Unix domain socket subscribers - for building a live
+// dashboard without tailing files. A subscriber that can't keep up has lines
+// dropped rather than slowing down or blocking the proxy; Dropped reports
+// the running total so a slow consumer can tell it happened.
+type StreamServer struct {
+	socketPath string
+	listener   net.Listener
+
+	mu      sync.Mutex
+	subs    map[*streamSubscriber]struct{}
+	closed  bool
+	dropped atomic.Uint64
+}
+
+type streamSubscriber struct {
+	ch chan []byte
+}
+
+// NewStreamServer listens on a Unix domain socket at socketPath, removing
+// any stale socket left behind by an unclean shutdown.
+func NewStreamServer(socketPath string) (*StreamServer, error) {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create event socket directory: %w", err)
+	}
+
+	_ = os.Remove(socketPath) // clear a stale socket from an unclean shutdown
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on event socket: %w", err)
+	}
+
+	s := &StreamServer{
+		socketPath: socketPath,
+		listener:   listener,
+		subs:       make(map[*streamSubscriber]struct{}),
+	}
+
+	go s.acceptLoop()
+
+	return s, nil
+}
+
+// SocketPath returns the path to the Unix socket.
+func (s *StreamServer) SocketPath() string {
+	return s.socketPath
+}
+
+// Dropped returns the total number of lines dropped across all subscribers
+// because they fell behind.
+func (s *StreamServer) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *StreamServer) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+			continue
+		}
+
+		sub := &streamSubscriber{ch: make(chan []byte, streamSubscriberBuffer)}
+
+		s.mu.Lock()
+		s.subs[sub] = struct{}{}
+		s.mu.Unlock()
+
+		go s.serveSubscriber(conn, sub)
+	}
+}
+
+func (s *StreamServer) serveSubscriber(conn net.Conn, sub *streamSubscriber) {
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.subs, sub)
+		s.mu.Unlock()
+	}()
+
+	for line := range sub.ch {
+		if _, err := conn.Write(line); err != nil {
+			return
+		}
+	}
+}
+
+// Publish fans line (a single JSON-encoded RequestLog, newline-terminated)
+// out to every connected subscriber. Never blocks: a subscriber whose buffer
+// is already full has this line dropped instead.
+func (s *StreamServer) Publish(line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subs {
+		select {
+		case sub.ch <- line:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Close stops accepting new subscribers, disconnects existing ones, and
+// removes the socket file.
+func (s *StreamServer) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for sub := range s.subs {
+		close(sub.ch)
+	}
+	s.subs = nil
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	_ = os.Remove(s.socketPath)
+	return err
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStreamServer_FanOut(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eventstream-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	socketPath := filepath.Join(tmpDir, EventSocketName)
+	s, err := NewStreamServer(socketPath)
+	if err != nil {
+		t.Fatalf("NewStreamServer failed: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	conn1, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("subscriber 1 dial failed: %v", err)
+	}
+	defer func() { _ = conn1.Close() }()
+
+	conn2, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("subscriber 2 dial failed: %v", err)
+	}
+	defer func() { _ = conn2.Close() }()
+
+	waitForSubscribers(t, s, 2)
+
+	s.Publish([]byte("hello\n"))
+
+	for i, conn := range []net.Conn{conn1, conn2} {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			t.Fatalf("subscriber %d failed to read: %v", i+1, err)
+		}
+		if line != "hello\n" {
+			t.Errorf("subscriber %d got %q, want %q", i+1, line, "hello\n")
+		}
+	}
+}
+
+func TestStreamServer_SlowSubscriberDropsInsteadOfBlocking(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eventstream-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	socketPath := filepath.Join(tmpDir, EventSocketName)
+	s, err := NewStreamServer(socketPath)
+	if err != nil {
+		t.Fatalf("NewStreamServer failed: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	waitForSubscribers(t, s, 1)
+
+	// Never read from conn: publishing far past its buffer must not block.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < streamSubscriberBuffer*4; i++ {
+			s.Publish([]byte("x\n"))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a slow subscriber instead of dropping")
+	}
+
+	if s.Dropped() == 0 {
+		t.Error("expected some lines to be dropped for the unread subscriber")
+	}
+}
+
+func TestStreamServer_CloseRemovesSocket(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eventstream-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	socketPath := filepath.Join(tmpDir, EventSocketName)
+	s, err := NewStreamServer(socketPath)
+	if err != nil {
+		t.Fatalf("NewStreamServer failed: %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("expected socket file to be removed, stat err = %v", err)
+	}
+}
+
+// waitForSubscribers polls until the StreamServer has registered n
+// subscribers, since acceptLoop registers them asynchronously after Dial returns.
+func waitForSubscribers(t *testing.T, s *StreamServer, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		count := len(s.subs)
+		s.mu.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d subscribers", n)
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +27,16 @@ type RotatingFileWriterConfig struct {
 	ArchiveSuffix string // File name suffix for rotated files (e.g., ".jsonl.gz"), empty to disable compression
 	MaxSize       int64  // Max file size before rotation (bytes)
 	MaxFiles      int    // Max number of files to keep
+
+	// MaxAge prunes files (active and archived) older than this once
+	// exceeded, checked after every rotation alongside MaxFiles. Zero means
+	// no age-based pruning.
+	MaxAge time.Duration
+
+	// MaxTotalSize prunes the oldest files once the combined size of all
+	// files under Prefix exceeds this many bytes, checked after every
+	// rotation alongside MaxFiles. Zero means no size-based pruning.
+	MaxTotalSize int64
 }
 
 // RotatingFileWriter writes to rotating log files.
@@ -205,12 +216,33 @@ func (w *RotatingFileWriter) compressFile(srcPath string) {
 	_ = os.Remove(srcPath)
 }
 
+// findNextIndex returns one past the highest index already used by today's
+// files (active or archived). Using the highest index rather than a count
+// of surviving files matters once pruning can remove files before the
+// count reaches MaxFiles (e.g. MaxAge/MaxTotalSize) - a plain count would
+// reuse an already-used index and silently append to that file's leftover
+// content instead of starting a fresh one.
 func (w *RotatingFileWriter) findNextIndex() int {
 	today := time.Now().Format("20060102")
-	// Count both active (.jsonl) and archived (.jsonl.gz) files
-	pattern := filepath.Join(w.cfg.Dir, fmt.Sprintf("%s_%s_*", w.cfg.Prefix, today))
+	prefix := fmt.Sprintf("%s_%s_", w.cfg.Prefix, today)
+	pattern := filepath.Join(w.cfg.Dir, prefix+"*")
 	matches, _ := filepath.Glob(pattern)
-	return len(matches)
+
+	maxIndex := -1
+	for _, m := range matches {
+		rest := strings.TrimPrefix(filepath.Base(m), prefix)
+		if len(rest) < 4 {
+			continue
+		}
+		idx, err := strconv.Atoi(rest[:4])
+		if err != nil {
+			continue
+		}
+		if idx > maxIndex {
+			maxIndex = idx
+		}
+	}
+	return maxIndex + 1
 }
 
 // findLastFile returns the most recent uncompressed file for today and its size
@@ -235,17 +267,21 @@ func (w *RotatingFileWriter) findLastFile() (string, int64) {
 	return lastFile, info.Size()
 }
 
+// pruneOldFiles removes the oldest files (active and archived) under Prefix
+// once any of MaxFiles, MaxAge, or MaxTotalSize is exceeded. The three
+// limits are independent and additive - a file violating any one of them is
+// removed - so e.g. MaxFiles=50 and MaxTotalSize="500MB" both apply at once.
 func (w *RotatingFileWriter) pruneOldFiles() {
-	// Prune both active and archived files
 	pattern := filepath.Join(w.cfg.Dir, w.cfg.Prefix+"*")
 	matches, err := filepath.Glob(pattern)
-	if err != nil || len(matches) <= w.cfg.MaxFiles {
+	if err != nil {
 		return
 	}
 
 	type fileInfo struct {
 		path    string
 		modTime time.Time
+		size    int64
 	}
 	files := make([]fileInfo, 0, len(matches))
 	for _, path := range matches {
@@ -253,17 +289,114 @@ func (w *RotatingFileWriter) pruneOldFiles() {
 		if err != nil {
 			continue
 		}
-		files = append(files, fileInfo{path: path, modTime: info.ModTime()})
+		files = append(files, fileInfo{path: path, modTime: info.ModTime(), size: info.Size()})
 	}
 
 	sort.Slice(files, func(i, j int) bool {
 		return files[i].modTime.Before(files[j].modTime)
 	})
 
-	toRemove := len(files) - w.cfg.MaxFiles
-	for i := range toRemove {
-		_ = os.Remove(files[i].path)
+	remove := make(map[string]bool, len(files))
+
+	if w.cfg.MaxFiles > 0 && len(files) > w.cfg.MaxFiles {
+		for _, f := range files[:len(files)-w.cfg.MaxFiles] {
+			remove[f.path] = true
+		}
+	}
+
+	if w.cfg.MaxAge > 0 {
+		cutoff := time.Now().Add(-w.cfg.MaxAge)
+		for _, f := range files {
+			if f.modTime.Before(cutoff) {
+				remove[f.path] = true
+			}
+		}
+	}
+
+	if w.cfg.MaxTotalSize > 0 {
+		var total int64
+		for _, f := range files {
+			if !remove[f.path] {
+				total += f.size
+			}
+		}
+		for _, f := range files {
+			if total <= w.cfg.MaxTotalSize {
+				break
+			}
+			if remove[f.path] {
+				continue
+			}
+			remove[f.path] = true
+			total -= f.size
+		}
+	}
+
+	for _, f := range files {
+		if remove[f.path] {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+// ParseLogRetentionAge parses a duration string for [proxy.logs] max_age,
+// accepting plain Go duration syntax ("48h") plus "d"/"w" day/week suffixes
+// ("7d", "2w") that time.ParseDuration doesn't support.
+func ParseLogRetentionAge(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	unit := s[len(s)-1]
+	value, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	switch unit {
+	case 'd':
+		return time.Duration(value) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(value) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use h, d, or w, or a Go duration)", s, string(unit))
+	}
+}
+
+// ParseLogRetentionSize parses a byte-size string for [proxy.logs]
+// max_total_size, e.g. "500MB", "2G", "1024K", or a bare byte count.
+func ParseLogRetentionSize(s string) (int64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	numeric := strings.TrimSuffix(s, "B")
+	numeric = strings.TrimSuffix(numeric, "b")
+
+	multiplier := int64(1)
+	switch last := numeric[len(numeric)-1]; last {
+	case 'K', 'k':
+		multiplier = 1 << 10
+		numeric = numeric[:len(numeric)-1]
+	case 'M', 'm':
+		multiplier = 1 << 20
+		numeric = numeric[:len(numeric)-1]
+	case 'G', 'g':
+		multiplier = 1 << 30
+		numeric = numeric[:len(numeric)-1]
+	case 'T', 't':
+		multiplier = 1 << 40
+		numeric = numeric[:len(numeric)-1]
+	}
+
+	n, err := strconv.ParseInt(numeric, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: expected a number optionally suffixed with K/M/G/T (and an optional trailing B)", s)
 	}
+	return n * multiplier, nil
 }
 
 func (w *RotatingFileWriter) Close() error {
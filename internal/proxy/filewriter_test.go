@@ -3,6 +3,7 @@ package proxy
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -142,6 +143,146 @@ func TestRotatingFileWriter_Pruning(t *testing.T) {
 	}
 }
 
+func TestRotatingFileWriter_PruningByMaxTotalSize(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "filewriter-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	w, err := NewRotatingFileWriter(RotatingFileWriterConfig{
+		Dir:          tmpDir,
+		Prefix:       "test",
+		Suffix:       ".log",
+		MaxSize:      5, // rotate on every write
+		MaxFiles:     100,
+		MaxTotalSize: 15, // keep at most 3 5-byte files worth of data
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter failed: %v", err)
+	}
+
+	for i := range 10 {
+		if _, err := w.Write([]byte(fmt.Sprintf("%04d\n", i))); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	allFiles, _ := filepath.Glob(filepath.Join(tmpDir, "test_*"))
+	var total int64
+	for _, f := range allFiles {
+		info, err := os.Stat(f)
+		if err != nil {
+			t.Fatalf("Stat(%s) failed: %v", f, err)
+		}
+		total += info.Size()
+	}
+	if total > 15 {
+		t.Errorf("expected combined size <= 15 bytes after pruning, got %d across %d files", total, len(allFiles))
+	}
+
+	// The newest file should survive, the oldest should not.
+	if _, err := os.Stat(filepath.Join(tmpDir, "test_"+time.Now().Format("20060102")+"_0009.log")); err != nil {
+		t.Errorf("expected newest rotation to survive pruning: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "test_"+time.Now().Format("20060102")+"_0000.log")); !os.IsNotExist(err) {
+		t.Errorf("expected oldest rotation to be pruned, got err=%v", err)
+	}
+}
+
+func TestRotatingFileWriter_PruningByMaxAge(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "filewriter-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	oldFile := filepath.Join(tmpDir, "test_20200101_0000.log")
+	if err := os.WriteFile(oldFile, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	oldTime := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(oldFile, oldTime, oldTime); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	w, err := NewRotatingFileWriter(RotatingFileWriterConfig{
+		Dir:      tmpDir,
+		Prefix:   "test",
+		Suffix:   ".log",
+		MaxSize:  1024,
+		MaxFiles: 100,
+		MaxAge:   time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter failed: %v", err)
+	}
+	if _, err := w.Write([]byte("fresh\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Errorf("expected file older than MaxAge to be pruned, got err=%v", err)
+	}
+}
+
+func TestParseLogRetentionAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"48h", 48 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 2 * 7 * 24 * time.Hour, false},
+		{"", 0, true},
+		{"nope", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLogRetentionAge(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLogRetentionAge(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLogRetentionAge(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseLogRetentionSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"1K", 1 << 10, false},
+		{"500MB", 500 * (1 << 20), false},
+		{"2G", 2 << 30, false},
+		{"", 0, true},
+		{"nope", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLogRetentionSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLogRetentionSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLogRetentionSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestRotatingFileWriter_ReuseExistingFile(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "filewriter-test-*")
 	if err != nil {
@@ -1,32 +1,80 @@
 package proxy
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
 
 // FilterEngine evaluates HTTP requests against filter rules.
 type FilterEngine struct {
-	config        *FilterConfig
-	compiledRules []compiledRule
-	mu            sync.RWMutex
+	config            *FilterConfig
+	compiledRules     []compiledRule
+	compiledBodyRules []compiledBodyRule
+	mu                sync.RWMutex
 
 	// Decision cache for ask mode (host -> action)
 	decisionCache map[string]FilterAction
 	cacheMu       sync.RWMutex
+
+	// persistPath, when set via EnableDecisionPersistence, is where the
+	// decision cache is loaded from and saved to on disk. Empty means the
+	// cache is session-only, as it was before disk persistence existed.
+	persistPath string
+	cacheTTL    time.Duration
+	cacheExpiry map[string]time.Time
+
+	// rateLimiters holds one token bucket per (rule pattern, host) pair
+	// that has hit a RateLimit rule at least once. Purely in-memory - it's
+	// per-session and resets cleanly on restart, unlike the decision cache.
+	rateLimiters   map[string]*tokenBucket
+	rateLimitersMu sync.Mutex
+}
+
+// persistedDecision is the on-disk representation of a single cached
+// decision in the file at persistPath.
+type persistedDecision struct {
+	Action    FilterAction `json:"action"`
+	ExpiresAt time.Time    `json:"expires_at"`
 }
 
 // compiledRule is a filter rule with a pre-compiled matcher.
 type compiledRule struct {
 	rule    FilterRule
 	matcher func(string) bool
+
+	// regex is set only for PatternTypeRegex rules, so a matched rule's
+	// Reason can expand named capture groups (e.g. "blocked ${pkg}") via
+	// expandReason. nil for exact/glob rules.
+	regex *regexp.Regexp
+
+	// rateLimitCount/rateLimitWindow are the parsed form of rule.RateLimit,
+	// set only when rule.RateLimit is non-empty.
+	rateLimitCount  int
+	rateLimitWindow time.Duration
+}
+
+// compiledBodyRule is a BodyFilterRule with ContentType lower-cased and
+// MaxBodySize pre-parsed to bytes.
+type compiledBodyRule struct {
+	rule        BodyFilterRule
+	contentType string // lower-cased; empty means "any"
+	maxBodySize int64  // 0 means this rule doesn't limit size
 }
 
 // NewFilterEngine creates a new filter engine with the given configuration.
@@ -42,6 +90,8 @@ func NewFilterEngine(cfg *FilterConfig) (*FilterEngine, error) {
 	engine := &FilterEngine{
 		config:        cfg,
 		decisionCache: make(map[string]FilterAction),
+		cacheExpiry:   make(map[string]time.Time),
+		rateLimiters:  make(map[string]*tokenBucket),
 	}
 
 	// Compile all rules
@@ -53,14 +103,41 @@ func NewFilterEngine(cfg *FilterConfig) (*FilterEngine, error) {
 		engine.compiledRules = append(engine.compiledRules, compiled)
 	}
 
+	for _, rule := range cfg.BodyRules {
+		compiled, err := compileBodyRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile body rule: %w", err)
+		}
+		engine.compiledBodyRules = append(engine.compiledBodyRules, compiled)
+	}
+
 	return engine, nil
 }
 
+// compileBodyRule lower-cases ContentType and pre-parses MaxBodySize.
+func compileBodyRule(rule BodyFilterRule) (compiledBodyRule, error) {
+	compiled := compiledBodyRule{
+		rule:        rule,
+		contentType: strings.ToLower(strings.TrimSpace(rule.ContentType)),
+	}
+
+	if rule.MaxBodySize != "" {
+		size, err := ParseLogRetentionSize(rule.MaxBodySize)
+		if err != nil {
+			return compiledBodyRule{}, fmt.Errorf("invalid max_body_size %q: %w", rule.MaxBodySize, err)
+		}
+		compiled.maxBodySize = size
+	}
+
+	return compiled, nil
+}
+
 // compileRule creates a compiled rule with a pre-built matcher function.
 func compileRule(rule FilterRule) (compiledRule, error) {
 	patternType := rule.DetectPatternType()
 
 	var matcher func(string) bool
+	var regex *regexp.Regexp
 
 	switch patternType {
 	case PatternTypeExact:
@@ -89,15 +166,28 @@ func compileRule(rule FilterRule) (compiledRule, error) {
 		matcher = func(s string) bool {
 			return re.MatchString(s)
 		}
+		regex = re
 
 	default:
 		return compiledRule{}, fmt.Errorf("unknown pattern type: %s", patternType)
 	}
 
-	return compiledRule{
+	compiled := compiledRule{
 		rule:    rule,
 		matcher: matcher,
-	}, nil
+		regex:   regex,
+	}
+
+	if rule.RateLimit != "" {
+		count, window, err := ParseRateLimit(rule.RateLimit)
+		if err != nil {
+			return compiledRule{}, err
+		}
+		compiled.rateLimitCount = count
+		compiled.rateLimitWindow = window
+	}
+
+	return compiled, nil
 }
 
 // Match evaluates the request against filter rules and returns a decision.
@@ -127,18 +217,33 @@ func (e *FilterEngine) Match(req *http.Request) FilterDecision {
 
 	// Evaluate rules in order
 	for _, compiled := range e.compiledRules {
-		target := e.getMatchTarget(req, compiled.rule.GetScope())
+		target := e.getMatchTarget(req, compiled.rule)
 		if compiled.matcher(target) {
+			if compiled.rule.RateLimit != "" && !e.allowRateLimited(compiled, req.Host) {
+				return FilterDecision{
+					Action:    FilterActionRateLimited,
+					Rule:      &compiled.rule,
+					Reason:    fmt.Sprintf("rate limit exceeded (%s) for %s", compiled.rule.RateLimit, NormalizeHost(req.Host)),
+					IsDefault: false,
+				}
+			}
+
 			reason := compiled.rule.Reason
 			if reason == "" {
 				reason = fmt.Sprintf("matched rule: %s", compiled.rule.Pattern)
+			} else {
+				reason = expandReason(reason, compiled.regex, target)
 			}
-			return FilterDecision{
+			decision := FilterDecision{
 				Action:    compiled.rule.Action,
 				Rule:      &compiled.rule,
 				Reason:    reason,
 				IsDefault: false,
 			}
+			if compiled.rule.Action == FilterActionRewrite {
+				decision.RewrittenURL = rewriteURL(compiled, target)
+			}
+			return decision
 		}
 	}
 
@@ -151,9 +256,62 @@ func (e *FilterEngine) Match(req *http.Request) FilterDecision {
 	}
 }
 
-// getMatchTarget extracts the appropriate string to match based on scope.
-func (e *FilterEngine) getMatchTarget(req *http.Request, scope FilterScope) string {
-	switch scope {
+// MatchBody evaluates a POST/PUT request's Content-Type and buffered body
+// size against FilterConfig.BodyRules, independent of the host/path/url
+// rules evaluated by Match. bodyLen is the number of body bytes
+// RequestLogger.LogRequest actually buffered; truncated reports whether the
+// real body is at least MaxBufferedBodySize bytes, in which case it's
+// treated as exceeding every rule's MaxBodySize rather than silently
+// passing a body of unknown size through.
+func (e *FilterEngine) MatchBody(req *http.Request, bodyLen int, truncated bool) FilterDecision {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.config.IsEnabled() || len(e.compiledBodyRules) == 0 {
+		return FilterDecision{Action: FilterActionAllow, IsDefault: true, Reason: "no body rules configured"}
+	}
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		return FilterDecision{Action: FilterActionAllow, IsDefault: true, Reason: "body rules only apply to POST/PUT"}
+	}
+
+	contentType := normalizeContentType(req.Header.Get("Content-Type"))
+
+	for _, compiled := range e.compiledBodyRules {
+		if compiled.maxBodySize > 0 && (truncated || int64(bodyLen) > compiled.maxBodySize) {
+			reason := compiled.rule.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("request body exceeds max_body_size (%s)", compiled.rule.MaxBodySize)
+			}
+			return FilterDecision{Action: FilterActionBlock, Reason: reason, BodyTooLarge: true}
+		}
+
+		if compiled.contentType != "" && compiled.contentType == contentType {
+			reason := compiled.rule.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("request content-type %q is blocked", contentType)
+			}
+			return FilterDecision{Action: FilterActionBlock, Reason: reason}
+		}
+	}
+
+	return FilterDecision{Action: FilterActionAllow, IsDefault: true, Reason: "no body rule matched"}
+}
+
+// normalizeContentType strips any ";"-delimited parameters (e.g.
+// "; charset=utf-8") from a Content-Type header value and lower-cases the
+// result, so "application/json; charset=utf-8" matches a rule configured
+// with just "application/json".
+func normalizeContentType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// getMatchTarget extracts the appropriate string to match based on the
+// rule's scope.
+func (e *FilterEngine) getMatchTarget(req *http.Request, rule FilterRule) string {
+	switch rule.GetScope() {
 	case FilterScopeHost:
 		return NormalizeHost(req.Host)
 
@@ -163,11 +321,149 @@ func (e *FilterEngine) getMatchTarget(req *http.Request, scope FilterScope) stri
 	case FilterScopeURL:
 		return req.URL.String()
 
+	case FilterScopeQuery:
+		return req.URL.Query().Get(rule.QueryParam)
+
 	default:
 		return NormalizeHost(req.Host)
 	}
 }
 
+// MatchResponse evaluates a response against response-scoped filter rules
+// (FilterScopeStatus, FilterScopeLocationHost) and returns a decision.
+// Rules scoped to the request (host, path, url, query) are ignored here -
+// they were already evaluated by Match before the request went out. req is
+// the original request, used to resolve a relative Location header.
+func (e *FilterEngine) MatchResponse(resp *http.Response, req *http.Request) FilterDecision {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.config.IsEnabled() {
+		return FilterDecision{
+			Action:    FilterActionAllow,
+			IsDefault: true,
+			Reason:    "filtering disabled",
+		}
+	}
+
+	for _, compiled := range e.compiledRules {
+		if !compiled.rule.GetScope().IsResponseScope() {
+			continue
+		}
+		target := e.getResponseMatchTarget(resp, req, compiled.rule)
+		if target != "" && compiled.matcher(target) {
+			reason := compiled.rule.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("matched rule: %s", compiled.rule.Pattern)
+			} else {
+				reason = expandReason(reason, compiled.regex, target)
+			}
+			return FilterDecision{
+				Action:    compiled.rule.Action,
+				Rule:      &compiled.rule,
+				Reason:    reason,
+				IsDefault: false,
+			}
+		}
+	}
+
+	return FilterDecision{
+		Action:    FilterActionAllow,
+		IsDefault: true,
+		Reason:    "no response rule matched",
+	}
+}
+
+// getResponseMatchTarget extracts the appropriate string to match based on
+// the rule's (response-only) scope. Returns "" if the scope doesn't apply,
+// e.g. FilterScopeLocationHost on a response with no Location header.
+func (e *FilterEngine) getResponseMatchTarget(resp *http.Response, req *http.Request, rule FilterRule) string {
+	switch rule.GetScope() {
+	case FilterScopeStatus:
+		return strconv.Itoa(resp.StatusCode)
+
+	case FilterScopeLocationHost:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return ""
+		}
+		locURL, err := req.URL.Parse(location)
+		if err != nil {
+			return ""
+		}
+		return NormalizeHost(locURL.Host)
+
+	default:
+		return ""
+	}
+}
+
+// expandReason interpolates named regex capture groups from a rule's match
+// into its Reason template, e.g. a pattern with `(?P<pkg>[^/]+)` and
+// Reason = "blocked package ${pkg}" becomes "blocked package leftpad". Falls
+// back to the literal reason unchanged for glob/exact rules (re == nil) or a
+// regex with no named groups, so existing reasons with a literal "$" in them
+// aren't affected.
+func expandReason(reason string, re *regexp.Regexp, target string) string {
+	if re == nil {
+		return reason
+	}
+
+	hasNamedGroup := false
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			hasNamedGroup = true
+			break
+		}
+	}
+	if !hasNamedGroup {
+		return reason
+	}
+
+	match := re.FindStringSubmatchIndex(target)
+	if match == nil {
+		return reason
+	}
+
+	return string(re.ExpandString(nil, reason, target, match))
+}
+
+// rewriteURL computes the FilterActionRewrite target for a matched rule: if
+// the rule's pattern is a regex, compiled.rule.RewriteTo is expanded against
+// target using Go regexp syntax ($1, ${name}, ...), the same as
+// ResponseRewriteRule.Replacement; otherwise RewriteTo is used verbatim as
+// the complete new URL.
+func rewriteURL(compiled compiledRule, target string) string {
+	if compiled.regex == nil {
+		return compiled.rule.RewriteTo
+	}
+	match := compiled.regex.FindStringSubmatchIndex(target)
+	if match == nil {
+		return compiled.rule.RewriteTo
+	}
+	return string(compiled.regex.ExpandString(nil, compiled.rule.RewriteTo, target, match))
+}
+
+// hostFilterDecision evaluates target (a CONNECT "host:port") against
+// engine's host-scoped rules, for call sites that only ever see a CONNECT
+// target and no further request: SocksServer and the no_mitm_hosts
+// passthrough in setupMITM. Path/URL/query-scoped rules fall back to
+// matching against "/", since there's no request line to take them from at
+// this point in the connection. engine may be nil, in which case everything
+// is allowed.
+func hostFilterDecision(engine *FilterEngine, target string) FilterDecision {
+	if engine == nil || !engine.IsEnabled() {
+		return FilterDecision{Action: FilterActionAllow, IsDefault: true, Reason: "filtering disabled"}
+	}
+
+	req := &http.Request{
+		Method: "CONNECT",
+		Host:   target,
+		URL:    &url.URL{Host: target, Path: "/"},
+	}
+	return engine.Match(req)
+}
+
 // NormalizeHost extracts the hostname without port, handling IPv6 addresses correctly.
 func NormalizeHost(hostport string) string {
 	// Use net.SplitHostPort for robust parsing
@@ -182,8 +478,28 @@ func NormalizeHost(hostport string) string {
 	return host
 }
 
+// allowRateLimited consumes a token from the bucket for compiled's rule on
+// host, creating the bucket on first use, and reports whether the request
+// may proceed.
+func (e *FilterEngine) allowRateLimited(compiled compiledRule, host string) bool {
+	key := compiled.rule.Pattern + "|" + NormalizeHost(host)
+
+	e.rateLimitersMu.Lock()
+	bucket, ok := e.rateLimiters[key]
+	if !ok {
+		refillPerSec := float64(compiled.rateLimitCount) / compiled.rateLimitWindow.Seconds()
+		bucket = newTokenBucket(float64(compiled.rateLimitCount), refillPerSec)
+		e.rateLimiters[key] = bucket
+	}
+	e.rateLimitersMu.Unlock()
+
+	return bucket.Allow()
+}
+
 // CacheDecision stores a decision for future requests to the same host.
 // The host is normalized (port removed) to ensure consistent cache keys.
+// If EnableDecisionPersistence was called, the decision is also written to
+// disk so it survives a sandbox restart.
 func (e *FilterEngine) CacheDecision(host string, action FilterAction) {
 	if !e.config.IsCacheEnabled() {
 		return
@@ -191,7 +507,16 @@ func (e *FilterEngine) CacheDecision(host string, action FilterAction) {
 
 	e.cacheMu.Lock()
 	defer e.cacheMu.Unlock()
-	e.decisionCache[NormalizeHost(host)] = action
+	host = NormalizeHost(host)
+	e.decisionCache[host] = action
+	if e.persistPath != "" {
+		e.cacheExpiry[host] = time.Now().Add(e.cacheTTL)
+		if err := e.persistDecisionsLocked(); err != nil {
+			// Best-effort: the in-memory cache still serves this session
+			// even if the file couldn't be written.
+			log.Printf("filter: failed to persist decision cache: %v", err)
+		}
+	}
 }
 
 // getCachedDecision retrieves a cached decision for a host.
@@ -202,11 +527,152 @@ func (e *FilterEngine) getCachedDecision(host string) FilterAction {
 	return e.decisionCache[NormalizeHost(host)]
 }
 
-// ClearCache clears all cached decisions.
+// ClearCache clears all cached decisions, in memory and (if enabled) on disk.
 func (e *FilterEngine) ClearCache() {
 	e.cacheMu.Lock()
 	defer e.cacheMu.Unlock()
 	e.decisionCache = make(map[string]FilterAction)
+	e.cacheExpiry = make(map[string]time.Time)
+	if e.persistPath != "" {
+		if err := e.persistDecisionsLocked(); err != nil {
+			log.Printf("filter: failed to persist decision cache: %v", err)
+		}
+	}
+}
+
+// EnableDecisionPersistence turns on disk persistence for cached ask-mode
+// decisions: sandboxRoot/filter-decisions.json is read for unexpired
+// entries now, and every future CacheDecision call rewrites it. Call once
+// after NewFilterEngine, before the engine starts serving requests.
+func (e *FilterEngine) EnableDecisionPersistence(sandboxRoot string) error {
+	ttl, err := e.config.GetCacheTTL()
+	if err != nil {
+		return fmt.Errorf("invalid cache_ttl: %w", err)
+	}
+
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+	e.persistPath = DecisionCachePath(sandboxRoot)
+	e.cacheTTL = ttl
+
+	data, err := os.ReadFile(e.persistPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read decision cache: %w", err)
+	}
+
+	var onDisk map[string]persistedDecision
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		return fmt.Errorf("failed to parse decision cache: %w", err)
+	}
+
+	now := time.Now()
+	for host, decision := range onDisk {
+		if decision.ExpiresAt.Before(now) {
+			continue // expired - ask again instead of loading
+		}
+		e.decisionCache[host] = decision.Action
+		e.cacheExpiry[host] = decision.ExpiresAt
+	}
+
+	return nil
+}
+
+// persistDecisionsLocked writes the current decision cache to persistPath.
+// Callers must hold cacheMu.
+func (e *FilterEngine) persistDecisionsLocked() error {
+	onDisk := make(map[string]persistedDecision, len(e.decisionCache))
+	for host, action := range e.decisionCache {
+		onDisk[host] = persistedDecision{Action: action, ExpiresAt: e.cacheExpiry[host]}
+	}
+
+	data, err := json.MarshalIndent(onDisk, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode decision cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.persistPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create sandbox directory: %w", err)
+	}
+
+	return os.WriteFile(e.persistPath, data, 0o600)
+}
+
+// DecisionEntry is an exported, CLI-facing view of one cached ask-mode
+// decision: the host it applies to, the remembered action, and when it
+// expires. See LoadDecisionCache.
+type DecisionEntry struct {
+	Host      string       `json:"host"`
+	Action    FilterAction `json:"action"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// LoadDecisionCache reads the persisted ask-mode decision cache at
+// sandboxRoot (see DecisionCachePath) and returns one DecisionEntry per
+// cached host, sorted by host. Unlike EnableDecisionPersistence, expired
+// entries are included rather than dropped - callers (e.g. "filter
+// decisions list") decide how to present them. Returns an empty slice, not
+// an error, if no cache file exists yet.
+func LoadDecisionCache(sandboxRoot string) ([]DecisionEntry, error) {
+	data, err := os.ReadFile(DecisionCachePath(sandboxRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decision cache: %w", err)
+	}
+
+	var onDisk map[string]persistedDecision
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		return nil, fmt.Errorf("failed to parse decision cache: %w", err)
+	}
+
+	entries := make([]DecisionEntry, 0, len(onDisk))
+	for host, decision := range onDisk {
+		entries = append(entries, DecisionEntry{Host: host, Action: decision.Action, ExpiresAt: decision.ExpiresAt})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Host < entries[j].Host })
+	return entries, nil
+}
+
+// RemoveDecision deletes a single host's cached decision from the
+// persisted cache at sandboxRoot, leaving the rest intact, and reports
+// whether the host had a cached decision to remove.
+//
+// Like ClearCache, this only touches the on-disk file: a sandbox session
+// already running with this host's decision loaded into memory keeps
+// using it until the session restarts and reloads the file.
+func RemoveDecision(sandboxRoot, host string) (bool, error) {
+	path := DecisionCachePath(sandboxRoot)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read decision cache: %w", err)
+	}
+
+	var onDisk map[string]persistedDecision
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		return false, fmt.Errorf("failed to parse decision cache: %w", err)
+	}
+
+	host = NormalizeHost(host)
+	if _, ok := onDisk[host]; !ok {
+		return false, nil
+	}
+	delete(onDisk, host)
+
+	out, err := json.MarshalIndent(onDisk, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to encode decision cache: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		return false, fmt.Errorf("failed to write decision cache: %w", err)
+	}
+	return true, nil
 }
 
 // IsEnabled returns true if filtering is active.
@@ -243,3 +709,86 @@ func BlockResponse(req *http.Request, reason string) *http.Response {
 		Request:       req,
 	}
 }
+
+// RateLimitResponse creates an HTTP 429 response for requests rejected by a
+// rule's RateLimit.
+func RateLimitResponse(req *http.Request, reason string) *http.Response {
+	body := fmt.Sprintf("Request rate limited by devsandbox filter: %s\n", reason)
+
+	return &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":   []string{"text/plain; charset=utf-8"},
+			"Content-Length": []string{fmt.Sprintf("%d", len(body))},
+			"X-Blocked-By":   []string{"devsandbox-filter"},
+		},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// BodyTooLargeResponse creates an HTTP 413 response for requests blocked by
+// a FilterConfig.BodyRules max_body_size rule.
+func BodyTooLargeResponse(req *http.Request, reason string) *http.Response {
+	body := fmt.Sprintf("Request blocked by devsandbox filter: %s\n", reason)
+
+	return &http.Response{
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Status:     "413 Request Entity Too Large",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":   []string{"text/plain; charset=utf-8"},
+			"Content-Length": []string{fmt.Sprintf("%d", len(body))},
+			"X-Blocked-By":   []string{"devsandbox-filter"},
+		},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// tokenBucket is a simple token-bucket rate limiter: tokens refill
+// continuously at refillPerSec up to max, and Allow consumes one.
+type tokenBucket struct {
+	mu           sync.Mutex
+	tokens       float64
+	max          float64
+	refillPerSec float64
+	lastRefill   time.Time
+}
+
+func newTokenBucket(max float64, refillPerSec float64) *tokenBucket {
+	return &tokenBucket{
+		tokens:       max,
+		max:          max,
+		refillPerSec: refillPerSec,
+		lastRefill:   time.Now(),
+	}
+}
+
+// Allow reports whether a token is available, consuming it if so.
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+	b.tokens += elapsed * b.refillPerSec
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,42 @@
+package proxy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFilterConfigFile reads a standalone filter policy file and unmarshals
+// it into a FilterConfig, auto-detecting the format from the file
+// extension: ".yaml" and ".yml" are parsed as YAML, everything else
+// (including ".toml") as TOML. Both formats populate the same
+// FilterConfig/FilterRule fields and are validated identically, so teams
+// that standardize on YAML policy tooling don't need to convert to TOML.
+func LoadFilterConfigFile(path string) (*FilterConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter file %s: %w", path, err)
+	}
+
+	cfg := &FilterConfig{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML filter file %s: %w", path, err)
+		}
+	default:
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML filter file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid filter file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const filterTOMLDoc = `
+default_action = "block"
+ask_timeout = 45
+cache_decisions = false
+
+[[rules]]
+pattern = "*.github.com"
+action = "allow"
+scope = "host"
+
+[[rules]]
+pattern = "evil.example.com"
+action = "block"
+scope = "host"
+reason = "known malicious host"
+`
+
+const filterYAMLDoc = `
+default_action: block
+ask_timeout: 45
+cache_decisions: false
+rules:
+  - pattern: "*.github.com"
+    action: allow
+    scope: host
+  - pattern: evil.example.com
+    action: block
+    scope: host
+    reason: known malicious host
+`
+
+func TestLoadFilterConfigFile_YAMLAndTOMLEquivalent(t *testing.T) {
+	dir := t.TempDir()
+
+	tomlPath := filepath.Join(dir, "policy.toml")
+	writeTestFile(t, tomlPath, filterTOMLDoc)
+
+	yamlPath := filepath.Join(dir, "policy.yaml")
+	writeTestFile(t, yamlPath, filterYAMLDoc)
+
+	tomlCfg, err := LoadFilterConfigFile(tomlPath)
+	if err != nil {
+		t.Fatalf("LoadFilterConfigFile(toml) failed: %v", err)
+	}
+
+	yamlCfg, err := LoadFilterConfigFile(yamlPath)
+	if err != nil {
+		t.Fatalf("LoadFilterConfigFile(yaml) failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(tomlCfg, yamlCfg) {
+		t.Errorf("YAML and TOML produced different FilterConfig values:\nTOML: %+v\nYAML: %+v", tomlCfg, yamlCfg)
+	}
+}
+
+func TestLoadFilterConfigFile_YmlExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yml")
+	writeTestFile(t, path, filterYAMLDoc)
+
+	cfg, err := LoadFilterConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadFilterConfigFile(.yml) failed: %v", err)
+	}
+	if cfg.DefaultAction != FilterActionBlock {
+		t.Errorf("DefaultAction = %q, want %q", cfg.DefaultAction, FilterActionBlock)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Errorf("len(Rules) = %d, want 2", len(cfg.Rules))
+	}
+}
+
+func TestLoadFilterConfigFile_InvalidRuleFailsValidation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	writeTestFile(t, path, "default_action: block\nrules:\n  - pattern: \"\"\n    action: allow\n")
+
+	if _, err := LoadFilterConfigFile(path); err == nil {
+		t.Fatal("expected validation error for rule with empty pattern")
+	}
+}
+
+func TestLoadFilterConfigFile_MissingFile(t *testing.T) {
+	if _, err := LoadFilterConfigFile("/nonexistent/policy.yaml"); err == nil {
+		t.Fatal("expected error for missing filter file")
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write test file %s: %v", path, err)
+	}
+}
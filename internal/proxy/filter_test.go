@@ -1,9 +1,12 @@
 package proxy
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
@@ -85,6 +88,90 @@ func TestFilterEngine_RegexPattern(t *testing.T) {
 	}
 }
 
+func TestFilterEngine_RegexNamedGroupReason(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow, // blacklist behavior
+		Rules: []FilterRule{
+			{
+				Pattern: `^/pkg/(?P<pkg>[^/]+)`,
+				Action:  FilterActionBlock,
+				Scope:   FilterScopePath,
+				Type:    PatternTypeRegex,
+				Reason:  "blocked package ${pkg}",
+			},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{
+		Host: "example.com",
+		URL:  &url.URL{Host: "example.com", Path: "/pkg/leftpad"},
+	}
+	decision := engine.Match(req)
+	if decision.Action != FilterActionBlock {
+		t.Fatalf("got action %s, want block", decision.Action)
+	}
+	if want := "blocked package leftpad"; decision.Reason != want {
+		t.Errorf("Reason = %q, want %q", decision.Reason, want)
+	}
+}
+
+func TestFilterEngine_RegexReason_NoNamedGroupsUnchanged(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{
+				Pattern: `^/pkg/[^/]+`,
+				Action:  FilterActionBlock,
+				Scope:   FilterScopePath,
+				Type:    PatternTypeRegex,
+				Reason:  "blocked, ${not} a real group",
+			},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{
+		Host: "example.com",
+		URL:  &url.URL{Host: "example.com", Path: "/pkg/leftpad"},
+	}
+	decision := engine.Match(req)
+	if want := "blocked, ${not} a real group"; decision.Reason != want {
+		t.Errorf("Reason = %q, want unchanged %q", decision.Reason, want)
+	}
+}
+
+func TestFilterEngine_GlobReason_LiteralDollarUnchanged(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "*.tracking.io", Action: FilterActionBlock, Reason: "tracking domain, $5 fine"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{
+		Host: "ads.tracking.io",
+		URL:  &url.URL{Host: "ads.tracking.io", Path: "/"},
+	}
+	decision := engine.Match(req)
+	if want := "tracking domain, $5 fine"; decision.Reason != want {
+		t.Errorf("Reason = %q, want unchanged %q", decision.Reason, want)
+	}
+}
+
 func TestFilterEngine_BlacklistMode(t *testing.T) {
 	cfg := &FilterConfig{
 		DefaultAction: FilterActionAllow, // blacklist behavior
@@ -163,6 +250,124 @@ func TestFilterEngine_PathScope(t *testing.T) {
 	}
 }
 
+func TestFilterEngine_QueryScope(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow, // blacklist behavior
+		Rules: []FilterRule{
+			{Pattern: "raw", Action: FilterActionBlock, Scope: FilterScopeQuery, QueryParam: "format", Type: PatternTypeExact},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		expected FilterAction
+	}{
+		{"blocked raw format", "format=raw", FilterActionBlock},
+		{"allowed other format", "format=json", FilterActionAllow},
+		{"allowed missing param", "other=1", FilterActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &url.URL{Host: "example.com", Path: "/download", RawQuery: tt.rawQuery}
+			req := &http.Request{Host: "example.com", URL: u}
+			decision := engine.Match(req)
+			if decision.Action != tt.expected {
+				t.Errorf("got action %s, want %s", decision.Action, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterEngine_RewriteAction(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{
+				Pattern:   `^https://registry\.npmjs\.org(/.*)$`,
+				Action:    FilterActionRewrite,
+				Scope:     FilterScopeURL,
+				Type:      PatternTypeRegex,
+				RewriteTo: "https://mirror.internal$1",
+			},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	u := &url.URL{Scheme: "https", Host: "registry.npmjs.org", Path: "/left-pad"}
+	req := &http.Request{Host: "registry.npmjs.org", URL: u}
+	decision := engine.Match(req)
+
+	if decision.Action != FilterActionRewrite {
+		t.Fatalf("got action %s, want %s", decision.Action, FilterActionRewrite)
+	}
+	want := "https://mirror.internal/left-pad"
+	if decision.RewrittenURL != want {
+		t.Errorf("got rewritten URL %q, want %q", decision.RewrittenURL, want)
+	}
+}
+
+func TestFilterEngine_RewriteAction_NoCaptureGroups(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "https://old.example.com/*", Action: FilterActionRewrite, Scope: FilterScopeURL, Type: PatternTypeGlob, RewriteTo: "https://new.example.com/"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	u := &url.URL{Scheme: "https", Host: "old.example.com", Path: "/foo"}
+	req := &http.Request{Host: "old.example.com", URL: u}
+	decision := engine.Match(req)
+
+	if decision.RewrittenURL != "https://new.example.com/" {
+		t.Errorf("got rewritten URL %q, want %q", decision.RewrittenURL, "https://new.example.com/")
+	}
+}
+
+func TestFilterRule_Validate_RewriteRequiresTargetAndURLScope(t *testing.T) {
+	rule := FilterRule{Pattern: "https://old.example.com/*", Action: FilterActionRewrite, Scope: FilterScopeURL}
+	if err := rule.Validate(); err == nil {
+		t.Error("expected error when rewrite_to is missing")
+	}
+
+	rule.RewriteTo = "https://new.example.com/"
+	if err := rule.Validate(); err != nil {
+		t.Errorf("unexpected error with rewrite_to set: %v", err)
+	}
+
+	rule.Scope = FilterScopeHost
+	if err := rule.Validate(); err == nil {
+		t.Error("expected error when scope is not url for a rewrite rule")
+	}
+}
+
+func TestFilterRule_Validate_QueryScopeRequiresParam(t *testing.T) {
+	rule := FilterRule{Pattern: "raw", Action: FilterActionBlock, Scope: FilterScopeQuery}
+	if err := rule.Validate(); err == nil {
+		t.Error("expected error when query_param is missing for scope=query")
+	}
+
+	rule.QueryParam = "format"
+	if err := rule.Validate(); err != nil {
+		t.Errorf("unexpected error with query_param set: %v", err)
+	}
+}
+
 func TestFilterEngine_DisabledMode(t *testing.T) {
 	cfg := &FilterConfig{
 		// DefaultAction empty = filtering disabled
@@ -276,6 +481,30 @@ func TestFilterConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid ask_via tty",
+			cfg: FilterConfig{
+				DefaultAction: FilterActionAsk,
+				AskVia:        AskViaTTY,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid ask_via notify",
+			cfg: FilterConfig{
+				DefaultAction: FilterActionAsk,
+				AskVia:        AskViaNotify,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid ask_via",
+			cfg: FilterConfig{
+				DefaultAction: FilterActionAsk,
+				AskVia:        "bogus",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -425,6 +654,582 @@ func TestFilterEngine_CacheNormalization(t *testing.T) {
 	}
 }
 
+func TestFilterEngine_MatchResponse_Status(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "3*", Action: FilterActionBlock, Scope: FilterScopeStatus, Type: PatternTypeGlob, Reason: "redirects blocked"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{Host: "example.com", URL: &url.URL{Host: "example.com", Path: "/"}}
+
+	tests := []struct {
+		name     string
+		status   int
+		expected FilterAction
+	}{
+		{"redirect blocked", http.StatusFound, FilterActionBlock},
+		{"ok allowed", http.StatusOK, FilterActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			decision := engine.MatchResponse(resp, req)
+			if decision.Action != tt.expected {
+				t.Errorf("got action %s, want %s", decision.Action, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterEngine_MatchResponse_LocationHost(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "*.internal.corp", Action: FilterActionBlock, Scope: FilterScopeLocationHost, Reason: "redirect to internal host"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{Host: "example.com", URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/"}}
+
+	tests := []struct {
+		name     string
+		location string
+		expected FilterAction
+	}{
+		{"redirect to internal host blocked", "https://svc.internal.corp/secret", FilterActionBlock},
+		{"redirect to external host allowed", "https://other.example.org/", FilterActionAllow},
+		{"relative redirect allowed", "/other", FilterActionAllow},
+		{"no location header allowed", "", FilterActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.location != "" {
+				header.Set("Location", tt.location)
+			}
+			resp := &http.Response{StatusCode: http.StatusFound, Header: header}
+			decision := engine.MatchResponse(resp, req)
+			if decision.Action != tt.expected {
+				t.Errorf("got action %s, want %s", decision.Action, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterEngine_MatchResponse_IgnoresRequestScopedRules(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "*", Action: FilterActionBlock, Scope: FilterScopeHost},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{Host: "example.com", URL: &url.URL{Host: "example.com", Path: "/"}}
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	decision := engine.MatchResponse(resp, req)
+	if decision.Action != FilterActionAllow {
+		t.Errorf("expected request-scoped rule to be ignored by MatchResponse, got %s", decision.Action)
+	}
+}
+
+func TestFilterEngine_DecisionPersistence_SurvivesRestart(t *testing.T) {
+	sandboxRoot := t.TempDir()
+
+	cfg := &FilterConfig{DefaultAction: FilterActionAsk, CacheDecisions: boolPtr(true)}
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+	if err := engine.EnableDecisionPersistence(sandboxRoot); err != nil {
+		t.Fatalf("EnableDecisionPersistence: %v", err)
+	}
+	engine.CacheDecision("example.com", FilterActionAllow)
+
+	if _, err := os.Stat(DecisionCachePath(sandboxRoot)); err != nil {
+		t.Fatalf("expected decision cache file to be written: %v", err)
+	}
+
+	// Simulate a restart: a fresh engine loading the same sandbox root.
+	restarted, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+	if err := restarted.EnableDecisionPersistence(sandboxRoot); err != nil {
+		t.Fatalf("EnableDecisionPersistence: %v", err)
+	}
+
+	if got := restarted.getCachedDecision("example.com"); got != FilterActionAllow {
+		t.Errorf("expected persisted decision to be loaded, got %q", got)
+	}
+}
+
+func TestFilterEngine_DecisionPersistence_ExpiredEntriesAreDropped(t *testing.T) {
+	sandboxRoot := t.TempDir()
+
+	onDisk := map[string]persistedDecision{
+		"example.com": {Action: FilterActionAllow, ExpiresAt: time.Now().Add(-time.Hour)},
+		"fresh.com":   {Action: FilterActionBlock, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	data, err := json.Marshal(onDisk)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(DecisionCachePath(sandboxRoot), data, 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	cfg := &FilterConfig{DefaultAction: FilterActionAsk, CacheDecisions: boolPtr(true)}
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+	if err := engine.EnableDecisionPersistence(sandboxRoot); err != nil {
+		t.Fatalf("EnableDecisionPersistence: %v", err)
+	}
+
+	if got := engine.getCachedDecision("example.com"); got != "" {
+		t.Errorf("expected expired entry to be dropped, got %q", got)
+	}
+	if got := engine.getCachedDecision("fresh.com"); got != FilterActionBlock {
+		t.Errorf("expected unexpired entry to be loaded, got %q", got)
+	}
+}
+
+func TestFilterEngine_ClearCache_RemovesPersistedEntries(t *testing.T) {
+	sandboxRoot := t.TempDir()
+
+	cfg := &FilterConfig{DefaultAction: FilterActionAsk, CacheDecisions: boolPtr(true)}
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+	if err := engine.EnableDecisionPersistence(sandboxRoot); err != nil {
+		t.Fatalf("EnableDecisionPersistence: %v", err)
+	}
+	engine.CacheDecision("example.com", FilterActionAllow)
+	engine.ClearCache()
+
+	data, err := os.ReadFile(DecisionCachePath(sandboxRoot))
+	if err != nil {
+		t.Fatalf("failed to read decision cache: %v", err)
+	}
+	var onDisk map[string]persistedDecision
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatalf("failed to parse decision cache: %v", err)
+	}
+	if len(onDisk) != 0 {
+		t.Errorf("expected empty decision cache on disk after ClearCache, got %v", onDisk)
+	}
+}
+
+func TestLoadDecisionCache_SortedAndIncludesExpired(t *testing.T) {
+	sandboxRoot := t.TempDir()
+
+	onDisk := map[string]persistedDecision{
+		"fresh.com":   {Action: FilterActionAllow, ExpiresAt: time.Now().Add(time.Hour)},
+		"example.com": {Action: FilterActionBlock, ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+	data, err := json.Marshal(onDisk)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+	if err := os.WriteFile(DecisionCachePath(sandboxRoot), data, 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	entries, err := LoadDecisionCache(sandboxRoot)
+	if err != nil {
+		t.Fatalf("LoadDecisionCache: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Host != "example.com" || entries[1].Host != "fresh.com" {
+		t.Errorf("expected entries sorted by host, got %v", entries)
+	}
+	if entries[0].Action != FilterActionBlock {
+		t.Errorf("expected expired entry to still be returned, got %v", entries[0])
+	}
+}
+
+func TestLoadDecisionCache_NoFile(t *testing.T) {
+	entries, err := LoadDecisionCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadDecisionCache: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries for missing cache file, got %v", entries)
+	}
+}
+
+func TestRemoveDecision(t *testing.T) {
+	sandboxRoot := t.TempDir()
+
+	cfg := &FilterConfig{DefaultAction: FilterActionAsk, CacheDecisions: boolPtr(true)}
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+	if err := engine.EnableDecisionPersistence(sandboxRoot); err != nil {
+		t.Fatalf("EnableDecisionPersistence: %v", err)
+	}
+	engine.CacheDecision("example.com", FilterActionAllow)
+	engine.CacheDecision("other.com", FilterActionBlock)
+
+	removed, err := RemoveDecision(sandboxRoot, "example.com")
+	if err != nil {
+		t.Fatalf("RemoveDecision: %v", err)
+	}
+	if !removed {
+		t.Error("expected RemoveDecision to report the host was removed")
+	}
+
+	entries, err := LoadDecisionCache(sandboxRoot)
+	if err != nil {
+		t.Fatalf("LoadDecisionCache: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Host != "other.com" {
+		t.Errorf("expected only other.com to remain, got %v", entries)
+	}
+
+	removed, err = RemoveDecision(sandboxRoot, "missing.com")
+	if err != nil {
+		t.Fatalf("RemoveDecision: %v", err)
+	}
+	if removed {
+		t.Error("expected RemoveDecision to report no host was removed")
+	}
+}
+
+func TestFilterConfig_GetCacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"unset defaults to 24h", "", DefaultCacheTTL, false},
+		{"explicit duration", "1h", time.Hour, false},
+		{"invalid duration", "not-a-duration", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FilterConfig{CacheTTL: tt.ttl}
+			got, err := cfg.GetCacheTTL()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("GetCacheTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterConfig_GetAskVia(t *testing.T) {
+	tests := []struct {
+		name string
+		via  AskVia
+		want AskVia
+	}{
+		{"unset defaults to tty", "", AskViaTTY},
+		{"explicit tty", AskViaTTY, AskViaTTY},
+		{"explicit notify", AskViaNotify, AskViaNotify},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FilterConfig{AskVia: tt.via}
+			if got := cfg.GetAskVia(); got != tt.want {
+				t.Errorf("GetAskVia() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		wantCount  int
+		wantWindow time.Duration
+		wantErr    bool
+	}{
+		{"per minute", "60/min", 60, time.Minute, false},
+		{"per second", "10/s", 10, time.Second, false},
+		{"per hour", "100/hour", 100, time.Hour, false},
+		{"missing slash", "60", 0, 0, true},
+		{"non-numeric count", "abc/min", 0, 0, true},
+		{"zero count", "0/min", 0, 0, true},
+		{"unknown unit", "60/fortnight", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, window, err := ParseRateLimit(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRateLimit(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if count != tt.wantCount || window != tt.wantWindow {
+				t.Errorf("ParseRateLimit(%q) = (%d, %v), want (%d, %v)", tt.spec, count, window, tt.wantCount, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestFilterRule_Validate_RateLimit(t *testing.T) {
+	valid := FilterRule{Pattern: "*.example.com", Action: FilterActionAllow, RateLimit: "60/min"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid rate_limit to pass, got: %v", err)
+	}
+
+	invalid := FilterRule{Pattern: "*.example.com", Action: FilterActionAllow, RateLimit: "bogus"}
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected invalid rate_limit to fail validation")
+	}
+}
+
+func TestFilterEngine_RateLimit_ExceedsLimit(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionBlock,
+		Rules: []FilterRule{
+			{Pattern: "api.example.com", Action: FilterActionAllow, Scope: FilterScopeHost, RateLimit: "2/min"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{
+		Host: "api.example.com",
+		URL:  &url.URL{Host: "api.example.com", Path: "/"},
+	}
+
+	for i := 0; i < 2; i++ {
+		decision := engine.Match(req)
+		if decision.Action != FilterActionAllow {
+			t.Fatalf("request %d: expected allow, got %s", i, decision.Action)
+		}
+	}
+
+	decision := engine.Match(req)
+	if decision.Action != FilterActionRateLimited {
+		t.Errorf("expected rate_limited on 3rd request, got %s", decision.Action)
+	}
+}
+
+func TestFilterEngine_RateLimit_PerHostKeying(t *testing.T) {
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionBlock,
+		Rules: []FilterRule{
+			{Pattern: "*.example.com", Action: FilterActionAllow, Scope: FilterScopeHost, RateLimit: "1/min"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	reqA := &http.Request{Host: "a.example.com", URL: &url.URL{Host: "a.example.com", Path: "/"}}
+	reqB := &http.Request{Host: "b.example.com", URL: &url.URL{Host: "b.example.com", Path: "/"}}
+
+	if decision := engine.Match(reqA); decision.Action != FilterActionAllow {
+		t.Fatalf("expected a.example.com to be allowed, got %s", decision.Action)
+	}
+	if decision := engine.Match(reqB); decision.Action != FilterActionAllow {
+		t.Fatalf("expected b.example.com's own bucket to be unaffected, got %s", decision.Action)
+	}
+	if decision := engine.Match(reqA); decision.Action != FilterActionRateLimited {
+		t.Errorf("expected a.example.com's second request to be rate limited, got %s", decision.Action)
+	}
+}
+
+func TestBodyFilterRule_Validate(t *testing.T) {
+	if err := (&BodyFilterRule{}).Validate(); err == nil {
+		t.Error("expected empty rule to fail validation")
+	}
+
+	if err := (&BodyFilterRule{ContentType: "application/zip"}).Validate(); err != nil {
+		t.Errorf("expected content_type-only rule to pass, got: %v", err)
+	}
+
+	if err := (&BodyFilterRule{MaxBodySize: "10MB"}).Validate(); err != nil {
+		t.Errorf("expected max_body_size-only rule to pass, got: %v", err)
+	}
+
+	if err := (&BodyFilterRule{MaxBodySize: "bogus"}).Validate(); err == nil {
+		t.Error("expected invalid max_body_size to fail validation")
+	}
+}
+
+func newBodyTestRequest(method, contentType string) *http.Request {
+	req := &http.Request{
+		Method: method,
+		Host:   "upload.example.com",
+		URL:    &url.URL{Host: "upload.example.com", Path: "/"},
+		Header: http.Header{},
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestFilterEngine_MatchBody_ContentType(t *testing.T) {
+	cfg := &FilterConfig{
+		BodyRules: []BodyFilterRule{
+			{ContentType: "application/zip", Reason: "no archives"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := newBodyTestRequest(http.MethodPost, "application/zip; charset=binary")
+	decision := engine.MatchBody(req, 10, false)
+	if decision.Action != FilterActionBlock {
+		t.Fatalf("expected block, got %s", decision.Action)
+	}
+	if decision.Reason != "no archives" {
+		t.Errorf("expected custom reason, got %q", decision.Reason)
+	}
+	if decision.BodyTooLarge {
+		t.Error("content-type block should not set BodyTooLarge")
+	}
+
+	allowed := newBodyTestRequest(http.MethodPost, "application/json")
+	if decision := engine.MatchBody(allowed, 10, false); decision.Action != FilterActionAllow {
+		t.Errorf("expected allow for non-matching content-type, got %s", decision.Action)
+	}
+}
+
+func TestFilterEngine_MatchBody_MaxSize(t *testing.T) {
+	cfg := &FilterConfig{
+		BodyRules: []BodyFilterRule{
+			{MaxBodySize: "100B"},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := newBodyTestRequest(http.MethodPost, "application/octet-stream")
+
+	if decision := engine.MatchBody(req, 50, false); decision.Action != FilterActionAllow {
+		t.Errorf("expected allow under the size limit, got %s", decision.Action)
+	}
+
+	decision := engine.MatchBody(req, 200, false)
+	if decision.Action != FilterActionBlock {
+		t.Fatalf("expected block over the size limit, got %s", decision.Action)
+	}
+	if !decision.BodyTooLarge {
+		t.Error("expected BodyTooLarge to be set for a size-based block")
+	}
+
+	if decision := engine.MatchBody(req, 1, true); decision.Action != FilterActionBlock {
+		t.Errorf("expected a truncated body to be treated as over the limit, got %s", decision.Action)
+	}
+}
+
+func TestFilterEngine_MatchBody_OnlyAppliesToPostPut(t *testing.T) {
+	cfg := &FilterConfig{
+		BodyRules: []BodyFilterRule{{MaxBodySize: "1B"}},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := newBodyTestRequest(http.MethodGet, "")
+	if decision := engine.MatchBody(req, 1000, false); decision.Action != FilterActionAllow {
+		t.Errorf("expected GET requests to bypass body rules, got %s", decision.Action)
+	}
+}
+
+func TestFilterEngine_MatchBody_NoRulesConfigured(t *testing.T) {
+	engine, err := NewFilterEngine(DefaultFilterConfig())
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := newBodyTestRequest(http.MethodPost, "application/zip")
+	if decision := engine.MatchBody(req, 1000, false); decision.Action != FilterActionAllow {
+		t.Errorf("expected allow with no body rules configured, got %s", decision.Action)
+	}
+}
+
+func TestFilterConfig_IsEnabled_BodyRulesAlone(t *testing.T) {
+	cfg := &FilterConfig{BodyRules: []BodyFilterRule{{ContentType: "application/zip"}}}
+	if !cfg.IsEnabled() {
+		t.Error("expected a config with only BodyRules to be enabled")
+	}
+}
+
+func TestBodyTooLargeResponse(t *testing.T) {
+	req := &http.Request{
+		Host: "upload.example.com",
+		URL:  &url.URL{Host: "upload.example.com", Path: "/"},
+	}
+
+	resp := BodyTooLargeResponse(req, "too big")
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Blocked-By") != "devsandbox-filter" {
+		t.Errorf("expected X-Blocked-By header")
+	}
+}
+
+func TestRateLimitResponse(t *testing.T) {
+	req := &http.Request{
+		Host: "limited.example.com",
+		URL:  &url.URL{Host: "limited.example.com", Path: "/test"},
+	}
+
+	resp := RateLimitResponse(req, "test rate limit reason")
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Blocked-By") != "devsandbox-filter" {
+		t.Errorf("expected X-Blocked-By header")
+	}
+}
@@ -3,9 +3,15 @@ package proxy
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// DefaultCacheTTL is how long a persisted ask-mode decision stays valid
+// when FilterConfig.CacheTTL is unset.
+const DefaultCacheTTL = 24 * time.Hour
+
 // FilterAction represents the action to take for a request.
 type FilterAction string
 
@@ -16,6 +22,34 @@ const (
 	FilterActionBlock FilterAction = "block"
 	// FilterActionAsk prompts the user for a decision.
 	FilterActionAsk FilterAction = "ask"
+	// FilterActionMock serves a canned response from FilterRule.Mock
+	// instead of letting the request reach the network.
+	FilterActionMock FilterAction = "mock"
+	// FilterActionRewrite transparently redirects the request to
+	// FilterRule.RewriteTo before it's dialed, updating the request's URL,
+	// Host header, and (via the default Transport's SNI-follows-dial-host
+	// behavior) TLS SNI together so the sandboxed process never sees the
+	// substitution.
+	FilterActionRewrite FilterAction = "rewrite"
+	// FilterActionRateLimited is a decision-only action (never a valid
+	// FilterRule.Action value): a matching rule's RateLimit was exceeded,
+	// so the request is rejected with 429 regardless of the rule's
+	// configured Action.
+	FilterActionRateLimited FilterAction = "rate_limited"
+)
+
+// AskVia selects how ask-mode decisions are collected from the user.
+type AskVia string
+
+const (
+	// AskViaTTY requires a connected "devsandbox proxy monitor" terminal
+	// (the default).
+	AskViaTTY AskVia = "tty"
+	// AskViaNotify sends a desktop notification (via notify-send/D-Bus)
+	// with Allow/Deny actions, falling back to AskViaTTY if no
+	// notification daemon is reachable. Useful when the sandboxed process
+	// is a long-running background task with no attached monitor.
+	AskViaNotify AskVia = "notify"
 )
 
 // FilterScope defines what part of the request to match against.
@@ -28,8 +62,27 @@ const (
 	FilterScopePath FilterScope = "path"
 	// FilterScopeURL matches against the full URL.
 	FilterScopeURL FilterScope = "url"
+	// FilterScopeQuery matches against the value of a single query
+	// parameter, named by FilterRule.QueryParam.
+	FilterScopeQuery FilterScope = "query"
+	// FilterScopeStatus matches against the response status code, e.g.
+	// "404" (exact) or "3*" (glob). Only evaluated on the response, via
+	// FilterEngine.MatchResponse - a rule using this scope is ignored by
+	// Match.
+	FilterScopeStatus FilterScope = "status"
+	// FilterScopeLocationHost matches against the host of the response's
+	// Location header, resolved against the request URL if relative. Lets
+	// a rule catch a redirect to an unapproved host. Only evaluated on the
+	// response, via FilterEngine.MatchResponse.
+	FilterScopeLocationHost FilterScope = "location_host"
 )
 
+// IsResponseScope reports whether the scope is only meaningful against a
+// response (status code, redirect target) rather than a request.
+func (s FilterScope) IsResponseScope() bool {
+	return s == FilterScopeStatus || s == FilterScopeLocationHost
+}
+
 // PatternType indicates how the pattern should be matched.
 type PatternType string
 
@@ -45,21 +98,47 @@ const (
 // FilterRule defines a single filtering rule.
 type FilterRule struct {
 	// Pattern is the pattern to match against (exact, glob, or regex).
-	Pattern string `toml:"pattern"`
+	Pattern string `toml:"pattern" yaml:"pattern"`
 
 	// Action specifies what to do when the rule matches.
-	Action FilterAction `toml:"action"`
+	Action FilterAction `toml:"action" yaml:"action"`
 
 	// Scope defines what part of the request to match.
 	// Default: host
-	Scope FilterScope `toml:"scope"`
+	Scope FilterScope `toml:"scope" yaml:"scope"`
 
 	// Type specifies the pattern matching type.
 	// Default: glob. Auto-detected as regex if pattern contains ^$|()[]{}\+
-	Type PatternType `toml:"type"`
+	Type PatternType `toml:"type" yaml:"type"`
+
+	// QueryParam names the query parameter to match against when Scope is
+	// "query". Required when Scope is "query", ignored otherwise.
+	QueryParam string `toml:"query_param" yaml:"query_param"`
 
 	// Reason is an optional human-readable explanation shown when blocking.
-	Reason string `toml:"reason"`
+	Reason string `toml:"reason" yaml:"reason"`
+
+	// Mock is the path to a JSON file describing the canned response to
+	// serve for this rule (status, headers, body). Required when Action
+	// is "mock", ignored otherwise. See MockResponseSpec.
+	Mock string `toml:"mock" yaml:"mock"`
+
+	// RewriteTo is the replacement URL used when Action is "rewrite".
+	// Required when Action is "rewrite", ignored otherwise. Scope must be
+	// "url" so the full request URL is available to match against. If the
+	// rule's pattern is a regex, RewriteTo is expanded with Go regexp
+	// syntax ($1, ${name}, ...) against the match, the same as
+	// ResponseRewriteRule.Replacement; otherwise it's used verbatim as the
+	// new URL.
+	RewriteTo string `toml:"rewrite_to" yaml:"rewrite_to"`
+
+	// RateLimit caps how often this rule's matches may proceed, as
+	// "<count>/<unit>" (e.g. "60/min", "10/s", "100/hour"). Enforced via a
+	// token bucket keyed by rule+host in FilterEngine, independent of
+	// Action - even an "allow" rule can be rate limited. Exceeding it
+	// responds 429 instead of letting the request through. Optional;
+	// empty means unlimited.
+	RateLimit string `toml:"rate_limit" yaml:"rate_limit"`
 }
 
 // FilterConfig holds the complete filter configuration.
@@ -70,23 +149,42 @@ type FilterConfig struct {
 	// - "block": block unmatched requests (whitelist behavior)
 	// - "allow": allow unmatched requests (blacklist behavior)
 	// - "ask": prompt user for unmatched requests
-	DefaultAction FilterAction `toml:"default_action"`
+	DefaultAction FilterAction `toml:"default_action" yaml:"default_action"`
 
 	// AskTimeout is the timeout in seconds for ask mode decisions.
 	// Default: 30
-	AskTimeout int `toml:"ask_timeout"`
+	AskTimeout int `toml:"ask_timeout" yaml:"ask_timeout"`
+
+	// AskVia selects how ask-mode decisions are collected: "tty" (default,
+	// the devsandbox proxy monitor) or "notify" (desktop notification,
+	// falling back to "tty" if no notification daemon is reachable).
+	AskVia AskVia `toml:"ask_via" yaml:"ask_via"`
 
 	// CacheDecisions enables caching of ask mode decisions for the session.
 	// Default: true
-	CacheDecisions *bool `toml:"cache_decisions"`
+	CacheDecisions *bool `toml:"cache_decisions" yaml:"cache_decisions"`
+
+	// CacheTTL is how long a cached ask-mode decision survives on disk
+	// across sandbox restarts, as a Go duration string (e.g. "24h").
+	// Expired entries are dropped on load and re-asked. Has no effect if
+	// CacheDecisions is false. Default: 24h.
+	CacheTTL string `toml:"cache_ttl" yaml:"cache_ttl"`
 
 	// Rules is the list of filter rules, evaluated in order.
-	Rules []FilterRule `toml:"rules"`
+	Rules []FilterRule `toml:"rules" yaml:"rules"`
+
+	// BodyRules inspects POST/PUT request bodies, blocking uploads by
+	// Content-Type or size rather than by host/path/url like Rules.
+	// Evaluated independently of Rules via FilterEngine.MatchBody, in
+	// order, after Rules has allowed the request through.
+	BodyRules []BodyFilterRule `toml:"body_rules" yaml:"body_rules"`
 }
 
-// IsEnabled returns true if filtering is enabled.
+// IsEnabled returns true if filtering is enabled: DefaultAction is set, or
+// at least one BodyRules entry is configured (body rules have no default
+// action of their own - they simply don't match).
 func (c *FilterConfig) IsEnabled() bool {
-	return c != nil && c.DefaultAction != ""
+	return c != nil && (c.DefaultAction != "" || len(c.BodyRules) > 0)
 }
 
 // IsCacheEnabled returns whether decision caching is enabled (default: true).
@@ -113,6 +211,23 @@ func (c *FilterConfig) GetAskTimeout() int {
 	return c.AskTimeout
 }
 
+// GetAskVia returns how ask-mode decisions are collected, defaulting to
+// AskViaTTY.
+func (c *FilterConfig) GetAskVia() AskVia {
+	if c.AskVia == "" {
+		return AskViaTTY
+	}
+	return c.AskVia
+}
+
+// GetCacheTTL parses CacheTTL, defaulting to DefaultCacheTTL when unset.
+func (c *FilterConfig) GetCacheTTL() (time.Duration, error) {
+	if c.CacheTTL == "" {
+		return DefaultCacheTTL, nil
+	}
+	return time.ParseDuration(c.CacheTTL)
+}
+
 // Validate checks the filter configuration for errors.
 func (c *FilterConfig) Validate() error {
 	// Validate default action
@@ -125,6 +240,19 @@ func (c *FilterConfig) Validate() error {
 		}
 	}
 
+	if c.CacheTTL != "" {
+		if _, err := time.ParseDuration(c.CacheTTL); err != nil {
+			return fmt.Errorf("invalid cache_ttl: %w", err)
+		}
+	}
+
+	switch c.AskVia {
+	case "", AskViaTTY, AskViaNotify:
+		// Valid
+	default:
+		return fmt.Errorf("invalid ask_via: %q (must be tty or notify)", c.AskVia)
+	}
+
 	// Validate rules
 	for i, rule := range c.Rules {
 		if err := rule.Validate(); err != nil {
@@ -132,6 +260,12 @@ func (c *FilterConfig) Validate() error {
 		}
 	}
 
+	for i, rule := range c.BodyRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("body_rules[%d]: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
@@ -143,20 +277,48 @@ func (r *FilterRule) Validate() error {
 
 	// Validate action
 	switch r.Action {
-	case FilterActionAllow, FilterActionBlock, FilterActionAsk:
+	case FilterActionAllow, FilterActionBlock, FilterActionAsk, FilterActionMock, FilterActionRewrite:
 		// Valid
 	case "":
 		return fmt.Errorf("action is required")
 	default:
-		return fmt.Errorf("invalid action: %q (must be allow, block, or ask)", r.Action)
+		return fmt.Errorf("invalid action: %q (must be allow, block, ask, mock, or rewrite)", r.Action)
+	}
+
+	if r.Action == FilterActionMock {
+		if r.Mock == "" {
+			return fmt.Errorf("mock is required when action is %q", FilterActionMock)
+		}
+		if _, err := LoadMockResponse(r.Mock); err != nil {
+			return fmt.Errorf("mock: %w", err)
+		}
+	}
+
+	if r.Action == FilterActionRewrite {
+		if r.RewriteTo == "" {
+			return fmt.Errorf("rewrite_to is required when action is %q", FilterActionRewrite)
+		}
+		if r.GetScope() != FilterScopeURL {
+			return fmt.Errorf("scope must be %q when action is %q", FilterScopeURL, FilterActionRewrite)
+		}
+	}
+
+	if r.RateLimit != "" {
+		if _, _, err := ParseRateLimit(r.RateLimit); err != nil {
+			return fmt.Errorf("rate_limit: %w", err)
+		}
 	}
 
 	// Validate scope (default to host if empty)
 	switch r.Scope {
-	case FilterScopeHost, FilterScopePath, FilterScopeURL, "":
+	case FilterScopeHost, FilterScopePath, FilterScopeURL, FilterScopeQuery, FilterScopeStatus, FilterScopeLocationHost, "":
 		// Valid
 	default:
-		return fmt.Errorf("invalid scope: %q (must be host, path, or url)", r.Scope)
+		return fmt.Errorf("invalid scope: %q (must be host, path, url, query, status, or location_host)", r.Scope)
+	}
+
+	if r.Scope == FilterScopeQuery && r.QueryParam == "" {
+		return fmt.Errorf("query_param is required when scope is %q", FilterScopeQuery)
 	}
 
 	// Validate pattern type
@@ -204,6 +366,68 @@ func (r *FilterRule) GetScope() FilterScope {
 	return r.Scope
 }
 
+// BodyFilterRule blocks a POST/PUT request by its body's Content-Type or
+// size, independent of FilterRule's host/path/url matching. At least one of
+// ContentType or MaxBodySize must be set.
+type BodyFilterRule struct {
+	// ContentType blocks requests whose (parameter-stripped) Content-Type
+	// header equals this value, e.g. "application/zip". Matched
+	// case-insensitively. Optional.
+	ContentType string `toml:"content_type" yaml:"content_type"`
+
+	// MaxBodySize blocks requests whose body exceeds this size, as a
+	// byte-size string (e.g. "10MB", "512K" - see ParseLogRetentionSize).
+	// A body RequestLogger couldn't fully buffer (see MaxBufferedBodySize)
+	// is treated as exceeding this, even if its true size is unknown.
+	// Optional.
+	MaxBodySize string `toml:"max_body_size" yaml:"max_body_size"`
+
+	// Reason is shown when blocking a request. Defaults to a message
+	// naming the rule that matched.
+	Reason string `toml:"reason" yaml:"reason"`
+}
+
+// Validate checks a body filter rule for errors.
+func (r *BodyFilterRule) Validate() error {
+	if r.ContentType == "" && r.MaxBodySize == "" {
+		return fmt.Errorf("at least one of content_type or max_body_size is required")
+	}
+	if r.MaxBodySize != "" {
+		if _, err := ParseLogRetentionSize(r.MaxBodySize); err != nil {
+			return fmt.Errorf("invalid max_body_size: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseRateLimit parses a FilterRule.RateLimit spec of the form
+// "<count>/<unit>" (e.g. "60/min", "10/s", "100/hour") into a token count
+// and the window it refills over.
+func ParseRateLimit(spec string) (count int, window time.Duration, err error) {
+	parts := strings.SplitN(spec, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid rate limit %q: expected format <count>/<unit> (e.g. 60/min)", spec)
+	}
+
+	count, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || count <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate limit %q: count must be a positive integer", spec)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(parts[1])) {
+	case "s", "sec", "second", "seconds":
+		window = time.Second
+	case "min", "minute", "minutes":
+		window = time.Minute
+	case "hour", "hours", "h":
+		window = time.Hour
+	default:
+		return 0, 0, fmt.Errorf("invalid rate limit %q: unknown unit %q (must be s, min, or hour)", spec, parts[1])
+	}
+
+	return count, window, nil
+}
+
 // DefaultFilterConfig returns a disabled filter configuration.
 func DefaultFilterConfig() *FilterConfig {
 	return &FilterConfig{}
@@ -222,4 +446,13 @@ type FilterDecision struct {
 
 	// IsDefault indicates whether the default action was used.
 	IsDefault bool
+
+	// RewrittenURL is the target URL when Action is FilterActionRewrite,
+	// computed from the matched rule's RewriteTo. Empty otherwise.
+	RewrittenURL string
+
+	// BodyTooLarge indicates the decision came from a BodyFilterRule's
+	// MaxBodySize, so a block should respond 413 rather than 403 (see
+	// FilterEngine.MatchBody, BodyTooLargeResponse).
+	BodyTooLarge bool
 }
@@ -0,0 +1,175 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// isGRPCContentType reports whether ct (an HTTP Content-Type header value)
+// indicates a gRPC message body, e.g. "application/grpc" or
+// "application/grpc+proto".
+func isGRPCContentType(ct string) bool {
+	return strings.HasPrefix(ct, "application/grpc")
+}
+
+// grpcFrameSizes splits a gRPC message stream into per-message sizes. Each
+// frame is a 1-byte compressed flag followed by a 4-byte big-endian length
+// and the message bytes. A body that isn't a well-formed frame stream
+// (e.g. truncated mid-frame) yields whatever complete frames were found,
+// rather than failing the whole log entry.
+func grpcFrameSizes(body []byte) []int {
+	var sizes []int
+	for len(body) >= 5 {
+		length := binary.BigEndian.Uint32(body[1:5])
+		body = body[5:]
+		if uint64(length) > uint64(len(body)) {
+			break
+		}
+		sizes = append(sizes, int(length))
+		body = body[length:]
+	}
+	return sizes
+}
+
+// grpcFramePayloads is grpcFrameSizes, but returns the message payloads
+// themselves instead of just their sizes.
+func grpcFramePayloads(body []byte) [][]byte {
+	var payloads [][]byte
+	for len(body) >= 5 {
+		length := binary.BigEndian.Uint32(body[1:5])
+		body = body[5:]
+		if uint64(length) > uint64(len(body)) {
+			break
+		}
+		payloads = append(payloads, body[:length])
+		body = body[length:]
+	}
+	return payloads
+}
+
+// grpcStatus extracts the grpc-status/grpc-message pair from a response's
+// trailers, falling back to the same headers sent as plain headers (for
+// trailers-only responses). ok is false if no grpc-status was present.
+func grpcStatus(header, trailer http.Header) (code string, message string, ok bool) {
+	raw := trailer.Get("Grpc-Status")
+	msg := trailer.Get("Grpc-Message")
+	if raw == "" {
+		raw = header.Get("Grpc-Status")
+		msg = header.Get("Grpc-Message")
+	}
+	if raw == "" {
+		return "", "", false
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		return codes.Code(n).String(), msg, true
+	}
+	return raw, msg, true
+}
+
+// GRPCConfig configures gRPC message decoding for the proxy's request log.
+type GRPCConfig struct {
+	// DescriptorSetPath is the path to a compiled FileDescriptorSet used to
+	// decode gRPC message bodies to JSON. If empty, gRPC calls are still
+	// logged (method, status, message sizes) but bodies are not decoded.
+	DescriptorSetPath string
+}
+
+// GRPCDecoder decodes gRPC message bodies to JSON using a compiled
+// descriptor set, so request logs show structured payloads instead of
+// opaque protobuf bytes.
+type GRPCDecoder struct {
+	files *protoregistry.Files
+}
+
+// NewGRPCDecoder loads cfg.DescriptorSetPath into a GRPCDecoder. cfg may be
+// nil or have an empty DescriptorSetPath, in which case it returns (nil,
+// nil): callers should treat a nil decoder as "body decoding unavailable",
+// not as an error.
+func NewGRPCDecoder(cfg *GRPCConfig) (*GRPCDecoder, error) {
+	if cfg == nil || cfg.DescriptorSetPath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(cfg.DescriptorSetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gRPC descriptor set %s: %w", cfg.DescriptorSetPath, err)
+	}
+
+	var fdSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fdSet); err != nil {
+		return nil, fmt.Errorf("failed to parse gRPC descriptor set %s: %w", cfg.DescriptorSetPath, err)
+	}
+
+	files, err := protodesc.NewFiles(&fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gRPC descriptor registry from %s: %w", cfg.DescriptorSetPath, err)
+	}
+
+	return &GRPCDecoder{files: files}, nil
+}
+
+// methodDescriptor finds the method descriptor for fullMethod, formatted as
+// gRPC sends it over HTTP: "/package.Service/Method".
+func (d *GRPCDecoder) methodDescriptor(fullMethod string) protoreflect.MethodDescriptor {
+	parts := strings.SplitN(strings.TrimPrefix(fullMethod, "/"), "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	serviceName, methodName := parts[0], parts[1]
+
+	desc, err := d.files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	return service.Methods().ByName(protoreflect.Name(methodName))
+}
+
+// DecodeMessages decodes each length-prefixed gRPC message in payloads as
+// either the request or response type of fullMethod, returning one JSON
+// document per message. It returns (nil, nil), not an error, if fullMethod
+// isn't found in the descriptor set (e.g. a service the configured
+// descriptor set doesn't cover), so one unmatched call doesn't drop the
+// rest of the log entry.
+func (d *GRPCDecoder) DecodeMessages(fullMethod string, payloads [][]byte, request bool) ([]json.RawMessage, error) {
+	method := d.methodDescriptor(fullMethod)
+	if method == nil {
+		return nil, nil
+	}
+
+	msgDesc := method.Output()
+	if request {
+		msgDesc = method.Input()
+	}
+
+	docs := make([]json.RawMessage, 0, len(payloads))
+	for _, payload := range payloads {
+		msg := dynamicpb.NewMessage(msgDesc)
+		if err := proto.Unmarshal(payload, msg); err != nil {
+			return nil, fmt.Errorf("failed to decode gRPC message for %s: %w", fullMethod, err)
+		}
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal gRPC message for %s as JSON: %w", fullMethod, err)
+		}
+		docs = append(docs, data)
+	}
+	return docs, nil
+}
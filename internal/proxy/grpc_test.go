@@ -0,0 +1,187 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"net/http"
+	"os"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// frame builds a single length-prefixed gRPC message frame (uncompressed).
+func frame(payload []byte) []byte {
+	buf := make([]byte, 5+len(payload))
+	buf[0] = 0
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(payload)))
+	copy(buf[5:], payload)
+	return buf
+}
+
+func TestGRPCFrameSizes(t *testing.T) {
+	body := append(frame([]byte("abc")), frame([]byte("de"))...)
+
+	sizes := grpcFrameSizes(body)
+	if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 2 {
+		t.Errorf("grpcFrameSizes(%v) = %v, want [3 2]", body, sizes)
+	}
+}
+
+func TestGRPCFrameSizes_Truncated(t *testing.T) {
+	body := append(frame([]byte("abc")), []byte{0, 0, 0, 0, 9, 'x'}...)
+
+	sizes := grpcFrameSizes(body)
+	if len(sizes) != 1 || sizes[0] != 3 {
+		t.Errorf("grpcFrameSizes() with a truncated trailing frame = %v, want [3]", sizes)
+	}
+}
+
+func TestGRPCFramePayloads(t *testing.T) {
+	body := append(frame([]byte("abc")), frame([]byte("de"))...)
+
+	payloads := grpcFramePayloads(body)
+	if len(payloads) != 2 || string(payloads[0]) != "abc" || string(payloads[1]) != "de" {
+		t.Errorf("grpcFramePayloads(%v) = %v", body, payloads)
+	}
+}
+
+func TestIsGRPCContentType(t *testing.T) {
+	cases := map[string]bool{
+		"application/grpc":       true,
+		"application/grpc+proto": true,
+		"application/grpc+json":  true,
+		"application/json":       false,
+		"":                       false,
+	}
+	for ct, want := range cases {
+		if got := isGRPCContentType(ct); got != want {
+			t.Errorf("isGRPCContentType(%q) = %v, want %v", ct, got, want)
+		}
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	header := http.Header{"Grpc-Status": {"0"}}
+	trailer := http.Header{"Grpc-Status": {"5"}, "Grpc-Message": {"not found"}}
+
+	if code, msg, ok := grpcStatus(header, trailer); !ok || code != "NotFound" || msg != "not found" {
+		t.Errorf("grpcStatus() with trailer present = (%q, %q, %v), want (NotFound, not found, true)", code, msg, ok)
+	}
+
+	if code, _, ok := grpcStatus(header, http.Header{}); !ok || code != "OK" {
+		t.Errorf("grpcStatus() should fall back to the header when no trailer is set, got (%q, %v)", code, ok)
+	}
+
+	if _, _, ok := grpcStatus(http.Header{}, http.Header{}); ok {
+		t.Error("grpcStatus() should report ok=false when no grpc-status is present anywhere")
+	}
+}
+
+func TestNewGRPCDecoder_NoConfig(t *testing.T) {
+	d, err := NewGRPCDecoder(nil)
+	if err != nil || d != nil {
+		t.Fatalf("NewGRPCDecoder(nil) = (%v, %v), want (nil, nil)", d, err)
+	}
+
+	d, err = NewGRPCDecoder(&GRPCConfig{})
+	if err != nil || d != nil {
+		t.Fatalf("NewGRPCDecoder(&GRPCConfig{}) = (%v, %v), want (nil, nil)", d, err)
+	}
+}
+
+func TestNewGRPCDecoder_MissingFile(t *testing.T) {
+	if _, err := NewGRPCDecoder(&GRPCConfig{DescriptorSetPath: "/nonexistent/descriptors.bin"}); err == nil {
+		t.Error("NewGRPCDecoder() with a missing descriptor set should return an error")
+	}
+}
+
+// testDescriptorSet builds a minimal FileDescriptorSet, by hand rather than
+// via protoc, describing a Greeter service with one method that takes and
+// returns google.protobuf.StringValue, so DecodeMessages can be exercised
+// without shelling out to a proto compiler in this test.
+func testDescriptorSet(t *testing.T) []byte {
+	t.Helper()
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("greeter.proto"),
+		Package: proto.String("test"),
+		Dependency: []string{
+			"google/protobuf/wrappers.proto",
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Greeter"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("SayHello"),
+						InputType:  proto.String(".google.protobuf.StringValue"),
+						OutputType: proto.String(".google.protobuf.StringValue"),
+					},
+				},
+			},
+		},
+	}
+
+	wrappersFile := (&wrapperspb.StringValue{}).ProtoReflect().Descriptor().ParentFile()
+	wrappersFDProto := protodesc.ToFileDescriptorProto(wrappersFile)
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{wrappersFDProto, fd},
+	}
+
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal test descriptor set: %v", err)
+	}
+	return data
+}
+
+func TestGRPCDecoder_DecodeMessages(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/descriptors.bin"
+	if err := os.WriteFile(path, testDescriptorSet(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewGRPCDecoder(&GRPCConfig{DescriptorSetPath: path})
+	if err != nil {
+		t.Fatalf("NewGRPCDecoder() error: %v", err)
+	}
+
+	reqMsg, err := proto.Marshal(wrapperspb.String("world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := d.DecodeMessages("/test.Greeter/SayHello", [][]byte{reqMsg}, true)
+	if err != nil {
+		t.Fatalf("DecodeMessages() error: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("DecodeMessages() returned %d documents, want 1", len(docs))
+	}
+	if got := string(docs[0]); got != `"world"` {
+		t.Errorf("DecodeMessages() = %s, want \"world\"", got)
+	}
+}
+
+func TestGRPCDecoder_DecodeMessages_UnknownMethod(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/descriptors.bin"
+	if err := os.WriteFile(path, testDescriptorSet(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewGRPCDecoder(&GRPCConfig{DescriptorSetPath: path})
+	if err != nil {
+		t.Fatalf("NewGRPCDecoder() error: %v", err)
+	}
+
+	docs, err := d.DecodeMessages("/unknown.Service/Method", [][]byte{[]byte("x")}, true)
+	if err != nil || docs != nil {
+		t.Errorf("DecodeMessages() for an unknown method = (%v, %v), want (nil, nil)", docs, err)
+	}
+}
@@ -0,0 +1,170 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics accumulates Prometheus-style counters for a proxy Server,
+// incremented from the request/response hooks in setupLogging. All fields
+// are safe for concurrent use.
+type Metrics struct {
+	requestsTotal    uint64
+	bytesTransferred uint64
+	activeConns      int64
+
+	statusClasses [6]uint64 // index: 1xx..5xx at 0..4, anything else (incl. no response) at 5
+
+	mu              sync.Mutex
+	filterDecisions map[FilterAction]uint64
+}
+
+// NewMetrics creates an empty Metrics collector.
+func NewMetrics() *Metrics {
+	return &Metrics{filterDecisions: make(map[FilterAction]uint64)}
+}
+
+// IncRequests counts one incoming request and marks a connection active.
+func (m *Metrics) IncRequests() {
+	atomic.AddUint64(&m.requestsTotal, 1)
+	atomic.AddInt64(&m.activeConns, 1)
+}
+
+// ObserveResponse records a completed request: its status class, the
+// request+response body bytes transferred, and marks the connection no
+// longer active. statusCode is 0 for requests that never got a real
+// response (e.g. blocked before reaching the network).
+func (m *Metrics) ObserveResponse(statusCode int, bytes int64) {
+	atomic.AddInt64(&m.activeConns, -1)
+	atomic.AddUint64(&m.bytesTransferred, uint64(bytes)) //nolint:gosec // bytes is a body length, never negative
+	atomic.AddUint64(&m.statusClasses[statusClassIndex(statusCode)], 1)
+}
+
+// IncFilterDecision counts one filter decision for the given action.
+func (m *Metrics) IncFilterDecision(action FilterAction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.filterDecisions[action]++
+}
+
+func statusClassIndex(code int) int {
+	class := code / 100
+	if class < 1 || class > 5 {
+		return 5
+	}
+	return class - 1
+}
+
+// WriteProm writes all counters in Prometheus text exposition format.
+func (m *Metrics) WriteProm(w io.Writer) {
+	fmt.Fprintf(w, "# HELP devsandbox_proxy_requests_total Total number of requests handled by the proxy.\n")
+	fmt.Fprintf(w, "# TYPE devsandbox_proxy_requests_total counter\n")
+	fmt.Fprintf(w, "devsandbox_proxy_requests_total %d\n", atomic.LoadUint64(&m.requestsTotal))
+
+	fmt.Fprintf(w, "# HELP devsandbox_proxy_requests_by_status_total Requests by response status class.\n")
+	fmt.Fprintf(w, "# TYPE devsandbox_proxy_requests_by_status_total counter\n")
+	for i, label := range []string{"1xx", "2xx", "3xx", "4xx", "5xx", "none"} {
+		fmt.Fprintf(w, "devsandbox_proxy_requests_by_status_total{class=%q} %d\n", label, atomic.LoadUint64(&m.statusClasses[i]))
+	}
+
+	fmt.Fprintf(w, "# HELP devsandbox_proxy_bytes_transferred_total Request and response body bytes logged by the proxy.\n")
+	fmt.Fprintf(w, "# TYPE devsandbox_proxy_bytes_transferred_total counter\n")
+	fmt.Fprintf(w, "devsandbox_proxy_bytes_transferred_total %d\n", atomic.LoadUint64(&m.bytesTransferred))
+
+	fmt.Fprintf(w, "# HELP devsandbox_proxy_filter_decisions_total Filter decisions by action.\n")
+	fmt.Fprintf(w, "# TYPE devsandbox_proxy_filter_decisions_total counter\n")
+	m.mu.Lock()
+	actions := make([]string, 0, len(m.filterDecisions))
+	for action := range m.filterDecisions {
+		actions = append(actions, string(action))
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		fmt.Fprintf(w, "devsandbox_proxy_filter_decisions_total{action=%q} %d\n", action, m.filterDecisions[FilterAction(action)])
+	}
+	m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP devsandbox_proxy_active_connections Requests currently being proxied.\n")
+	fmt.Fprintf(w, "# TYPE devsandbox_proxy_active_connections gauge\n")
+	fmt.Fprintf(w, "devsandbox_proxy_active_connections %d\n", atomic.LoadInt64(&m.activeConns))
+}
+
+// MetricsServer exposes a Metrics collector over HTTP for Prometheus to
+// scrape, on a separate listener from the proxy itself. It's off by
+// default (proxy.metrics_addr unset) and, per Config.MetricsAddr's
+// validation, only ever binds to loopback.
+type MetricsServer struct {
+	addr      string
+	metrics   *Metrics
+	isRunning func() bool
+
+	mu       sync.Mutex
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewMetricsServer creates a MetricsServer listening on addr, reporting m's
+// counters and isRunning() for /healthz.
+func NewMetricsServer(addr string, m *Metrics, isRunning func() bool) *MetricsServer {
+	return &MetricsServer{addr: addr, metrics: m, isRunning: isRunning}
+}
+
+func (ms *MetricsServer) Start() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.listener != nil {
+		return fmt.Errorf("metrics server already running")
+	}
+
+	listener, err := net.Listen("tcp", ms.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics on %s: %w", ms.addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		ms.metrics.WriteProm(w)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if ms.isRunning != nil && !ms.isRunning() {
+			http.Error(w, "proxy not running", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	ms.listener = listener
+	ms.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := ms.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, net.ErrClosed) {
+			log.Printf("metrics server error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (ms *MetricsServer) Stop() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.listener == nil {
+		return nil
+	}
+
+	err := ms.listener.Close()
+	ms.listener = nil
+	ms.server = nil
+	return err
+}
@@ -0,0 +1,122 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_WriteProm(t *testing.T) {
+	m := NewMetrics()
+
+	m.IncRequests()
+	m.IncRequests()
+	m.ObserveResponse(200, 100)
+	m.ObserveResponse(404, 50)
+	m.IncFilterDecision(FilterActionAllow)
+	m.IncFilterDecision(FilterActionBlock)
+	m.IncFilterDecision(FilterActionBlock)
+
+	var sb strings.Builder
+	m.WriteProm(&sb)
+	out := sb.String()
+
+	if !strings.Contains(out, "devsandbox_proxy_requests_total 2\n") {
+		t.Errorf("missing requests_total, got:\n%s", out)
+	}
+	if !strings.Contains(out, `devsandbox_proxy_requests_by_status_total{class="2xx"} 1`) {
+		t.Errorf("missing 2xx count, got:\n%s", out)
+	}
+	if !strings.Contains(out, `devsandbox_proxy_requests_by_status_total{class="4xx"} 1`) {
+		t.Errorf("missing 4xx count, got:\n%s", out)
+	}
+	if !strings.Contains(out, "devsandbox_proxy_bytes_transferred_total 150\n") {
+		t.Errorf("missing bytes_transferred_total, got:\n%s", out)
+	}
+	if !strings.Contains(out, `devsandbox_proxy_filter_decisions_total{action="allow"} 1`) {
+		t.Errorf("missing allow decisions, got:\n%s", out)
+	}
+	if !strings.Contains(out, `devsandbox_proxy_filter_decisions_total{action="block"} 2`) {
+		t.Errorf("missing block decisions, got:\n%s", out)
+	}
+	if !strings.Contains(out, "devsandbox_proxy_active_connections 0\n") {
+		t.Errorf("missing active_connections, got:\n%s", out)
+	}
+}
+
+func TestMetrics_ActiveConnections(t *testing.T) {
+	m := NewMetrics()
+
+	m.IncRequests()
+	m.IncRequests()
+	m.ObserveResponse(200, 0)
+
+	var sb strings.Builder
+	m.WriteProm(&sb)
+	if !strings.Contains(sb.String(), "devsandbox_proxy_active_connections 1\n") {
+		t.Errorf("want 1 active connection after one of two requests completed, got:\n%s", sb.String())
+	}
+}
+
+func TestStatusClassIndex(t *testing.T) {
+	cases := []struct {
+		code int
+		want int
+	}{
+		{100, 0},
+		{200, 1},
+		{301, 2},
+		{404, 3},
+		{500, 4},
+		{0, 5},
+		{999, 5},
+	}
+	for _, c := range cases {
+		if got := statusClassIndex(c.code); got != c.want {
+			t.Errorf("statusClassIndex(%d) = %d, want %d", c.code, got, c.want)
+		}
+	}
+}
+
+func TestMetricsServer_HealthzAndMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.IncRequests()
+	m.ObserveResponse(200, 10)
+
+	running := true
+	ms := NewMetricsServer("127.0.0.1:0", m, func() bool { return running })
+	if err := ms.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = ms.Stop() }()
+
+	addr := ms.listener.Addr().String()
+
+	resp, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", resp.StatusCode)
+	}
+	_ = resp.Body.Close()
+
+	running = false
+	resp, err = http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/healthz status = %d, want 503 when not running", resp.StatusCode)
+	}
+	_ = resp.Body.Close()
+
+	resp, err = http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/metrics status = %d, want 200", resp.StatusCode)
+	}
+}
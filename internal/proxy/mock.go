@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// MockResponseSpec describes a canned HTTP response served for a
+// FilterActionMock rule, loaded from the JSON file at FilterRule.Mock.
+type MockResponseSpec struct {
+	// Status is the HTTP status code to return. Default: 200.
+	Status int `json:"status"`
+
+	// Headers are the response headers to set.
+	Headers map[string]string `json:"headers"`
+
+	// Body is the response body, served as-is.
+	Body string `json:"body"`
+}
+
+// GetStatus returns the configured status, defaulting to 200.
+func (m *MockResponseSpec) GetStatus() int {
+	if m.Status == 0 {
+		return http.StatusOK
+	}
+	return m.Status
+}
+
+// LoadMockResponse reads and parses a mock response file.
+func LoadMockResponse(path string) (*MockResponseSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock response file: %w", err)
+	}
+
+	var spec MockResponseSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse mock response file: %w", err)
+	}
+
+	return &spec, nil
+}
+
+// MockResponse builds an *http.Response for a matched FilterActionMock
+// rule. The rule's Mock file is re-read on every call so edits take effect
+// without restarting the sandbox.
+func MockResponse(req *http.Request, rule *FilterRule) (*http.Response, error) {
+	spec, err := LoadMockResponse(rule.Mock)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	for k, v := range spec.Headers {
+		header.Set(k, v)
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+	header.Set("X-Mocked-By", "devsandbox-filter")
+
+	status := spec.GetStatus()
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(spec.Body)),
+		ContentLength: int64(len(spec.Body)),
+		Request:       req,
+	}, nil
+}
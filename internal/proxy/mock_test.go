@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMockFile(t *testing.T, spec string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mock.json")
+	if err := os.WriteFile(path, []byte(spec), 0o644); err != nil {
+		t.Fatalf("failed to write mock file: %v", err)
+	}
+	return path
+}
+
+func TestMockResponse_ServesConfiguredBody(t *testing.T) {
+	path := writeMockFile(t, `{"status": 201, "headers": {"Content-Type": "application/json"}, "body": "{\"ok\":true}"}`)
+
+	rule := &FilterRule{Action: FilterActionMock, Mock: path}
+	req := &http.Request{Host: "example.com", URL: &url.URL{Host: "example.com", Path: "/"}}
+
+	resp, err := MockResponse(req, rule)
+	if err != nil {
+		t.Fatalf("MockResponse failed: %v", err)
+	}
+	if resp.StatusCode != 201 {
+		t.Errorf("StatusCode = %d, want 201", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %q, want {\"ok\":true}", body)
+	}
+}
+
+func TestMockResponse_DefaultsStatusAndContentType(t *testing.T) {
+	path := writeMockFile(t, `{"body": "hello"}`)
+
+	rule := &FilterRule{Action: FilterActionMock, Mock: path}
+	req := &http.Request{Host: "example.com", URL: &url.URL{Host: "example.com", Path: "/"}}
+
+	resp, err := MockResponse(req, rule)
+	if err != nil {
+		t.Fatalf("MockResponse failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q, want application/octet-stream", got)
+	}
+}
+
+func TestMockResponse_MissingFileErrors(t *testing.T) {
+	rule := &FilterRule{Action: FilterActionMock, Mock: filepath.Join(t.TempDir(), "missing.json")}
+	req := &http.Request{Host: "example.com", URL: &url.URL{Host: "example.com", Path: "/"}}
+
+	if _, err := MockResponse(req, rule); err == nil {
+		t.Fatal("expected error for missing mock file")
+	}
+}
+
+func TestFilterEngine_Match_MockAction(t *testing.T) {
+	path := writeMockFile(t, `{"status": 200, "body": "mocked"}`)
+
+	cfg := &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: "api.example.com", Action: FilterActionMock, Scope: FilterScopeHost, Mock: path},
+		},
+	}
+
+	engine, err := NewFilterEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create filter engine: %v", err)
+	}
+
+	req := &http.Request{Host: "api.example.com", URL: &url.URL{Host: "api.example.com", Path: "/"}}
+	decision := engine.Match(req)
+	if decision.Action != FilterActionMock {
+		t.Errorf("Action = %s, want mock", decision.Action)
+	}
+	if decision.Rule == nil || decision.Rule.Mock != path {
+		t.Errorf("expected decision.Rule.Mock = %q, got %+v", path, decision.Rule)
+	}
+}
+
+func TestFilterRule_Validate_MockRequiresFile(t *testing.T) {
+	rule := FilterRule{Pattern: "example.com", Action: FilterActionMock}
+	if err := rule.Validate(); err == nil {
+		t.Fatal("expected error when mock action has no mock file")
+	}
+
+	rule.Mock = filepath.Join(t.TempDir(), "missing.json")
+	if err := rule.Validate(); err == nil {
+		t.Fatal("expected error when mock file doesn't exist")
+	}
+
+	rule.Mock = writeMockFile(t, `{"status": 200, "body": "ok"}`)
+	if err := rule.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
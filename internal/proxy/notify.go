@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// NotifyAsker prompts for ask-mode decisions via a desktop notification
+// (notify-send/D-Bus) with Allow/Deny actions, instead of requiring a
+// connected "devsandbox proxy monitor" terminal. Useful when the sandboxed
+// process is a long-running background task with no attached monitor.
+type NotifyAsker struct {
+	timeout time.Duration
+}
+
+// NewNotifyAsker creates a notifier that waits up to timeout for the user to
+// click a notification action.
+func NewNotifyAsker(timeout time.Duration) *NotifyAsker {
+	return &NotifyAsker{timeout: timeout}
+}
+
+// Available reports whether a notification daemon is reachable: notify-send
+// is on PATH and a D-Bus session bus is configured. Callers should fall back
+// to the TTY monitor when this is false.
+func (n *NotifyAsker) Available() bool {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return false
+	}
+	return os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+}
+
+// Ask sends a desktop notification with Allow/Deny actions for req and
+// blocks until the user picks one or n.timeout elapses. notify-send prints
+// the chosen action's key to stdout when the daemon supports actions; a
+// timeout, a closed notification, or a daemon without action support all
+// resolve to FilterActionBlock, the safe default.
+func (n *NotifyAsker) Ask(ctx context.Context, req *AskRequest) (FilterAction, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	summary := fmt.Sprintf("devsandbox: approve %s request?", req.Method)
+	body := fmt.Sprintf("%s %s", req.Method, req.URL)
+
+	cmd := exec.CommandContext(ctx, "notify-send",
+		"--app-name=devsandbox",
+		fmt.Sprintf("--expire-time=%d", n.timeout.Milliseconds()),
+		"--action=allow=Allow",
+		"--action=deny=Deny",
+		summary, body,
+	)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return FilterActionBlock, ErrTimeout
+		}
+		return FilterActionBlock, fmt.Errorf("notify-send failed: %w", err)
+	}
+
+	switch strings.TrimSpace(stdout.String()) {
+	case "allow":
+		return FilterActionAllow, nil
+	case "deny":
+		return FilterActionBlock, nil
+	default:
+		// Closed without picking an action, or the daemon doesn't support
+		// actions at all - default to the safe choice.
+		return FilterActionBlock, nil
+	}
+}
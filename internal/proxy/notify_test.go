@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubNotifySend writes a fake notify-send script to a temp dir, prepends it
+// to PATH for the duration of the test, and returns nothing - callers just
+// need notify-send to be found on PATH afterwards.
+func stubNotifySend(t *testing.T, script string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notify-send")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestNotifyAsker_Available_NoNotifySend(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // empty dir, no notify-send
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/tmp/fake-bus")
+
+	n := NewNotifyAsker(time.Second)
+	if n.Available() {
+		t.Error("expected Available() to be false without notify-send on PATH")
+	}
+}
+
+func TestNotifyAsker_Available_NoSessionBus(t *testing.T) {
+	stubNotifySend(t, "echo allow")
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+
+	n := NewNotifyAsker(time.Second)
+	if n.Available() {
+		t.Error("expected Available() to be false without DBUS_SESSION_BUS_ADDRESS")
+	}
+}
+
+func TestNotifyAsker_Available_Ready(t *testing.T) {
+	stubNotifySend(t, "echo allow")
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/tmp/fake-bus")
+
+	n := NewNotifyAsker(time.Second)
+	if !n.Available() {
+		t.Error("expected Available() to be true with notify-send and a session bus")
+	}
+}
+
+func TestNotifyAsker_Ask_Allow(t *testing.T) {
+	stubNotifySend(t, "echo allow")
+
+	n := NewNotifyAsker(5 * time.Second)
+	action, err := n.Ask(context.Background(), &AskRequest{ID: "1", Method: "GET", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+	if action != FilterActionAllow {
+		t.Errorf("expected FilterActionAllow, got %q", action)
+	}
+}
+
+func TestNotifyAsker_Ask_Deny(t *testing.T) {
+	stubNotifySend(t, "echo deny")
+
+	n := NewNotifyAsker(5 * time.Second)
+	action, err := n.Ask(context.Background(), &AskRequest{ID: "1", Method: "GET", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+	if action != FilterActionBlock {
+		t.Errorf("expected FilterActionBlock, got %q", action)
+	}
+}
+
+func TestNotifyAsker_Ask_NoActionSupport_DefaultsToBlock(t *testing.T) {
+	// Daemons without action support just display the notification and
+	// print nothing to stdout.
+	stubNotifySend(t, "true")
+
+	n := NewNotifyAsker(5 * time.Second)
+	action, err := n.Ask(context.Background(), &AskRequest{ID: "1", Method: "GET", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+	if action != FilterActionBlock {
+		t.Errorf("expected FilterActionBlock when no action is printed, got %q", action)
+	}
+}
+
+func TestNotifyAsker_Ask_Timeout(t *testing.T) {
+	stubNotifySend(t, fmt.Sprintf("sleep %f", (200*time.Millisecond).Seconds()))
+
+	n := NewNotifyAsker(20 * time.Millisecond)
+	action, err := n.Ask(context.Background(), &AskRequest{ID: "1", Method: "GET", URL: "https://example.com"})
+	if action != FilterActionBlock {
+		t.Errorf("expected FilterActionBlock on timeout, got %q", action)
+	}
+	if err == nil {
+		t.Error("expected an error on timeout")
+	}
+}
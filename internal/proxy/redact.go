@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// redactedPlaceholder replaces matched header values and body regex matches
+// before a request/response is logged.
+const redactedPlaceholder = "***REDACTED***"
+
+// RedactConfig lists additional header names and body regex patterns to
+// redact from persisted request/response logs, on top of the built-in
+// default header set (see defaultRedactHeaders).
+type RedactConfig struct {
+	// Headers are additional header names to redact. Matching is
+	// case-insensitive.
+	Headers []string
+
+	// BodyPatterns are regular expressions applied to request/response
+	// bodies; matches are replaced with redactedPlaceholder.
+	BodyPatterns []string
+}
+
+// Redactor applies RedactConfig, plus a built-in default header set, to
+// request/response headers and bodies before RequestLogger persists them.
+type Redactor struct {
+	headers      map[string]bool
+	bodyPatterns []*regexp.Regexp
+}
+
+// defaultRedactHeaders are redacted even with no RedactConfig: the common
+// set of headers that tend to carry secrets (auth tokens, session cookies).
+var defaultRedactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"Proxy-Authorization",
+}
+
+// NewRedactor compiles cfg into a Redactor. cfg may be nil, in which case
+// only the default header set is redacted.
+func NewRedactor(cfg *RedactConfig) (*Redactor, error) {
+	r := &Redactor{headers: make(map[string]bool)}
+	for _, h := range defaultRedactHeaders {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, h := range cfg.Headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, pattern := range cfg.BodyPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact body pattern %q: %w", pattern, err)
+		}
+		r.bodyPatterns = append(r.bodyPatterns, re)
+	}
+	return r, nil
+}
+
+// RedactHeaders returns a copy of headers with values for configured header
+// names replaced by redactedPlaceholder.
+func (r *Redactor) RedactHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string][]string, len(headers))
+	for k, v := range headers {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			redacted[k] = []string{redactedPlaceholder}
+		} else {
+			redacted[k] = v
+		}
+	}
+	return redacted
+}
+
+// RedactBody replaces every match of every configured body pattern with
+// redactedPlaceholder.
+func (r *Redactor) RedactBody(body []byte) []byte {
+	for _, re := range r.bodyPatterns {
+		body = re.ReplaceAll(body, []byte(redactedPlaceholder))
+	}
+	return body
+}
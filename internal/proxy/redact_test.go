@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"testing"
+)
+
+func TestRedactor_DefaultHeaders(t *testing.T) {
+	r, err := NewRedactor(nil)
+	if err != nil {
+		t.Fatalf("NewRedactor(nil) failed: %v", err)
+	}
+
+	headers := map[string][]string{
+		"Authorization": {"Bearer secret"},
+		"authorization": {"lowercase-variant"},
+		"X-Custom":      {"keep me"},
+	}
+
+	redacted := r.RedactHeaders(headers)
+	if redacted["Authorization"][0] != redactedPlaceholder {
+		t.Errorf("Authorization = %v, want redacted", redacted["Authorization"])
+	}
+	if redacted["authorization"][0] != redactedPlaceholder {
+		t.Errorf("case-insensitive authorization = %v, want redacted", redacted["authorization"])
+	}
+	if redacted["X-Custom"][0] != "keep me" {
+		t.Errorf("X-Custom = %v, want unchanged", redacted["X-Custom"])
+	}
+}
+
+func TestRedactor_CustomHeaders(t *testing.T) {
+	r, err := NewRedactor(&RedactConfig{Headers: []string{"x-internal-token"}})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	headers := map[string][]string{"X-Internal-Token": {"abc123"}}
+	redacted := r.RedactHeaders(headers)
+	if redacted["X-Internal-Token"][0] != redactedPlaceholder {
+		t.Errorf("X-Internal-Token = %v, want redacted", redacted["X-Internal-Token"])
+	}
+}
+
+func TestRedactor_RedactHeaders_Nil(t *testing.T) {
+	r, _ := NewRedactor(nil)
+	if got := r.RedactHeaders(nil); got != nil {
+		t.Errorf("RedactHeaders(nil) = %v, want nil", got)
+	}
+}
+
+func TestRedactor_BodyPatterns(t *testing.T) {
+	r, err := NewRedactor(&RedactConfig{BodyPatterns: []string{`"password"\s*:\s*"[^"]*"`}})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	body := []byte(`{"user":"alice","password":"s3cr3t"}`)
+	got := string(r.RedactBody(body))
+	want := `{"user":"alice",***REDACTED***}`
+	if got != want {
+		t.Errorf("RedactBody = %q, want %q", got, want)
+	}
+}
+
+func TestNewRedactor_InvalidPattern(t *testing.T) {
+	if _, err := NewRedactor(&RedactConfig{BodyPatterns: []string{"("}}); err == nil {
+		t.Fatal("expected error for invalid regex pattern")
+	}
+}
@@ -5,19 +5,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"devsandbox/internal/logging"
 )
 
+// writeQueueSize bounds how many marshaled entries can be pending for the
+// background writer goroutine before Log blocks. It's large enough to
+// absorb a burst without blocking request handling, while still exerting
+// backpressure (rather than dropping entries) if the disk falls behind.
+const writeQueueSize = 256
+
 const (
 	RequestLogPrefix        = "requests"
 	RequestLogSuffix        = ".jsonl"    // Active file (uncompressed for tailing)
 	RequestLogArchiveSuffix = ".jsonl.gz" // Rotated files (compressed)
 )
 
+// MaxBufferedBodySize caps how much of a request body LogRequest reads into
+// memory. A chunked body has no Content-Length to check up front, and a
+// client can lie about the one it sends, so without a hard cap a large or
+// infinite upload would be buffered in full before anything (logging, body
+// filtering) got a chance to act on it. Past this cap the *buffered* copy is
+// truncated, but the bytes already read are replayed ahead of whatever's
+// left of the original body, so the upstream request is never truncated -
+// only what we keep in memory for logging/filtering is bounded.
+const MaxBufferedBodySize = 32 * 1024 * 1024 // 32MB
+
 // RequestLog represents a logged HTTP request/response pair
 type RequestLog struct {
 	Timestamp       time.Time           `json:"ts"`
@@ -32,59 +50,269 @@ type RequestLog struct {
 	Error           string              `json:"error,omitempty"`
 	FilterAction    string              `json:"filter_action,omitempty"`
 	FilterReason    string              `json:"filter_reason,omitempty"`
+	Rewritten       bool                `json:"rewritten,omitempty"`
+	RewrittenURL    string              `json:"rewritten_url,omitempty"`
+	BodyTruncated   bool                `json:"body_truncated,omitempty"`
+
+	// gRPC-specific fields, populated when the request/response
+	// Content-Type is application/grpc. GRPCReqJSON/GRPCRespJSON are only
+	// populated when the logger has a descriptor set configured
+	// (proxy.grpc.descriptors); RequestBody/ResponseBody are left empty
+	// for gRPC calls since the raw frames are opaque without one.
+	GRPCMethod    string            `json:"grpc_method,omitempty"`
+	GRPCStatus    string            `json:"grpc_status,omitempty"`
+	GRPCMessage   string            `json:"grpc_message,omitempty"`
+	GRPCReqSizes  []int             `json:"grpc_req_sizes,omitempty"`
+	GRPCRespSizes []int             `json:"grpc_resp_sizes,omitempty"`
+	GRPCReqJSON   []json.RawMessage `json:"grpc_req_json,omitempty"`
+	GRPCRespJSON  []json.RawMessage `json:"grpc_resp_json,omitempty"`
+}
+
+// LogRetentionConfig controls pruning of rotated request logs
+// (requests_*.jsonl.gz under logs/proxy/), set via [proxy.logs] and threaded
+// down via NewRequestLogger so the policy only affects the request log
+// writer, not the unrelated internal-error-log writer server.go also keeps.
+type LogRetentionConfig struct {
+	// MaxAge prunes rotated files older than this once exceeded (e.g.
+	// "7d", "48h" - see ParseLogRetentionAge). Empty means no age limit.
+	MaxAge string
+
+	// MaxTotalSize prunes the oldest rotated files once their combined
+	// size exceeds this (e.g. "500MB", "2G" - see ParseLogRetentionSize).
+	// Empty means no size limit.
+	MaxTotalSize string
+
+	// MaxFiles caps the total number of files (active + rotated) kept.
+	// Zero means RotatingFileWriter's own default (5).
+	MaxFiles int
 }
 
 // RequestLogger writes HTTP request/response logs to rotating gzip-compressed files
 // and optionally forwards them to remote destinations.
+//
+// Writes to the underlying file are serialized through a single background
+// goroutine fed by writeQueue, rather than being written inline under a
+// lock: Log only has to marshal the entry and hand it off, so a slow disk
+// (or the synchronous rotate/compress that a full file triggers) never
+// blocks the request path that called Log.
 type RequestLogger struct {
 	writer         *RotatingFileWriter
 	dispatcher     *logging.Dispatcher
 	ownsDispatcher bool // true if this logger created/owns the dispatcher
-	mu             sync.Mutex
+	redactor       *Redactor
+	grpcDecoder    *GRPCDecoder // nil unless proxy.grpc.descriptors is configured
+	paused         atomic.Bool  // when true, Log is a no-op (requests are still filtered upstream)
+	traceHook      func(*RequestLog)
+	stream         *StreamServer
+
+	// writeQueue is guarded against sending on a closed channel by
+	// closeMu: Log/writeMarker/Flush hold it for read while they send, and
+	// Close takes it exclusively before closing the channel, so a send
+	// that acquired the read lock is always able to complete before Close
+	// proceeds. closed records whether that close has already happened.
+	writeQueue chan writeJob
+	writeDone  chan struct{}
+	closeMu    sync.RWMutex
+	closed     bool
+}
+
+// writeJob is a unit of work for writeLoop: either a marshaled entry to
+// append to the log file, or (when data is nil) a flush barrier whose done
+// channel is closed once every job queued ahead of it has been written.
+type writeJob struct {
+	data []byte
+	done chan struct{}
 }
 
 // NewRequestLogger creates a new request logger.
 // If dispatcher is provided, logs will also be forwarded to remote destinations.
 // If ownsDispatcher is true, the dispatcher will be closed when the logger is closed.
-func NewRequestLogger(dir string, dispatcher *logging.Dispatcher, ownsDispatcher bool) (*RequestLogger, error) {
-	writer, err := NewRotatingFileWriter(RotatingFileWriterConfig{
+// redact may be nil, in which case only the default secret-header set is redacted.
+// grpc may be nil, in which case gRPC message bodies are not decoded to JSON.
+// retention may be nil, in which case only RotatingFileWriter's default
+// MaxFiles cap applies (no age or total-size limit).
+func NewRequestLogger(dir string, dispatcher *logging.Dispatcher, ownsDispatcher bool, redact *RedactConfig, grpc *GRPCConfig, retention *LogRetentionConfig) (*RequestLogger, error) {
+	writerCfg := RotatingFileWriterConfig{
 		Dir:           dir,
 		Prefix:        RequestLogPrefix,
 		Suffix:        RequestLogSuffix,
 		ArchiveSuffix: RequestLogArchiveSuffix,
-	})
+	}
+	if retention != nil {
+		writerCfg.MaxFiles = retention.MaxFiles
+		if retention.MaxAge != "" {
+			age, err := ParseLogRetentionAge(retention.MaxAge)
+			if err != nil {
+				return nil, fmt.Errorf("proxy.logs.max_age: %w", err)
+			}
+			writerCfg.MaxAge = age
+		}
+		if retention.MaxTotalSize != "" {
+			size, err := ParseLogRetentionSize(retention.MaxTotalSize)
+			if err != nil {
+				return nil, fmt.Errorf("proxy.logs.max_total_size: %w", err)
+			}
+			writerCfg.MaxTotalSize = size
+		}
+	}
+
+	writer, err := NewRotatingFileWriter(writerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := NewRedactor(redact)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcDecoder, err := NewGRPCDecoder(grpc)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RequestLogger{
+	rl := &RequestLogger{
 		writer:         writer,
 		dispatcher:     dispatcher,
 		ownsDispatcher: ownsDispatcher,
-	}, nil
+		redactor:       redactor,
+		grpcDecoder:    grpcDecoder,
+		writeQueue:     make(chan writeJob, writeQueueSize),
+		writeDone:      make(chan struct{}),
+	}
+	go rl.writeLoop()
+	return rl, nil
+}
+
+// writeLoop is the single consumer of writeQueue, serializing every write
+// to the underlying file (including any rotation/compression it triggers)
+// onto one goroutine. It runs until writeQueue is closed (by Close), then
+// closes writeDone so Close can wait for the last queued entry to land
+// before closing the file out from under it.
+func (rl *RequestLogger) writeLoop() {
+	defer close(rl.writeDone)
+	for job := range rl.writeQueue {
+		if job.data != nil {
+			if _, err := rl.writer.Write(job.data); err != nil {
+				log.Printf("reqlog: failed to write entry: %v", err)
+			}
+		}
+		if job.done != nil {
+			close(job.done)
+		}
+	}
+}
+
+// enqueue hands job to the background writer, returning false without
+// blocking if the logger has already been closed (in which case there's
+// nothing left to write it).
+func (rl *RequestLogger) enqueue(job writeJob) bool {
+	rl.closeMu.RLock()
+	defer rl.closeMu.RUnlock()
+	if rl.closed {
+		return false
+	}
+	rl.writeQueue <- job
+	return true
+}
+
+// Flush blocks until every entry queued ahead of the call has been written
+// (or failed and been logged via log.Printf). Tests use it to make a
+// just-logged entry's effect on the file observable before reading it back.
+// It's a no-op if the logger has already been closed.
+func (rl *RequestLogger) Flush() {
+	done := make(chan struct{})
+	if !rl.enqueue(writeJob{done: done}) {
+		return
+	}
+	<-done
+}
+
+// Paused reports whether log persistence is currently paused.
+func (rl *RequestLogger) Paused() bool {
+	return rl.paused.Load()
+}
+
+// SetPaused pauses or resumes log persistence without stopping the proxy.
+// While paused, requests are still filtered upstream but Log becomes a
+// no-op, so nothing is written to disk or forwarded remotely. A marker
+// entry is always written on a state transition, so a paused window is
+// visible when reviewing the log later. Calling SetPaused with the
+// current state is a no-op (no duplicate marker).
+func (rl *RequestLogger) SetPaused(paused bool) {
+	if !rl.paused.CompareAndSwap(!paused, paused) {
+		return
+	}
+
+	action := "resumed"
+	if paused {
+		action = "paused"
+	}
+	rl.writeMarker(fmt.Sprintf("logging %s", action))
+}
+
+// writeMarker queues a marker entry for the background writer, bypassing
+// the paused check in Log.
+func (rl *RequestLogger) writeMarker(message string) {
+	data, err := json.Marshal(&RequestLog{Timestamp: time.Now(), Method: "LOGGING", URL: message})
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	rl.enqueue(writeJob{data: data})
 }
 
-// Log writes a request/response pair to the log and forwards to remote destinations.
+// SetTraceHook registers a callback invoked synchronously for every request
+// passed to Log, regardless of the paused state. It drives live console
+// tracing (e.g. --trace-http) without depending on persisted logs. Pass nil
+// to disable.
+func (rl *RequestLogger) SetTraceHook(fn func(*RequestLog)) {
+	rl.traceHook = fn
+}
+
+// SetStream registers a StreamServer that every request/response pair is
+// published to as a JSON line, regardless of the paused state (see
+// SetTraceHook). Pass nil to disable.
+func (rl *RequestLogger) SetStream(stream *StreamServer) {
+	rl.stream = stream
+}
+
+// Log queues a request/response pair for the background writer and
+// forwards it to remote destinations. It is a no-op while the logger is
+// paused (see SetPaused). The local write happens asynchronously on
+// writeLoop, so a slow disk or a rotation it triggers never blocks the
+// caller; a write that ultimately fails is reported via log.Printf rather
+// than through the returned error, which only reflects marshaling.
 func (rl *RequestLogger) Log(entry *RequestLog) error {
+	if rl.traceHook != nil {
+		rl.traceHook(entry)
+	}
+
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
 	data = append(data, '\n')
 
-	// Write to local file (protected by lock)
-	rl.mu.Lock()
-	_, writeErr := rl.writer.Write(data)
-	rl.mu.Unlock()
+	if rl.stream != nil {
+		rl.stream.Publish(data)
+	}
+
+	if rl.paused.Load() {
+		return nil
+	}
+
+	rl.enqueue(writeJob{data: data})
 
-	// Forward to remote destinations outside the lock to prevent blocking
-	// on slow network I/O (syslog, OTLP, etc.)
+	// Forward to remote destinations outside the write queue to prevent
+	// blocking on slow network I/O (syslog, OTLP, etc.)
 	if rl.dispatcher != nil && rl.dispatcher.HasWriters() {
 		logEntry := rl.toLogEntry(entry)
 		_ = rl.dispatcher.Write(logEntry) // Don't fail on remote errors
 	}
 
-	return writeErr
+	return nil
 }
 
 // toLogEntry converts a RequestLog to a logging.Entry for remote forwarding.
@@ -122,28 +350,93 @@ func (rl *RequestLogger) toLogEntry(req *RequestLog) *logging.Entry {
 	}
 }
 
-// LogRequest captures request details and returns a log entry
+// LogRequest captures request details and returns a log entry. Header
+// values and body content matching the logger's redaction rules are
+// replaced before the entry is stored; the original bytes are never kept.
 func (rl *RequestLogger) LogRequest(req *http.Request) (*RequestLog, []byte) {
 	entry := &RequestLog{
 		Timestamp:      time.Now(),
 		Method:         req.Method,
 		URL:            req.URL.String(),
-		RequestHeaders: redactHeaders(cloneHeaders(req.Header)),
+		RequestHeaders: rl.redactor.RedactHeaders(cloneHeaders(req.Header)),
 	}
 
-	// Read and restore request body
+	// Read and restore request body, capped at MaxBufferedBodySize so a
+	// large or chunked upload can't be buffered in full before logging or
+	// body filtering gets a chance to act on it.
 	var reqBody []byte
 	if req.Body != nil {
-		reqBody, _ = io.ReadAll(req.Body)
-		_ = req.Body.Close()
-		req.Body = io.NopCloser(bytes.NewReader(reqBody))
-		entry.RequestBody = reqBody
+		var err error
+		reqBody, entry.BodyTruncated, req.Body, err = bufferBody(req.Body)
+		if err != nil {
+			reqBody = nil
+		}
+	}
+
+	if isGRPCContentType(req.Header.Get("Content-Type")) {
+		rl.logGRPCRequest(entry, req.URL.Path, reqBody)
+	} else if reqBody != nil {
+		entry.RequestBody = rl.redactor.RedactBody(reqBody)
 	}
 
 	return entry, reqBody
 }
 
-// LogResponse completes the log entry with response details
+// bufferBody reads up to MaxBufferedBodySize bytes of r into memory,
+// returning the buffered prefix, whether it was truncated, and a
+// replacement io.ReadCloser that replays the buffered prefix followed by
+// whatever of r was left unread - so a body too large to log in full is
+// still sent upstream in full.
+func bufferBody(r io.ReadCloser) (buffered []byte, truncated bool, replacement io.ReadCloser, err error) {
+	read, err := io.ReadAll(io.LimitReader(r, MaxBufferedBodySize+1))
+	if err != nil {
+		_ = r.Close()
+		return nil, false, io.NopCloser(bytes.NewReader(nil)), err
+	}
+
+	if int64(len(read)) <= MaxBufferedBodySize {
+		_ = r.Close()
+		return read, false, io.NopCloser(bytes.NewReader(read)), nil
+	}
+
+	// read already holds MaxBufferedBodySize+1 bytes pulled off r, so the
+	// replay must replay all of read (not just the truncated copy kept for
+	// logging) before falling through to whatever of r is left unread.
+	buffered = read[:MaxBufferedBodySize]
+	return buffered, true, &bodyReplayCloser{Reader: io.MultiReader(bytes.NewReader(read), r), closer: r}, nil
+}
+
+// bodyReplayCloser replays a buffered prefix followed by the rest of an
+// unread body, closing the original body when the replay is closed.
+type bodyReplayCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (b *bodyReplayCloser) Close() error {
+	return b.closer.Close()
+}
+
+// logGRPCRequest populates entry's gRPC fields from a request body framed
+// as length-prefixed gRPC messages. RequestBody is left empty for gRPC
+// calls; GRPCReqSizes (and GRPCReqJSON, when a descriptor set is
+// configured) carry the useful information instead.
+func (rl *RequestLogger) logGRPCRequest(entry *RequestLog, path string, body []byte) {
+	entry.GRPCMethod = path
+	entry.GRPCReqSizes = grpcFrameSizes(body)
+	if rl.grpcDecoder == nil {
+		return
+	}
+	docs, err := rl.grpcDecoder.DecodeMessages(path, grpcFramePayloads(body), true)
+	if err != nil {
+		return
+	}
+	entry.GRPCReqJSON = docs
+}
+
+// LogResponse completes the log entry with response details. Header values
+// and body content matching the logger's redaction rules are replaced
+// before the entry is stored; the original bytes are never kept.
 func (rl *RequestLogger) LogResponse(entry *RequestLog, resp *http.Response, startTime time.Time) []byte {
 	entry.Duration = time.Since(startTime)
 
@@ -153,7 +446,7 @@ func (rl *RequestLogger) LogResponse(entry *RequestLog, resp *http.Response, sta
 	}
 
 	entry.StatusCode = resp.StatusCode
-	entry.ResponseHeaders = redactHeaders(cloneHeaders(resp.Header))
+	entry.ResponseHeaders = rl.redactor.RedactHeaders(cloneHeaders(resp.Header))
 
 	// Read and restore response body
 	var respBody []byte
@@ -161,43 +454,55 @@ func (rl *RequestLogger) LogResponse(entry *RequestLog, resp *http.Response, sta
 		respBody, _ = io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 		resp.Body = io.NopCloser(bytes.NewReader(respBody))
-		entry.ResponseBody = respBody
+	}
+
+	if isGRPCContentType(resp.Header.Get("Content-Type")) || entry.GRPCMethod != "" {
+		rl.logGRPCResponse(entry, resp, respBody)
+	} else if respBody != nil {
+		entry.ResponseBody = rl.redactor.RedactBody(respBody)
 	}
 
 	return respBody
 }
 
-// Close closes the logger and flushes remote destinations.
-// The dispatcher is only closed if this logger owns it.
-func (rl *RequestLogger) Close() error {
-	if rl.dispatcher != nil && rl.ownsDispatcher {
-		_ = rl.dispatcher.Close()
+// logGRPCResponse populates entry's gRPC fields from a response body
+// framed as length-prefixed gRPC messages, plus the grpc-status/
+// grpc-message trailer. ResponseBody is left empty for gRPC calls.
+func (rl *RequestLogger) logGRPCResponse(entry *RequestLog, resp *http.Response, body []byte) {
+	entry.GRPCRespSizes = grpcFrameSizes(body)
+	if code, msg, ok := grpcStatus(resp.Header, resp.Trailer); ok {
+		entry.GRPCStatus = code
+		entry.GRPCMessage = msg
 	}
-	return rl.writer.Close()
-}
 
-var sensitiveHeaders = map[string]bool{
-	"Authorization":       true,
-	"Cookie":              true,
-	"Set-Cookie":          true,
-	"X-Api-Key":           true,
-	"X-Auth-Token":        true,
-	"Proxy-Authorization": true,
+	if rl.grpcDecoder == nil || entry.GRPCMethod == "" {
+		return
+	}
+	docs, err := rl.grpcDecoder.DecodeMessages(entry.GRPCMethod, grpcFramePayloads(body), false)
+	if err != nil {
+		return
+	}
+	entry.GRPCRespJSON = docs
 }
 
-func redactHeaders(headers map[string][]string) map[string][]string {
-	if headers == nil {
-		return nil
+// Close stops the background writer (after it drains any queued entries),
+// closes the underlying file, and flushes remote destinations. The
+// dispatcher is only closed if this logger owns it. Safe to call
+// concurrently with in-flight Log/writeMarker calls: they'll either land
+// before the close or be silently skipped, never panic on a closed channel.
+func (rl *RequestLogger) Close() error {
+	rl.closeMu.Lock()
+	if !rl.closed {
+		rl.closed = true
+		close(rl.writeQueue)
 	}
-	redacted := make(map[string][]string, len(headers))
-	for k, v := range headers {
-		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
-			redacted[k] = []string{"[REDACTED]"}
-		} else {
-			redacted[k] = v
-		}
+	rl.closeMu.Unlock()
+	<-rl.writeDone
+
+	if rl.dispatcher != nil && rl.ownsDispatcher {
+		_ = rl.dispatcher.Close()
 	}
-	return redacted
+	return rl.writer.Close()
 }
 
 func cloneHeaders(h http.Header) map[string][]string {
@@ -1,14 +1,19 @@
 package proxy
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"testing"
 )
 
 func TestLogRequest_RedactsSensitiveHeaders(t *testing.T) {
 	dir := t.TempDir()
-	rl, err := NewRequestLogger(dir, nil, false)
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -40,9 +45,251 @@ func TestLogRequest_RedactsSensitiveHeaders(t *testing.T) {
 	}
 }
 
-func TestRedactHeaders_Nil(t *testing.T) {
-	result := redactHeaders(nil)
-	if result != nil {
-		t.Error("redactHeaders(nil) should return nil")
+func TestRequestLogger_PauseResume(t *testing.T) {
+	dir := t.TempDir()
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rl.Close() }()
+
+	if rl.Paused() {
+		t.Fatal("logger should not start paused")
+	}
+
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/before"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rl.SetPaused(true)
+	if !rl.Paused() {
+		t.Error("Paused() = false after SetPaused(true)")
+	}
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/during-pause"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rl.SetPaused(true) // no-op, should not write a second marker
+	rl.SetPaused(false)
+	if rl.Paused() {
+		t.Error("Paused() = true after SetPaused(false)")
+	}
+
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/after"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rl.Flush()
+	data, err := os.ReadFile(rl.writer.CurrentPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	if strings.Count(content, "logging paused") != 1 {
+		t.Errorf("expected exactly one 'logging paused' marker, got content: %s", content)
+	}
+	if strings.Count(content, "logging resumed") != 1 {
+		t.Errorf("expected exactly one 'logging resumed' marker, got content: %s", content)
+	}
+	if strings.Contains(content, "during-pause") {
+		t.Error("request logged while paused should not be persisted")
+	}
+	if !strings.Contains(content, "/before") || !strings.Contains(content, "/after") {
+		t.Error("requests logged while not paused should be persisted")
+	}
+}
+
+func TestLogRequest_GRPC_NoDescriptor(t *testing.T) {
+	dir := t.TempDir()
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rl.Close() }()
+
+	body := frame([]byte("payload"))
+	req, _ := http.NewRequest("POST", "https://api.example.com/test.Greeter/SayHello", io.NopCloser(bytes.NewReader(body)))
+	req.Header.Set("Content-Type", "application/grpc")
+
+	entry, _ := rl.LogRequest(req)
+
+	if entry.GRPCMethod != "/test.Greeter/SayHello" {
+		t.Errorf("GRPCMethod = %q, want /test.Greeter/SayHello", entry.GRPCMethod)
+	}
+	if len(entry.GRPCReqSizes) != 1 || entry.GRPCReqSizes[0] != len("payload") {
+		t.Errorf("GRPCReqSizes = %v, want [7]", entry.GRPCReqSizes)
+	}
+	if entry.RequestBody != nil {
+		t.Error("RequestBody should be left empty for gRPC calls without a decoder")
+	}
+	if entry.GRPCReqJSON != nil {
+		t.Error("GRPCReqJSON should be nil without a configured descriptor set")
+	}
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/grpc"}},
+		Trailer:    http.Header{"Grpc-Status": {"0"}},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+	rl.LogResponse(entry, resp, entry.Timestamp)
+
+	if entry.GRPCStatus != "OK" {
+		t.Errorf("GRPCStatus = %q, want OK", entry.GRPCStatus)
+	}
+	if entry.ResponseBody != nil {
+		t.Error("ResponseBody should be left empty for gRPC calls")
+	}
+}
+
+func TestRequestLogger_TraceHook(t *testing.T) {
+	dir := t.TempDir()
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rl.Close() }()
+
+	var traced []string
+	rl.SetTraceHook(func(e *RequestLog) {
+		traced = append(traced, e.URL)
+	})
+
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/one"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rl.SetPaused(true)
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/two"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traced) != 2 {
+		t.Fatalf("expected trace hook to fire for every Log call including while paused, got %v", traced)
+	}
+	if traced[0] != "https://example.com/one" || traced[1] != "https://example.com/two" {
+		t.Errorf("unexpected traced URLs: %v", traced)
+	}
+
+	rl.SetTraceHook(nil)
+	if err := rl.Log(&RequestLog{Method: "GET", URL: "https://example.com/three"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(traced) != 2 {
+		t.Error("trace hook should not fire after being cleared")
+	}
+}
+
+func TestBufferBody_UnderLimit(t *testing.T) {
+	body := io.NopCloser(bytes.NewReader([]byte("hello world")))
+
+	buffered, truncated, replacement, err := bufferBody(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated {
+		t.Error("a body under the limit should not be truncated")
+	}
+	if string(buffered) != "hello world" {
+		t.Errorf("unexpected buffered body: %q", buffered)
+	}
+
+	replayed, _ := io.ReadAll(replacement)
+	if string(replayed) != "hello world" {
+		t.Errorf("unexpected replayed body: %q", replayed)
+	}
+}
+
+func TestBufferBody_OverLimit(t *testing.T) {
+	full := bytes.Repeat([]byte("a"), MaxBufferedBodySize+1000)
+	body := io.NopCloser(bytes.NewReader(full))
+
+	buffered, truncated, replacement, err := bufferBody(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated {
+		t.Error("a body over the limit should be truncated")
+	}
+	if len(buffered) != MaxBufferedBodySize {
+		t.Errorf("expected buffered copy capped at %d bytes, got %d", MaxBufferedBodySize, len(buffered))
+	}
+
+	replayed, err := io.ReadAll(replacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(replayed, full) {
+		t.Error("replayed body should reproduce the full original body, not the truncated copy")
+	}
+}
+
+func TestLogRequest_OversizedBodySetsBodyTruncated(t *testing.T) {
+	dir := t.TempDir()
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rl.Close() }()
+
+	full := bytes.Repeat([]byte("x"), MaxBufferedBodySize+100)
+	req, _ := http.NewRequest("POST", "https://upload.example.com/", bytes.NewReader(full))
+
+	entry, reqBody := rl.LogRequest(req)
+
+	if !entry.BodyTruncated {
+		t.Error("expected BodyTruncated to be set for an oversized body")
+	}
+	if len(reqBody) != MaxBufferedBodySize {
+		t.Errorf("expected the returned body to be capped at %d bytes, got %d", MaxBufferedBodySize, len(reqBody))
+	}
+
+	replayed, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(replayed, full) {
+		t.Error("req.Body should still replay the full original body after LogRequest")
+	}
+}
+
+// TestRequestLogger_Log_ConcurrentCallers hammers Log from many goroutines
+// at once to exercise the background writer under -race: every entry is
+// handed to the same writeQueue and written by a single consumer goroutine,
+// so this should be race-free regardless of how many callers overlap.
+func TestRequestLogger_Log_ConcurrentCallers(t *testing.T) {
+	dir := t.TempDir()
+	rl, err := NewRequestLogger(dir, nil, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rl.Close() }()
+
+	const goroutines = 50
+	const entriesPerGoroutine = 20
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < entriesPerGoroutine; i++ {
+				_ = rl.Log(&RequestLog{Method: "GET", URL: fmt.Sprintf("https://example.com/%d/%d", g, i)})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	rl.Flush()
+	data, err := os.ReadFile(rl.writer.CurrentPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Count(data, []byte("\n"))
+	want := goroutines * entriesPerGoroutine
+	if lines != want {
+		t.Errorf("expected %d logged lines, got %d", want, lines)
 	}
 }
@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// maxRewriteBodySize caps how much of a response body ResponseRewriter will
+// process. Larger bodies are left untouched - buffering an arbitrarily large
+// response here just to run a regex over it isn't worth the memory spike for
+// what's meant as a local-dev convenience, not a general body transform.
+const maxRewriteBodySize = 10 * 1024 * 1024 // 10MB
+
+// ResponseRewriteRule rewrites text response bodies from hosts matching
+// Host, for local-dev use against a staging/prod API (e.g. replacing a prod
+// URL baked into JSON responses with localhost).
+type ResponseRewriteRule struct {
+	// Host is a glob pattern (doublestar syntax) matched against the
+	// request's hostname, e.g. "api.example.com" or "*.example.com".
+	Host string
+	// Pattern is a regular expression matched against the response body.
+	Pattern string
+	// Replacement replaces each match of Pattern, using Go regexp
+	// expansion syntax ($1, ${name}, ...).
+	Replacement string
+}
+
+type compiledRewriteRule struct {
+	rule    ResponseRewriteRule
+	pattern *regexp.Regexp
+}
+
+// ResponseRewriter applies a list of ResponseRewriteRule to response bodies
+// in the proxy's OnResponse hook.
+type ResponseRewriter struct {
+	rules []compiledRewriteRule
+}
+
+// NewResponseRewriter compiles rules into a ResponseRewriter.
+func NewResponseRewriter(rules []ResponseRewriteRule) (*ResponseRewriter, error) {
+	rw := &ResponseRewriter{}
+	for _, rule := range rules {
+		if !doublestar.ValidatePattern(rule.Host) {
+			return nil, fmt.Errorf("invalid response_rewrite host pattern %q", rule.Host)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response_rewrite pattern %q: %w", rule.Pattern, err)
+		}
+		rw.rules = append(rw.rules, compiledRewriteRule{rule: rule, pattern: re})
+	}
+	return rw, nil
+}
+
+// Rewrite applies every rule whose Host matches host to body, in order,
+// stopping early if body exceeds maxRewriteBodySize. It returns the
+// (possibly unchanged) body and whether any rule actually matched.
+func (rw *ResponseRewriter) Rewrite(host string, body []byte) ([]byte, bool) {
+	if rw == nil || len(body) > maxRewriteBodySize {
+		return body, false
+	}
+
+	rewritten := false
+	for _, c := range rw.rules {
+		if matched, _ := doublestar.Match(c.rule.Host, host); !matched {
+			continue
+		}
+		if !c.pattern.Match(body) {
+			continue
+		}
+		body = c.pattern.ReplaceAll(body, []byte(c.rule.Replacement))
+		rewritten = true
+	}
+	return body, rewritten
+}
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResponseRewriter_MatchAndReplace(t *testing.T) {
+	rw, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "api.example.com", Pattern: `https://api\.example\.com`, Replacement: "http://localhost:8080"},
+	})
+	if err != nil {
+		t.Fatalf("NewResponseRewriter failed: %v", err)
+	}
+
+	body := []byte(`{"url":"https://api.example.com/v1/thing"}`)
+	got, rewritten := rw.Rewrite("api.example.com", body)
+	if !rewritten {
+		t.Fatal("expected rewritten = true")
+	}
+	want := `{"url":"http://localhost:8080/v1/thing"}`
+	if string(got) != want {
+		t.Errorf("Rewrite body = %q, want %q", got, want)
+	}
+}
+
+func TestResponseRewriter_HostMismatch(t *testing.T) {
+	rw, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "api.example.com", Pattern: "prod", Replacement: "dev"},
+	})
+	if err != nil {
+		t.Fatalf("NewResponseRewriter failed: %v", err)
+	}
+
+	body := []byte("prod")
+	got, rewritten := rw.Rewrite("other.example.com", body)
+	if rewritten {
+		t.Error("expected rewritten = false for non-matching host")
+	}
+	if string(got) != "prod" {
+		t.Errorf("body = %q, want unchanged", got)
+	}
+}
+
+func TestResponseRewriter_HostGlob(t *testing.T) {
+	rw, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "*.example.com", Pattern: "prod", Replacement: "dev"},
+	})
+	if err != nil {
+		t.Fatalf("NewResponseRewriter failed: %v", err)
+	}
+
+	got, rewritten := rw.Rewrite("api.example.com", []byte("prod"))
+	if !rewritten || string(got) != "dev" {
+		t.Errorf("Rewrite(api.example.com) = %q, %v, want %q, true", got, rewritten, "dev")
+	}
+}
+
+func TestResponseRewriter_BodyTooLarge(t *testing.T) {
+	rw, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "*", Pattern: "x", Replacement: "y"},
+	})
+	if err != nil {
+		t.Fatalf("NewResponseRewriter failed: %v", err)
+	}
+
+	body := []byte(strings.Repeat("x", maxRewriteBodySize+1))
+	got, rewritten := rw.Rewrite("anything", body)
+	if rewritten {
+		t.Error("expected rewritten = false for oversized body")
+	}
+	if len(got) != len(body) {
+		t.Error("expected oversized body to be returned unchanged")
+	}
+}
+
+func TestResponseRewriter_NilIsNoop(t *testing.T) {
+	var rw *ResponseRewriter
+	got, rewritten := rw.Rewrite("any.example.com", []byte("hello"))
+	if rewritten || string(got) != "hello" {
+		t.Errorf("Rewrite on nil rewriter = %q, %v, want unchanged and false", got, rewritten)
+	}
+}
+
+func TestNewResponseRewriter_InvalidHostPattern(t *testing.T) {
+	if _, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "[", Pattern: "x", Replacement: "y"},
+	}); err == nil {
+		t.Fatal("expected error for invalid host pattern")
+	}
+}
+
+func TestNewResponseRewriter_InvalidRegexPattern(t *testing.T) {
+	if _, err := NewResponseRewriter([]ResponseRewriteRule{
+		{Host: "*", Pattern: "(", Replacement: "y"},
+	}); err == nil {
+		t.Fatal("expected error for invalid regex pattern")
+	}
+}
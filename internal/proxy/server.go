@@ -1,18 +1,27 @@
 package proxy
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/elazarl/goproxy"
 
 	"devsandbox/internal/logging"
@@ -34,7 +43,14 @@ type Server struct {
 	filterEngine        *FilterEngine
 	askServer           *AskServer
 	askQueue            *AskQueue
+	notifier            *NotifyAsker
 	credentialInjectors []CredentialInjector
+	responseRewriter    *ResponseRewriter
+	socks               *SocksServer
+	socksListener       net.Listener
+	metrics             *Metrics
+	metricsServer       *MetricsServer
+	streamServer        *StreamServer
 	wg                  sync.WaitGroup
 	mu                  sync.Mutex
 	running             bool
@@ -59,8 +75,18 @@ func NewServer(cfg *Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create proxy logger: %w", err)
 	}
 
-	// Route goproxy's internal warnings to rotating file
-	proxy.Logger = log.New(proxyLogger, "", log.LstdFlags)
+	// Route goproxy's internal warnings to rotating file, watching for
+	// "Cannot handshake client" lines along the way (see mitmHandshakeWriter).
+	proxy.Logger = log.New(&mitmHandshakeWriter{Writer: proxyLogger, events: cfg.EventLogger}, "", log.LstdFlags)
+
+	// Leaf certificates are signed fresh by goproxy on every TLS handshake
+	// (see TLSConfigFromCA in the vendored goproxy library), so long-running
+	// connections that reconnect always get a leaf with a full validity
+	// window - no explicit re-signing logic is needed here. The CA itself is
+	// long-lived but not eternal, so warn well ahead of its own expiry.
+	if ca.NearingExpiry() {
+		proxy.Logger.Printf("WARNING: proxy CA certificate expires in %d day(s); run 'devsandbox proxy ca rotate' to regenerate it", ca.DaysUntilExpiry())
+	}
 
 	// Use shared dispatcher if provided, otherwise create one from config.
 	// Track ownership so we know who is responsible for closing it.
@@ -76,7 +102,7 @@ func NewServer(cfg *Config) (*Server, error) {
 	}
 
 	// Create request logger for persisting full request/response data
-	reqLogger, err := NewRequestLogger(cfg.LogDir, dispatcher, ownsDispatcher)
+	reqLogger, err := NewRequestLogger(cfg.LogDir, dispatcher, ownsDispatcher, cfg.Redact, cfg.GRPC, cfg.LogRetention)
 	if err != nil {
 		_ = proxyLogger.Close()
 		if ownsDispatcher && dispatcher != nil {
@@ -85,6 +111,21 @@ func NewServer(cfg *Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create request logger: %w", err)
 	}
 
+	// Start the live event streaming socket if configured
+	var streamServer *StreamServer
+	if cfg.StreamSocket {
+		streamServer, err = NewStreamServer(EventSocketPath(cfg.SandboxBase))
+		if err != nil {
+			_ = proxyLogger.Close()
+			_ = reqLogger.Close()
+			if ownsDispatcher && dispatcher != nil {
+				_ = dispatcher.Close()
+			}
+			return nil, fmt.Errorf("failed to start event stream socket: %w", err)
+		}
+		reqLogger.SetStream(streamServer)
+	}
+
 	// Create filter engine if configured
 	var filterEngine *FilterEngine
 	if cfg.Filter != nil && cfg.Filter.IsEnabled() {
@@ -92,23 +133,54 @@ func NewServer(cfg *Config) (*Server, error) {
 		if err != nil {
 			_ = proxyLogger.Close()
 			_ = reqLogger.Close()
+			if streamServer != nil {
+				_ = streamServer.Close()
+			}
 			return nil, fmt.Errorf("failed to create filter engine: %w", err)
 		}
+		if cfg.Filter.IsCacheEnabled() {
+			if err := filterEngine.EnableDecisionPersistence(cfg.SandboxBase); err != nil {
+				_ = proxyLogger.Close()
+				_ = reqLogger.Close()
+				if streamServer != nil {
+					_ = streamServer.Close()
+				}
+				return nil, fmt.Errorf("failed to load persisted filter decisions: %w", err)
+			}
+		}
 	}
 
 	// Set up ask mode if configured (default_action = ask)
 	var askServer *AskServer
 	var askQueue *AskQueue
+	var notifier *NotifyAsker
 	if cfg.Filter != nil && cfg.Filter.DefaultAction == FilterActionAsk {
 		askServer, err = NewAskServer(cfg.SandboxBase)
 		if err != nil {
 			_ = proxyLogger.Close()
 			_ = reqLogger.Close()
+			if streamServer != nil {
+				_ = streamServer.Close()
+			}
 			return nil, fmt.Errorf("failed to create ask server: %w", err)
 		}
 
 		timeout := time.Duration(cfg.Filter.GetAskTimeout()) * time.Second
 		askQueue = NewAskQueue(askServer, filterEngine, timeout)
+
+		if cfg.Filter.GetAskVia() == AskViaNotify {
+			notifier = NewNotifyAsker(timeout)
+		}
+	}
+
+	responseRewriter, err := NewResponseRewriter(cfg.ResponseRewrite)
+	if err != nil {
+		_ = proxyLogger.Close()
+		_ = reqLogger.Close()
+		if streamServer != nil {
+			_ = streamServer.Close()
+		}
+		return nil, fmt.Errorf("failed to create response rewriter: %w", err)
 	}
 
 	s := &Server{
@@ -120,18 +192,47 @@ func NewServer(cfg *Config) (*Server, error) {
 		filterEngine:        filterEngine,
 		askServer:           askServer,
 		askQueue:            askQueue,
+		notifier:            notifier,
 		credentialInjectors: cfg.CredentialInjectors,
+		responseRewriter:    responseRewriter,
+		streamServer:        streamServer,
+	}
+
+	if cfg.SocksEnabled {
+		s.socks = NewSocksServer(reqLogger, filterEngine)
+	}
+
+	if cfg.MetricsAddr != "" {
+		s.metrics = NewMetrics()
+		s.metricsServer = NewMetricsServer(cfg.MetricsAddr, s.metrics, s.IsRunning)
 	}
 
 	s.setupMITM()
+	s.setupUpstreamProxy()
 	s.setupLogging()
 
 	return s, nil
 }
 
 func (s *Server) setupMITM() {
-	// Configure MITM for all HTTPS connections
-	s.proxy.OnRequest().HandleConnect(goproxy.AlwaysMitm)
+	// Intercept every HTTPS connection except hosts listed in NoMITMHosts,
+	// which are tunneled through untouched (see hostMatchesNoMITM) - for
+	// hosts that pin their TLS certificate and would otherwise just fail
+	// the handshake against our generated leaf cert. Skipping MITM also
+	// skips the per-request DoFunc hook that normally runs the filter
+	// engine (setupLogging), so a no-MITM host still gets a host-scope
+	// filter decision here, before the tunnel is allowed through -
+	// otherwise a blocked host could dodge the filter entirely just by
+	// being added to NoMITMHosts.
+	s.proxy.OnRequest().HandleConnect(goproxy.FuncHttpsHandler(func(host string, ctx *goproxy.ProxyCtx) (*goproxy.ConnectAction, string) {
+		if !hostMatchesNoMITM(host, s.config.NoMITMHosts) {
+			return goproxy.MitmConnect, host
+		}
+		if hostFilterDecision(s.filterEngine, host).Action == FilterActionBlock {
+			return goproxy.RejectConnect, host
+		}
+		return goproxy.OkConnect, host
+	}))
 
 	// Set up certificate generation
 	goproxy.GoproxyCa = tls.Certificate{
@@ -147,9 +248,95 @@ func (s *Server) setupMITM() {
 	}
 }
 
+// hostMatchesNoMITM reports whether host (as seen in a CONNECT request,
+// "host:port") matches any of the glob patterns (doublestar syntax) in
+// patterns, matched against the hostname with its port stripped.
+func hostMatchesNoMITM(host string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	hostname := NormalizeHost(host)
+	for _, pattern := range patterns {
+		if matched, _ := doublestar.Match(pattern, hostname); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mitmHandshakeWriter wraps goproxy's internal logger writer to detect
+// "Cannot handshake client" lines - logged when the TLS handshake with our
+// generated leaf cert fails - and record a clear, host-named internal event
+// pointing at the fix (no_mitm_hosts or network.allow_direct), in addition
+// to passing the line through unmodified to the proxy log file.
+type mitmHandshakeWriter struct {
+	io.Writer
+	events *EventLogger
+}
+
+var mitmHandshakeFailureRe = regexp.MustCompile(`Cannot handshake client (\S+) (.+)`)
+
+func (w *mitmHandshakeWriter) Write(p []byte) (int, error) {
+	if w.events != nil {
+		if m := mitmHandshakeFailureRe.FindSubmatch(p); m != nil {
+			host, reason := string(m[1]), strings.TrimSpace(string(m[2]))
+			w.events.Warnf("mitm", "TLS handshake with %s failed (%s); if this host pins its certificate, add it to proxy.no_mitm_hosts or network.allow_direct", host, reason)
+		}
+	}
+	return w.Writer.Write(p)
+}
+
+// setupUpstreamProxy chains the server's own outbound connections through
+// another HTTP(S) proxy (e.g. a corporate egress proxy), when configured via
+// Config.UpstreamProxy or the host's HTTPS_PROXY. It only changes where this
+// server dials out to reach the real internet - the address it listens on
+// for the sandboxed process is unaffected.
+func (s *Server) setupUpstreamProxy() {
+	upstream := s.config.GetUpstreamProxy()
+	if upstream == "" {
+		return
+	}
+
+	upstreamURL, err := url.Parse(upstream)
+	if err != nil {
+		s.proxy.Logger.Printf("WARNING: invalid upstream proxy %q: %v (dialing directly)", upstream, err)
+		return
+	}
+	if upstreamURL.Scheme == "" {
+		upstreamURL.Scheme = "http"
+	}
+
+	// Plain HTTP requests go through Tr.Proxy, which also handles Basic
+	// auth from the URL's userinfo automatically (net/http's Transport).
+	s.proxy.Tr.Proxy = http.ProxyURL(upstreamURL)
+
+	// CONNECT tunnels (used for MITM'd HTTPS) dial the upstream directly
+	// rather than through Tr, so auth needs to be added by hand - goproxy's
+	// dialer ignores the URL's userinfo.
+	s.proxy.ConnectDial = s.proxy.NewConnectDialToProxyWithHandler(upstreamURL.String(), upstreamProxyAuthHandler(upstreamURL))
+}
+
+// upstreamProxyAuthHandler returns a connectReqHandler that adds a
+// Proxy-Authorization header to CONNECT requests sent to upstreamURL, when
+// it carries "user:pass@" credentials. Returns nil (no-op) otherwise.
+func upstreamProxyAuthHandler(upstreamURL *url.URL) func(req *http.Request) {
+	if upstreamURL.User == nil {
+		return nil
+	}
+	password, _ := upstreamURL.User.Password()
+	creds := base64.StdEncoding.EncodeToString([]byte(upstreamURL.User.Username() + ":" + password))
+	return func(req *http.Request) {
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+}
+
 func (s *Server) setupLogging() {
 	// Set up request logging and filtering
 	s.proxy.OnRequest().DoFunc(func(req *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response) {
+		if s.metrics != nil {
+			s.metrics.IncRequests()
+		}
+
 		// Capture request for logging (before credential injection to avoid logging tokens)
 		entry, reqBody := s.reqLogger.LogRequest(req)
 		ctx.UserData = entry
@@ -172,6 +359,9 @@ func (s *Server) setupLogging() {
 			entry.FilterAction = string(decision.Action)
 			entry.FilterReason = decision.Reason
 		}
+		if s.metrics != nil {
+			s.metrics.IncFilterDecision(decision.Action)
+		}
 
 		switch decision.Action {
 		case FilterActionBlock:
@@ -181,9 +371,62 @@ func (s *Server) setupLogging() {
 			if entry != nil {
 				s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
 				_ = s.reqLogger.Log(entry)
+				s.recordMetrics(entry)
+			}
+			return nil, resp
+
+		case FilterActionRateLimited:
+			// Rule's RateLimit was exceeded - reject with 429 regardless
+			// of the rule's configured Action.
+			resp := RateLimitResponse(req, decision.Reason)
+			if entry != nil {
+				s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
+				_ = s.reqLogger.Log(entry)
+				s.recordMetrics(entry)
 			}
 			return nil, resp
 
+		case FilterActionMock:
+			// Serve the canned response instead of reaching the network.
+			resp, err := MockResponse(req, decision.Rule)
+			if err != nil {
+				resp = BlockResponse(req, fmt.Sprintf("mock response unavailable: %v", err))
+				if entry != nil {
+					entry.FilterAction = string(FilterActionBlock)
+					entry.FilterReason = fmt.Sprintf("mock response unavailable: %v", err)
+				}
+			}
+			if entry != nil {
+				s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
+				_ = s.reqLogger.Log(entry)
+				s.recordMetrics(entry)
+			}
+			return nil, resp
+
+		case FilterActionRewrite:
+			// Transparently redirect the request to the rule's target
+			// before it's dialed. Updating req.URL.Host (and the Host
+			// header) is enough: the default Transport dials whatever
+			// req.URL.Host says and derives TLS SNI from that same dial
+			// host, so both stay consistent without extra plumbing.
+			newURL, err := url.Parse(decision.RewrittenURL)
+			if err != nil || newURL.Host == "" {
+				resp := BlockResponse(req, fmt.Sprintf("invalid rewrite target %q: %v", decision.RewrittenURL, err))
+				if entry != nil {
+					entry.FilterAction = string(FilterActionBlock)
+					entry.FilterReason = fmt.Sprintf("invalid rewrite target: %v", err)
+					s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
+					_ = s.reqLogger.Log(entry)
+					s.recordMetrics(entry)
+				}
+				return nil, resp
+			}
+			req.URL = newURL
+			req.Host = newURL.Host
+			if entry != nil {
+				entry.RewrittenURL = newURL.String()
+			}
+
 		case FilterActionAsk:
 			// Handle ask mode
 			if s.askQueue != nil {
@@ -195,6 +438,7 @@ func (s *Server) setupLogging() {
 						entry.FilterReason = "blocked by user decision"
 						s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
 						_ = s.reqLogger.Log(entry)
+						s.recordMetrics(entry)
 					}
 					return nil, resp
 				}
@@ -211,26 +455,112 @@ func (s *Server) setupLogging() {
 					if entry != nil {
 						s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
 						_ = s.reqLogger.Log(entry)
+						s.recordMetrics(entry)
 					}
 					return nil, resp
 				}
 			}
 		}
 
+		// Body rules (Content-Type / size) run independently of the
+		// host/path/url decision above, on whatever LogRequest buffered.
+		bodyDecision := s.filterEngine.MatchBody(req, len(reqBody), entry != nil && entry.BodyTruncated)
+		if bodyDecision.Action == FilterActionBlock {
+			resp := BlockResponse(req, bodyDecision.Reason)
+			if bodyDecision.BodyTooLarge {
+				resp = BodyTooLargeResponse(req, bodyDecision.Reason)
+			}
+			if entry != nil {
+				entry.FilterAction = string(FilterActionBlock)
+				entry.FilterReason = bodyDecision.Reason
+				s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
+				_ = s.reqLogger.Log(entry)
+				s.recordMetrics(entry)
+			}
+			if s.metrics != nil {
+				s.metrics.IncFilterDecision(FilterActionBlock)
+			}
+			return nil, resp
+		}
+
 		return req, nil
 	})
 
 	s.proxy.OnResponse().DoFunc(func(resp *http.Response, ctx *goproxy.ProxyCtx) *http.Response {
+		entry, _ := ctx.UserData.(*RequestLog)
+
+		// A successful WebSocket handshake hands off to a raw bidirectional
+		// tunnel (see handleWebSocketUpgrade) rather than a normal response
+		// body, so it skips filtering, rewriting, and body logging below.
+		if resp != nil && isWebSocketUpgrade(resp.Header) {
+			return s.handleWebSocketUpgrade(resp, ctx, entry)
+		}
+
+		// Response-side filtering catches things a request-only filter
+		// can't, e.g. an allowed host redirecting to one that isn't. This
+		// runs even if the request was already allowed outbound.
+		if resp != nil && s.filterEngine != nil && s.filterEngine.IsEnabled() {
+			decision := s.filterEngine.MatchResponse(resp, ctx.Req)
+			if s.metrics != nil {
+				s.metrics.IncFilterDecision(decision.Action)
+			}
+			if decision.Action == FilterActionBlock {
+				blocked := BlockResponse(ctx.Req, decision.Reason)
+				if entry != nil {
+					entry.FilterAction = string(decision.Action)
+					entry.FilterReason = decision.Reason
+					s.reqLogger.LogResponse(entry, blocked, entry.Timestamp)
+					_ = s.reqLogger.Log(entry)
+					s.recordMetrics(entry)
+				}
+				return blocked
+			}
+		}
+
+		// Rewrite text response bodies for local-dev use before the body is
+		// read (and the original bytes discarded) by the log entry below.
+		if resp != nil && resp.Body != nil && s.responseRewriter != nil {
+			body, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if readErr == nil {
+				host := NormalizeHost(ctx.Req.Host)
+				rewritten, changed := s.responseRewriter.Rewrite(host, body)
+				if changed {
+					resp.Body = io.NopCloser(bytes.NewReader(rewritten))
+					resp.ContentLength = int64(len(rewritten))
+					resp.Header.Set("Content-Length", strconv.Itoa(len(rewritten)))
+					if entry != nil {
+						entry.Rewritten = true
+					}
+				} else {
+					resp.Body = io.NopCloser(bytes.NewReader(body))
+				}
+			} else {
+				resp.Body = io.NopCloser(bytes.NewReader(nil))
+			}
+		}
+
 		// Complete and persist log entry
-		if entry, ok := ctx.UserData.(*RequestLog); ok {
+		if entry != nil {
 			s.reqLogger.LogResponse(entry, resp, entry.Timestamp)
 			_ = s.reqLogger.Log(entry)
+			s.recordMetrics(entry)
 		}
 
 		return resp
 	})
 }
 
+// recordMetrics reports a completed request's status class and transferred
+// bytes (request + response body, as captured by the request logger) to
+// the metrics collector, if one is configured.
+func (s *Server) recordMetrics(entry *RequestLog) {
+	if s.metrics == nil || entry == nil {
+		return
+	}
+	s.metrics.ObserveResponse(entry.StatusCode, int64(len(entry.RequestBody)+len(entry.ResponseBody)))
+}
+
 func (s *Server) Start() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -287,6 +617,30 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	if s.socks != nil {
+		socksListener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", bindAddr, s.config.GetSocksPort()))
+		if err != nil {
+			_ = s.listener.Close()
+			s.wg.Wait()
+			s.running = false
+			return fmt.Errorf("failed to listen for SOCKS5 on port %d: %w", s.config.GetSocksPort(), err)
+		}
+		s.socksListener = socksListener
+		s.socks.Serve(socksListener)
+	}
+
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Start(); err != nil {
+			_ = s.listener.Close()
+			if s.socksListener != nil {
+				_ = s.socksListener.Close()
+			}
+			s.wg.Wait()
+			s.running = false
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -315,6 +669,12 @@ func (s *Server) Stop() error {
 	if s.listener != nil {
 		_ = s.listener.Close()
 	}
+	if s.socks != nil {
+		_ = s.socks.Close()
+	}
+	if s.metricsServer != nil {
+		_ = s.metricsServer.Stop()
+	}
 
 	s.wg.Wait()
 
@@ -333,6 +693,9 @@ func (s *Server) Stop() error {
 	if s.proxyLogger != nil {
 		_ = s.proxyLogger.Close()
 	}
+	if s.streamServer != nil {
+		_ = s.streamServer.Close()
+	}
 
 	return nil
 }
@@ -376,6 +739,20 @@ func (s *Server) handleAskMode(req *http.Request, entry *RequestLog, reqBody []b
 		askReq.Body = preview
 	}
 
+	// Prefer a desktop notification when configured and a notification
+	// daemon is reachable; otherwise fall back to the TTY monitor below.
+	if s.notifier != nil && s.notifier.Available() {
+		action, err := s.notifier.Ask(context.Background(), askReq)
+		if err == nil {
+			s.proxy.Logger.Printf("ASK DECISION (notify): %s %s -> %s", req.Method, req.URL.String(), action)
+			if entry != nil && action != FilterActionAllow {
+				entry.FilterReason = fmt.Sprintf("denied via desktop notification: %s", action)
+			}
+			return action
+		}
+		s.proxy.Logger.Printf("notify ask failed for %s %s (%s), falling back to TTY monitor", req.Method, req.URL.String(), err)
+	}
+
 	// Request approval from user
 	action, err := s.askQueue.RequestApproval(askReq)
 	if err != nil {
@@ -400,6 +777,8 @@ func (s *Server) handleAskMode(req *http.Request, entry *RequestLog, reqBody []b
 		return FilterActionBlock
 	}
 
+	s.proxy.Logger.Printf("ASK DECISION (tty): %s %s -> %s", req.Method, req.URL.String(), action)
+
 	return action
 }
 
@@ -419,6 +798,14 @@ func (s *Server) Port() int {
 	return s.config.Port
 }
 
+// SocksPort returns the SOCKS5 listener port, or 0 if SOCKS5 is not enabled.
+func (s *Server) SocksPort() int {
+	if s.socks == nil {
+		return 0
+	}
+	return s.config.GetSocksPort()
+}
+
 func (s *Server) Config() *Config {
 	return s.config
 }
@@ -432,3 +819,19 @@ func (s *Server) IsRunning() bool {
 	defer s.mu.Unlock()
 	return s.running
 }
+
+// TogglePauseLogging pauses or resumes request log persistence without
+// stopping the proxy, and returns the new paused state. Intended to be
+// triggered by SIGUSR1 for fine-grained privacy control during a session.
+func (s *Server) TogglePauseLogging() bool {
+	paused := !s.reqLogger.Paused()
+	s.reqLogger.SetPaused(paused)
+	return paused
+}
+
+// SetTraceHook registers a callback invoked synchronously for every request
+// logged by the proxy or SOCKS5 listener, regardless of the log-pause state.
+// Used by --trace-http to drive a live console summary. Pass nil to disable.
+func (s *Server) SetTraceHook(fn func(*RequestLog)) {
+	s.reqLogger.SetTraceHook(fn)
+}
@@ -1,14 +1,23 @@
 package proxy
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -36,6 +45,84 @@ func TestNewServer(t *testing.T) {
 	}
 }
 
+func TestServer_WarnsOnNearExpiryCA(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := NewConfig(tmpDir, 0)
+	writeTestCA(t, cfg, time.Now().Add(24*time.Hour))
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	defer func() { _ = server.proxyLogger.Close() }()
+
+	entries, err := os.ReadDir(cfg.InternalLogDir)
+	if err != nil {
+		t.Fatalf("failed to read internal log dir: %v", err)
+	}
+
+	var found bool
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(cfg.InternalLogDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read log file %q: %v", entry.Name(), err)
+		}
+		if strings.Contains(string(data), "proxy CA certificate expires") {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected a near-expiry CA warning in the internal proxy log")
+	}
+}
+
+// writeTestCA writes a self-signed CA certificate/key pair valid until
+// notAfter to cfg's CA paths, mirroring CreateCA but with a caller-chosen
+// expiry so tests can simulate a CA that is about to expire.
+func writeTestCA(t *testing.T, cfg *Config, notAfter time.Time) {
+	t.Helper()
+
+	if err := cfg.EnsureCADir(); err != nil {
+		t.Fatalf("failed to create CA dir: %v", err)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		IsCA:         true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	if err := os.WriteFile(cfg.CACertPath, certPEM, 0o644); err != nil {
+		t.Fatalf("failed to write CA cert: %v", err)
+	}
+	if err := os.WriteFile(cfg.CAKeyPath, keyPEM, 0o600); err != nil {
+		t.Fatalf("failed to write CA key: %v", err)
+	}
+}
+
 func TestServerStartStop(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
 	if err != nil {
@@ -198,6 +285,202 @@ func TestServerHTTPSProxy(t *testing.T) {
 	}
 }
 
+func TestServer_NoMITMHosts_TunnelsWithoutInterception(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, "hello from pinned server")
+	}))
+	defer testServer.Close()
+
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	testURL, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	cfg := NewConfig(tmpDir, 18083)
+	cfg.NoMITMHosts = []string{testURL.Hostname()}
+
+	proxyServer, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := proxyServer.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = proxyServer.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+
+	proxyURL, _ := url.Parse(fmt.Sprintf("http://%s", proxyServer.Addr()))
+
+	// Restrictive pool: trusts only the pinned server's own certificate,
+	// not our proxy CA. If this host were intercepted, the handshake
+	// against our generated leaf cert would fail here.
+	certPool := x509.NewCertPool()
+	certPool.AddCert(testServer.Certificate())
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{RootCAs: certPool},
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	resp, err := client.Get(testServer.URL)
+	if err != nil {
+		t.Fatalf("request through no_mitm_hosts tunnel failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello from pinned server" {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestServer_NoMITMHosts_StillEnforcesHostFilter(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, "hello from pinned server")
+	}))
+	defer testServer.Close()
+
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	testURL, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+	hostname := testURL.Hostname()
+
+	cfg := NewConfig(tmpDir, 18089)
+	cfg.NoMITMHosts = []string{hostname}
+	cfg.Filter = &FilterConfig{
+		DefaultAction: FilterActionAllow,
+		Rules: []FilterRule{
+			{Pattern: hostname, Action: FilterActionBlock, Scope: FilterScopeHost},
+		},
+	}
+
+	proxyServer, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := proxyServer.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = proxyServer.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+
+	proxyURL, _ := url.Parse(fmt.Sprintf("http://%s", proxyServer.Addr()))
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(testServer.Certificate())
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{RootCAs: certPool},
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	_, err = client.Get(testServer.URL)
+	if err == nil {
+		t.Fatal("expected request to a blocked no_mitm_hosts host to fail, it succeeded")
+	}
+}
+
+func TestServer_MITMHandshakeFailure_RecordsEvent(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := NewConfig(tmpDir, 18084)
+	eventLogger, err := NewEventLogger(cfg.InternalLogDir)
+	if err != nil {
+		t.Fatalf("NewEventLogger failed: %v", err)
+	}
+	cfg.EventLogger = eventLogger
+
+	proxyServer, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := proxyServer.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = proxyServer.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+
+	proxyURL, _ := url.Parse(fmt.Sprintf("http://%s", proxyServer.Addr()))
+
+	// Empty pool trusts nothing, including our generated leaf cert -
+	// simulates a client that pins its own certificate and rejects ours.
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{RootCAs: x509.NewCertPool()},
+		},
+		Timeout: 2 * time.Second,
+	}
+
+	if _, err := client.Get(testServer.URL); err == nil {
+		t.Fatal("expected handshake against untrusted leaf cert to fail")
+	}
+
+	// The server-side handshake failure is logged by goproxy from its own
+	// connection-handling goroutine, which may still be unwinding when the
+	// client's Get call returns its own (client-side) error above.
+	var events []Event
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		var err error
+		events, err = ReadEvents(cfg.InternalLogDir, "mitm", time.Time{})
+		if err != nil {
+			t.Fatalf("ReadEvents failed: %v", err)
+		}
+		if len(events) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := eventLogger.Close(); err != nil {
+		t.Fatalf("failed to close event logger: %v", err)
+	}
+
+	if len(events) == 0 {
+		t.Fatal("expected a mitm event recording the failed handshake")
+	}
+	if !strings.Contains(events[0].Message, "no_mitm_hosts") {
+		t.Errorf("expected event message to mention no_mitm_hosts, got: %s", events[0].Message)
+	}
+}
+
 func TestServerDynamicPortSelection(t *testing.T) {
 	// Create two servers requesting the same port
 	// The second should automatically get a different port
@@ -257,3 +540,157 @@ func TestServerDynamicPortSelection(t *testing.T) {
 
 	t.Logf("Server 1 port: %d, Server 2 port: %d", server1.Port(), server2.Port())
 }
+
+func TestServerUpstreamProxy_HTTPTraversal(t *testing.T) {
+	// Final destination the request should reach, once relayed by the stub
+	// upstream proxy.
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, "hello from destination")
+	}))
+	defer destServer.Close()
+
+	var visited atomic.Bool
+	upstreamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visited.Store(true)
+
+		outReq := r.Clone(r.Context())
+		outReq.RequestURI = ""
+		resp, err := http.DefaultTransport.RoundTrip(outReq)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		for k, vals := range resp.Header {
+			for _, v := range vals {
+				w.Header().Add(k, v)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer upstreamServer.Close()
+
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cfg := NewConfig(tmpDir, 18082)
+	cfg.UpstreamProxy = upstreamServer.URL
+
+	proxyServer, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	if err := proxyServer.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = proxyServer.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+
+	proxyURL, _ := url.Parse(fmt.Sprintf("http://%s", proxyServer.Addr()))
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		Timeout:   5 * time.Second,
+	}
+
+	resp, err := client.Get(destServer.URL)
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello from destination" {
+		t.Errorf("unexpected body: %s", body)
+	}
+
+	if !visited.Load() {
+		t.Error("request did not traverse the stub upstream proxy")
+	}
+}
+
+func TestServerUpstreamProxy_BasicAuthForwarded(t *testing.T) {
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer destServer.Close()
+
+	var gotAuth atomic.Value // string
+	upstreamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth.Store(r.Header.Get("Proxy-Authorization"))
+
+		outReq := r.Clone(r.Context())
+		outReq.RequestURI = ""
+		resp, err := http.DefaultTransport.RoundTrip(outReq)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		w.WriteHeader(resp.StatusCode)
+	}))
+	defer upstreamServer.Close()
+
+	tmpDir, err := os.MkdirTemp("", "proxy-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	upstreamURL, _ := url.Parse(upstreamServer.URL)
+	upstreamURL.User = url.UserPassword("corpuser", "corppass")
+
+	cfg := NewConfig(tmpDir, 18083)
+	cfg.UpstreamProxy = upstreamURL.String()
+
+	proxyServer, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	if err := proxyServer.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = proxyServer.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+
+	proxyURL, _ := url.Parse(fmt.Sprintf("http://%s", proxyServer.Addr()))
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		Timeout:   5 * time.Second,
+	}
+
+	resp, err := client.Get(destServer.URL)
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("corpuser:corppass"))
+	if got, _ := gotAuth.Load().(string); got != wantAuth {
+		t.Errorf("Proxy-Authorization = %q, want %q", got, wantAuth)
+	}
+}
+
+func TestConfig_GetUpstreamProxy(t *testing.T) {
+	cfg := &Config{UpstreamProxy: "http://corp-proxy:8080"}
+	if got := cfg.GetUpstreamProxy(); got != "http://corp-proxy:8080" {
+		t.Errorf("GetUpstreamProxy() = %q, want configured value", got)
+	}
+
+	cfg = &Config{}
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:9090")
+	if got := cfg.GetUpstreamProxy(); got != "http://env-proxy:9090" {
+		t.Errorf("GetUpstreamProxy() = %q, want env fallback", got)
+	}
+}
@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SOCKS5 protocol constants (RFC 1928). We only implement what's needed to
+// tunnel CONNECT requests: no-auth handshake and the CONNECT command with
+// IPv4, IPv6, and domain name address types.
+const (
+	socks5Version = 0x05
+
+	socks5MethodNoAuth       = 0x00
+	socks5MethodNoAcceptable = 0xFF
+
+	socks5CmdConnect = 0x01
+
+	socks5AtypIPv4   = 0x01
+	socks5AtypDomain = 0x03
+	socks5AtypIPv6   = 0x04
+
+	socks5ReplySucceeded     = 0x00
+	socks5ReplyGeneralFail   = 0x01
+	socks5ReplyNotAllowed    = 0x02
+	socks5ReplyHostUnreach   = 0x04
+	socks5ReplyConnRefused   = 0x05
+	socks5ReplyCmdNotSupport = 0x07
+)
+
+// SocksServer is a minimal SOCKS5 listener that tunnels CONNECT requests
+// through the same RequestLogger and FilterEngine host rules as the HTTP
+// proxy, so tools that only speak SOCKS (via ALL_PROXY) are still logged
+// and filtered.
+type SocksServer struct {
+	reqLogger    *RequestLogger
+	filterEngine *FilterEngine
+	listener     net.Listener
+	wg           sync.WaitGroup
+}
+
+// NewSocksServer creates a SOCKS5 server that reuses the given request
+// logger and filter engine. filterEngine may be nil, in which case all
+// connections are allowed.
+func NewSocksServer(reqLogger *RequestLogger, filterEngine *FilterEngine) *SocksServer {
+	return &SocksServer{
+		reqLogger:    reqLogger,
+		filterEngine: filterEngine,
+	}
+}
+
+// Serve starts accepting connections on listener. It returns immediately;
+// connections are handled in background goroutines until Close is called.
+func (s *SocksServer) Serve(listener net.Listener) {
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.handleConn(conn)
+			}()
+		}
+	}()
+}
+
+// Close stops the listener and waits for in-flight connections to finish.
+func (s *SocksServer) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *SocksServer) handleConn(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	if err := socksHandshake(conn); err != nil {
+		return
+	}
+
+	target, err := socksReadConnectRequest(conn)
+	if err != nil {
+		return
+	}
+
+	entry := &RequestLog{
+		Timestamp: time.Now(),
+		Method:    "CONNECT",
+		URL:       "socks5://" + target,
+	}
+
+	decision := s.filterDecision(target)
+	entry.FilterAction = string(decision.Action)
+	entry.FilterReason = decision.Reason
+
+	if decision.Action == FilterActionBlock {
+		_ = socksWriteReply(conn, socks5ReplyNotAllowed)
+		entry.Error = "blocked by filter: " + decision.Reason
+		entry.Duration = time.Since(entry.Timestamp)
+		s.log(entry)
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", target, 10*time.Second)
+	if err != nil {
+		_ = socksWriteReply(conn, socksDialErrorReply(err))
+		entry.Error = err.Error()
+		entry.Duration = time.Since(entry.Timestamp)
+		s.log(entry)
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+
+	if err := socksWriteReply(conn, socks5ReplySucceeded); err != nil {
+		entry.Error = err.Error()
+		entry.Duration = time.Since(entry.Timestamp)
+		s.log(entry)
+		return
+	}
+
+	socksTunnel(conn, upstream)
+
+	entry.Duration = time.Since(entry.Timestamp)
+	s.log(entry)
+}
+
+func (s *SocksServer) log(entry *RequestLog) {
+	if s.reqLogger == nil {
+		return
+	}
+	_ = s.reqLogger.Log(entry)
+}
+
+// filterDecision evaluates the target host against the filter engine's
+// host-scoped rules (see hostFilterDecision), since a SOCKS CONNECT tunnel
+// carries no request line.
+func (s *SocksServer) filterDecision(target string) FilterDecision {
+	return hostFilterDecision(s.filterEngine, target)
+}
+
+// socksDialErrorReply maps a dial error to the closest SOCKS5 reply code.
+func socksDialErrorReply(err error) byte {
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return socks5ReplyHostUnreach
+	}
+	return socks5ReplyConnRefused
+}
+
+// socksHandshake performs the SOCKS5 no-auth method negotiation.
+func socksHandshake(conn net.Conn) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != socks5Version {
+		return fmt.Errorf("unsupported SOCKS version: %d", header[0])
+	}
+
+	nMethods := int(header[1])
+	methods := make([]byte, nMethods)
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return err
+	}
+
+	for _, m := range methods {
+		if m == socks5MethodNoAuth {
+			_, err := conn.Write([]byte{socks5Version, socks5MethodNoAuth})
+			return err
+		}
+	}
+
+	_, _ = conn.Write([]byte{socks5Version, socks5MethodNoAcceptable})
+	return fmt.Errorf("client does not support no-auth")
+}
+
+// socksReadConnectRequest reads a SOCKS5 request and returns "host:port" for
+// CONNECT commands. Other commands (BIND, UDP ASSOCIATE) are rejected.
+func socksReadConnectRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if header[0] != socks5Version {
+		return "", fmt.Errorf("unsupported SOCKS version: %d", header[0])
+	}
+	if header[1] != socks5CmdConnect {
+		_ = socksWriteReply(conn, socks5ReplyCmdNotSupport)
+		return "", fmt.Errorf("unsupported SOCKS command: %d", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case socks5AtypIPv4:
+		addr := make([]byte, 4)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+
+	case socks5AtypDomain:
+		lenBuf := make([]byte, 1)
+		if _, err := io.ReadFull(conn, lenBuf); err != nil {
+			return "", err
+		}
+		domain := make([]byte, lenBuf[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+
+	case socks5AtypIPv6:
+		addr := make([]byte, 16)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+
+	default:
+		_ = socksWriteReply(conn, socks5ReplyCmdNotSupport)
+		return "", fmt.Errorf("unsupported SOCKS address type: %d", header[3])
+	}
+
+	portBuf := make([]byte, 2)
+	if _, err := io.ReadFull(conn, portBuf); err != nil {
+		return "", err
+	}
+	port := binary.BigEndian.Uint16(portBuf)
+
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+// socksWriteReply writes a SOCKS5 reply with a zeroed BND.ADDR/BND.PORT,
+// which is acceptable for CONNECT replies where clients don't rely on them.
+func socksWriteReply(conn net.Conn, replyCode byte) error {
+	reply := []byte{socks5Version, replyCode, 0x00, socks5AtypIPv4, 0, 0, 0, 0, 0, 0}
+	_, err := conn.Write(reply)
+	return err
+}
+
+// socksTunnel pipes data in both directions until either side closes,
+// blocking until both directions have finished.
+func socksTunnel(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		if c, ok := a.(interface{ CloseWrite() error }); ok {
+			_ = c.CloseWrite()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		if c, ok := b.(interface{ CloseWrite() error }); ok {
+			_ = c.CloseWrite()
+		}
+	}()
+
+	wg.Wait()
+}
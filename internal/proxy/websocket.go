@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elazarl/goproxy"
+)
+
+// isWebSocketUpgrade reports whether header (a request's or a response's)
+// carries the "Connection: Upgrade" / "Upgrade: websocket" pair that marks
+// a WebSocket handshake. Mirrors the vendored goproxy library's unexported
+// check of the same name, since goproxy already hijacks the connection and
+// tunnels raw bytes once it sees this on the response - we only need to
+// recognize it ourselves to keep our own hooks from reading the body.
+func isWebSocketUpgrade(header http.Header) bool {
+	return headerContains(header, "Connection", "Upgrade") && headerContains(header, "Upgrade", "websocket")
+}
+
+func headerContains(header http.Header, name, value string) bool {
+	for _, v := range header[name] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(value, strings.TrimSpace(s)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleWebSocketUpgrade runs from the response hook when resp is a
+// successful "101 Switching Protocols" WebSocket handshake. As documented
+// on http.Response.Body since Go 1.12, such a Body also implements
+// io.Writer, and goproxy's proxyWebsocket (see the vendored
+// elazarl/goproxy) tunnels raw frames through it in both directions after
+// we return - there is no further response to read or rewrite. Reading it
+// the normal way (responseRewriter, RequestLogger.LogResponse) would block
+// on an open-ended stream instead of a bounded body, which is what used to
+// make WebSocket connections hang or get mangled.
+//
+// Host-level filter rules already ran against the handshake request in the
+// request hook, same as any other request. What's special here is just:
+// skip body logging for the tunneled frames, and log a single structured
+// entry once the session ends with byte counts instead of a body.
+func (s *Server) handleWebSocketUpgrade(resp *http.Response, ctx *goproxy.ProxyCtx, entry *RequestLog) *http.Response {
+	method, url := "", ""
+	if ctx.Req != nil {
+		method, url = ctx.Req.Method, ctx.Req.URL.String()
+	}
+	s.proxy.Logger.Printf("WS session opened: %s %s", method, url)
+
+	rw, ok := resp.Body.(io.ReadWriter)
+	if !ok {
+		// Not hijackable (e.g. a test double) - goproxy will warn and close
+		// the connection on its own; nothing more for us to do.
+		return resp
+	}
+
+	startTime := time.Now()
+	if entry != nil {
+		startTime = entry.Timestamp
+	}
+
+	resp.Body = &wsTunnelConn{
+		rw:     rw,
+		closer: resp.Body,
+		onClose: func(bytesIn, bytesOut int64) {
+			s.proxy.Logger.Printf("WS session closed: %s %s (%d bytes in, %d bytes out)", method, url, bytesIn, bytesOut)
+			if entry == nil {
+				return
+			}
+			entry.StatusCode = resp.StatusCode
+			entry.Duration = time.Since(startTime)
+			entry.FilterAction = "websocket"
+			entry.FilterReason = fmt.Sprintf("websocket session closed (%d bytes in, %d bytes out)", bytesIn, bytesOut)
+			_ = s.reqLogger.Log(entry)
+			if s.metrics != nil {
+				s.metrics.ObserveResponse(entry.StatusCode, bytesIn+bytesOut)
+			}
+		},
+	}
+	return resp
+}
+
+// wsTunnelConn wraps a hijacked WebSocket response body so the byte counts
+// flowing through goproxy's proxyWebsocket (plain io.Copy in each
+// direction) can be reported once the tunnel ends, without buffering or
+// otherwise touching the frames themselves. onClose fires at most once,
+// from whichever happens first: an explicit Close() (the MITM'd HTTPS
+// path defers this once filterResponse returns) or a Read/Write error
+// (the plain HTTP path tears down the tunnel without ever calling Close
+// on this body).
+type wsTunnelConn struct {
+	rw       io.ReadWriter
+	closer   io.Closer
+	bytesIn  int64
+	bytesOut int64
+	once     sync.Once
+	onClose  func(bytesIn, bytesOut int64)
+}
+
+func (c *wsTunnelConn) Read(p []byte) (int, error) {
+	n, err := c.rw.Read(p)
+	atomic.AddInt64(&c.bytesIn, int64(n))
+	if err != nil {
+		c.finish()
+	}
+	return n, err
+}
+
+func (c *wsTunnelConn) Write(p []byte) (int, error) {
+	n, err := c.rw.Write(p)
+	atomic.AddInt64(&c.bytesOut, int64(n))
+	if err != nil {
+		c.finish()
+	}
+	return n, err
+}
+
+func (c *wsTunnelConn) Close() error {
+	c.finish()
+	if c.closer != nil {
+		return c.closer.Close()
+	}
+	return nil
+}
+
+func (c *wsTunnelConn) finish() {
+	c.once.Do(func() {
+		if c.onClose != nil {
+			c.onClose(atomic.LoadInt64(&c.bytesIn), atomic.LoadInt64(&c.bytesOut))
+		}
+	})
+}
@@ -3,6 +3,7 @@ package sandbox
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"slices"
@@ -46,19 +47,66 @@ type mountInfo struct {
 	caller   string // function that added this mount for error messages
 }
 
+// OperationKind categorizes a recorded Operation so callers can filter by
+// category (binds, env, namespaces) without matching on the bwrap flag
+// string itself.
+type OperationKind string
+
+const (
+	OpNamespace  OperationKind = "namespace"  // --unshare-*, --die-with-parent, --share-net
+	OpBind       OperationKind = "bind"       // --bind, --ro-bind, --overlay, --tmp-overlay, --ro-overlay, --symlink
+	OpEnv        OperationKind = "env"        // --clearenv, --setenv
+	OpFilesystem OperationKind = "filesystem" // --proc, --dev, --tmpfs, --dir, --chdir
+)
+
+// Operation is a structured record of one bwrap-argument-producing call,
+// recorded by the Builder's primitive methods (ROBind, SetEnv, UnshareUser,
+// and friends) alongside the flat args this still appends to. Operations
+// exposes these as typed values so tests and inspection features (e.g. a
+// --trace-binds flag) can match on Kind/Flag/Dest instead of scanning
+// Build()'s flattened []string for flag names.
+type Operation struct {
+	Kind OperationKind
+	Flag string // the bwrap flag this operation produced, e.g. "--ro-bind"
+
+	// Source and Dest are populated for OpBind; ReadOnly distinguishes
+	// --ro-bind/--ro-overlay from --bind/--overlay/--tmp-overlay. Symlink
+	// operations use Source for the link target and Dest for the link path.
+	Source   string
+	Dest     string
+	ReadOnly bool
+
+	// EnvName and EnvValue are populated for a --setenv operation; a
+	// --clearenv operation leaves both empty.
+	EnvName  string
+	EnvValue string
+
+	// Caller is the exported Builder method that recorded this operation
+	// (e.g. "AddSystemBindings"), the same provenance trackMount already
+	// captures for mount-conflict error messages.
+	Caller string
+}
+
 type Builder struct {
 	cfg            *Config
 	args           []string
 	overlaySrcSeen bool // tracks if OverlaySrc was called before overlay mount
 	mounts         []mountInfo
+	operations     []Operation
 	err            error // captures errors from build steps (e.g., critical tool setup failures)
+
+	// seccompFile holds the compiled BPF program's read end, set by
+	// Seccomp. Callers must pass it through as the sandboxed process's
+	// sole extra file descriptor (see Seccomp's doc comment).
+	seccompFile *os.File
 }
 
 func NewBuilder(cfg *Config) *Builder {
 	return &Builder{
-		cfg:    cfg,
-		args:   make([]string, 0, initialArgsCapacity),
-		mounts: make([]mountInfo, 0, initialMountsCapacity),
+		cfg:        cfg,
+		args:       make([]string, 0, initialArgsCapacity),
+		mounts:     make([]mountInfo, 0, initialMountsCapacity),
+		operations: make([]Operation, 0, initialMountsCapacity),
 	}
 }
 
@@ -69,6 +117,19 @@ func (b *Builder) logWarnf(format string, args ...any) {
 	}
 }
 
+// logToolInfof/logToolErrorf report a tool setup result via ToolLogger, if any.
+func (b *Builder) logToolInfof(format string, args ...any) {
+	if b.cfg.ToolLogger != nil {
+		b.cfg.ToolLogger.Infof(format, args...)
+	}
+}
+
+func (b *Builder) logToolErrorf(format string, args ...any) {
+	if b.cfg.ToolLogger != nil {
+		b.cfg.ToolLogger.Errorf(format, args...)
+	}
+}
+
 // trackMount records a mount and checks for conflicts.
 // Panics if:
 // - The exact destination was already mounted (ambiguous)
@@ -140,90 +201,165 @@ func (b *Builder) Build() []string {
 	return b.args
 }
 
+// Operations returns the structured operations recorded so far, in the
+// order they were added - the same data Build() flattens into bwrap's
+// argv, before flattening. See Operation's doc comment for what's recorded
+// and why.
+func (b *Builder) Operations() []Operation {
+	return b.operations
+}
+
+func (b *Builder) recordOp(op Operation) {
+	b.operations = append(b.operations, op)
+}
+
+// Env reconstructs the final sandbox environment from the recorded bwrap
+// arguments: a --clearenv resets the map, and each --setenv NAME VALUE sets
+// a variable, in the order the Add*/SetEnv methods were called. It's used
+// by --print-env-diff to show how the sandbox environment differs from the
+// host without actually running the sandbox.
+func (b *Builder) Env() map[string]string {
+	env := make(map[string]string)
+	for i := 0; i < len(b.args); i++ {
+		switch b.args[i] {
+		case "--clearenv":
+			env = make(map[string]string)
+		case "--setenv":
+			if i+2 < len(b.args) {
+				env[b.args[i+1]] = b.args[i+2]
+				i += 2
+			}
+		}
+	}
+	return env
+}
+
 // Err returns any error that occurred during building.
 // This should be checked after all Add* methods are called.
 func (b *Builder) Err() error {
 	return b.err
 }
 
+// SeccompFile returns the read end of the compiled seccomp BPF program, or
+// nil if Seccomp was never called or was called with SeccompProfileNone.
+// Callers must pass this as the sandboxed process's sole extra file
+// descriptor and close it afterwards.
+func (b *Builder) SeccompFile() *os.File {
+	return b.seccompFile
+}
+
 func (b *Builder) add(args ...string) {
 	b.args = append(b.args, args...)
 }
 
 func (b *Builder) ClearEnv() *Builder {
+	b.recordOp(Operation{Kind: OpEnv, Flag: "--clearenv", Caller: getCaller(2)})
 	b.add("--clearenv")
 	return b
 }
 
 func (b *Builder) UnshareUser() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--unshare-user", Caller: getCaller(2)})
 	b.add("--unshare-user")
 	return b
 }
 
 func (b *Builder) UnsharePID() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--unshare-pid", Caller: getCaller(2)})
 	b.add("--unshare-pid")
 	return b
 }
 
 func (b *Builder) UnshareIPC() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--unshare-ipc", Caller: getCaller(2)})
 	b.add("--unshare-ipc")
 	return b
 }
 
 func (b *Builder) UnshareUTS() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--unshare-uts", Caller: getCaller(2)})
 	b.add("--unshare-uts")
 	return b
 }
 
 func (b *Builder) DieWithParent() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--die-with-parent", Caller: getCaller(2)})
 	b.add("--die-with-parent")
 	return b
 }
 
 func (b *Builder) Proc(dest string) *Builder {
+	b.recordOp(Operation{Kind: OpFilesystem, Flag: "--proc", Dest: dest, Caller: getCaller(2)})
 	b.add("--proc", dest)
 	return b
 }
 
 func (b *Builder) Dev(dest string) *Builder {
+	b.recordOp(Operation{Kind: OpFilesystem, Flag: "--dev", Dest: dest, Caller: getCaller(2)})
 	b.add("--dev", dest)
 	return b
 }
 
 func (b *Builder) Tmpfs(dest string) *Builder {
+	b.recordOp(Operation{Kind: OpFilesystem, Flag: "--tmpfs", Dest: dest, Caller: getCaller(2)})
 	b.add("--tmpfs", dest)
 	return b
 }
 
+// addTmp mounts /tmp according to cfg.TmpMode: a fresh tmpfs (default,
+// discarded on exit) or a persistent on-disk directory under SandboxRoot
+// (survives between --run steps and avoids OOM on large build outputs).
+func (b *Builder) addTmp() *Builder {
+	if b.cfg.TmpMode == TmpModeDisk {
+		if err := os.MkdirAll(b.cfg.TmpDir, 0o755); err != nil {
+			if b.err == nil {
+				b.err = fmt.Errorf("failed to create tmp dir %s: %w", b.cfg.TmpDir, err)
+			}
+			return b
+		}
+		return b.Bind(b.cfg.TmpDir, "/tmp")
+	}
+	return b.Tmpfs("/tmp")
+}
+
 func (b *Builder) ROBind(src, dest string) *Builder {
-	b.trackMount(dest, src, true, getCaller(2))
+	caller := getCaller(2)
+	b.trackMount(dest, src, true, caller)
+	b.recordOp(Operation{Kind: OpBind, Flag: "--ro-bind", Source: src, Dest: dest, ReadOnly: true, Caller: caller})
 	b.add("--ro-bind", src, dest)
 	return b
 }
 
 func (b *Builder) ROBindIfExists(src, dest string) *Builder {
 	if pathExists(src) {
-		b.trackMount(dest, src, true, getCaller(2))
+		caller := getCaller(2)
+		b.trackMount(dest, src, true, caller)
+		b.recordOp(Operation{Kind: OpBind, Flag: "--ro-bind", Source: src, Dest: dest, ReadOnly: true, Caller: caller})
 		b.add("--ro-bind", src, dest)
 	}
 	return b
 }
 
 func (b *Builder) Bind(src, dest string) *Builder {
-	b.trackMount(dest, src, false, getCaller(2))
+	caller := getCaller(2)
+	b.trackMount(dest, src, false, caller)
+	b.recordOp(Operation{Kind: OpBind, Flag: "--bind", Source: src, Dest: dest, Caller: caller})
 	b.add("--bind", src, dest)
 	return b
 }
 
 func (b *Builder) BindIfExists(src, dest string) *Builder {
 	if pathExists(src) {
-		b.trackMount(dest, src, false, getCaller(2))
+		caller := getCaller(2)
+		b.trackMount(dest, src, false, caller)
+		b.recordOp(Operation{Kind: OpBind, Flag: "--bind", Source: src, Dest: dest, Caller: caller})
 		b.add("--bind", src, dest)
 	}
 	return b
 }
 
 func (b *Builder) Symlink(target, linkPath string) *Builder {
+	b.recordOp(Operation{Kind: OpBind, Flag: "--symlink", Source: target, Dest: linkPath, Caller: getCaller(2)})
 	b.add("--symlink", target, linkPath)
 	return b
 }
@@ -257,7 +393,9 @@ func (b *Builder) requireOverlaySrc(method string) {
 // Panics if OverlaySrc was not called first.
 func (b *Builder) TmpOverlay(dest string) *Builder {
 	b.requireOverlaySrc("TmpOverlay")
-	b.trackMount(dest, "overlay:tmpfs", false, getCaller(2))
+	caller := getCaller(2)
+	b.trackMount(dest, "overlay:tmpfs", false, caller)
+	b.recordOp(Operation{Kind: OpBind, Flag: "--tmp-overlay", Dest: dest, Caller: caller})
 	b.add("--tmp-overlay", dest)
 	b.overlaySrcSeen = false // reset for next overlay
 	return b
@@ -270,7 +408,9 @@ func (b *Builder) TmpOverlay(dest string) *Builder {
 // Panics if OverlaySrc was not called first.
 func (b *Builder) Overlay(rwSrc, workDir, dest string) *Builder {
 	b.requireOverlaySrc("Overlay")
-	b.trackMount(dest, "overlay:"+rwSrc, false, getCaller(2))
+	caller := getCaller(2)
+	b.trackMount(dest, "overlay:"+rwSrc, false, caller)
+	b.recordOp(Operation{Kind: OpBind, Flag: "--overlay", Source: rwSrc, Dest: dest, Caller: caller})
 	b.add("--overlay", rwSrc, workDir, dest)
 	b.overlaySrcSeen = false // reset for next overlay
 	return b
@@ -280,28 +420,43 @@ func (b *Builder) Overlay(rwSrc, workDir, dest string) *Builder {
 // Panics if OverlaySrc was not called first.
 func (b *Builder) ROOverlay(dest string) *Builder {
 	b.requireOverlaySrc("ROOverlay")
-	b.trackMount(dest, "overlay:ro", true, getCaller(2))
+	caller := getCaller(2)
+	b.trackMount(dest, "overlay:ro", true, caller)
+	b.recordOp(Operation{Kind: OpBind, Flag: "--ro-overlay", Dest: dest, ReadOnly: true, Caller: caller})
 	b.add("--ro-overlay", dest)
 	b.overlaySrcSeen = false // reset for next overlay
 	return b
 }
 
 func (b *Builder) Dir(path string) *Builder {
+	b.recordOp(Operation{Kind: OpFilesystem, Flag: "--dir", Dest: path, Caller: getCaller(2)})
 	b.add("--dir", path)
 	return b
 }
 
 func (b *Builder) ShareNet() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--share-net", Caller: getCaller(2)})
 	b.add("--share-net")
 	return b
 }
 
+// UnshareNet gives the sandbox its own network namespace with only
+// loopback - no pasta/slirp4netns, no proxy, no route to anywhere else.
+// Used for --no-network.
+func (b *Builder) UnshareNet() *Builder {
+	b.recordOp(Operation{Kind: OpNamespace, Flag: "--unshare-net", Caller: getCaller(2)})
+	b.add("--unshare-net")
+	return b
+}
+
 func (b *Builder) Chdir(path string) *Builder {
+	b.recordOp(Operation{Kind: OpFilesystem, Flag: "--chdir", Dest: path, Caller: getCaller(2)})
 	b.add("--chdir", path)
 	return b
 }
 
 func (b *Builder) SetEnv(name, value string) *Builder {
+	b.recordOp(Operation{Kind: OpEnv, Flag: "--setenv", EnvName: name, EnvValue: value, Caller: getCaller(2)})
 	b.add("--setenv", name, value)
 	return b
 }
@@ -321,8 +476,9 @@ func (b *Builder) AddBaseArgs() *Builder {
 		UnshareUTS().
 		DieWithParent().
 		Proc("/proc").
-		Dev("/dev").
-		Tmpfs("/tmp")
+		Dev("/dev")
+
+	b.addTmp()
 
 	// Map current user inside the sandbox (prevents running as root)
 	uid := os.Getuid()
@@ -384,10 +540,51 @@ func (b *Builder) AddLocaleBindings() *Builder {
 	}
 
 	b.ROBindIfExists("/usr/share/zoneinfo", "/usr/share/zoneinfo")
+	b.ROBindIfExists("/usr/lib/locale", "/usr/lib/locale")
 
 	return b
 }
 
+// resolveLocale returns the locale to set LANG/LC_ALL to inside the sandbox:
+// [env].locale if configured, otherwise whatever the host has in LC_ALL or
+// LANG, otherwise "C.UTF-8" so output stays deterministic when nothing is
+// configured either way.
+func (b *Builder) resolveLocale() string {
+	if b.cfg.Locale != "" {
+		return b.cfg.Locale
+	}
+	if v := os.Getenv("LC_ALL"); v != "" {
+		return v
+	}
+	if v := os.Getenv("LANG"); v != "" {
+		return v
+	}
+	return "C.UTF-8"
+}
+
+// resolveSourceDateEpoch returns the value to set SOURCE_DATE_EPOCH to
+// inside the sandbox, and whether it resolved to anything at all. "git"
+// derives the value from HEAD's committer date in ProjectDir; any other
+// non-empty value is used as-is (already validated as a Unix timestamp by
+// config.Config.Validate). Empty means the feature is disabled.
+func (b *Builder) resolveSourceDateEpoch() (string, bool) {
+	if b.cfg.SourceDateEpoch == "" {
+		return "", false
+	}
+	if b.cfg.SourceDateEpoch != "git" {
+		return b.cfg.SourceDateEpoch, true
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	cmd.Dir = b.cfg.ProjectDir
+	out, err := cmd.Output()
+	if err != nil {
+		b.logWarnf("sandbox: failed to derive source_date_epoch from git: %v", err)
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
 func (b *Builder) AddCABindings() *Builder {
 	caPaths := []string{
 		"/etc/ca-certificates",
@@ -402,16 +599,39 @@ func (b *Builder) AddCABindings() *Builder {
 	return b
 }
 
+// AddHostCABindings binds the host's effective CA configuration
+// (SSL_CERT_FILE / SSL_CERT_DIR) into the sandbox read-only and sets the
+// matching env vars inside it. AddCABindings already covers the common
+// distro paths unconditionally; this is only for hosts that point OpenSSL
+// somewhere else (a custom bundle, an internal-CA directory outside
+// /etc/ssl). Only called when the user opts in with --trust-host-cas,
+// since it exposes wherever the host happens to point those vars.
+func (b *Builder) AddHostCABindings() *Builder {
+	if certFile := os.Getenv("SSL_CERT_FILE"); certFile != "" {
+		b.ROBindIfExists(certFile, certFile)
+		b.SetEnv("SSL_CERT_FILE", certFile)
+	}
+	if certDir := os.Getenv("SSL_CERT_DIR"); certDir != "" {
+		b.ROBindIfExists(certDir, certDir)
+		b.SetEnv("SSL_CERT_DIR", certDir)
+	}
+
+	return b
+}
+
 func (b *Builder) AddSandboxHome() *Builder {
 	home := b.cfg.HomeDir
 
-	// Use shared network unless proxy mode is enabled.
+	// Use shared network unless proxy mode or --no-network is enabled.
 	// Proxy mode uses pasta which creates an isolated network namespace
-	// where all traffic goes through the gateway to our proxy.
-	if !b.cfg.ProxyEnabled {
+	// where all traffic goes through the gateway to our proxy. --no-network
+	// unshares the namespace outright, leaving only loopback.
+	switch {
+	case b.cfg.NetworkDisabled:
+		b.UnshareNet()
+	case !b.cfg.ProxyEnabled:
 		b.ShareNet()
 	}
-	b.Bind(b.cfg.SandboxHome, home)
 
 	homeDirs := []string{
 		filepath.Join(home, ".config"),
@@ -421,6 +641,19 @@ func (b *Builder) AddSandboxHome() *Builder {
 		filepath.Join(home, ".local", "bin"),
 	}
 
+	if b.cfg.ReadOnlyHome {
+		b.ROBind(b.cfg.SandboxHome, home)
+		// Tools still need somewhere to write config/cache under $HOME, but
+		// a read-only home means it can't be the usual isolated on-disk
+		// dirs under SandboxHome - give each a tmpfs instead, so writes
+		// succeed for the session but none of it persists.
+		for _, d := range homeDirs {
+			b.Tmpfs(d)
+		}
+		return b
+	}
+
+	b.Bind(b.cfg.SandboxHome, home)
 	for _, d := range homeDirs {
 		b.Dir(d)
 	}
@@ -429,38 +662,266 @@ func (b *Builder) AddSandboxHome() *Builder {
 }
 
 // AddTools applies bindings from all available tools in the registry.
-// Tools are discovered automatically based on what's installed on the host.
+// Tools are discovered automatically based on what's installed on the host,
+// then narrowed by the [tools] enabled/disabled lists, if set.
 func (b *Builder) AddTools() *Builder {
 	home := b.cfg.HomeDir
 	sandboxHome := b.cfg.SandboxHome
+	active := b.activeTools(home)
 
 	// Configure tools that support it
-	for _, tool := range tools.Available(home) {
+	for _, tool := range active {
 		if configurable, ok := tool.(tools.ToolWithConfig); ok {
 			b.configureTool(configurable, tool.Name())
 		}
 	}
 
 	// Run setup for tools that need it (e.g., generate safe gitconfig, starship config)
-	for _, tool := range tools.Available(home) {
+	for _, tool := range active {
 		if setup, ok := tool.(tools.ToolWithSetup); ok {
 			if err := setup.Setup(home, sandboxHome); err != nil {
+				b.logToolErrorf("setup failed for %s: %v", tool.Name(), err)
 				b.err = fmt.Errorf("tool setup failed for %s: %w", tool.Name(), err)
 				return b
 			}
+			b.logToolInfof("setup ok for %s", tool.Name())
 		}
 	}
 
-	// Apply bindings from all available tools
-	for _, tool := range tools.Available(home) {
+	// Apply bindings from all active tools, resolving any dest-path
+	// conflicts (two tools, or a tool and an earlier config mount,
+	// wanting the same destination) before anything is actually mounted.
+	resolved := b.resolveBindingConflicts(b.collectToolBindings(active, home, sandboxHome))
+	for _, rb := range resolved {
+		b.applyBinding(rb.Binding, sandboxHome)
+	}
+	b.cfg.ResolvedToolBindings = resolved
+
+	// Collect shell-init snippets from every active tool, in registry
+	// (alphabetical) order, so the spawned shell has things like mise's
+	// activation or zoxide's database ready before the user's command runs.
+	var toolInit []string
+	for _, tool := range active {
+		if init := tool.ShellInit(string(b.cfg.Shell)); init != "" {
+			toolInit = append(toolInit, init)
+		}
+	}
+	b.cfg.ToolsShellInit = strings.Join(toolInit, "; ")
+
+	b.AddRequiredHelpers()
+
+	return b
+}
+
+// activeTools returns the tools available on the host, filtered down by the
+// [tools] enabled/disabled lists in the config file. With neither list set
+// this is exactly tools.Available(home).
+func (b *Builder) activeTools(home string) []tools.Tool {
+	return tools.Enabled(tools.Available(home), b.cfg.ToolsConfig)
+}
+
+// attributedBinding pairs a binding with the tool that contributed it,
+// before conflict resolution decides which binding in a group actually
+// gets applied.
+type attributedBinding struct {
+	toolName string
+	binding  tools.Binding
+}
+
+// collectToolBindings gathers every binding contributed by active, tagged
+// with the tool that produced it. Tools are iterated in the order given
+// (alphabetical, from tools.Available/Enabled), so ties in
+// resolveBindingConflicts resolve to the first tool alphabetically.
+func (b *Builder) collectToolBindings(active []tools.Tool, home, sandboxHome string) []attributedBinding {
+	var bindings []attributedBinding
+	for _, tool := range active {
 		for _, binding := range tool.Bindings(home, sandboxHome) {
-			b.applyBinding(binding, sandboxHome)
+			bindings = append(bindings, attributedBinding{toolName: tool.Name(), binding: binding})
+		}
+	}
+	return bindings
+}
+
+// bindingDest returns the cleaned destination path a binding occupies,
+// applying the same "defaults to Source" rule as applyBinding.
+func bindingDest(binding tools.Binding) string {
+	dest := binding.Dest
+	if dest == "" {
+		dest = binding.Source
+	}
+	return filepath.Clean(dest)
+}
+
+// toolPrecedence ranks a tool's bindings for conflict resolution; lower
+// wins. Explicit config mounts (custom mounts, project bindings) aren't
+// ranked here at all - AddCustomMounts and AddProjectBindings both run
+// before AddTools, so by the time this runs they already occupy b.mounts
+// and always win (see resolveBindingConflicts). Among tools, git wins
+// ties since its bindings are deliberately safety-filtered (see git.go's
+// Setup) and shouldn't be silently shadowed by a less careful tool.
+func toolPrecedence(toolName string) int {
+	if toolName == "git" {
+		return 0
+	}
+	return 1
+}
+
+// resolveBindingConflicts picks one binding per destination path out of
+// bindings, in precedence order: a destination already claimed by an
+// earlier build step (an explicit config mount or project binding,
+// tracked in b.mounts) always wins over any tool binding; among tools
+// contesting the same destination, toolPrecedence breaks the tie, then
+// first-seen. Every dropped binding is logged as a warning naming both
+// the winner and the loser, so a mount surprise has a paper trail
+// instead of silently shadowing another tool's file.
+func (b *Builder) resolveBindingConflicts(bindings []attributedBinding) []ResolvedBinding {
+	claimed := make(map[string]bool, len(b.mounts))
+	for _, m := range b.mounts {
+		claimed[filepath.Clean(m.dest)] = true
+	}
+
+	winners := make(map[string]attributedBinding)
+	var order []string
+	for _, ab := range bindings {
+		dest := bindingDest(ab.binding)
+
+		if claimed[dest] {
+			b.logWarnf("tool binding conflict: %s's binding for %s dropped - already mounted by an explicit config mount or project binding", ab.toolName, dest)
+			continue
+		}
+
+		existing, ok := winners[dest]
+		if !ok {
+			winners[dest] = ab
+			order = append(order, dest)
+			continue
+		}
+
+		if toolPrecedence(ab.toolName) < toolPrecedence(existing.toolName) {
+			b.logWarnf("tool binding conflict: %s's binding for %s dropped in favor of %s's (higher precedence)", existing.toolName, dest, ab.toolName)
+			winners[dest] = ab
+		} else {
+			b.logWarnf("tool binding conflict: %s's binding for %s dropped in favor of %s's (already claimed)", ab.toolName, dest, existing.toolName)
+		}
+	}
+
+	resolved := make([]ResolvedBinding, 0, len(order))
+	for _, dest := range order {
+		ab := winners[dest]
+		resolved = append(resolved, ResolvedBinding{ToolName: ab.toolName, Binding: ab.binding})
+	}
+	return resolved
+}
+
+// AddRequiredHelpers binds host binaries that configured tools declare as
+// required helpers - subprocess binaries a tool shells out to that may not
+// live under the already-bound system directories (/usr, /bin, /lib, ...),
+// e.g. a gpg/pinentry pair installed under /opt or via Homebrew. Declared
+// per tool as:
+//
+//	[tools.git]
+//	requires = ["gpg", "pinentry"]
+//
+// Each name is resolved on the host via exec.LookPath, then bound read-only
+// together with its dynamic library dependencies (resolved via ldd) so it
+// actually runs once inside the sandbox. A missing helper only logs a
+// warning rather than failing the build - the alternative is exactly the
+// "command not found" deep inside some tool's subprocess this exists to
+// avoid, just surfaced earlier.
+func (b *Builder) AddRequiredHelpers() *Builder {
+	for toolName, rawCfg := range b.cfg.ToolsConfig {
+		toolCfg, ok := rawCfg.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, name := range toStringSlice(toolCfg["requires"]) {
+			b.addRequiredHelper(toolName, name)
 		}
 	}
 
 	return b
 }
 
+// addRequiredHelper resolves name on the host PATH and binds it, plus its
+// shared library dependencies, read-only into the sandbox at the same path.
+func (b *Builder) addRequiredHelper(toolName, name string) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		b.logWarnf("tools.%s: required helper %q not found on PATH; subprocess calls to it will fail inside the sandbox", toolName, name)
+		return
+	}
+
+	b.ROBindIfExists(path, path)
+	for _, lib := range resolveSharedLibs(path) {
+		b.ROBindIfExists(lib, lib)
+	}
+}
+
+// resolveSharedLibs runs ldd against path and returns the host paths of its
+// dynamic library dependencies, skipping the vDSO and statically-linked
+// binaries (both of which ldd reports without a usable path). Best-effort:
+// a failure here just leaves the helper bound without its libs, the same
+// "might not run" outcome as not having this feature at all.
+func resolveSharedLibs(path string) []string {
+	out, err := exec.Command("ldd", path).Output()
+	if err != nil {
+		return nil
+	}
+
+	var libs []string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "statically linked") || strings.HasPrefix(line, "linux-vdso") {
+			continue
+		}
+
+		// Lines look like "libfoo.so.1 => /usr/lib/libfoo.so.1 (0x...)" or,
+		// for the dynamic linker itself, "/lib64/ld-linux-x86-64.so.2 (0x...)".
+		if idx := strings.Index(line, "=>"); idx != -1 {
+			line = strings.TrimSpace(line[idx+2:])
+		}
+		if fields := strings.Fields(line); len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
+			libs = append(libs, fields[0])
+		}
+	}
+	return libs
+}
+
+// toStringSlice converts a TOML-decoded value expected to be a string list
+// (decoded into []any, each element checked for string-ness) into
+// []string, ignoring anything that isn't one.
+func toStringSlice(v any) []string {
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// allowEnvrc reports whether [tools.direnv] allow_envrc = true, read directly
+// from ToolsConfig rather than through the Direnv tool's Configure, since
+// AddProjectBindings runs before AddTools configures it.
+func (b *Builder) allowEnvrc() bool {
+	return AllowEnvrcConfigured(b.cfg.ToolsConfig)
+}
+
+// AllowEnvrcConfigured reports whether toolsConfig (a Config.ToolsConfig map,
+// keyed by tool name) has [tools.direnv] allow_envrc = true.
+func AllowEnvrcConfigured(toolsConfig map[string]any) bool {
+	direnvCfg, ok := toolsConfig["direnv"].(map[string]any)
+	if !ok {
+		return false
+	}
+	allow, _ := direnvCfg["allow_envrc"].(bool)
+	return allow
+}
+
 // configureTool applies configuration to a tool based on sandbox config.
 func (b *Builder) configureTool(tool tools.ToolWithConfig, toolName string) {
 	// Build global config
@@ -574,7 +1035,7 @@ func (b *Builder) AddCustomMounts() *Builder {
 	}
 
 	engine := b.cfg.MountsConfig
-	if len(engine.Rules()) == 0 {
+	if len(engine.Rules()) == 0 && len(engine.Binds()) == 0 {
 		return b
 	}
 
@@ -596,9 +1057,43 @@ func (b *Builder) AddCustomMounts() *Builder {
 		b.applyMountRule(path, rule)
 	}
 
+	b.addCustomBindMounts(engine.Binds())
+
 	return b
 }
 
+// addCustomBindMounts applies extra bind mounts declared via
+// [[sandbox.mounts.binds]]. Config-level validation already rejected empty
+// sources, missing required sources, and a dest of $HOME, but the project
+// mount's path is only known here - reject a dest landing on or inside it too,
+// since either bind would otherwise race/shadow the project binding depending
+// on build order.
+func (b *Builder) addCustomBindMounts(binds []mounts.BindMount) {
+	for _, bind := range binds {
+		if bind.Dest == b.cfg.ProjectDir || b.isInsideProject(bind.Dest) {
+			b.logWarnf("mounts: skipping bind %q -> %q: dest would clobber the project mount", bind.Source, bind.Dest)
+			continue
+		}
+		if bind.Dest == b.cfg.HomeDir {
+			b.logWarnf("mounts: skipping bind %q -> %q: dest would clobber $HOME", bind.Source, bind.Dest)
+			continue
+		}
+
+		binding := tools.Binding{
+			Source:   bind.Source,
+			Dest:     bind.Dest,
+			ReadOnly: bind.ReadOnly,
+			Optional: bind.Optional,
+		}
+		if !binding.Optional && !pathExists(binding.Source) {
+			b.logWarnf("mounts: skipping bind %q -> %q: source not found", bind.Source, bind.Dest)
+			continue
+		}
+
+		b.applyBindMount(binding, bind.Dest)
+	}
+}
+
 // isInsideProject checks if a path is inside the project directory.
 func (b *Builder) isInsideProject(path string) bool {
 	projectDir := filepath.Clean(b.cfg.ProjectDir)
@@ -623,8 +1118,10 @@ func (b *Builder) applyMountRule(path string, rule mounts.Rule) {
 	switch rule.Mode {
 	case mounts.ModeHidden:
 		if info.IsDir() {
-			// Hiding directories is not supported - log and skip
-			b.logWarnf("mounts: cannot hide directory %q - use 'readonly', 'overlay', or 'tmpoverlay' mode instead (pattern: %s)", path, rule.Pattern)
+			// An empty tmpfs makes the directory appear present but empty,
+			// without needing a writable overlay for something meant to be
+			// inaccessible.
+			b.Tmpfs(path)
 			return
 		}
 		// For files within mounted paths, overlay with /dev/null
@@ -644,6 +1141,45 @@ func (b *Builder) applyMountRule(path string, rule mounts.Rule) {
 	}
 }
 
+// AddBlockedPaths masks BlockedPaths (the built-in secret-path defaults plus
+// any [security].blocked_paths) by overlaying /dev/null over matching files
+// and an empty tmpfs over matching directories. Called last among the
+// mount-affecting steps, after tools/custom mounts/project bindings, so
+// nothing mounted earlier can re-expose a path this is meant to hide.
+func (b *Builder) AddBlockedPaths() *Builder {
+	if len(b.cfg.BlockedPaths) == 0 {
+		return b
+	}
+
+	rules := make([]config.MountRule, len(b.cfg.BlockedPaths))
+	for i, pattern := range b.cfg.BlockedPaths {
+		rules[i] = config.MountRule{Pattern: pattern, Mode: "hidden"}
+	}
+	engine := mounts.NewEngine(config.MountsConfig{Rules: rules}, b.cfg.HomeDir)
+
+	expandedPaths := make(map[string]mounts.Rule)
+	for path, rule := range engine.ExpandedPaths() {
+		expandedPaths[path] = rule
+	}
+	if b.cfg.ProjectDir != "" {
+		for path, rule := range engine.ExpandedPathsInDir(b.cfg.ProjectDir) {
+			if _, exists := expandedPaths[path]; !exists {
+				expandedPaths[path] = rule
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(expandedPaths))
+	for path := range expandedPaths {
+		paths = append(paths, path)
+	}
+	for _, path := range sortPaths(paths) {
+		b.applyMountRule(path, expandedPaths[path])
+	}
+
+	return b
+}
+
 // sortPaths sorts paths so that parent directories come before children.
 // This ensures deterministic mount ordering.
 func sortPaths(paths []string) []string {
@@ -712,6 +1248,15 @@ func (b *Builder) AddProjectBindings() *Builder {
 		b.ROBind("/dev/null", path)
 	}
 
+	// .envrc is hidden the same way, unless [tools.direnv] allow_envrc = true
+	// explicitly opts this project back in.
+	if !b.allowEnvrc() {
+		envrcPath := filepath.Join(b.cfg.ProjectDir, ".envrc")
+		if _, err := os.Stat(envrcPath); err == nil {
+			b.ROBind("/dev/null", envrcPath)
+		}
+	}
+
 	b.Tmpfs(b.cfg.XDGRuntime)
 
 	return b
@@ -778,7 +1323,10 @@ func (b *Builder) AddEnvironment() *Builder {
 	b.SetEnv("LOGNAME", os.Getenv("LOGNAME"))
 	b.SetEnv("SHELL", b.cfg.ShellPath)
 	b.SetEnv("TERM", os.Getenv("TERM"))
-	b.SetEnv("LANG", os.Getenv("LANG"))
+
+	locale := b.resolveLocale()
+	b.SetEnv("LANG", locale)
+	b.SetEnv("LC_ALL", locale)
 
 	path := fmt.Sprintf("%s/.local/share/mise/shims:%s/.local/bin:/usr/local/bin:/usr/bin:/bin",
 		home, home)
@@ -803,8 +1351,14 @@ func (b *Builder) AddEnvironment() *Builder {
 	b.SetEnv("DEVSANDBOX", "1")
 	b.SetEnv("DEVSANDBOX_PROJECT", b.cfg.ProjectName)
 
-	// Add environment from all available tools
-	for _, tool := range tools.Available(home) {
+	// Reproducible build timestamps, if configured
+	if epoch, ok := b.resolveSourceDateEpoch(); ok {
+		b.SetEnv("SOURCE_DATE_EPOCH", epoch)
+		b.SetEnv("TZ", "UTC")
+	}
+
+	// Add environment from all active tools
+	for _, tool := range b.activeTools(home) {
 		for _, env := range tool.Environment(home, sandboxHome) {
 			if env.FromHost {
 				b.SetEnvIfSet(env.Name)
@@ -814,11 +1368,103 @@ func (b *Builder) AddEnvironment() *Builder {
 		}
 	}
 
+	// Copy allowlisted host variables, e.g. a custom FOO_API_URL, on top of
+	// the curated set above.
+	b.addEnvPassthrough()
+
+	// Load project-specific variables from the per-sandbox env file last, so
+	// they can override tool defaults (e.g. a project-local UV_CACHE_DIR).
+	// Secret-looking keys are never loaded without an explicit opt-in - see
+	// LoadSandboxEnv.
+	loaded, skipped, err := LoadSandboxEnv(b.cfg.SandboxRoot)
+	if err != nil {
+		b.logWarnf("failed to load sandbox env file: %v", err)
+	}
+	loadedNames := make([]string, 0, len(loaded))
+	for name := range loaded {
+		loadedNames = append(loadedNames, name)
+	}
+	slices.Sort(loadedNames)
+	for _, name := range loadedNames {
+		b.SetEnv(name, loaded[name])
+	}
+	for _, name := range skipped {
+		b.logWarnf("sandbox env file: skipping %q - looks like a secret; use 'devsandbox config env set --allow-secret' to opt in", name)
+	}
+
 	// Add proxy environment if enabled
 	if b.cfg.ProxyEnabled {
 		b.AddProxyEnvironment()
 	}
 
+	// Narrow PATH to an allowlist, if configured. Must run last so it
+	// overrides the PATH set above.
+	b.AddCommandAllowlist()
+
+	return b
+}
+
+// AddCommandAllowlist restricts the sandbox shell's PATH to only
+// cfg.AllowedCommands, for locked-down review sessions that shouldn't be
+// able to run arbitrary binaries. It builds a shim directory in the
+// sandbox home containing one symlink per allowed command, resolved
+// against the same directories AddEnvironment's default PATH searches
+// (mise shims, ~/.local/bin, /usr/local/bin, /usr/bin, /bin), and sets
+// PATH to that directory alone.
+//
+// This is a usability guardrail, not a hard security boundary: a process
+// that invokes a binary by absolute path (or execs an interpreter that
+// shells out internally) bypasses it entirely, since PATH only affects
+// lookups of a bare command name. For an actual enforcement boundary, pair
+// this with sandbox.seccomp_profile = "strict" or a tighter custom seccomp
+// profile. A command not in the allowlist simply isn't found on PATH -
+// there's no custom rejection message, just the shell's normal "command
+// not found".
+//
+// No-op if cfg.AllowedCommands is empty (the default).
+func (b *Builder) AddCommandAllowlist() *Builder {
+	if len(b.cfg.AllowedCommands) == 0 {
+		return b
+	}
+
+	home := b.cfg.HomeDir
+	candidateDirs := []string{
+		filepath.Join(home, ".local", "share", "mise", "shims"),
+		filepath.Join(home, ".local", "bin"),
+		"/usr/local/bin",
+		"/usr/bin",
+		"/bin",
+	}
+
+	shimDir := filepath.Join(b.cfg.SandboxHome, "devsandbox-allowed-bin")
+	if err := os.RemoveAll(shimDir); err != nil {
+		b.logWarnf("allowed_commands: failed to clear stale shim dir: %v", err)
+		return b
+	}
+	if err := os.MkdirAll(shimDir, 0o755); err != nil {
+		b.logWarnf("allowed_commands: failed to create shim dir: %v", err)
+		return b
+	}
+
+	for _, name := range b.cfg.AllowedCommands {
+		var target string
+		for _, dir := range candidateDirs {
+			candidate := filepath.Join(dir, name)
+			if pathExists(candidate) {
+				target = candidate
+				break
+			}
+		}
+		if target == "" {
+			b.logWarnf("allowed_commands: %q not found in any of %v, skipping", name, candidateDirs)
+			continue
+		}
+		if err := os.Symlink(target, filepath.Join(shimDir, name)); err != nil {
+			b.logWarnf("allowed_commands: failed to link %q: %v", name, err)
+		}
+	}
+
+	b.SetEnv("PATH", shimDir)
 	return b
 }
 
@@ -897,10 +1543,28 @@ func (b *Builder) AddProxyEnvironment() *Builder {
 	b.SetEnv("NODE_EXTRA_CA_CERTS", caCertPath)
 	b.SetEnv("CURL_CA_BUNDLE", caCertPath)
 	b.SetEnv("GIT_SSL_CAINFO", caCertPath)
-	b.SetEnv("SSL_CERT_FILE", caCertPath)
+
+	// Most tools ignore an SSL_CERT_FILE they don't recognize, but some JVMs
+	// treat it as a fatal startup error. tools.java.ca_env lets that case opt
+	// out of the blanket value below in favor of a generated Java truststore,
+	// or nothing at all.
+	switch b.javaCAEnv() {
+	case javaCAEnvTruststore:
+		b.addJavaTruststore()
+	case javaCAEnvNone:
+		// Leave SSL_CERT_FILE unset for Java; everything else above still applies.
+	default:
+		b.SetEnv("SSL_CERT_FILE", caCertPath)
+	}
 
 	b.SetEnv("DEVSANDBOX_PROXY", "1")
 
+	if b.cfg.SocksEnabled && b.cfg.SocksPort != 0 {
+		socksURL := fmt.Sprintf("socks5://%s:%d", b.cfg.GatewayIP, b.cfg.SocksPort)
+		b.SetEnv("ALL_PROXY", socksURL)
+		b.SetEnv("all_proxy", socksURL)
+	}
+
 	return b
 }
 
@@ -917,6 +1581,101 @@ func (b *Builder) AddProxyCACertificate() *Builder {
 	return b
 }
 
+// javaCAEnvMode selects how the proxy CA is exposed to Java, via
+// [tools.java] ca_env in config.toml.
+type javaCAEnvMode string
+
+const (
+	// javaCAEnvDefault sets SSL_CERT_FILE the same as every other tool.
+	javaCAEnvDefault javaCAEnvMode = "default"
+	// javaCAEnvTruststore generates a PKCS12 truststore containing the proxy
+	// CA and points the JVM at it via JAVA_TOOL_OPTIONS, which every JVM
+	// reads on startup regardless of how it's invoked.
+	javaCAEnvTruststore javaCAEnvMode = "javatruststore"
+	// javaCAEnvNone skips CA configuration for Java entirely.
+	javaCAEnvNone javaCAEnvMode = "none"
+)
+
+// JavaCAEnv returns the tools.java.ca_env override ("default",
+// "javatruststore", or "none") from the "java" section of ToolsConfig.
+// Shared by both isolation backends so bwrap and Docker agree on how a
+// given config is interpreted.
+func JavaCAEnv(toolCfg map[string]any) string {
+	switch fmt.Sprint(toolCfg["ca_env"]) {
+	case string(javaCAEnvTruststore):
+		return string(javaCAEnvTruststore)
+	case string(javaCAEnvNone):
+		return string(javaCAEnvNone)
+	default:
+		return string(javaCAEnvDefault)
+	}
+}
+
+// javaCAEnv reads tools.java.ca_env from ToolsConfig, defaulting to
+// javaCAEnvDefault if unset or not one of the recognized values.
+func (b *Builder) javaCAEnv() javaCAEnvMode {
+	var section map[string]any
+	if b.cfg.ToolsConfig != nil {
+		section, _ = b.cfg.ToolsConfig["java"].(map[string]any)
+	}
+	return javaCAEnvMode(JavaCAEnv(section))
+}
+
+// addJavaTruststore generates a PKCS12 truststore containing the proxy CA
+// certificate, binds it into the sandbox, and points every JVM at it via
+// JAVA_TOOL_OPTIONS. If generation fails (e.g. no JDK on the host), it warns
+// and leaves Java without proxy CA configuration rather than failing the
+// whole sandbox launch.
+func (b *Builder) addJavaTruststore() {
+	if b.cfg.ProxyCAPath == "" {
+		return
+	}
+
+	const truststorePassword = "changeit" // not a secret: only trusts the proxy's own CA
+	truststoreHostPath := filepath.Join(b.cfg.SandboxHome, "devsandbox-java-truststore.p12")
+
+	if err := GenerateJavaTruststore(b.cfg.ProxyCAPath, truststoreHostPath, truststorePassword); err != nil {
+		b.logWarnf("failed to generate Java truststore, HTTPS through the proxy will fail for Java tools: %v", err)
+		return
+	}
+
+	truststoreDest := "/tmp/devsandbox-java-truststore.p12"
+	b.ROBindIfExists(truststoreHostPath, truststoreDest)
+
+	b.SetEnv("JAVA_TOOL_OPTIONS", fmt.Sprintf(
+		"-Djavax.net.ssl.trustStore=%s -Djavax.net.ssl.trustStoreType=PKCS12 -Djavax.net.ssl.trustStorePassword=%s",
+		truststoreDest, truststorePassword,
+	))
+}
+
+// GenerateJavaTruststore creates a PKCS12 truststore at outPath trusting
+// caCertPath, using keytool from a JDK on the host's PATH. Shared by both
+// isolation backends (see Builder.addJavaTruststore for bwrap, and the
+// Docker isolator for the equivalent container-mode setup).
+func GenerateJavaTruststore(caCertPath, outPath, password string) error {
+	if _, err := exec.LookPath("keytool"); err != nil {
+		return fmt.Errorf("keytool not found: %w", err)
+	}
+
+	// keytool refuses to import over an existing alias, so start fresh
+	// rather than trying to detect whether the CA certificate changed.
+	_ = os.Remove(outPath)
+
+	cmd := exec.Command("keytool",
+		"-importcert", "-noprompt", "-trustcacerts",
+		"-alias", "devsandbox-proxy-ca",
+		"-file", caCertPath,
+		"-keystore", outPath,
+		"-storetype", "PKCS12",
+		"-storepass", password,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keytool failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return os.Chmod(outPath, 0o600)
+}
+
 func pathExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
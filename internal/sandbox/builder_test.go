@@ -2,9 +2,15 @@ package sandbox
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+
+	"devsandbox/internal/config"
+	"devsandbox/internal/sandbox/mounts"
+	"devsandbox/internal/sandbox/tools"
 )
 
 func TestBuilder_BasicArgs(t *testing.T) {
@@ -61,6 +67,31 @@ func TestBuilder_Bindings(t *testing.T) {
 	}
 }
 
+func TestBuilder_Operations(t *testing.T) {
+	cfg := &Config{}
+	b := NewBuilder(cfg)
+	b.UnsharePID().
+		ROBind("/usr", "/usr").
+		SetEnv("HOME", "/home/test")
+
+	ops := b.Operations()
+	if len(ops) != 3 {
+		t.Fatalf("Operations() = %v, want 3 entries", ops)
+	}
+
+	if ops[0].Kind != OpNamespace || ops[0].Flag != "--unshare-pid" {
+		t.Errorf("Operations()[0] = %+v, want Kind=%s Flag=--unshare-pid", ops[0], OpNamespace)
+	}
+
+	if ops[1].Kind != OpBind || ops[1].Flag != "--ro-bind" || ops[1].Source != "/usr" || ops[1].Dest != "/usr" || !ops[1].ReadOnly {
+		t.Errorf("Operations()[1] = %+v, want ro-bind /usr -> /usr", ops[1])
+	}
+
+	if ops[2].Kind != OpEnv || ops[2].Flag != "--setenv" || ops[2].EnvName != "HOME" || ops[2].EnvValue != "/home/test" {
+		t.Errorf("Operations()[2] = %+v, want setenv HOME=/home/test", ops[2])
+	}
+}
+
 func TestBuilder_Network_Chdir(t *testing.T) {
 	cfg := &Config{}
 	b := NewBuilder(cfg)
@@ -93,6 +124,21 @@ func TestBuilder_SetEnv(t *testing.T) {
 	}
 }
 
+func TestBuilder_Env(t *testing.T) {
+	cfg := &Config{}
+	b := NewBuilder(cfg)
+	b.SetEnv("HOME", "/home/test").
+		ClearEnv().
+		SetEnv("USER", "testuser").
+		SetEnv("USER", "overridden")
+
+	env := b.Env()
+	expected := map[string]string{"USER": "overridden"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("Env() = %v, want %v", env, expected)
+	}
+}
+
 func TestBuilder_AddBaseArgs(t *testing.T) {
 	cfg := &Config{}
 	b := NewBuilder(cfg)
@@ -143,6 +189,36 @@ func TestBuilder_AddBaseArgs(t *testing.T) {
 	}
 }
 
+func TestBuilder_AddBaseArgs_TmpModeDisk(t *testing.T) {
+	tmpDir := filepath.Join(t.TempDir(), "tmp")
+	cfg := &Config{TmpMode: TmpModeDisk, TmpDir: tmpDir}
+	b := NewBuilder(cfg)
+	b.AddBaseArgs()
+
+	if err := b.Err(); err != nil {
+		t.Fatalf("AddBaseArgs() failed: %v", err)
+	}
+
+	args := b.Build()
+
+	foundBind := false
+	for i, arg := range args {
+		if arg == "--bind" && i+2 < len(args) && args[i+1] == tmpDir && args[i+2] == "/tmp" {
+			foundBind = true
+		}
+		if arg == "--tmpfs" && i+1 < len(args) && args[i+1] == "/tmp" {
+			t.Error("AddBaseArgs() with TmpModeDisk should not mount /tmp as tmpfs")
+		}
+	}
+	if !foundBind {
+		t.Errorf("AddBaseArgs() with TmpModeDisk should bind %s at /tmp, got args: %v", tmpDir, args)
+	}
+
+	if info, err := os.Stat(tmpDir); err != nil || !info.IsDir() {
+		t.Errorf("AddBaseArgs() with TmpModeDisk should create %s: %v", tmpDir, err)
+	}
+}
+
 func TestBuilder_OverlaySrc(t *testing.T) {
 	cfg := &Config{}
 	b := NewBuilder(cfg)
@@ -473,3 +549,715 @@ func TestBuilderErr(t *testing.T) {
 		t.Error("expected non-nil args")
 	}
 }
+
+func TestBuilder_AddProxyEnvironment_JavaCAEnvDefault(t *testing.T) {
+	cfg := &Config{
+		ProxyEnabled: true,
+		ProxyPort:    8080,
+		GatewayIP:    "10.0.0.1",
+	}
+
+	b := NewBuilder(cfg)
+	b.AddProxyEnvironment()
+
+	env := b.Env()
+	if env["SSL_CERT_FILE"] != "/tmp/devsandbox-ca.crt" {
+		t.Errorf("SSL_CERT_FILE = %q, want the blanket CA cert path by default", env["SSL_CERT_FILE"])
+	}
+}
+
+func TestBuilder_AddProxyEnvironment_JavaCAEnvNone(t *testing.T) {
+	cfg := &Config{
+		ProxyEnabled: true,
+		ProxyPort:    8080,
+		GatewayIP:    "10.0.0.1",
+		ToolsConfig: map[string]any{
+			"java": map[string]any{"ca_env": "none"},
+		},
+	}
+
+	b := NewBuilder(cfg)
+	b.AddProxyEnvironment()
+
+	env := b.Env()
+	if _, ok := env["SSL_CERT_FILE"]; ok {
+		t.Errorf("SSL_CERT_FILE should be unset when tools.java.ca_env = \"none\", got %q", env["SSL_CERT_FILE"])
+	}
+	// Other tools are unaffected.
+	if env["NODE_EXTRA_CA_CERTS"] != "/tmp/devsandbox-ca.crt" {
+		t.Errorf("NODE_EXTRA_CA_CERTS = %q, want the blanket CA cert path", env["NODE_EXTRA_CA_CERTS"])
+	}
+}
+
+func TestBuilder_AddProxyEnvironment_JavaCAEnvTruststore_NoCAConfigured(t *testing.T) {
+	// With no ProxyCAPath set there's nothing to generate a truststore from,
+	// so this should behave like "none" without erroring.
+	cfg := &Config{
+		ProxyEnabled: true,
+		ProxyPort:    8080,
+		GatewayIP:    "10.0.0.1",
+		ToolsConfig: map[string]any{
+			"java": map[string]any{"ca_env": "javatruststore"},
+		},
+	}
+
+	b := NewBuilder(cfg)
+	b.AddProxyEnvironment()
+
+	env := b.Env()
+	if _, ok := env["SSL_CERT_FILE"]; ok {
+		t.Errorf("SSL_CERT_FILE should be unset when tools.java.ca_env = \"javatruststore\", got %q", env["SSL_CERT_FILE"])
+	}
+	if _, ok := env["JAVA_TOOL_OPTIONS"]; ok {
+		t.Error("JAVA_TOOL_OPTIONS should not be set without a proxy CA to build a truststore from")
+	}
+}
+
+func TestBuilder_ResolveLocale(t *testing.T) {
+	origLang, hadLang := os.LookupEnv("LANG")
+	origLCAll, hadLCAll := os.LookupEnv("LC_ALL")
+	t.Cleanup(func() {
+		if hadLang {
+			_ = os.Setenv("LANG", origLang)
+		} else {
+			_ = os.Unsetenv("LANG")
+		}
+		if hadLCAll {
+			_ = os.Setenv("LC_ALL", origLCAll)
+		} else {
+			_ = os.Unsetenv("LC_ALL")
+		}
+	})
+
+	tests := []struct {
+		name       string
+		cfgLocale  string
+		hostLang   string
+		hostLCAll  string
+		wantLocale string
+	}{
+		{"configured wins over host", "de_DE.UTF-8", "en_US.UTF-8", "en_US.UTF-8", "de_DE.UTF-8"},
+		{"LC_ALL wins over LANG", "", "en_US.UTF-8", "fr_FR.UTF-8", "fr_FR.UTF-8"},
+		{"falls back to LANG", "", "en_US.UTF-8", "", "en_US.UTF-8"},
+		{"defaults to C.UTF-8", "", "", "", "C.UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Setenv("LANG", tt.hostLang)
+			_ = os.Setenv("LC_ALL", tt.hostLCAll)
+
+			b := NewBuilder(&Config{Locale: tt.cfgLocale})
+			if got := b.resolveLocale(); got != tt.wantLocale {
+				t.Errorf("resolveLocale() = %q, want %q", got, tt.wantLocale)
+			}
+		})
+	}
+}
+
+func TestBuilder_AddHostCABindings(t *testing.T) {
+	origFile, hadFile := os.LookupEnv("SSL_CERT_FILE")
+	origDir, hadDir := os.LookupEnv("SSL_CERT_DIR")
+	t.Cleanup(func() {
+		if hadFile {
+			_ = os.Setenv("SSL_CERT_FILE", origFile)
+		} else {
+			_ = os.Unsetenv("SSL_CERT_FILE")
+		}
+		if hadDir {
+			_ = os.Setenv("SSL_CERT_DIR", origDir)
+		} else {
+			_ = os.Unsetenv("SSL_CERT_DIR")
+		}
+	})
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "custom-ca.pem")
+	if err := os.WriteFile(certFile, []byte("cert"), 0o644); err != nil {
+		t.Fatalf("failed to write cert file: %v", err)
+	}
+	certDir := filepath.Join(dir, "certs.d")
+	if err := os.Mkdir(certDir, 0o755); err != nil {
+		t.Fatalf("failed to create cert dir: %v", err)
+	}
+
+	_ = os.Setenv("SSL_CERT_FILE", certFile)
+	_ = os.Setenv("SSL_CERT_DIR", certDir)
+
+	b := NewBuilder(&Config{})
+	b.AddHostCABindings()
+
+	args := b.Build()
+	if !containsArgPair(args, "--ro-bind", certFile) {
+		t.Errorf("expected --ro-bind of %s, got args %v", certFile, args)
+	}
+	if !containsArgPair(args, "--ro-bind", certDir) {
+		t.Errorf("expected --ro-bind of %s, got args %v", certDir, args)
+	}
+
+	env := b.Env()
+	if env["SSL_CERT_FILE"] != certFile {
+		t.Errorf("SSL_CERT_FILE = %q, want %q", env["SSL_CERT_FILE"], certFile)
+	}
+	if env["SSL_CERT_DIR"] != certDir {
+		t.Errorf("SSL_CERT_DIR = %q, want %q", env["SSL_CERT_DIR"], certDir)
+	}
+}
+
+func TestBuilder_AddHostCABindings_NoHostConfig(t *testing.T) {
+	origFile, hadFile := os.LookupEnv("SSL_CERT_FILE")
+	origDir, hadDir := os.LookupEnv("SSL_CERT_DIR")
+	_ = os.Unsetenv("SSL_CERT_FILE")
+	_ = os.Unsetenv("SSL_CERT_DIR")
+	t.Cleanup(func() {
+		if hadFile {
+			_ = os.Setenv("SSL_CERT_FILE", origFile)
+		}
+		if hadDir {
+			_ = os.Setenv("SSL_CERT_DIR", origDir)
+		}
+	})
+
+	b := NewBuilder(&Config{})
+	b.AddHostCABindings()
+
+	if env := b.Env(); len(env) != 0 {
+		t.Errorf("expected no env vars set, got %v", env)
+	}
+}
+
+func containsArgPair(args []string, flag, value string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuilder_AddEnvironment_SetsLocale(t *testing.T) {
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh", Locale: "ja_JP.UTF-8"}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if env["LANG"] != "ja_JP.UTF-8" {
+		t.Errorf("LANG = %q, want %q", env["LANG"], "ja_JP.UTF-8")
+	}
+	if env["LC_ALL"] != "ja_JP.UTF-8" {
+		t.Errorf("LC_ALL = %q, want %q", env["LC_ALL"], "ja_JP.UTF-8")
+	}
+}
+
+func TestBuilder_AddEnvironment_SourceDateEpochLiteral(t *testing.T) {
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh", SourceDateEpoch: "1700000000"}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if env["SOURCE_DATE_EPOCH"] != "1700000000" {
+		t.Errorf("SOURCE_DATE_EPOCH = %q, want %q", env["SOURCE_DATE_EPOCH"], "1700000000")
+	}
+	if env["TZ"] != "UTC" {
+		t.Errorf("TZ = %q, want %q", env["TZ"], "UTC")
+	}
+}
+
+func TestBuilder_AddEnvironment_SourceDateEpochUnset(t *testing.T) {
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh"}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if _, ok := env["SOURCE_DATE_EPOCH"]; ok {
+		t.Errorf("SOURCE_DATE_EPOCH = %q, want unset", env["SOURCE_DATE_EPOCH"])
+	}
+}
+
+func TestBuilder_AddEnvironment_SourceDateEpochFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_COMMITTER_DATE=2023-11-14T22:13:20+00:00",
+			"GIT_AUTHOR_DATE=2023-11-14T22:13:20+00:00",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	run("init")
+	run("commit", "--allow-empty", "-m", "init")
+
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh", ProjectDir: dir, SourceDateEpoch: "git"}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if env["SOURCE_DATE_EPOCH"] != "1700000000" {
+		t.Errorf("SOURCE_DATE_EPOCH = %q, want %q", env["SOURCE_DATE_EPOCH"], "1700000000")
+	}
+	if env["TZ"] != "UTC" {
+		t.Errorf("TZ = %q, want %q", env["TZ"], "UTC")
+	}
+}
+
+func TestJavaCAEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]any
+		want string
+	}{
+		{"unset", nil, "default"},
+		{"default", map[string]any{"ca_env": "default"}, "default"},
+		{"truststore", map[string]any{"ca_env": "javatruststore"}, "javatruststore"},
+		{"none", map[string]any{"ca_env": "none"}, "none"},
+		{"unrecognized", map[string]any{"ca_env": "bogus"}, "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JavaCAEnv(tt.cfg); got != tt.want {
+				t.Errorf("JavaCAEnv(%v) = %q, want %q", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_AddProjectBindings_HidesEnvrcByDefault(t *testing.T) {
+	dir := t.TempDir()
+	envrcPath := filepath.Join(dir, ".envrc")
+	if err := os.WriteFile(envrcPath, []byte("export FOO=bar\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .envrc: %v", err)
+	}
+
+	b := NewBuilder(&Config{ProjectDir: dir, ConfigVisibility: "hidden"})
+	b.AddProjectBindings()
+
+	args := b.Build()
+	if !containsArgTriple(args, "--ro-bind", "/dev/null", envrcPath) {
+		t.Errorf("expected .envrc to be hidden with --ro-bind /dev/null %s, got args %v", envrcPath, args)
+	}
+}
+
+func TestBuilder_AddProjectBindings_AllowEnvrc(t *testing.T) {
+	dir := t.TempDir()
+	envrcPath := filepath.Join(dir, ".envrc")
+	if err := os.WriteFile(envrcPath, []byte("export FOO=bar\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .envrc: %v", err)
+	}
+
+	cfg := &Config{
+		ProjectDir:       dir,
+		ConfigVisibility: "hidden",
+		ToolsConfig: map[string]any{
+			"direnv": map[string]any{"allow_envrc": true},
+		},
+	}
+	b := NewBuilder(cfg)
+	b.AddProjectBindings()
+
+	args := b.Build()
+	if containsArgTriple(args, "--ro-bind", "/dev/null", envrcPath) {
+		t.Errorf("expected .envrc to stay visible when allow_envrc is set, got args %v", args)
+	}
+}
+
+func TestBuilder_AddBlockedPaths_HidesFile(t *testing.T) {
+	dir := t.TempDir()
+	secretsPath := filepath.Join(dir, "secrets.yaml")
+	if err := os.WriteFile(secretsPath, []byte("key: value\n"), 0o644); err != nil {
+		t.Fatalf("failed to write secrets.yaml: %v", err)
+	}
+
+	b := NewBuilder(&Config{ProjectDir: dir, HomeDir: "/home/test", BlockedPaths: []string{"secrets.yaml"}})
+	b.AddBlockedPaths()
+
+	args := b.Build()
+	if !containsArgTriple(args, "--ro-bind", "/dev/null", secretsPath) {
+		t.Errorf("expected %s to be hidden with --ro-bind /dev/null, got args %v", secretsPath, args)
+	}
+}
+
+func TestBuilder_AddBlockedPaths_HidesDirWithTmpfs(t *testing.T) {
+	home := t.TempDir()
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0o700); err != nil {
+		t.Fatalf("failed to create .ssh dir: %v", err)
+	}
+
+	b := NewBuilder(&Config{HomeDir: home, BlockedPaths: []string{"~/.ssh"}})
+	b.AddBlockedPaths()
+
+	args := b.Build()
+	if !containsArgPair(args, "--tmpfs", sshDir) {
+		t.Errorf("expected %s to be hidden with --tmpfs, got args %v", sshDir, args)
+	}
+}
+
+func TestBuilder_AddBlockedPaths_GlobMatch(t *testing.T) {
+	dir := t.TempDir()
+	localEnv := filepath.Join(dir, ".env.local")
+	if err := os.WriteFile(localEnv, []byte("SECRET=1\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .env.local: %v", err)
+	}
+
+	b := NewBuilder(&Config{ProjectDir: dir, HomeDir: "/home/test", BlockedPaths: []string{".env.*"}})
+	b.AddBlockedPaths()
+
+	args := b.Build()
+	if !containsArgTriple(args, "--ro-bind", "/dev/null", localEnv) {
+		t.Errorf("expected %s to be hidden with --ro-bind /dev/null, got args %v", localEnv, args)
+	}
+}
+
+func TestBuilder_AddBlockedPaths_NoneConfigured(t *testing.T) {
+	b := NewBuilder(&Config{HomeDir: "/home/test"})
+	b.AddBlockedPaths()
+
+	if args := b.Build(); len(args) != 0 {
+		t.Errorf("expected no args with no blocked paths configured, got %v", args)
+	}
+}
+
+func TestBuilder_AddCustomMounts_Binds(t *testing.T) {
+	dataDir := t.TempDir()
+
+	cfg := &Config{
+		ProjectDir: t.TempDir(),
+		HomeDir:    "/home/test",
+		MountsConfig: mounts.NewEngine(config.MountsConfig{
+			Binds: []config.MountBind{
+				{Source: dataDir, Dest: "/data", ReadOnly: true},
+			},
+		}, "/home/test"),
+	}
+	b := NewBuilder(cfg)
+	b.AddCustomMounts()
+
+	args := b.Build()
+	if !containsArgTriple(args, "--ro-bind", dataDir, "/data") {
+		t.Errorf("expected %s to be ro-bound at /data, got args %v", dataDir, args)
+	}
+}
+
+func TestBuilder_AddCustomMounts_BindsRejectProjectAndHomeClobber(t *testing.T) {
+	dataDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	cfg := &Config{
+		ProjectDir: projectDir,
+		HomeDir:    "/home/test",
+		MountsConfig: mounts.NewEngine(config.MountsConfig{
+			Binds: []config.MountBind{
+				{Source: dataDir, Dest: projectDir},
+				{Source: dataDir, Dest: "/home/test"},
+			},
+		}, "/home/test"),
+	}
+	b := NewBuilder(cfg)
+	b.AddCustomMounts()
+
+	args := b.Build()
+	if len(args) != 0 {
+		t.Errorf("expected binds clobbering the project mount or $HOME to be skipped, got args %v", args)
+	}
+}
+
+func TestBuilder_AddRequiredHelpers_BindsResolvedBinary(t *testing.T) {
+	helperPath, err := exec.LookPath("true")
+	if err != nil {
+		t.Skip("no \"true\" binary on PATH to test against")
+	}
+
+	cfg := &Config{
+		ToolsConfig: map[string]any{
+			"git": map[string]any{"requires": []any{"true"}},
+		},
+	}
+	b := NewBuilder(cfg)
+	b.AddRequiredHelpers()
+
+	args := b.Build()
+	if !containsArgTriple(args, "--ro-bind", helperPath, helperPath) {
+		t.Errorf("expected %s to be ro-bound, got args %v", helperPath, args)
+	}
+}
+
+func TestBuilder_AddRequiredHelpers_SkipsMissingHelper(t *testing.T) {
+	cfg := &Config{
+		ToolsConfig: map[string]any{
+			"git": map[string]any{"requires": []any{"devsandbox-no-such-helper"}},
+		},
+	}
+	b := NewBuilder(cfg)
+	b.AddRequiredHelpers()
+
+	args := b.Build()
+	if len(args) != 0 {
+		t.Errorf("expected no bindings for a missing helper, got args %v", args)
+	}
+}
+
+func TestToStringSlice(t *testing.T) {
+	cases := []struct {
+		name string
+		in   any
+		want []string
+	}{
+		{"toml string array", []any{"gpg", "pinentry"}, []string{"gpg", "pinentry"}},
+		{"not a slice", "gpg", nil},
+		{"nil", nil, nil},
+		{"mixed types", []any{"gpg", 1, "pinentry"}, []string{"gpg", "pinentry"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := toStringSlice(c.in)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("toStringSlice(%v) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func containsArgTriple(args []string, flag, value1, value2 string) bool {
+	for i := 0; i+2 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value1 && args[i+2] == value2 {
+			return true
+		}
+	}
+	return false
+}
+
+// TestBuilder_AddTools_ToolsShellInitMatchesShellOverride is a regression
+// test for the --shell override: the mise activation snippet AddTools
+// stores on cfg.ToolsShellInit must match the shell the user forced, not
+// whatever $SHELL happened to be detected as.
+func TestBuilder_AddTools_ToolsShellInitMatchesShellOverride(t *testing.T) {
+	if _, err := exec.LookPath("mise"); err != nil {
+		t.Skip("no mise binary on PATH to test against")
+	}
+
+	home := t.TempDir()
+	cfg := &Config{
+		HomeDir:     home,
+		SandboxHome: t.TempDir(),
+		Shell:       ShellZsh,
+	}
+
+	b := NewBuilder(cfg)
+	b.AddTools()
+
+	if !strings.Contains(cfg.ToolsShellInit, "mise activate zsh") {
+		t.Errorf("expected mise activate zsh, got ToolsShellInit=%q", cfg.ToolsShellInit)
+	}
+	if strings.Contains(cfg.ToolsShellInit, "mise activate fish") {
+		t.Errorf("did not expect fish activation when Shell was zsh, got: %q", cfg.ToolsShellInit)
+	}
+}
+
+func TestBuilder_AddCommandAllowlist_RestrictsPATH(t *testing.T) {
+	sandboxHome := t.TempDir()
+
+	cfg := &Config{
+		HomeDir:         "/home/test",
+		SandboxHome:     sandboxHome,
+		AllowedCommands: []string{"ls", "devsandbox-test-nonexistent-cmd"},
+	}
+
+	b := NewBuilder(cfg)
+	b.AddCommandAllowlist()
+
+	shimDir := filepath.Join(sandboxHome, "devsandbox-allowed-bin")
+
+	if _, err := os.Lstat(filepath.Join(shimDir, "ls")); err != nil {
+		t.Errorf("expected shim for allowed command %q, got error: %v", "ls", err)
+	}
+
+	// The disallowed command was never on the allowlist, so no shim was
+	// ever created for it - it can't be found on the restricted PATH.
+	if _, err := os.Lstat(filepath.Join(shimDir, "devsandbox-test-nonexistent-cmd")); !os.IsNotExist(err) {
+		t.Errorf("expected no shim for unresolvable command, got err=%v", err)
+	}
+
+	ops := b.Operations()
+	found := false
+	for _, op := range ops {
+		if op.Kind == OpEnv && op.EnvName == "PATH" {
+			found = true
+			if op.EnvValue != shimDir {
+				t.Errorf("PATH = %q, want %q", op.EnvValue, shimDir)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected AddCommandAllowlist to set PATH")
+	}
+}
+
+func TestBuilder_AddSandboxHome_ReadWrite(t *testing.T) {
+	cfg := &Config{
+		HomeDir:     "/home/test",
+		SandboxHome: "/sandboxes/myproject/home",
+	}
+
+	b := NewBuilder(cfg)
+	b.AddSandboxHome()
+
+	args := b.Build()
+	if !strings.Contains(strings.Join(args, " "), "--bind /sandboxes/myproject/home /home/test") {
+		t.Errorf("expected a read-write bind of SandboxHome, got %v", args)
+	}
+	for _, flag := range args {
+		if flag == "--ro-bind" || flag == "--tmpfs" {
+			t.Errorf("expected no ro-bind/tmpfs without ReadOnlyHome, got %v", args)
+		}
+	}
+}
+
+func TestBuilder_AddSandboxHome_ReadOnly(t *testing.T) {
+	cfg := &Config{
+		HomeDir:      "/home/test",
+		SandboxHome:  "/sandboxes/myproject/home",
+		ReadOnlyHome: true,
+	}
+
+	b := NewBuilder(cfg)
+	b.AddSandboxHome()
+
+	args := b.Build()
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--ro-bind /sandboxes/myproject/home /home/test") {
+		t.Errorf("expected a read-only bind of SandboxHome, got %v", args)
+	}
+
+	for _, d := range []string{
+		"/home/test/.config",
+		"/home/test/.cache",
+		"/home/test/.local/share",
+		"/home/test/.local/state",
+		"/home/test/.local/bin",
+	} {
+		if !strings.Contains(joined, "--tmpfs "+d) {
+			t.Errorf("expected %s to be redirected to tmpfs, got %v", d, args)
+		}
+	}
+}
+
+func TestBuilder_AddSandboxHome_NetworkDisabled(t *testing.T) {
+	cfg := &Config{
+		HomeDir:         "/home/test",
+		SandboxHome:     "/sandboxes/myproject/home",
+		NetworkDisabled: true,
+	}
+
+	b := NewBuilder(cfg)
+	b.AddSandboxHome()
+
+	args := b.Build()
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--unshare-net") {
+		t.Errorf("expected --unshare-net with NetworkDisabled, got %v", args)
+	}
+	if strings.Contains(joined, "--share-net") {
+		t.Errorf("expected no --share-net with NetworkDisabled, got %v", args)
+	}
+}
+
+func TestBuilder_AddCommandAllowlist_NoneConfigured(t *testing.T) {
+	sandboxHome := t.TempDir()
+
+	cfg := &Config{
+		HomeDir:     "/home/test",
+		SandboxHome: sandboxHome,
+	}
+
+	b := NewBuilder(cfg)
+	b.AddCommandAllowlist()
+
+	shimDir := filepath.Join(sandboxHome, "devsandbox-allowed-bin")
+	if _, err := os.Stat(shimDir); !os.IsNotExist(err) {
+		t.Error("expected no shim dir when AllowedCommands is empty")
+	}
+	if len(b.Operations()) != 0 {
+		t.Errorf("expected no operations when AllowedCommands is empty, got %v", b.Operations())
+	}
+}
+
+func TestBuilder_ResolveBindingConflicts_NoConflict(t *testing.T) {
+	b := NewBuilder(&Config{})
+
+	bindings := []attributedBinding{
+		{toolName: "git", binding: tools.Binding{Source: "/a"}},
+		{toolName: "mise", binding: tools.Binding{Source: "/b"}},
+	}
+
+	resolved := b.resolveBindingConflicts(bindings)
+	if len(resolved) != 2 {
+		t.Fatalf("resolveBindingConflicts() = %v, want both bindings kept", resolved)
+	}
+}
+
+func TestBuilder_ResolveBindingConflicts_GitWinsOverOtherTool(t *testing.T) {
+	b := NewBuilder(&Config{})
+
+	bindings := []attributedBinding{
+		{toolName: "mise", binding: tools.Binding{Source: "/host/mise-config", Dest: "/sandbox/.config"}},
+		{toolName: "git", binding: tools.Binding{Source: "/host/git-config", Dest: "/sandbox/.config"}},
+	}
+
+	resolved := b.resolveBindingConflicts(bindings)
+	if len(resolved) != 1 || resolved[0].ToolName != "git" {
+		t.Fatalf("resolveBindingConflicts() = %v, want git's binding to win", resolved)
+	}
+}
+
+func TestBuilder_ResolveBindingConflicts_FirstSeenWinsAmongNonGitTools(t *testing.T) {
+	b := NewBuilder(&Config{})
+
+	bindings := []attributedBinding{
+		{toolName: "atuin", binding: tools.Binding{Source: "/host/atuin", Dest: "/sandbox/shared"}},
+		{toolName: "direnv", binding: tools.Binding{Source: "/host/direnv", Dest: "/sandbox/shared"}},
+	}
+
+	resolved := b.resolveBindingConflicts(bindings)
+	if len(resolved) != 1 || resolved[0].ToolName != "atuin" {
+		t.Fatalf("resolveBindingConflicts() = %v, want the first-seen tool (atuin) to win", resolved)
+	}
+}
+
+func TestBuilder_ResolveBindingConflicts_ExplicitMountWins(t *testing.T) {
+	b := NewBuilder(&Config{})
+	b.trackMount("/sandbox/.config", "/host/explicit", false, "AddCustomMounts")
+
+	bindings := []attributedBinding{
+		{toolName: "git", binding: tools.Binding{Source: "/host/git-config", Dest: "/sandbox/.config"}},
+	}
+
+	resolved := b.resolveBindingConflicts(bindings)
+	if len(resolved) != 0 {
+		t.Errorf("resolveBindingConflicts() = %v, want the tool binding dropped in favor of the existing explicit mount", resolved)
+	}
+}
+
+func TestBuilder_ResolveBindingConflicts_DestDefaultsToSource(t *testing.T) {
+	b := NewBuilder(&Config{})
+	b.trackMount("/host/same-path", "/host/explicit", false, "AddCustomMounts")
+
+	bindings := []attributedBinding{
+		{toolName: "git", binding: tools.Binding{Source: "/host/same-path"}},
+	}
+
+	resolved := b.resolveBindingConflicts(bindings)
+	if len(resolved) != 0 {
+		t.Errorf("resolveBindingConflicts() = %v, want the tool binding dropped since its Source already matches a tracked mount dest", resolved)
+	}
+}
@@ -3,14 +3,18 @@ package sandbox
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"devsandbox/internal/bwrap"
 	"devsandbox/internal/config"
 	"devsandbox/internal/sandbox/mounts"
+	"devsandbox/internal/sandbox/tools"
 )
 
 const (
@@ -18,6 +22,36 @@ const (
 	SandboxBaseDir = "devsandbox"
 )
 
+// TmpMode controls how /tmp is provided inside the sandbox.
+type TmpMode string
+
+const (
+	// TmpModeTmpfs mounts /tmp as a fresh tmpfs, discarded on exit (default).
+	TmpModeTmpfs TmpMode = "tmpfs"
+	// TmpModeDisk binds a persistent on-disk directory under SandboxRoot at
+	// /tmp, so it survives between --run steps and isn't bounded by RAM.
+	TmpModeDisk TmpMode = "disk"
+)
+
+// SeccompProfile selects which syscall filter the sandbox's processes run
+// under (see Builder.Seccomp).
+type SeccompProfile string
+
+const (
+	// SeccompProfileDefault blocks a conservative set of syscalls with no
+	// legitimate use for mise/git/node workflows (ptrace, mount, keyctl,
+	// kernel module loading, etc). This is the default.
+	SeccompProfileDefault SeccompProfile = "default"
+	// SeccompProfileStrict blocks everything SeccompProfileDefault does,
+	// plus syscalls that are occasionally useful but more often abused for
+	// process introspection or namespace escapes (process_vm_readv,
+	// unshare, setns, ...). May break debuggers or tools that attach to
+	// other processes.
+	SeccompProfileStrict SeccompProfile = "strict"
+	// SeccompProfileNone disables seccomp filtering entirely.
+	SeccompProfileNone SeccompProfile = "none"
+)
+
 // Shell represents a supported shell type
 type Shell string
 
@@ -25,6 +59,7 @@ const (
 	ShellFish Shell = "fish"
 	ShellBash Shell = "bash"
 	ShellZsh  Shell = "zsh"
+	ShellNu   Shell = "nu"
 )
 
 type Config struct {
@@ -38,6 +73,39 @@ type Config struct {
 	Shell       Shell  // Detected shell (fish, bash, zsh)
 	ShellPath   string // Full path to shell binary
 
+	// ShellInit is a single command string run at shell startup, after tool
+	// activation and before the prompt/exec (see BuildShellCommand). It's
+	// injected as literal shell code in the target shell's syntax, not as a
+	// quoted argument - e.g. "source ./.venv/bin/activate".
+	ShellInit string
+
+	// ToolsShellInit is the combined shell-init snippet from every available
+	// tool's ShellInit(shell), in registry (alphabetical) order, joined and
+	// run before ShellInit. Set by Builder.AddTools. A tool contributes
+	// nothing here by returning "" from ShellInit - e.g. because it has no
+	// startup hook, or because it's been disabled via its own [tools.*]
+	// config.
+	ToolsShellInit string
+
+	// DotfilesInstallInit is the shell snippet that runs a dotfiles repo's
+	// install script, set by ApplyDotfiles when one is found and run once,
+	// after ToolsShellInit and before ShellInit (see BuildShellCommand). It
+	// references the script by its in-sandbox path under HomeDir, since
+	// ApplyDotfiles stages it on the host under SandboxHome, which
+	// Builder.AddSandboxHome binds to HomeDir inside the sandbox - running it
+	// here, rather than on the host, keeps an untrusted dotfiles repo's
+	// install script subject to the same sandboxing as everything else.
+	DotfilesInstallInit string
+
+	// Fast skips all shell-init injection (mise activation, ToolsShellInit,
+	// ShellInit, the prompt) for a scripted one-shot invocation, exec'ing
+	// the requested command directly instead of wrapping it in
+	// `sh -c "<init>; <cmd>"` - see BuildShellCommand. Only takes effect
+	// when args are present; an interactive shell always gets the full
+	// init path, since there'd be nothing to exec otherwise. Tool-managed
+	// versions (mise shims, etc.) may not be on PATH under --fast.
+	Fast bool
+
 	// Isolation backend ("bwrap" or "docker")
 	Isolation IsolationType
 
@@ -46,8 +114,15 @@ type Config struct {
 	ProxyPort    int
 	ProxyCAPath  string
 	GatewayIP    string
+	// SOCKS5 listener alongside the proxy (for tools that only honor ALL_PROXY).
+	SocksEnabled bool
+	SocksPort    int
 	// True if network namespace is isolated (pasta)
 	NetworkIsolated bool
+	// NetworkDisabled is --no-network: an unshared network namespace with
+	// only loopback and no pasta/slirp4netns/proxy, for a fully offline
+	// run. Mutually exclusive with ProxyEnabled.
+	NetworkDisabled bool
 
 	// PortForwardingRules contains validated port forwarding rules.
 	PortForwardingRules []config.PortForwardingRule
@@ -56,6 +131,17 @@ type Config struct {
 	OverlayEnabled bool           // Global overlay enable/disable
 	ToolsConfig    map[string]any // Per-tool configuration from config file
 
+	// TmpMode controls how /tmp is provided: TmpModeTmpfs (default) or
+	// TmpModeDisk for a persistent on-disk directory (TmpDir).
+	TmpMode TmpMode
+	// TmpDir is the persistent on-disk directory bound at /tmp when
+	// TmpMode is TmpModeDisk. Set by NewConfig to SandboxRoot/tmp.
+	TmpDir string
+
+	// SeccompProfile selects the syscall filter applied to sandboxed
+	// processes. Defaults to SeccompProfileDefault.
+	SeccompProfile SeccompProfile
+
 	// Custom mount settings
 	MountsConfig *mounts.Engine // Compiled mount rules
 
@@ -63,9 +149,76 @@ type Config struct {
 	// Values: "hidden", "readonly", "readwrite"
 	ConfigVisibility string
 
+	// TrustHostCAs binds the host's SSL_CERT_FILE/SSL_CERT_DIR into the
+	// sandbox read-only and sets the matching env vars, for hosts that keep
+	// custom CAs outside the distro paths AddCABindings already covers.
+	TrustHostCAs bool
+
+	// ReadOnlyHome mounts SandboxHome read-only (see Builder.AddSandboxHome),
+	// so a compromised tool can't persist anything outside the project dir
+	// and /tmp. Cache/config dirs tools need to write are redirected to
+	// tmpfs instead of the usual isolated on-disk dirs.
+	ReadOnlyHome bool
+
+	// ResourceLimits caps the sandboxed process's memory and CPU usage (see
+	// bwrap.ResourceLimits). Zero value means unlimited.
+	ResourceLimits bwrap.ResourceLimits
+
+	// Locale sets LANG/LC_ALL inside the sandbox, from [env].locale. Empty
+	// means fall back to the host's LC_ALL/LANG, and then to "C.UTF-8" if
+	// those are unset too - see Builder.resolveLocale.
+	Locale string
+
+	// SourceDateEpoch sets SOURCE_DATE_EPOCH (and TZ=UTC) inside the
+	// sandbox, from sandbox.source_date_epoch. Empty disables it. "git"
+	// derives the value from HEAD's committer date in ProjectDir; anything
+	// else is used as a literal Unix timestamp - see
+	// Builder.resolveSourceDateEpoch.
+	SourceDateEpoch string
+
+	// BlockedPaths is the effective list of paths masked inside the
+	// sandbox, from config.SecurityConfig.EffectiveBlockedPaths() - the
+	// built-in defaults (~/.ssh, .env, ...) plus any [security].blocked_paths.
+	// See Builder.AddBlockedPaths.
+	BlockedPaths []string
+
+	// AllowedCommands, from sandbox.allowed_commands, restricts the
+	// sandbox shell's PATH to only these command names. Empty leaves PATH
+	// unrestricted. See Builder.AddCommandAllowlist.
+	AllowedCommands []string
+
+	// EnvPassthrough lists additional host environment variable names (or
+	// glob patterns like "FOO_*") to copy into the sandbox on top of the
+	// curated set AddEnvironment already sets, from [env].passthrough and
+	// repeatable --env flags. Names matching envPassthroughDenylist are
+	// refused even if they match an entry here. See
+	// Builder.addEnvPassthrough.
+	EnvPassthrough []string
+
 	// Logger for reporting warnings and errors during sandbox setup.
 	// If nil, log messages are silently dropped.
 	Logger Logger
+
+	// ToolLogger reports per-tool setup results (see Builder.AddTools).
+	// Kept separate from Logger so `devsandbox logs internal --type tool`
+	// can filter to just these. If nil, log messages are silently dropped.
+	ToolLogger Logger
+
+	// ResolvedToolBindings is the final set of tool bindings actually
+	// applied, one entry per binding that survived conflict resolution,
+	// with the tool that contributed it. Set by Builder.AddTools; used by
+	// `--info --verbose` to show users which tool is responsible for each
+	// mount. Bindings dropped for conflicting with a higher-precedence
+	// source are logged as warnings rather than included here.
+	ResolvedToolBindings []ResolvedBinding
+}
+
+// ResolvedBinding pairs a tool binding with the tool that contributed it,
+// after conflict resolution has picked it as the winner for its
+// destination path. See Builder.resolveBindingConflicts.
+type ResolvedBinding struct {
+	ToolName string
+	Binding  tools.Binding
 }
 
 // Options allows customizing sandbox configuration.
@@ -103,18 +256,50 @@ func NewConfig(opts *Options) (*Config, error) {
 	shell, shellPath := DetectShell()
 
 	return &Config{
-		HomeDir:     homeDir,
-		ProjectDir:  projectDir,
-		ProjectName: projectName,
-		SandboxBase: baseDir,
-		SandboxRoot: sandboxRoot,
-		SandboxHome: sandboxHome,
-		XDGRuntime:  xdgRuntime,
-		Shell:       shell,
-		ShellPath:   shellPath,
+		HomeDir:        homeDir,
+		ProjectDir:     projectDir,
+		ProjectName:    projectName,
+		SandboxBase:    baseDir,
+		SandboxRoot:    sandboxRoot,
+		SandboxHome:    sandboxHome,
+		XDGRuntime:     xdgRuntime,
+		Shell:          shell,
+		ShellPath:      shellPath,
+		TmpMode:        TmpModeTmpfs,
+		TmpDir:         filepath.Join(sandboxRoot, "tmp"),
+		SeccompProfile: SeccompProfileDefault,
 	}, nil
 }
 
+// ResolveShellOverride resolves an explicit --shell value ("bash", "zsh",
+// "fish", or "nu") to a Shell and the path of its binary, so the chosen
+// shell drives both the shell dispatch (BuildShellCommand) and the mise
+// activation snippet it emits - not just the process that gets exec'd.
+// Unlike DetectShell's silent fallback to bash, an explicit request for a
+// shell that isn't installed is an error: the user asked for it by name.
+func ResolveShellOverride(name string) (Shell, string, error) {
+	var shell Shell
+	switch name {
+	case "bash":
+		shell = ShellBash
+	case "zsh":
+		shell = ShellZsh
+	case "fish":
+		shell = ShellFish
+	case "nu":
+		shell = ShellNu
+	default:
+		return "", "", fmt.Errorf("invalid --shell value %q: must be bash, zsh, fish, or nu", name)
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", "", fmt.Errorf("--shell %s: %q not found in PATH", name, name)
+	}
+
+	return shell, path, nil
+}
+
 func DetectShell() (Shell, string) {
 	shellEnv := os.Getenv("SHELL")
 	if shellEnv == "" {
@@ -128,6 +313,8 @@ func DetectShell() (Shell, string) {
 		return ShellFish, shellEnv
 	case strings.Contains(shellName, "zsh"):
 		return ShellZsh, shellEnv
+	case strings.Contains(shellName, "nu"):
+		return ShellNu, shellEnv
 	default:
 		// Default to bash for unknown shells
 		if shellEnv == "" || !strings.Contains(shellName, "bash") {
@@ -157,8 +344,12 @@ func (c *Config) EnsureSandboxDirs() error {
 		c.SandboxHome,
 		filepath.Join(c.SandboxHome, ".config"),
 		filepath.Join(c.SandboxHome, ".cache"),
-		filepath.Join(c.SandboxHome, ".cache", "go-build"), // Go build cache (isolated)
-		filepath.Join(c.SandboxHome, ".cache", "go-mod"),   // Go module cache (isolated)
+		filepath.Join(c.SandboxHome, ".cache", "go-build"),        // Go build cache (isolated)
+		filepath.Join(c.SandboxHome, ".cache", "go-mod"),          // Go module cache (isolated)
+		filepath.Join(c.SandboxHome, ".cache", "uv"),              // uv/pip package cache (isolated)
+		filepath.Join(c.SandboxHome, ".cache", "npm"),             // npm package cache (isolated)
+		filepath.Join(c.SandboxHome, ".cargo", "registry", "src"), // cargo extracted crate sources (isolated)
+		filepath.Join(c.SandboxHome, ".cargo", "target"),          // cargo build artifacts (isolated)
 		filepath.Join(c.SandboxHome, ".local", "share"),
 		filepath.Join(c.SandboxHome, ".local", "state"),
 		filepath.Join(c.SandboxHome, "go"), // GOPATH (isolated)
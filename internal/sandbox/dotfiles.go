@@ -0,0 +1,170 @@
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dotfilesInstallScripts are candidate install script names, tried in order,
+// run once after dotfiles are applied to the sandbox home.
+var dotfilesInstallScripts = []string{"install.sh", "bootstrap.sh", "setup.sh"}
+
+// dotfilesSecretNames lists entries never copied from a dotfiles repo, even
+// if present, so a dotfiles bootstrap can't reintroduce credentials that the
+// rest of the sandbox deliberately blocks.
+var dotfilesSecretNames = map[string]bool{
+	".ssh":             true,
+	".gnupg":           true,
+	".aws":             true,
+	".netrc":           true,
+	".npmrc":           true,
+	".git-credentials": true,
+}
+
+// ApplyDotfiles clones or copies a dotfiles repo into the sandbox home and
+// arranges for its install script to run, once per sandbox. repoOrPath may
+// be a git URL or a local filesystem path. It is a no-op if dotfiles were
+// already applied to this sandbox. Callers should warn on error rather than
+// treat it as fatal -- a broken dotfiles repo must not block the sandbox
+// from starting.
+//
+// The install script itself isn't run here: cfg is mutated so that the next
+// BuildShellCommand call runs it inside the sandbox, the same as the real
+// command - repoOrPath may point at an untrusted repo, and its install
+// script should never execute unsandboxed on the host.
+func ApplyDotfiles(cfg *Config, repoOrPath string) error {
+	m, err := LoadMetadata(cfg.SandboxRoot)
+	if err != nil {
+		return fmt.Errorf("dotfiles: load metadata: %w", err)
+	}
+	if m.DotfilesApplied {
+		return nil
+	}
+
+	stageDir, err := os.MkdirTemp("", "devsandbox-dotfiles-")
+	if err != nil {
+		return fmt.Errorf("dotfiles: create staging dir: %w", err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	if err := fetchDotfiles(repoOrPath, stageDir); err != nil {
+		return err
+	}
+
+	if err := copyDotfiles(stageDir, cfg.SandboxHome); err != nil {
+		return err
+	}
+
+	// Mark applied before running the install script: the files are in
+	// place either way, and a failing install script shouldn't cause the
+	// repo to be re-cloned and re-copied on every future launch.
+	m.DotfilesApplied = true
+	if err := SaveMetadata(m, cfg.SandboxRoot); err != nil {
+		return fmt.Errorf("dotfiles: save metadata: %w", err)
+	}
+
+	cfg.DotfilesInstallInit = dotfilesInstallInit(cfg.HomeDir, cfg.SandboxHome)
+	return nil
+}
+
+// fetchDotfiles populates dest with the contents of repoOrPath, either by
+// copying a local directory or cloning a git repository.
+func fetchDotfiles(repoOrPath, dest string) error {
+	if info, err := os.Stat(repoOrPath); err == nil && info.IsDir() {
+		return copyDotfiles(repoOrPath, dest)
+	}
+
+	cmd := exec.Command("git", "clone", "--depth", "1", repoOrPath, dest)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("dotfiles: git clone %s: %w: %s", repoOrPath, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// copyDotfiles copies regular files and directories from src into dest,
+// skipping VCS metadata and anything on the secret blocklist.
+func copyDotfiles(src, dest string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("dotfiles: read %s: %w", src, err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == ".git" || dotfilesSecretNames[name] {
+			continue
+		}
+		if name == ".env" || strings.HasPrefix(name, ".env.") {
+			continue
+		}
+
+		srcPath := filepath.Join(src, name)
+		destPath := filepath.Join(dest, name)
+
+		if entry.IsDir() {
+			if err := os.MkdirAll(destPath, 0o755); err != nil {
+				return fmt.Errorf("dotfiles: mkdir %s: %w", destPath, err)
+			}
+			if err := copyDotfiles(srcPath, destPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := copyDotfile(srcPath, destPath); err != nil {
+			return fmt.Errorf("dotfiles: copy %s: %w", srcPath, err)
+		}
+	}
+
+	return nil
+}
+
+func copyDotfile(src, dest string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		// Dotfiles repos commonly symlink within themselves; skip rather
+		// than risk resolving outside the staged tree.
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// dotfilesInstallInit returns the shell snippet that runs the first
+// recognized install script found at the root of sandboxHome (a host path),
+// or "" if none of the candidate names are present. The snippet references
+// the script by its in-sandbox path under homeDir rather than sandboxHome,
+// since the two only coincide inside the sandbox, where Builder.AddSandboxHome
+// binds sandboxHome to homeDir - the script is meant to run there, with the
+// same mounts, network isolation and seccomp filter as the sandboxed command
+// itself, not on the host.
+func dotfilesInstallInit(homeDir, sandboxHome string) string {
+	for _, name := range dotfilesInstallScripts {
+		if _, err := os.Stat(filepath.Join(sandboxHome, name)); err != nil {
+			continue
+		}
+		return "bash " + ShellQuote(filepath.Join(homeDir, name))
+	}
+
+	return ""
+}
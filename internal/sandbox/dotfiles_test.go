@@ -0,0 +1,110 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyDotfiles_CopiesAndSkipsSecrets(t *testing.T) {
+	repoDir := t.TempDir()
+	writeFile(t, filepath.Join(repoDir, ".bashrc"), "export FOO=bar\n")
+	writeFile(t, filepath.Join(repoDir, ".ssh", "id_rsa"), "should-not-be-copied")
+	writeFile(t, filepath.Join(repoDir, ".env"), "SECRET=1")
+
+	sandboxRoot := t.TempDir()
+	sandboxHome := filepath.Join(sandboxRoot, "home")
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{SandboxRoot: sandboxRoot, SandboxHome: sandboxHome, ProjectDir: t.TempDir(), Shell: ShellBash}
+	if _, err := os.Stat(filepath.Join(sandboxRoot, MetadataFile)); os.IsNotExist(err) {
+		if err := SaveMetadata(CreateMetadata(cfg), sandboxRoot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ApplyDotfiles(cfg, repoDir); err != nil {
+		t.Fatalf("ApplyDotfiles() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".bashrc")); err != nil {
+		t.Errorf(".bashrc was not copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".ssh")); !os.IsNotExist(err) {
+		t.Errorf(".ssh should not be copied, got err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".env")); !os.IsNotExist(err) {
+		t.Errorf(".env should not be copied, got err = %v", err)
+	}
+
+	m, err := LoadMetadata(sandboxRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.DotfilesApplied {
+		t.Error("metadata.DotfilesApplied = false, want true")
+	}
+}
+
+func TestApplyDotfiles_SetsInstallInitForSandboxedExecution(t *testing.T) {
+	repoDir := t.TempDir()
+	writeFile(t, filepath.Join(repoDir, "install.sh"), "#!/bin/sh\necho hi\n")
+
+	sandboxRoot := t.TempDir()
+	sandboxHome := filepath.Join(sandboxRoot, "home")
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{SandboxRoot: sandboxRoot, SandboxHome: sandboxHome, HomeDir: "/home/sandbox", ProjectDir: t.TempDir(), Shell: ShellBash}
+	if err := SaveMetadata(CreateMetadata(cfg), sandboxRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyDotfiles(cfg, repoDir); err != nil {
+		t.Fatalf("ApplyDotfiles() error = %v", err)
+	}
+
+	want := "bash /home/sandbox/install.sh"
+	if cfg.DotfilesInstallInit != want {
+		t.Errorf("cfg.DotfilesInstallInit = %q, want %q", cfg.DotfilesInstallInit, want)
+	}
+}
+
+func TestApplyDotfiles_SkipsIfAlreadyApplied(t *testing.T) {
+	repoDir := t.TempDir()
+	writeFile(t, filepath.Join(repoDir, ".bashrc"), "export FOO=bar\n")
+
+	sandboxRoot := t.TempDir()
+	sandboxHome := filepath.Join(sandboxRoot, "home")
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{SandboxRoot: sandboxRoot, SandboxHome: sandboxHome, ProjectDir: t.TempDir(), Shell: ShellBash}
+	m := CreateMetadata(cfg)
+	m.DotfilesApplied = true
+	if err := SaveMetadata(m, sandboxRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyDotfiles(cfg, repoDir); err != nil {
+		t.Fatalf("ApplyDotfiles() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".bashrc")); !os.IsNotExist(err) {
+		t.Errorf("dotfiles should not be re-applied, got err = %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
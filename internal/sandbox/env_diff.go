@@ -0,0 +1,63 @@
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+// envSecretPattern matches environment variable names that commonly hold
+// secrets. Values for matching names are redacted in PrintEnvDiff output so
+// --print-env-diff can't be used to exfiltrate credentials.
+var envSecretPattern = regexp.MustCompile(`(?i)(TOKEN|SECRET|KEY|PASSWORD|PASSWD|CREDENTIAL|AUTH)`)
+
+// redactEnvValue returns "[REDACTED]" for variable names matching
+// envSecretPattern, and value unchanged otherwise.
+func redactEnvValue(name, value string) string {
+	if envSecretPattern.MatchString(name) {
+		return "[REDACTED]"
+	}
+	return value
+}
+
+// PrintEnvDiff writes a human-readable summary of how sandboxEnv differs
+// from hostEnv to w: variables added by the sandbox, removed by
+// --clearenv, and changed. Values for names that look like secrets are
+// redacted.
+func PrintEnvDiff(w io.Writer, hostEnv, sandboxEnv map[string]string) {
+	var added, removed, changed []string
+
+	for name, value := range sandboxEnv {
+		hostValue, inHost := hostEnv[name]
+		if !inHost {
+			added = append(added, fmt.Sprintf("  + %s=%s", name, redactEnvValue(name, value)))
+			continue
+		}
+		if hostValue != value {
+			changed = append(changed, fmt.Sprintf("  ~ %s=%s -> %s", name, redactEnvValue(name, hostValue), redactEnvValue(name, value)))
+		}
+	}
+	for name, value := range hostEnv {
+		if _, inSandbox := sandboxEnv[name]; !inSandbox {
+			removed = append(removed, fmt.Sprintf("  - %s=%s", name, redactEnvValue(name, value)))
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	printSection := func(title string, lines []string) {
+		fmt.Fprintf(w, "%s (%d):\n", title, len(lines))
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	fmt.Fprintln(w, "=== Sandbox Environment Diff ===")
+	printSection("Added", added)
+	printSection("Removed", removed)
+	printSection("Changed", changed)
+	fmt.Fprintln(w, "=================================")
+}
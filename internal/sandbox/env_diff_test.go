@@ -0,0 +1,49 @@
+package sandbox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintEnvDiff(t *testing.T) {
+	hostEnv := map[string]string{
+		"PATH":         "/usr/bin",
+		"LANG":         "en_US.UTF-8",
+		"GITHUB_TOKEN": "ghp_supersecret",
+	}
+	sandboxEnv := map[string]string{
+		"PATH":       "/usr/local/bin:/usr/bin",
+		"LANG":       "en_US.UTF-8",
+		"DEVSANDBOX": "1",
+	}
+
+	var buf bytes.Buffer
+	PrintEnvDiff(&buf, hostEnv, sandboxEnv)
+	out := buf.String()
+
+	if !strings.Contains(out, "+ DEVSANDBOX=1") {
+		t.Errorf("expected DEVSANDBOX to be listed as added, got:\n%s", out)
+	}
+	if !strings.Contains(out, "- GITHUB_TOKEN=[REDACTED]") {
+		t.Errorf("expected GITHUB_TOKEN to be listed as removed and redacted, got:\n%s", out)
+	}
+	if strings.Contains(out, "ghp_supersecret") {
+		t.Errorf("secret value leaked into diff output:\n%s", out)
+	}
+	if !strings.Contains(out, "~ PATH=/usr/bin -> /usr/local/bin:/usr/bin") {
+		t.Errorf("expected PATH to be listed as changed, got:\n%s", out)
+	}
+	if strings.Contains(out, "LANG") {
+		t.Errorf("unchanged variable LANG should not appear in diff, got:\n%s", out)
+	}
+}
+
+func TestRedactEnvValue(t *testing.T) {
+	if got := redactEnvValue("API_KEY", "secret"); got != "[REDACTED]" {
+		t.Errorf("redactEnvValue(API_KEY) = %q, want [REDACTED]", got)
+	}
+	if got := redactEnvValue("LANG", "en_US.UTF-8"); got != "en_US.UTF-8" {
+		t.Errorf("redactEnvValue(LANG) = %q, want unchanged", got)
+	}
+}
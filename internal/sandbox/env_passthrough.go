@@ -0,0 +1,76 @@
+package sandbox
+
+import (
+	"os"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// envPassthroughDenylist matches environment variable names that are never
+// passed through, even if they match an allowlist entry in
+// cfg.EnvPassthrough - these look like credentials, and passthrough is meant
+// for things like TERM, COLORTERM, or a project's own FOO_API_URL, not
+// secrets. Reuses envSecretPattern, the same check --print-env-diff uses to
+// redact values.
+var envPassthroughDenylist = envSecretPattern
+
+// addEnvPassthrough copies host environment variables matching
+// cfg.EnvPassthrough (exact names or glob patterns like "FOO_*") into the
+// sandbox, skipping unset variables and anything matching
+// envPassthroughDenylist regardless of whether it was explicitly allowed.
+func (b *Builder) addEnvPassthrough() *Builder {
+	for _, name := range MatchedEnvPassthroughNames(b.cfg.EnvPassthrough) {
+		b.SetEnvIfSet(name)
+	}
+	return b
+}
+
+// MatchedEnvPassthroughNames returns the names of currently-set host
+// environment variables that patterns (exact names or glob patterns like
+// "FOO_*") allow through, for --info to show the effective passthrough
+// list alongside the raw config.
+func MatchedEnvPassthroughNames(patterns []string) []string {
+	return matchedEnvPassthroughNames(patterns, os.Environ())
+}
+
+// matchedEnvPassthroughNames returns the names of environment variables
+// (from environ, in "NAME=value" form, e.g. os.Environ()) that match one of
+// the patterns and survive envPassthroughDenylist, de-duplicated.
+func matchedEnvPassthroughNames(patterns []string, environ []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var matched []string
+	for _, kv := range environ {
+		name, _, ok := strings.Cut(kv, "=")
+		if !ok || seen[name] {
+			continue
+		}
+		if envPassthroughDenylist.MatchString(name) {
+			continue
+		}
+		if !matchesAnyEnvPattern(name, patterns) {
+			continue
+		}
+		seen[name] = true
+		matched = append(matched, name)
+	}
+	return matched
+}
+
+// matchesAnyEnvPattern reports whether name equals or glob-matches
+// (doublestar, same engine as the proxy filter's domain rules) any pattern.
+func matchesAnyEnvPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
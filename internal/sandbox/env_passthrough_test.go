@@ -0,0 +1,82 @@
+package sandbox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatchedEnvPassthroughNames_ExactAndGlob(t *testing.T) {
+	environ := []string{
+		"TERM=xterm",
+		"COLORTERM=truecolor",
+		"FOO_API_URL=https://example.com",
+		"BAR_API_URL=https://example.com",
+		"UNRELATED=1",
+	}
+
+	got := matchedEnvPassthroughNames([]string{"TERM", "FOO_*"}, environ)
+	sort.Strings(got)
+
+	want := []string{"FOO_API_URL", "TERM"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchedEnvPassthroughNames() = %v, want %v", got, want)
+	}
+}
+
+func TestMatchedEnvPassthroughNames_DenylistWinsOverAllowlist(t *testing.T) {
+	environ := []string{
+		"FOO_API_URL=https://example.com",
+		"FOO_API_TOKEN=secret",
+		"FOO_SECRET_KEY=secret",
+	}
+
+	got := matchedEnvPassthroughNames([]string{"FOO_*"}, environ)
+
+	want := []string{"FOO_API_URL"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchedEnvPassthroughNames() = %v, want %v (secrets-looking names must be refused)", got, want)
+	}
+}
+
+func TestMatchedEnvPassthroughNames_UnsetPatternIsNoop(t *testing.T) {
+	got := matchedEnvPassthroughNames([]string{"DOES_NOT_EXIST"}, []string{"TERM=xterm"})
+	if len(got) != 0 {
+		t.Errorf("matchedEnvPassthroughNames() = %v, want empty", got)
+	}
+}
+
+func TestMatchedEnvPassthroughNames_NoPatterns(t *testing.T) {
+	got := matchedEnvPassthroughNames(nil, []string{"TERM=xterm"})
+	if got != nil {
+		t.Errorf("matchedEnvPassthroughNames() = %v, want nil", got)
+	}
+}
+
+func TestBuilder_AddEnvironment_EnvPassthrough(t *testing.T) {
+	t.Setenv("FOO_API_URL", "https://example.com")
+	t.Setenv("FOO_API_TOKEN", "secret")
+
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh", EnvPassthrough: []string{"FOO_*"}}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if env["FOO_API_URL"] != "https://example.com" {
+		t.Errorf("FOO_API_URL = %q, want %q", env["FOO_API_URL"], "https://example.com")
+	}
+	if _, ok := env["FOO_API_TOKEN"]; ok {
+		t.Error("FOO_API_TOKEN should not be passed through, it looks like a secret")
+	}
+}
+
+func TestBuilder_AddEnvironment_EnvPassthroughEmpty(t *testing.T) {
+	cfg := &Config{HomeDir: "/home/test", ShellPath: "/bin/sh"}
+	b := NewBuilder(cfg)
+	b.AddEnvironment()
+
+	env := b.Env()
+	if _, ok := env["FOO_API_URL"]; ok {
+		t.Error("expected no passthrough vars with EnvPassthrough unset")
+	}
+}
@@ -0,0 +1,111 @@
+package sandbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile is the sidecar file a sandbox's invocation history is
+// appended to, one JSON object per line.
+const HistoryFile = "history.jsonl"
+
+const (
+	maxHistoryArgs   = 64
+	maxHistoryArgLen = 2048
+)
+
+// HistoryEntry is one recorded devsandbox invocation against a sandbox.
+type HistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Argv      []string  `json:"argv"`
+	// ExitCode is the sandboxed command's exit status, or nil if it
+	// couldn't be captured - the fast bwrap path replaces this process via
+	// syscall.Exec before the command even runs, so there's nothing to
+	// report back to.
+	ExitCode *int `json:"exit_code,omitempty"`
+	Proxy    bool `json:"proxy"`
+}
+
+// AppendHistory appends entry to the sandbox's history.jsonl. Argv is
+// truncated first, since devsandbox invocations are trusted user input but
+// an absurdly long argv shouldn't bloat the file forever.
+func AppendHistory(sandboxRoot string, entry HistoryEntry) error {
+	entry.Argv = truncateArgv(entry.Argv)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(sandboxRoot, HistoryFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+
+	return nil
+}
+
+// truncateArgv caps argv to maxHistoryArgs entries and each entry to
+// maxHistoryArgLen characters, so a pathological invocation can't make the
+// history file unbounded.
+func truncateArgv(argv []string) []string {
+	truncated := argv
+	if len(truncated) > maxHistoryArgs {
+		overflow := len(truncated) - maxHistoryArgs
+		truncated = append([]string{}, truncated[:maxHistoryArgs]...)
+		truncated = append(truncated, fmt.Sprintf("...(%d more args)", overflow))
+	}
+
+	out := make([]string, len(truncated))
+	for i, arg := range truncated {
+		if len(arg) > maxHistoryArgLen {
+			arg = arg[:maxHistoryArgLen] + "...(truncated)"
+		}
+		out[i] = arg
+	}
+	return out
+}
+
+// LoadHistory reads every recorded entry for a sandbox, oldest first. A
+// missing history file is not an error - it just means nothing has been
+// recorded yet. Malformed lines are skipped rather than failing the whole
+// read, so one corrupted entry doesn't hide the rest of the history.
+func LoadHistory(sandboxRoot string) ([]HistoryEntry, error) {
+	f, err := os.Open(filepath.Join(sandboxRoot, HistoryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
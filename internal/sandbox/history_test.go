@@ -0,0 +1,72 @@
+package sandbox
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendHistory_WritesAndLoadEntriesInOrder(t *testing.T) {
+	root := t.TempDir()
+
+	first := 0
+	entries := []HistoryEntry{
+		{Timestamp: time.Now(), Argv: []string{"devsandbox", "npm", "install"}, ExitCode: &first, Proxy: false},
+		{Timestamp: time.Now(), Argv: []string{"devsandbox", "--proxy", "curl", "example.com"}, Proxy: true},
+	}
+	for _, e := range entries {
+		if err := AppendHistory(root, e); err != nil {
+			t.Fatalf("AppendHistory failed: %v", err)
+		}
+	}
+
+	loaded, err := LoadHistory(root)
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("got %d entries, want 2", len(loaded))
+	}
+	if loaded[0].ExitCode == nil || *loaded[0].ExitCode != 0 {
+		t.Errorf("entry 0 ExitCode = %v, want 0", loaded[0].ExitCode)
+	}
+	if loaded[1].ExitCode != nil {
+		t.Errorf("entry 1 ExitCode = %v, want nil", loaded[1].ExitCode)
+	}
+	if !loaded[1].Proxy {
+		t.Error("entry 1 Proxy = false, want true")
+	}
+	if strings.Join(loaded[0].Argv, " ") != "devsandbox npm install" {
+		t.Errorf("entry 0 Argv = %v", loaded[0].Argv)
+	}
+}
+
+func TestLoadHistory_MissingFileReturnsNilNotError(t *testing.T) {
+	entries, err := LoadHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if entries != nil {
+		t.Errorf("entries = %v, want nil", entries)
+	}
+}
+
+func TestTruncateArgv_CapsCountAndLength(t *testing.T) {
+	longArg := strings.Repeat("x", maxHistoryArgLen+100)
+	argv := append([]string{longArg}, make([]string, maxHistoryArgs+10)...)
+	for i := 1; i < len(argv); i++ {
+		argv[i] = "arg"
+	}
+
+	out := truncateArgv(argv)
+
+	if len(out) != maxHistoryArgs+1 {
+		t.Fatalf("got %d args, want %d (cap + overflow marker)", len(out), maxHistoryArgs+1)
+	}
+	if !strings.HasSuffix(out[0], "...(truncated)") {
+		t.Errorf("first arg not truncated: %q", out[0][:20])
+	}
+	if !strings.Contains(out[len(out)-1], "more args") {
+		t.Errorf("last entry = %q, want overflow marker", out[len(out)-1])
+	}
+}
@@ -0,0 +1,36 @@
+package sandbox
+
+import "time"
+
+// homeSizeCheckInterval is how long a cached SandboxHome size is trusted
+// before CheckHomeSize walks the tree again. Sandbox homes don't usually
+// balloon within a single hour, so this keeps repeated launches cheap.
+const homeSizeCheckInterval = time.Hour
+
+// CheckHomeSize returns the size of cfg.SandboxHome, using a cached value
+// from metadata when it was computed within homeSizeCheckInterval instead
+// of walking the full tree on every launch. warn is true if warnThreshold
+// is positive and the size exceeds it.
+func CheckHomeSize(cfg *Config, warnThreshold int64) (sizeBytes int64, warn bool, err error) {
+	m, err := LoadMetadata(cfg.SandboxRoot)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !m.LastSizeCheckedAt.IsZero() && time.Since(m.LastSizeCheckedAt) < homeSizeCheckInterval {
+		sizeBytes = m.LastSizeBytes
+	} else {
+		sizeBytes, err = GetSandboxSize(cfg.SandboxHome)
+		if err != nil {
+			return 0, false, err
+		}
+		m.LastSizeBytes = sizeBytes
+		m.LastSizeCheckedAt = time.Now()
+		if err := SaveMetadata(m, cfg.SandboxRoot); err != nil {
+			return sizeBytes, false, err
+		}
+	}
+
+	warn = warnThreshold > 0 && sizeBytes > warnThreshold
+	return sizeBytes, warn, nil
+}
@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckHomeSize(t *testing.T) {
+	sandboxRoot := t.TempDir()
+	sandboxHome := filepath.Join(sandboxRoot, "home")
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sandboxHome, "big.bin"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{SandboxRoot: sandboxRoot, SandboxHome: sandboxHome, ProjectDir: t.TempDir(), Shell: ShellBash}
+	if err := SaveMetadata(CreateMetadata(cfg), sandboxRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	size, warn, err := CheckHomeSize(cfg, 1024)
+	if err != nil {
+		t.Fatalf("CheckHomeSize() error = %v", err)
+	}
+	if size < 2048 {
+		t.Errorf("size = %d, want at least 2048", size)
+	}
+	if !warn {
+		t.Error("warn = false, want true (size exceeds threshold)")
+	}
+
+	m, err := LoadMetadata(sandboxRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.LastSizeBytes != size {
+		t.Errorf("metadata.LastSizeBytes = %d, want %d", m.LastSizeBytes, size)
+	}
+	if m.LastSizeCheckedAt.IsZero() {
+		t.Error("metadata.LastSizeCheckedAt should be set")
+	}
+
+	_, warn, err = CheckHomeSize(cfg, 1<<30)
+	if err != nil {
+		t.Fatalf("CheckHomeSize() error = %v", err)
+	}
+	if warn {
+		t.Error("warn = true, want false (size below threshold)")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,12 +31,23 @@ type Metadata struct {
 	LastUsed   time.Time     `json:"last_used"`
 	Shell      Shell         `json:"shell"`
 	Isolation  IsolationType `json:"isolation,omitempty"` // "bwrap" or "docker"
+	// DotfilesApplied is true once a dotfiles repo has been cloned/copied
+	// into this sandbox's home and its install script has been run.
+	DotfilesApplied bool `json:"dotfiles_applied,omitempty"`
+	// LastSizeBytes and LastSizeCheckedAt cache the result of the last
+	// SandboxHome size walk, so CheckHomeSize doesn't have to walk the
+	// full tree on every launch.
+	LastSizeBytes     int64     `json:"last_size_bytes,omitempty"`
+	LastSizeCheckedAt time.Time `json:"last_size_checked_at,omitempty"`
 	// Computed fields (not persisted)
 	SandboxRoot string `json:"-"`
 	SizeBytes   int64  `json:"-"`
 	Orphaned    bool   `json:"-"` // True if project_dir no longer exists
 	Active      bool   `json:"-"` // Session currently running (lock held)
 	State       string `json:"-"` // For Docker: "running", "stopped", "exited"
+	// ActivePorts lists the proxy/SOCKS ports claimed by running sessions
+	// against this sandbox, from its port registry (see RegisterPort).
+	ActivePorts []PortRegistryEntry `json:"-"`
 }
 
 // SaveMetadata writes metadata to the sandbox directory
@@ -131,6 +144,31 @@ func ListSandboxes(baseDir string) ([]*Metadata, error) {
 	return sandboxes, nil
 }
 
+// FindExistingSandbox resolves the sandbox directory for a project without
+// creating one, returning its metadata. Returns (nil, nil) if no sandbox
+// directory exists yet for this project.
+func FindExistingSandbox(projectDir, baseDir string) (*Metadata, error) {
+	name := GenerateSandboxName(projectDir)
+	sandboxRoot := filepath.Join(baseDir, name)
+
+	if _, err := os.Stat(sandboxRoot); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	m, err := LoadMetadata(sandboxRoot)
+	if err != nil {
+		m = createMetadataFromDir(sandboxRoot, name)
+	}
+	if m.Isolation == "" {
+		m.Isolation = IsolationBwrap
+	}
+
+	return m, nil
+}
+
 // createMetadataFromDir creates metadata for a sandbox without metadata.json
 func createMetadataFromDir(sandboxRoot, name string) *Metadata {
 	info, err := os.Stat(sandboxRoot)
@@ -280,6 +318,42 @@ func FormatSize(bytes int64) string {
 	}
 }
 
+// ParseSize parses a human-friendly size like "10G", "512MB", or "1024" (bytes)
+// into a byte count. Suffixes are case-insensitive and the trailing "B" is
+// optional (e.g. "10G" and "10GB" are equivalent).
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	upper := strings.ToUpper(s)
+	upper = strings.TrimSuffix(upper, "B")
+
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(upper, "K"):
+		unit = 1024
+		upper = strings.TrimSuffix(upper, "K")
+	case strings.HasSuffix(upper, "M"):
+		unit = 1024 * 1024
+		upper = strings.TrimSuffix(upper, "M")
+	case strings.HasSuffix(upper, "G"):
+		unit = 1024 * 1024 * 1024
+		upper = strings.TrimSuffix(upper, "G")
+	case strings.HasSuffix(upper, "T"):
+		unit = 1024 * 1024 * 1024 * 1024
+		upper = strings.TrimSuffix(upper, "T")
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+
+	return int64(value * float64(unit)), nil
+}
+
 // ListAllSandboxes returns all sandboxes (both bwrap and docker)
 func ListAllSandboxes(baseDir string) ([]*Metadata, error) {
 	// Get bwrap sandboxes
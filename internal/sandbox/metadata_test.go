@@ -289,6 +289,41 @@ func TestFormatSize(t *testing.T) {
 	}
 }
 
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"500", 500},
+		{"1K", 1024},
+		{"1KB", 1024},
+		{"10M", 10 * 1024 * 1024},
+		{"10G", 10 * 1024 * 1024 * 1024},
+		{"1T", 1024 * 1024 * 1024 * 1024},
+		{"1.5G", int64(1.5 * 1024 * 1024 * 1024)},
+		{"10g", 10 * 1024 * 1024 * 1024}, // case-insensitive
+	}
+
+	for _, tt := range tests {
+		result, err := ParseSize(tt.input)
+		if err != nil {
+			t.Errorf("ParseSize(%q) error = %v", tt.input, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestParseSize_Invalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "G"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("ParseSize(%q) expected error, got nil", input)
+		}
+	}
+}
+
 func TestSelectForPruning_SkipsActive(t *testing.T) {
 	sandboxes := []*Metadata{
 		{Name: "active", Active: true, Orphaned: true},
@@ -304,3 +339,51 @@ func TestSelectForPruning_SkipsActive(t *testing.T) {
 		t.Errorf("Expected inactive to be pruned, got %s", toPrune[0].Name)
 	}
 }
+
+func TestFindExistingSandbox_NotFound(t *testing.T) {
+	baseDir := t.TempDir()
+
+	m, err := FindExistingSandbox("/some/project", baseDir)
+	if err != nil {
+		t.Fatalf("FindExistingSandbox failed: %v", err)
+	}
+	if m != nil {
+		t.Errorf("expected nil metadata for a project with no sandbox, got %+v", m)
+	}
+}
+
+func TestFindExistingSandbox_Found(t *testing.T) {
+	baseDir := t.TempDir()
+	projectDir := "/home/user/projects/test"
+	name := GenerateSandboxName(projectDir)
+	sandboxRoot := filepath.Join(baseDir, name)
+
+	if err := os.MkdirAll(sandboxRoot, 0o755); err != nil {
+		t.Fatalf("failed to create sandbox dir: %v", err)
+	}
+
+	original := &Metadata{
+		Name:       name,
+		ProjectDir: projectDir,
+		CreatedAt:  time.Now().Truncate(time.Second),
+		LastUsed:   time.Now().Truncate(time.Second),
+		Shell:      ShellBash,
+	}
+	if err := SaveMetadata(original, sandboxRoot); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+
+	m, err := FindExistingSandbox(projectDir, baseDir)
+	if err != nil {
+		t.Fatalf("FindExistingSandbox failed: %v", err)
+	}
+	if m == nil {
+		t.Fatal("expected to find the sandbox, got nil")
+	}
+	if m.SandboxRoot != sandboxRoot {
+		t.Errorf("SandboxRoot mismatch: got %s, want %s", m.SandboxRoot, sandboxRoot)
+	}
+	if m.Isolation != IsolationBwrap {
+		t.Errorf("expected Isolation to default to bwrap, got %s", m.Isolation)
+	}
+}
@@ -21,7 +21,8 @@ type Logger interface {
 type Mode string
 
 const (
-	// ModeHidden overlays the path with /dev/null, making it inaccessible.
+	// ModeHidden overlays the path with /dev/null (files) or an empty
+	// tmpfs (directories), making it inaccessible.
 	ModeHidden Mode = "hidden"
 	// ModeReadOnly mounts the path as read-only.
 	ModeReadOnly Mode = "readonly"
@@ -40,9 +41,18 @@ type Rule struct {
 	Mode     Mode
 }
 
+// BindMount represents a compiled extra bind mount from [[sandbox.mounts.binds]].
+type BindMount struct {
+	Source   string // Host path, with ~ expanded
+	Dest     string // Sandbox path, with ~ expanded (defaults to Source)
+	ReadOnly bool
+	Optional bool
+}
+
 // Engine evaluates paths against mount rules.
 type Engine struct {
 	rules   []Rule
+	binds   []BindMount
 	homeDir string
 	logger  Logger
 }
@@ -71,12 +81,32 @@ func NewEngine(cfg config.MountsConfig, homeDir string) *Engine {
 		})
 	}
 
+	var binds []BindMount
+	for _, bind := range cfg.Binds {
+		dest := bind.Dest
+		if dest == "" {
+			dest = bind.Source
+		}
+		binds = append(binds, BindMount{
+			Source:   expandHome(bind.Source, homeDir),
+			Dest:     expandHome(dest, homeDir),
+			ReadOnly: bind.ReadOnly,
+			Optional: bind.Optional,
+		})
+	}
+
 	return &Engine{
 		rules:   rules,
+		binds:   binds,
 		homeDir: homeDir,
 	}
 }
 
+// Binds returns all configured extra bind mounts.
+func (e *Engine) Binds() []BindMount {
+	return e.binds
+}
+
 // parseMode converts a string mode to Mode, defaulting to ModeReadOnly.
 func parseMode(s string) Mode {
 	switch s {
@@ -57,6 +57,35 @@ func TestNewEngine(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("binds default dest to source and expand home", func(t *testing.T) {
+		cfg := config.MountsConfig{
+			Binds: []config.MountBind{
+				{Source: "~/data", ReadOnly: true},
+				{Source: "/data", Dest: "~/mounted-data", Optional: true},
+			},
+		}
+		engine := NewEngine(cfg, homeDir)
+
+		binds := engine.Binds()
+		if len(binds) != 2 {
+			t.Fatalf("expected 2 binds, got %d", len(binds))
+		}
+
+		if binds[0].Source != filepath.Join(homeDir, "data") || binds[0].Dest != binds[0].Source {
+			t.Errorf("expected bind 0 dest to default to expanded source, got %+v", binds[0])
+		}
+		if !binds[0].ReadOnly {
+			t.Error("expected bind 0 to be read-only")
+		}
+
+		if binds[1].Dest != filepath.Join(homeDir, "mounted-data") {
+			t.Errorf("expected bind 1 dest to be expanded, got %q", binds[1].Dest)
+		}
+		if !binds[1].Optional {
+			t.Error("expected bind 1 to be optional")
+		}
+	})
 }
 
 func TestExpandHome(t *testing.T) {
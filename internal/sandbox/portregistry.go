@@ -0,0 +1,183 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// PortRegistryFileName is the name of the per-project active-port registry
+// file within a sandbox directory.
+const PortRegistryFileName = "ports.json"
+
+// maxPortScan bounds how far NextAvailablePort will search past the
+// preferred port before giving up, mirroring proxy.MaxPortRetries' role for
+// the listener's own bind retries.
+const maxPortScan = 1000
+
+// PortRegistryEntry records one running session's proxy (and optional SOCKS)
+// port, so concurrent sessions against the same project can pick distinct
+// ports deterministically instead of relying on racing listen() retries, and
+// so `devsandbox sandboxes list` can show what's in use.
+type PortRegistryEntry struct {
+	PID       int       `json:"pid"`
+	ProxyPort int       `json:"proxy_port"`
+	SocksPort int       `json:"socks_port,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// RegisterPort claims proxyPort (and socksPort, if non-zero) for the calling
+// process in sandboxRoot's port registry, after pruning entries for
+// processes that are no longer running. It does not check whether the ports
+// are actually free on the host - the proxy server's own listen retry still
+// handles that - it only keeps two sessions against the same project from
+// picking the same port in the first place.
+func RegisterPort(sandboxRoot string, proxyPort, socksPort int) error {
+	return withPortRegistry(sandboxRoot, func(entries []PortRegistryEntry) ([]PortRegistryEntry, error) {
+		entries = removePortEntry(entries, os.Getpid())
+		entries = append(entries, PortRegistryEntry{
+			PID:       os.Getpid(),
+			ProxyPort: proxyPort,
+			SocksPort: socksPort,
+			StartedAt: time.Now(),
+		})
+		return entries, nil
+	})
+}
+
+// ReleasePort removes the calling process's entry from sandboxRoot's port
+// registry. Safe to call even if the process was never registered.
+func ReleasePort(sandboxRoot string) error {
+	return withPortRegistry(sandboxRoot, func(entries []PortRegistryEntry) ([]PortRegistryEntry, error) {
+		return removePortEntry(entries, os.Getpid()), nil
+	})
+}
+
+// ActivePorts returns sandboxRoot's port registry entries, after pruning any
+// that belong to processes that are no longer running.
+func ActivePorts(sandboxRoot string) ([]PortRegistryEntry, error) {
+	var result []PortRegistryEntry
+	err := withPortRegistry(sandboxRoot, func(entries []PortRegistryEntry) ([]PortRegistryEntry, error) {
+		entries = pruneDeadEntries(entries)
+		result = entries
+		return entries, nil
+	})
+	return result, err
+}
+
+// NextAvailablePort returns the lowest port >= preferred that isn't already
+// claimed by another active session in sandboxRoot's registry.
+func NextAvailablePort(sandboxRoot string, preferred int) (int, error) {
+	entries, err := ActivePorts(sandboxRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	taken := make(map[int]bool, len(entries)*2)
+	for _, e := range entries {
+		taken[e.ProxyPort] = true
+		if e.SocksPort != 0 {
+			taken[e.SocksPort] = true
+		}
+	}
+
+	port := preferred
+	for i := 0; i < maxPortScan; i++ {
+		if port > 65535 {
+			break
+		}
+		if !taken[port] {
+			return port, nil
+		}
+		port++
+	}
+
+	return 0, fmt.Errorf("no available port found starting from %d after scanning %d ports", preferred, maxPortScan)
+}
+
+// removePortEntry returns entries with any record for pid removed, and
+// prunes entries whose process is no longer running along the way.
+func removePortEntry(entries []PortRegistryEntry, pid int) []PortRegistryEntry {
+	pruned := pruneDeadEntries(entries)
+	for i, e := range pruned {
+		if e.PID == pid {
+			return append(pruned[:i], pruned[i+1:]...)
+		}
+	}
+	return pruned
+}
+
+// pruneDeadEntries returns entries whose process is still running.
+func pruneDeadEntries(entries []PortRegistryEntry) []PortRegistryEntry {
+	pruned := make([]PortRegistryEntry, 0, len(entries))
+	for _, e := range entries {
+		if processAlive(e.PID) {
+			pruned = append(pruned, e)
+		}
+	}
+	return pruned
+}
+
+// processAlive reports whether pid still refers to a running process.
+// FindProcess never errors on Unix, so the liveness check is the signal.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// withPortRegistry locks sandboxRoot's port registry file, loads its
+// entries, passes them to fn, and writes back whatever fn returns. The lock
+// is held for the duration of fn so read-modify-write is atomic across
+// concurrent sessions.
+func withPortRegistry(sandboxRoot string, fn func(entries []PortRegistryEntry) ([]PortRegistryEntry, error)) error {
+	path := filepath.Join(sandboxRoot, PortRegistryFileName)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open port registry: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock port registry: %w", err)
+	}
+	defer func() { _ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN) }()
+
+	var entries []PortRegistryEntry
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("failed to read port registry: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			// Corrupt registry - start fresh rather than failing the session.
+			entries = nil
+		}
+	}
+
+	entries, err = fn(entries)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode port registry: %w", err)
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate port registry: %w", err)
+	}
+	if _, err := f.WriteAt(out, 0); err != nil {
+		return fmt.Errorf("failed to write port registry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegisterPort_AppearsInActivePorts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := RegisterPort(tmpDir, 9001, 9002); err != nil {
+		t.Fatalf("RegisterPort failed: %v", err)
+	}
+	defer func() { _ = ReleasePort(tmpDir) }()
+
+	entries, err := ActivePorts(tmpDir)
+	if err != nil {
+		t.Fatalf("ActivePorts failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].PID != os.Getpid() || entries[0].ProxyPort != 9001 || entries[0].SocksPort != 9002 {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+}
+
+func TestReleasePort_RemovesEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := RegisterPort(tmpDir, 9001, 0); err != nil {
+		t.Fatalf("RegisterPort failed: %v", err)
+	}
+	if err := ReleasePort(tmpDir); err != nil {
+		t.Fatalf("ReleasePort failed: %v", err)
+	}
+
+	entries, err := ActivePorts(tmpDir)
+	if err != nil {
+		t.Fatalf("ActivePorts failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after release, got %d", len(entries))
+	}
+}
+
+func TestNextAvailablePort_NoEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	port, err := NextAvailablePort(tmpDir, 9000)
+	if err != nil {
+		t.Fatalf("NextAvailablePort failed: %v", err)
+	}
+	if port != 9000 {
+		t.Errorf("expected 9000 with no prior registrations, got %d", port)
+	}
+}
+
+func TestNextAvailablePort_SkipsTakenPorts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Simulate two other active sessions by registering entries against
+	// real, currently-running processes (entries for dead PIDs get pruned).
+	proc1 := startSleepProcess(t)
+	proc2 := startSleepProcess(t)
+	writePortRegistryEntries(t, tmpDir, []PortRegistryEntry{
+		{PID: proc1, ProxyPort: 9000},
+		{PID: proc2, ProxyPort: 9001},
+	})
+
+	port, err := NextAvailablePort(tmpDir, 9000)
+	if err != nil {
+		t.Fatalf("NextAvailablePort failed: %v", err)
+	}
+	if port != 9002 {
+		t.Errorf("expected 9002 (9000 and 9001 taken), got %d", port)
+	}
+}
+
+func TestActivePorts_PrunesDeadProcesses(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A PID well above any real process table size - guaranteed not to
+	// exist, unlike a just-exited child's PID, which the OS can reuse
+	// almost immediately under a low pid_max.
+	const deadPID = 1 << 30
+
+	writePortRegistryEntries(t, tmpDir, []PortRegistryEntry{
+		{PID: deadPID, ProxyPort: 9000},
+	})
+
+	entries, err := ActivePorts(tmpDir)
+	if err != nil {
+		t.Fatalf("ActivePorts failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected dead process's entry to be pruned, got %+v", entries)
+	}
+}
+
+// TestPortRegistry_ConcurrentAccess hammers the registry with concurrent
+// register/release/list calls to verify withPortRegistry's file locking
+// serializes access correctly - no panics, no corrupted JSON, and no lost
+// updates (RegisterPort followed by ReleasePort always leaves zero entries).
+func TestPortRegistry_ConcurrentAccess(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+			if err := RegisterPort(tmpDir, port, 0); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := ActivePorts(tmpDir); err != nil {
+				errs <- err
+				return
+			}
+			if err := ReleasePort(tmpDir); err != nil {
+				errs <- err
+			}
+		}(9000 + i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent registry access failed: %v", err)
+	}
+
+	entries, err := ActivePorts(tmpDir)
+	if err != nil {
+		t.Fatalf("ActivePorts failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected all entries released, got %+v", entries)
+	}
+}
+
+// startSleepProcess starts a short-lived child process and returns its PID,
+// so tests can register registry entries against a PID that processAlive
+// will actually find running. The process is killed during cleanup.
+func startSleepProcess(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start helper process: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd.Process.Pid
+}
+
+// writePortRegistryEntries writes entries directly to sandboxRoot's port
+// registry file, bypassing RegisterPort, for tests that need specific PIDs.
+func writePortRegistryEntries(t *testing.T, sandboxRoot string, entries []PortRegistryEntry) {
+	t.Helper()
+	for i := range entries {
+		if entries[i].StartedAt.IsZero() {
+			entries[i].StartedAt = time.Now()
+		}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatalf("failed to marshal entries: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sandboxRoot, PortRegistryFileName), data, 0o644); err != nil {
+		t.Fatalf("failed to write registry file: %v", err)
+	}
+}
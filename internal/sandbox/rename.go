@@ -0,0 +1,100 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RenameSandbox re-points m at newProjectDir: it moves the sandbox
+// directory from its current <basename>-<hash> name to the one
+// GenerateSandboxName derives for newProjectDir, and updates the saved
+// metadata's ProjectDir/Name to match. Used by `devsandbox rename` when a
+// project directory is moved or renamed, so the existing sandbox (its
+// caches, shell history, etc.) is kept instead of a fresh one being
+// created under the new name on next launch.
+//
+// Refuses to rename a Docker sandbox (isolation is looked up by container
+// name derived from the project, not a renamable directory) or one with
+// an active session. Returns an error without touching anything if a
+// sandbox already exists at the destination name.
+func RenameSandbox(m *Metadata, newProjectDir string, baseDir string) (*Metadata, error) {
+	if m.Isolation == IsolationDocker {
+		return nil, fmt.Errorf("renaming a Docker sandbox is not supported")
+	}
+	if IsSessionActive(m.SandboxRoot) {
+		return nil, fmt.Errorf("sandbox %q has an active session; stop it before renaming", m.Name)
+	}
+
+	newName := GenerateSandboxName(newProjectDir)
+	newSandboxRoot := filepath.Join(baseDir, newName)
+
+	if newSandboxRoot == m.SandboxRoot {
+		m.ProjectDir = newProjectDir
+		m.Orphaned = false
+		if err := SaveMetadata(m, m.SandboxRoot); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	if _, err := os.Stat(newSandboxRoot); err == nil {
+		return nil, fmt.Errorf("a sandbox already exists at %q", newSandboxRoot)
+	}
+
+	if err := os.Rename(m.SandboxRoot, newSandboxRoot); err != nil {
+		return nil, fmt.Errorf("failed to move sandbox directory: %w", err)
+	}
+
+	m.SandboxRoot = newSandboxRoot
+	m.Name = newName
+	m.ProjectDir = newProjectDir
+	m.Orphaned = false
+
+	if err := SaveMetadata(m, m.SandboxRoot); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindOrphanedSandboxByBasename looks through every sandbox under baseDir
+// for exactly one orphaned sandbox (its recorded project directory no
+// longer exists) whose project directory has the same basename as
+// projectDir. Used by `devsandbox rename --adopt` to find the sandbox a
+// moved project used to have, without requiring the user to remember its
+// old path.
+//
+// Returns an error if there's no match or more than one, since silently
+// picking one of several candidates could re-point the wrong sandbox at
+// this project.
+func FindOrphanedSandboxByBasename(baseDir, projectDir string) (*Metadata, error) {
+	sandboxes, err := ListAllSandboxes(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wantBase := filepath.Base(projectDir)
+	var matches []*Metadata
+	for _, m := range sandboxes {
+		if !m.Orphaned {
+			continue
+		}
+		if filepath.Base(m.ProjectDir) == wantBase {
+			matches = append(matches, m)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no orphaned sandbox found with basename %q; use --from <oldpath> to adopt a specific one", wantBase)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.Name)
+		}
+		return nil, fmt.Errorf("multiple orphaned sandboxes match basename %q: %v; use --from <oldpath> to pick one", wantBase, names)
+	}
+}
@@ -0,0 +1,111 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameSandbox_MovesDirectoryAndUpdatesMetadata(t *testing.T) {
+	baseDir := t.TempDir()
+	oldProjectDir := filepath.Join(t.TempDir(), "myapp")
+	newProjectDir := filepath.Join(t.TempDir(), "myapp-renamed")
+
+	oldName := GenerateSandboxName(oldProjectDir)
+	oldRoot := filepath.Join(baseDir, oldName)
+	if err := os.MkdirAll(oldRoot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Metadata{Name: oldName, ProjectDir: oldProjectDir, SandboxRoot: oldRoot}
+	if err := SaveMetadata(m, oldRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := RenameSandbox(m, newProjectDir, baseDir)
+	if err != nil {
+		t.Fatalf("RenameSandbox failed: %v", err)
+	}
+
+	wantName := GenerateSandboxName(newProjectDir)
+	if renamed.Name != wantName {
+		t.Errorf("Name = %q, want %q", renamed.Name, wantName)
+	}
+	if renamed.ProjectDir != newProjectDir {
+		t.Errorf("ProjectDir = %q, want %q", renamed.ProjectDir, newProjectDir)
+	}
+	if _, err := os.Stat(oldRoot); !os.IsNotExist(err) {
+		t.Error("expected old sandbox directory to be gone")
+	}
+
+	loaded, err := LoadMetadata(renamed.SandboxRoot)
+	if err != nil {
+		t.Fatalf("LoadMetadata failed: %v", err)
+	}
+	if loaded.ProjectDir != newProjectDir {
+		t.Errorf("persisted ProjectDir = %q, want %q", loaded.ProjectDir, newProjectDir)
+	}
+}
+
+func TestRenameSandbox_RefusesWhenDestinationExists(t *testing.T) {
+	baseDir := t.TempDir()
+	oldProjectDir := filepath.Join(t.TempDir(), "myapp")
+	newProjectDir := filepath.Join(t.TempDir(), "myapp-renamed")
+
+	oldName := GenerateSandboxName(oldProjectDir)
+	oldRoot := filepath.Join(baseDir, oldName)
+	if err := os.MkdirAll(oldRoot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Metadata{Name: oldName, ProjectDir: oldProjectDir, SandboxRoot: oldRoot}
+
+	newName := GenerateSandboxName(newProjectDir)
+	if err := os.MkdirAll(filepath.Join(baseDir, newName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RenameSandbox(m, newProjectDir, baseDir); err == nil {
+		t.Error("expected an error when the destination sandbox already exists")
+	}
+	if _, err := os.Stat(oldRoot); err != nil {
+		t.Error("old sandbox directory should be untouched after a refused rename")
+	}
+}
+
+func TestRenameSandbox_RefusesDockerSandbox(t *testing.T) {
+	m := &Metadata{Name: "myapp-abc123", ProjectDir: "/tmp/myapp", Isolation: IsolationDocker}
+
+	if _, err := RenameSandbox(m, "/tmp/myapp-renamed", t.TempDir()); err == nil {
+		t.Error("expected an error renaming a Docker sandbox")
+	}
+}
+
+func TestFindOrphanedSandboxByBasename(t *testing.T) {
+	baseDir := t.TempDir()
+	goneDir := filepath.Join(t.TempDir(), "myapp")
+	name := GenerateSandboxName(goneDir)
+	root := filepath.Join(baseDir, name)
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m := &Metadata{Name: name, ProjectDir: goneDir, SandboxRoot: root}
+	if err := SaveMetadata(m, root); err != nil {
+		t.Fatal(err)
+	}
+	// goneDir itself no longer exists, so this sandbox is orphaned.
+
+	newProjectDir := filepath.Join(t.TempDir(), "myapp")
+	found, err := FindOrphanedSandboxByBasename(baseDir, newProjectDir)
+	if err != nil {
+		t.Fatalf("FindOrphanedSandboxByBasename failed: %v", err)
+	}
+	if found.Name != name {
+		t.Errorf("found sandbox %q, want %q", found.Name, name)
+	}
+}
+
+func TestFindOrphanedSandboxByBasename_NoMatch(t *testing.T) {
+	baseDir := t.TempDir()
+	if _, err := FindOrphanedSandboxByBasename(baseDir, "/tmp/nonexistent-project"); err == nil {
+		t.Error("expected an error when no orphaned sandbox matches")
+	}
+}
@@ -0,0 +1,188 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SandboxEnvFileName is the name of the per-sandbox environment file under
+// SandboxRoot. Unlike --env-file (applied for a single invocation), its
+// contents are loaded on every launch for that sandbox.
+const SandboxEnvFileName = "env"
+
+// sandboxEnvAllowSecretMarker is a comment line that, when placed
+// immediately before a KEY=VALUE line, opts that key into being loaded even
+// though its name looks like a secret (see envSecretPattern). Without it,
+// secret-looking keys are silently skipped - the same "never auto-load
+// secrets" rule --print-env-diff's redaction enforces, applied at load time
+// instead of at display time.
+const sandboxEnvAllowSecretMarker = "# allow-secret"
+
+// SandboxEnvPath returns the path to the per-sandbox environment file.
+func SandboxEnvPath(sandboxRoot string) string {
+	return filepath.Join(sandboxRoot, SandboxEnvFileName)
+}
+
+// LoadSandboxEnv reads SandboxRoot/env and returns the variables it defines.
+// Blank lines and comment lines (starting with #) are ignored. A key whose
+// name matches envSecretPattern is skipped unless it's preceded by a
+// "# allow-secret" marker line; skipped keys are returned in skipped so
+// callers can warn about them. A missing file is not an error - it just
+// means there's nothing to load yet.
+func LoadSandboxEnv(sandboxRoot string) (loaded map[string]string, skipped []string, err error) {
+	path := SandboxEnvPath(sandboxRoot)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	loaded = make(map[string]string)
+	allowSecret := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			allowSecret = line == sandboxEnvAllowSecretMarker
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			allowSecret = false
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if envSecretPattern.MatchString(key) && !allowSecret {
+			skipped = append(skipped, key)
+			allowSecret = false
+			continue
+		}
+		loaded[key] = value
+		allowSecret = false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return loaded, skipped, nil
+}
+
+// SetSandboxEnvVar adds or updates key=value in SandboxRoot/env, creating
+// the file if necessary. Keys whose name looks like a secret (see
+// envSecretPattern) are rejected unless allowSecret is true, in which case
+// a "# allow-secret" marker is written immediately above the line so
+// LoadSandboxEnv picks it up.
+func SetSandboxEnvVar(sandboxRoot, key, value string, allowSecret bool) error {
+	if key == "" {
+		return fmt.Errorf("env key must not be empty")
+	}
+	if envSecretPattern.MatchString(key) && !allowSecret {
+		return fmt.Errorf("%q looks like a secret; pass --allow-secret to store it in the sandbox env file anyway", key)
+	}
+
+	path := SandboxEnvPath(sandboxRoot)
+	lines, err := readLinesIfExists(path)
+	if err != nil {
+		return err
+	}
+
+	newLines := make([]string, 0, len(lines)+2)
+	replaced := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		lineKey, _, ok := strings.Cut(trimmed, "=")
+		if ok && strings.TrimSpace(lineKey) == key && !strings.HasPrefix(trimmed, "#") {
+			// Drop a marker we're about to replace so it doesn't go stale.
+			if len(newLines) > 0 && newLines[len(newLines)-1] == sandboxEnvAllowSecretMarker {
+				newLines = newLines[:len(newLines)-1]
+			}
+			if allowSecret {
+				newLines = append(newLines, sandboxEnvAllowSecretMarker)
+			}
+			newLines = append(newLines, key+"="+value)
+			replaced = true
+			continue
+		}
+		newLines = append(newLines, line)
+	}
+	if !replaced {
+		if allowSecret {
+			newLines = append(newLines, sandboxEnvAllowSecretMarker)
+		}
+		newLines = append(newLines, key+"="+value)
+	}
+
+	return writeLines(path, newLines)
+}
+
+// UnsetSandboxEnvVar removes key (and its allow-secret marker, if any) from
+// SandboxRoot/env. It reports whether the key was present.
+func UnsetSandboxEnvVar(sandboxRoot, key string) (bool, error) {
+	path := SandboxEnvPath(sandboxRoot)
+	lines, err := readLinesIfExists(path)
+	if err != nil {
+		return false, err
+	}
+
+	newLines := make([]string, 0, len(lines))
+	found := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		lineKey, _, ok := strings.Cut(trimmed, "=")
+		if ok && strings.TrimSpace(lineKey) == key && !strings.HasPrefix(trimmed, "#") {
+			if len(newLines) > 0 && newLines[len(newLines)-1] == sandboxEnvAllowSecretMarker {
+				newLines = newLines[:len(newLines)-1]
+			}
+			found = true
+			continue
+		}
+		newLines = append(newLines, line)
+	}
+
+	if !found {
+		return false, nil
+	}
+	return true, writeLines(path, newLines)
+}
+
+func readLinesIfExists(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(text, "\n"), nil
+}
+
+func writeLines(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	// 0600: entries may hold opted-in secret values (--allow-secret).
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSandboxEnv_MissingFile(t *testing.T) {
+	loaded, skipped, err := LoadSandboxEnv(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadSandboxEnv: %v", err)
+	}
+	if len(loaded) != 0 || len(skipped) != 0 {
+		t.Errorf("expected no entries for a missing file, got loaded=%v skipped=%v", loaded, skipped)
+	}
+}
+
+func TestLoadSandboxEnv_ParsesAndSkipsSecrets(t *testing.T) {
+	root := t.TempDir()
+	content := `# project-specific variables
+FOO=bar
+
+# a comment
+BAZ=qux with spaces
+
+MY_API_KEY=should-be-skipped
+
+# allow-secret
+MY_TOKEN=opted-in
+`
+	if err := os.WriteFile(SandboxEnvPath(root), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, skipped, err := LoadSandboxEnv(root)
+	if err != nil {
+		t.Fatalf("LoadSandboxEnv: %v", err)
+	}
+
+	want := map[string]string{
+		"FOO":      "bar",
+		"BAZ":      "qux with spaces",
+		"MY_TOKEN": "opted-in",
+	}
+	for k, v := range want {
+		if loaded[k] != v {
+			t.Errorf("loaded[%q] = %q, want %q", k, loaded[k], v)
+		}
+	}
+	if _, ok := loaded["MY_API_KEY"]; ok {
+		t.Errorf("expected MY_API_KEY to be skipped, not loaded")
+	}
+	if len(skipped) != 1 || skipped[0] != "MY_API_KEY" {
+		t.Errorf("skipped = %v, want [MY_API_KEY]", skipped)
+	}
+}
+
+func TestSetSandboxEnvVar_RejectsSecretWithoutOptIn(t *testing.T) {
+	root := t.TempDir()
+	if err := SetSandboxEnvVar(root, "API_SECRET", "x", false); err == nil {
+		t.Error("expected an error setting a secret-looking key without --allow-secret")
+	}
+}
+
+func TestSetSandboxEnvVar_AllowSecretRoundTrips(t *testing.T) {
+	root := t.TempDir()
+	if err := SetSandboxEnvVar(root, "API_SECRET", "x", true); err != nil {
+		t.Fatalf("SetSandboxEnvVar: %v", err)
+	}
+
+	loaded, skipped, err := LoadSandboxEnv(root)
+	if err != nil {
+		t.Fatalf("LoadSandboxEnv: %v", err)
+	}
+	if loaded["API_SECRET"] != "x" {
+		t.Errorf("expected API_SECRET to load once opted in, got loaded=%v skipped=%v", loaded, skipped)
+	}
+}
+
+func TestSetSandboxEnvVar_UpdatesExistingKey(t *testing.T) {
+	root := t.TempDir()
+	if err := SetSandboxEnvVar(root, "FOO", "1", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSandboxEnvVar(root, "FOO", "2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _, err := LoadSandboxEnv(root)
+	if err != nil {
+		t.Fatalf("LoadSandboxEnv: %v", err)
+	}
+	if loaded["FOO"] != "2" {
+		t.Errorf("loaded[FOO] = %q, want %q", loaded["FOO"], "2")
+	}
+
+	data, err := os.ReadFile(SandboxEnvPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); countOccurrences(got, "FOO=") != 1 {
+		t.Errorf("expected a single FOO= line after updating, got:\n%s", got)
+	}
+}
+
+func TestUnsetSandboxEnvVar(t *testing.T) {
+	root := t.TempDir()
+	if err := SetSandboxEnvVar(root, "FOO", "1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := UnsetSandboxEnvVar(root, "FOO")
+	if err != nil {
+		t.Fatalf("UnsetSandboxEnvVar: %v", err)
+	}
+	if !found {
+		t.Error("expected UnsetSandboxEnvVar to report the key was found")
+	}
+
+	loaded, _, err := LoadSandboxEnv(root)
+	if err != nil {
+		t.Fatalf("LoadSandboxEnv: %v", err)
+	}
+	if _, ok := loaded["FOO"]; ok {
+		t.Errorf("expected FOO to be removed, still loaded: %v", loaded)
+	}
+
+	found, err = UnsetSandboxEnvVar(root, "NOPE")
+	if err != nil {
+		t.Fatalf("UnsetSandboxEnvVar: %v", err)
+	}
+	if found {
+		t.Error("expected UnsetSandboxEnvVar to report a missing key as not found")
+	}
+}
+
+func TestSandboxEnvPath(t *testing.T) {
+	root := "/tmp/example-sandbox"
+	want := filepath.Join(root, "env")
+	if got := SandboxEnvPath(root); got != want {
+		t.Errorf("SandboxEnvPath(%q) = %q, want %q", root, got, want)
+	}
+}
+
+func countOccurrences(s, substr string) int {
+	count := 0
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,195 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// seccompDefaultBlocked lists syscalls blocked by SeccompProfileDefault:
+// ones with no legitimate use in mise/git/node workflows but that could let
+// a sandboxed process interfere with other processes, the kernel, or the
+// host's storage.
+var seccompDefaultBlocked = []uintptr{
+	unix.SYS_PTRACE,
+	unix.SYS_KEYCTL,
+	unix.SYS_ADD_KEY,
+	unix.SYS_REQUEST_KEY,
+	unix.SYS_MOUNT,
+	unix.SYS_UMOUNT2,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_REBOOT,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_SWAPON,
+	unix.SYS_SWAPOFF,
+	unix.SYS_BPF,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_ACCT,
+	unix.SYS_QUOTACTL,
+}
+
+// seccompStrictExtraBlocked lists syscalls additionally blocked by
+// SeccompProfileStrict: more often abused for process introspection or
+// namespace escapes than needed by ordinary dev tooling.
+var seccompStrictExtraBlocked = []uintptr{
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_USERFAULTFD,
+	unix.SYS_PERSONALITY,
+	unix.SYS_UNSHARE,
+	unix.SYS_SETNS,
+}
+
+// Seccomp compiles the named profile ("default", "strict", or "none") into a
+// classic BPF program, writes it to a temp file, and adds the --seccomp
+// argument bwrap needs to load it.
+//
+// The --seccomp value assumes the compiled filter's file descriptor is
+// passed to bwrap as the child's sole extra file descriptor, landing at fd 3
+// (stdin/stdout/stderr occupy 0-2) - see Builder.SeccompFile. Callers that
+// add other inherited file descriptors before it must adjust accordingly.
+func (b *Builder) Seccomp(profile SeccompProfile) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if profile == "" {
+		profile = SeccompProfileDefault
+	}
+	if profile == SeccompProfileNone {
+		return b
+	}
+
+	prog, err := compileSeccompProfile(profile)
+	if err != nil {
+		b.err = fmt.Errorf("failed to compile seccomp profile %q: %w", profile, err)
+		return b
+	}
+
+	f, err := os.CreateTemp("", "devsandbox-seccomp-*.bpf")
+	if err != nil {
+		b.err = fmt.Errorf("failed to create seccomp filter file: %w", err)
+		return b
+	}
+	if _, err := f.Write(prog); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		b.err = fmt.Errorf("failed to write seccomp filter file: %w", err)
+		return b
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		b.err = fmt.Errorf("failed to rewind seccomp filter file: %w", err)
+		return b
+	}
+	// The file is unlinked immediately; bwrap only needs the fd, not the
+	// path, and we don't want a stray temp file left behind on exit.
+	os.Remove(f.Name())
+
+	b.seccompFile = f
+	b.add("--seccomp", "3")
+	return b
+}
+
+// seccompDataArchOffset is the offset of the "arch" field (an AUDIT_ARCH_*
+// constant identifying the syscall ABI in use) within struct seccomp_data,
+// right after the 32-bit "nr" field at offset 0.
+const seccompDataArchOffset = 4
+
+// nativeAuditArch returns the AUDIT_ARCH_* constant for the ABI devsandbox
+// itself was built for. A seccomp filter that only checks "nr" assumes
+// every syscall arrives using that ABI's numbering - but on x86_64, a
+// process can invoke the legacy 32-bit or x32 ABI instead (e.g. via
+// int 0x80), whose syscall numbers don't line up with seccompDefaultBlocked
+// at all, letting a blocked syscall like ptrace or mount through under a
+// different number. Checking arch first and rejecting anything but the
+// native one closes that off, matching how runc/Docker's default profile
+// guards against compat-ABI bypasses.
+func nativeAuditArch() (uint32, error) {
+	switch runtime.GOARCH {
+	case "amd64":
+		return unix.AUDIT_ARCH_X86_64, nil
+	case "arm64":
+		return unix.AUDIT_ARCH_AARCH64, nil
+	default:
+		return 0, fmt.Errorf("seccomp: unsupported GOARCH %q", runtime.GOARCH)
+	}
+}
+
+// compileSeccompProfile builds a classic BPF program that kills the process
+// on any syscall ABI other than the one devsandbox was built for, returns
+// SECCOMP_RET_ERRNO(EPERM) for every syscall in the named profile's
+// blocklist, and SECCOMP_RET_ALLOW otherwise.
+func compileSeccompProfile(profile SeccompProfile) ([]byte, error) {
+	blocked := append([]uintptr{}, seccompDefaultBlocked...)
+	switch profile {
+	case SeccompProfileDefault:
+		// blocked is already the default list
+	case SeccompProfileStrict:
+		blocked = append(blocked, seccompStrictExtraBlocked...)
+	default:
+		return nil, fmt.Errorf("unknown seccomp profile %q", profile)
+	}
+
+	arch, err := nativeAuditArch()
+	if err != nil {
+		return nil, err
+	}
+
+	errnoAction := uint32(unix.SECCOMP_RET_ERRNO) | (uint32(unix.EPERM) & uint32(unix.SECCOMP_RET_DATA))
+
+	// Load arch first: if it doesn't match the ABI devsandbox was built
+	// for, kill the process outright rather than falling through to the
+	// nr checks below, which are meaningless for a different ABI's
+	// syscall numbering.
+	prog := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArchOffset),
+	}
+	// If arch matches, skip the kill (jt=1) and fall into the nr checks.
+	// Otherwise (jf=0) fall through to RET_KILL_PROCESS immediately.
+	prog = append(prog, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0))
+	prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, uint32(unix.SECCOMP_RET_KILL_PROCESS)))
+
+	// Load the syscall number (first field of struct seccomp_data, offset 0).
+	prog = append(prog, bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0))
+	for _, nr := range blocked {
+		// If nr matches, fall through (jt=0) to the RET_ERRNO below.
+		// Otherwise skip it (jf=1) and move on to the next check.
+		prog = append(prog, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(nr), 0, 1))
+		prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, errnoAction))
+	}
+	prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, uint32(unix.SECCOMP_RET_ALLOW)))
+
+	return marshalSockFilter(prog), nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+// marshalSockFilter encodes a classic BPF program as the kernel expects it:
+// a tightly packed array of "struct sock_filter { u16 code; u8 jt; u8 jf;
+// u32 k; }", native (little-endian on every arch devsandbox builds for).
+func marshalSockFilter(prog []unix.SockFilter) []byte {
+	buf := make([]byte, len(prog)*8)
+	for i, f := range prog {
+		b := buf[i*8 : i*8+8]
+		binary.LittleEndian.PutUint16(b[0:2], f.Code)
+		b[2] = f.Jt
+		b[3] = f.Jf
+		binary.LittleEndian.PutUint32(b[4:8], f.K)
+	}
+	return buf
+}
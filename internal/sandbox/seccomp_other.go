@@ -0,0 +1,9 @@
+//go:build !linux
+
+package sandbox
+
+// Seccomp is a no-op outside Linux: bwrap and seccomp are both Linux-only,
+// so there's nothing to compile a filter for here.
+func (b *Builder) Seccomp(_ SeccompProfile) *Builder {
+	return b
+}
@@ -0,0 +1,135 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCompileSeccompProfile_BlocksPtrace(t *testing.T) {
+	prog, err := compileSeccompProfile(SeccompProfileDefault)
+	if err != nil {
+		t.Fatalf("compileSeccompProfile() failed: %v", err)
+	}
+	if len(prog)%8 != 0 {
+		t.Fatalf("compiled program length %d is not a multiple of sock_filter size (8)", len(prog))
+	}
+	if len(prog) == 0 {
+		t.Fatal("compiled program is empty")
+	}
+}
+
+func TestCompileSeccompProfile_StrictBlocksMore(t *testing.T) {
+	def, err := compileSeccompProfile(SeccompProfileDefault)
+	if err != nil {
+		t.Fatalf("compileSeccompProfile(default) failed: %v", err)
+	}
+	strict, err := compileSeccompProfile(SeccompProfileStrict)
+	if err != nil {
+		t.Fatalf("compileSeccompProfile(strict) failed: %v", err)
+	}
+	if len(strict) <= len(def) {
+		t.Errorf("expected strict profile to block more syscalls than default: default=%d bytes, strict=%d bytes", len(def), len(strict))
+	}
+}
+
+func TestCompileSeccompProfile_UnknownProfile(t *testing.T) {
+	if _, err := compileSeccompProfile("bogus"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestCompileSeccompProfile_GuardsArch(t *testing.T) {
+	prog, err := compileSeccompProfile(SeccompProfileDefault)
+	if err != nil {
+		t.Fatalf("compileSeccompProfile() failed: %v", err)
+	}
+
+	// The first instruction must load the arch field, not nr, so a
+	// syscall made via a different ABI (e.g. the x86_64 host's 32-bit
+	// compat syscalls) can't reach the nr checks with numbers that mean
+	// something else entirely.
+	wantFirst := bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArchOffset)
+	first := marshalSockFilter([]unix.SockFilter{wantFirst})
+	if string(prog[:8]) != string(first) {
+		t.Errorf("expected first instruction to load the arch field at offset %d", seccompDataArchOffset)
+	}
+
+	// The second instruction must kill the process on an arch mismatch.
+	killAction := uint32(unix.SECCOMP_RET_KILL_PROCESS)
+	foundKill := false
+	for i := 1; i*8+8 <= len(prog); i++ {
+		k := uint32(prog[i*8+4]) | uint32(prog[i*8+5])<<8 | uint32(prog[i*8+6])<<16 | uint32(prog[i*8+7])<<24
+		code := uint16(prog[i*8]) | uint16(prog[i*8+1])<<8
+		if code == unix.BPF_RET|unix.BPF_K && k == killAction {
+			foundKill = true
+			break
+		}
+	}
+	if !foundKill {
+		t.Error("expected a RET_KILL_PROCESS instruction guarding the arch check")
+	}
+}
+
+func TestNativeAuditArch(t *testing.T) {
+	arch, err := nativeAuditArch()
+	if err != nil {
+		t.Fatalf("nativeAuditArch() failed on a supported GOARCH: %v", err)
+	}
+	if arch == 0 {
+		t.Error("expected a non-zero AUDIT_ARCH_* constant")
+	}
+}
+
+func TestMarshalSockFilter(t *testing.T) {
+	prog := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, 42, 0, 1),
+	}
+	buf := marshalSockFilter(prog)
+	if len(buf) != 16 {
+		t.Fatalf("expected 16 bytes for 2 instructions, got %d", len(buf))
+	}
+	// Second instruction's K field (bytes 12-15, little-endian) should be 42.
+	k := uint32(buf[12]) | uint32(buf[13])<<8 | uint32(buf[14])<<16 | uint32(buf[15])<<24
+	if k != 42 {
+		t.Errorf("expected K=42, got %d", k)
+	}
+}
+
+func TestBuilder_Seccomp_None(t *testing.T) {
+	b := NewBuilder(&Config{})
+	b.Seccomp(SeccompProfileNone)
+	if b.SeccompFile() != nil {
+		t.Error("Seccomp(none) should not produce a filter file")
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Seccomp(none) should not error: %v", err)
+	}
+}
+
+func TestBuilder_Seccomp_Default(t *testing.T) {
+	b := NewBuilder(&Config{})
+	b.Seccomp(SeccompProfileDefault)
+	if err := b.Err(); err != nil {
+		t.Fatalf("Seccomp(default) failed: %v", err)
+	}
+	f := b.SeccompFile()
+	if f == nil {
+		t.Fatal("Seccomp(default) should produce a filter file")
+	}
+	defer f.Close()
+
+	found := false
+	args := b.Build()
+	for i, arg := range args {
+		if arg == "--seccomp" && i+1 < len(args) && args[i+1] == "3" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected --seccomp 3 in build args, got %v", args)
+	}
+}
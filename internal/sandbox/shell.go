@@ -5,9 +5,9 @@ import (
 	"strings"
 )
 
-// shellQuote quotes a string for safe use in a shell command.
+// ShellQuote quotes a string for safe use in a shell command.
 // Returns the string unchanged if it's safe, otherwise wraps in single quotes.
-func shellQuote(s string) string {
+func ShellQuote(s string) string {
 	// If the string is empty, return quoted empty string
 	if s == "" {
 		return "''"
@@ -32,67 +32,108 @@ func shellQuote(s string) string {
 	return "'" + escaped + "'"
 }
 
-// BuildShellCommand creates the command to run inside the sandbox
+// BuildShellCommand creates the command to run inside the sandbox. When
+// cfg.Fast is set and args are present, it skips straight to exec'ing the
+// requested command - no shell, no mise/tool/ShellInit injection - instead
+// of the usual `sh -c "<init>; <cmd>"` wrapper. Interactive invocations
+// (no args) always get the full init path, since there'd be nothing to
+// exec otherwise.
 func BuildShellCommand(cfg *Config, args []string) []string {
+	if cfg.Fast && len(args) > 0 {
+		return args
+	}
+
 	switch cfg.Shell {
 	case ShellFish:
 		return buildFishCommand(cfg, args)
 	case ShellZsh:
 		return buildZshCommand(cfg, args)
+	case ShellNu:
+		return buildNuCommand(cfg, args)
 	default:
 		return buildBashCommand(cfg, args)
 	}
 }
 
 func buildFishCommand(cfg *Config, args []string) []string {
-	miseActivation := "if command -q mise; mise activate fish | source; end"
+	init := joinShellParts(cfg.ToolsShellInit, cfg.DotfilesInstallInit, cfg.ShellInit)
 
 	if len(args) == 0 {
 		greeting := fmt.Sprintf(`set -gx fish_greeting "🔒 Sandbox: %s | .env blocked | No SSH/git push"`, cfg.ProjectName)
-		fishInit := miseActivation + "; " + greeting + "; exec fish"
+		fishInit := joinShellParts(init, greeting, "exec fish")
 		return []string{cfg.ShellPath, "-c", fishInit}
 	}
 
 	cmdString := shellJoinArgs(args)
-	fishCmd := miseActivation + "; " + cmdString
+	fishCmd := joinShellParts(init, cmdString)
 	return []string{cfg.ShellPath, "-c", fishCmd}
 }
 
 func buildBashCommand(cfg *Config, args []string) []string {
-	miseActivation := `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`
+	init := joinShellParts(cfg.ToolsShellInit, cfg.DotfilesInstallInit, cfg.ShellInit)
 
 	if len(args) == 0 {
 		// Set PS1 prompt with sandbox indicator
 		ps1 := fmt.Sprintf(`PS1="🔒 [%s] \w $ "`, cfg.ProjectName)
-		bashInit := miseActivation + "; " + ps1 + "; exec bash --norc --noprofile"
+		bashInit := joinShellParts(init, ps1, "exec bash --norc --noprofile")
 		return []string{cfg.ShellPath, "-c", bashInit}
 	}
 
 	cmdString := shellJoinArgs(args)
-	bashCmd := miseActivation + "; " + cmdString
+	bashCmd := joinShellParts(init, cmdString)
 	return []string{cfg.ShellPath, "-c", bashCmd}
 }
 
 func buildZshCommand(cfg *Config, args []string) []string {
-	miseActivation := `if command -v mise &>/dev/null; then eval "$(mise activate zsh)"; fi`
+	init := joinShellParts(cfg.ToolsShellInit, cfg.DotfilesInstallInit, cfg.ShellInit)
 
 	if len(args) == 0 {
 		// Set PROMPT with sandbox indicator
 		prompt := fmt.Sprintf(`PROMPT="🔒 [%s] %%~ $ "`, cfg.ProjectName)
-		zshInit := miseActivation + "; " + prompt + "; exec zsh --no-rcs"
+		zshInit := joinShellParts(init, prompt, "exec zsh --no-rcs")
 		return []string{cfg.ShellPath, "-c", zshInit}
 	}
 
 	cmdString := shellJoinArgs(args)
-	zshCmd := miseActivation + "; " + cmdString
+	zshCmd := joinShellParts(init, cmdString)
 	return []string{cfg.ShellPath, "-c", zshCmd}
 }
 
+func buildNuCommand(cfg *Config, args []string) []string {
+	init := joinShellParts(cfg.ToolsShellInit, cfg.DotfilesInstallInit, cfg.ShellInit)
+
+	if len(args) == 0 {
+		prompt := fmt.Sprintf(`$env.PROMPT_COMMAND = {|| $"🔒 [%s] (pwd) $ " }`, cfg.ProjectName)
+		nuInit := joinShellParts(init, prompt, "exec nu")
+		return []string{cfg.ShellPath, "-c", nuInit}
+	}
+
+	cmdString := shellJoinArgs(args)
+	nuCmd := joinShellParts(init, cmdString)
+	return []string{cfg.ShellPath, "-c", nuCmd}
+}
+
+// joinShellParts joins shell init snippets with "; ", dropping any empty
+// ones (an unset --shell-init, or a tool's ShellInit left empty because it
+// has no startup hook or is disabled) so they don't leave a stray leading or
+// doubled separator in the generated command. Each part is concatenated as
+// literal shell code in the target shell's syntax - there's nothing to
+// shell-quote here since these aren't data arguments.
+func joinShellParts(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "; ")
+}
+
 // shellJoinArgs joins arguments with proper shell quoting.
 func shellJoinArgs(args []string) string {
 	quoted := make([]string, len(args))
 	for i, arg := range args {
-		quoted[i] = shellQuote(arg)
+		quoted[i] = ShellQuote(arg)
 	}
 	return strings.Join(quoted, " ")
 }
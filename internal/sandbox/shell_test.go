@@ -1,15 +1,17 @@
 package sandbox
 
 import (
+	"os/exec"
 	"strings"
 	"testing"
 )
 
 func TestBuildShellCommand_Fish_Interactive(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellFish,
-		ShellPath:   "/usr/bin/fish",
+		ProjectName:    "testproject",
+		Shell:          ShellFish,
+		ShellPath:      "/usr/bin/fish",
+		ToolsShellInit: "if command -q mise; mise activate fish | source; end",
 	}
 
 	cmd := BuildShellCommand(cfg, []string{})
@@ -41,9 +43,10 @@ func TestBuildShellCommand_Fish_Interactive(t *testing.T) {
 
 func TestBuildShellCommand_Fish_SingleCommand(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellFish,
-		ShellPath:   "/usr/bin/fish",
+		ProjectName:    "testproject",
+		Shell:          ShellFish,
+		ShellPath:      "/usr/bin/fish",
+		ToolsShellInit: "if command -q mise; mise activate fish | source; end",
 	}
 
 	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
@@ -71,9 +74,10 @@ func TestBuildShellCommand_Fish_SingleCommand(t *testing.T) {
 
 func TestBuildShellCommand_Bash_Interactive(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellBash,
-		ShellPath:   "/bin/bash",
+		ProjectName:    "testproject",
+		Shell:          ShellBash,
+		ShellPath:      "/bin/bash",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`,
 	}
 
 	cmd := BuildShellCommand(cfg, []string{})
@@ -101,9 +105,10 @@ func TestBuildShellCommand_Bash_Interactive(t *testing.T) {
 
 func TestBuildShellCommand_Bash_SingleCommand(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellBash,
-		ShellPath:   "/bin/bash",
+		ProjectName:    "testproject",
+		Shell:          ShellBash,
+		ShellPath:      "/bin/bash",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`,
 	}
 
 	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
@@ -123,9 +128,10 @@ func TestBuildShellCommand_Bash_SingleCommand(t *testing.T) {
 
 func TestBuildShellCommand_Zsh_Interactive(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellZsh,
-		ShellPath:   "/usr/bin/zsh",
+		ProjectName:    "testproject",
+		Shell:          ShellZsh,
+		ShellPath:      "/usr/bin/zsh",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate zsh)"; fi`,
 	}
 
 	cmd := BuildShellCommand(cfg, []string{})
@@ -153,9 +159,10 @@ func TestBuildShellCommand_Zsh_Interactive(t *testing.T) {
 
 func TestBuildShellCommand_Zsh_SingleCommand(t *testing.T) {
 	cfg := &Config{
-		ProjectName: "testproject",
-		Shell:       ShellZsh,
-		ShellPath:   "/usr/bin/zsh",
+		ProjectName:    "testproject",
+		Shell:          ShellZsh,
+		ShellPath:      "/usr/bin/zsh",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate zsh)"; fi`,
 	}
 
 	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
@@ -173,6 +180,168 @@ func TestBuildShellCommand_Zsh_SingleCommand(t *testing.T) {
 	}
 }
 
+func TestBuildShellCommand_Nu_Interactive(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellNu,
+		ShellPath:      "/usr/bin/nu",
+		ToolsShellInit: `if (which mise | is-not-empty) { mise activate nu | save -f ($env.HOME + "/.cache/mise-activate.nu"); source ($env.HOME + "/.cache/mise-activate.nu") }`,
+	}
+
+	cmd := BuildShellCommand(cfg, []string{})
+
+	if len(cmd) != 3 {
+		t.Fatalf("Expected 3 elements, got %d: %v", len(cmd), cmd)
+	}
+
+	if cmd[0] != "/usr/bin/nu" {
+		t.Errorf("Expected nu shell, got %s", cmd[0])
+	}
+
+	if !strings.Contains(cmd[2], "mise activate nu") {
+		t.Error("Expected mise activation in command")
+	}
+
+	if !strings.Contains(cmd[2], "PROMPT_COMMAND") {
+		t.Error("Expected PROMPT_COMMAND in interactive mode")
+	}
+
+	if !strings.Contains(cmd[2], "exec nu") {
+		t.Error("Expected exec nu in interactive mode")
+	}
+}
+
+func TestBuildShellCommand_Nu_SingleCommand(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellNu,
+		ShellPath:      "/usr/bin/nu",
+		ToolsShellInit: `if (which mise | is-not-empty) { mise activate nu | save -f ($env.HOME + "/.cache/mise-activate.nu"); source ($env.HOME + "/.cache/mise-activate.nu") }`,
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if cmd[0] != "/usr/bin/nu" {
+		t.Errorf("Expected nu shell, got %s", cmd[0])
+	}
+
+	if cmd[1] != "-c" {
+		t.Errorf("Expected -c flag, got %s", cmd[1])
+	}
+
+	if !strings.Contains(cmd[2], "mise activate nu") {
+		t.Error("Expected mise activation in command")
+	}
+
+	if !strings.Contains(cmd[2], "npm install") {
+		t.Error("Expected 'npm install' in command")
+	}
+}
+
+func TestBuildShellCommand_Fish_ShellInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellFish,
+		ShellPath:      "/usr/bin/fish",
+		ToolsShellInit: "if command -q mise; mise activate fish | source; end",
+		ShellInit:      "source ./.venv/bin/activate.fish",
+	}
+
+	cmd := BuildShellCommand(cfg, []string{})
+
+	if !strings.Contains(cmd[2], "mise activate fish | source; end; source ./.venv/bin/activate.fish; set -gx fish_greeting") {
+		t.Errorf("expected shell init to run after mise activation and before the greeting, got: %s", cmd[2])
+	}
+}
+
+func TestBuildShellCommand_Bash_ShellInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellBash,
+		ShellPath:      "/bin/bash",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`,
+		ShellInit:      "source ./.venv/bin/activate",
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if !strings.Contains(cmd[2], `fi; source ./.venv/bin/activate; npm install`) {
+		t.Errorf("expected shell init to run after mise activation and before the command, got: %s", cmd[2])
+	}
+}
+
+func TestBuildShellCommand_Zsh_ShellInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellZsh,
+		ShellPath:      "/usr/bin/zsh",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate zsh)"; fi`,
+		ShellInit:      "source ./.venv/bin/activate",
+	}
+
+	cmd := BuildShellCommand(cfg, []string{})
+
+	if !strings.Contains(cmd[2], `fi; source ./.venv/bin/activate; PROMPT=`) {
+		t.Errorf("expected shell init to run after mise activation and before the prompt, got: %s", cmd[2])
+	}
+}
+
+func TestBuildShellCommand_Bash_DotfilesInstallInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName:         "testproject",
+		Shell:               ShellBash,
+		ShellPath:           "/bin/bash",
+		ToolsShellInit:      `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`,
+		DotfilesInstallInit: "bash /home/sandbox/install.sh",
+		ShellInit:           "source ./.venv/bin/activate",
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if !strings.Contains(cmd[2], `fi; bash /home/sandbox/install.sh; source ./.venv/bin/activate; npm install`) {
+		t.Errorf("expected dotfiles install to run after mise activation and before ShellInit, got: %s", cmd[2])
+	}
+}
+
+func TestBuildShellCommand_NoShellInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellBash,
+		ShellPath:      "/bin/bash",
+		ToolsShellInit: `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`,
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if strings.Contains(cmd[2], "  ;") || strings.HasPrefix(cmd[2], ";") {
+		t.Errorf("expected no stray separator when ShellInit is empty, got: %s", cmd[2])
+	}
+}
+
+// TestBuildShellCommand_NoToolsShellInit confirms that leaving ToolsShellInit
+// unset (no tools available, or all disabled via config) doesn't inject any
+// tool activation or leave a stray separator - see Builder.AddTools, which
+// populates ToolsShellInit by aggregating each available tool's ShellInit.
+func TestBuildShellCommand_NoToolsShellInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName: "testproject",
+		Shell:       ShellBash,
+		ShellPath:   "/bin/bash",
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if strings.Contains(cmd[2], "mise activate") {
+		t.Errorf("expected no mise activation when ToolsShellInit is unset, got: %s", cmd[2])
+	}
+	if strings.HasPrefix(cmd[2], ";") {
+		t.Errorf("expected no stray separator when ToolsShellInit is empty, got: %s", cmd[2])
+	}
+	if cmd[2] != "npm install" {
+		t.Errorf("expected bare command with nothing enabled, got: %s", cmd[2])
+	}
+}
+
 func TestDetectShell(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -204,6 +373,12 @@ func TestDetectShell(t *testing.T) {
 			expectedShell: ShellZsh,
 			expectedPath:  "/bin/zsh",
 		},
+		{
+			name:          "nu shell",
+			shellEnv:      "/usr/bin/nu",
+			expectedShell: ShellNu,
+			expectedPath:  "/usr/bin/nu",
+		},
 	}
 
 	for _, tt := range tests {
@@ -220,6 +395,58 @@ func TestDetectShell(t *testing.T) {
 	}
 }
 
+func TestResolveShellOverride(t *testing.T) {
+	tests := []struct {
+		name          string
+		override      string
+		expectedShell Shell
+	}{
+		{"bash", "bash", ShellBash},
+		{"zsh", "zsh", ShellZsh},
+		{"fish", "fish", ShellFish},
+		{"nu", "nu", ShellNu},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := exec.LookPath(tt.override); err != nil {
+				t.Skipf("%s not installed in test environment", tt.override)
+			}
+
+			shell, path, err := ResolveShellOverride(tt.override)
+			if err != nil {
+				t.Fatalf("ResolveShellOverride(%q) error: %v", tt.override, err)
+			}
+			if shell != tt.expectedShell {
+				t.Errorf("ResolveShellOverride(%q) shell = %v, want %v", tt.override, shell, tt.expectedShell)
+			}
+			if path == "" {
+				t.Errorf("ResolveShellOverride(%q) returned empty path", tt.override)
+			}
+		})
+	}
+}
+
+func TestResolveShellOverride_Invalid(t *testing.T) {
+	if _, _, err := ResolveShellOverride("tcsh"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestResolveShellOverride_NotInstalled(t *testing.T) {
+	if _, err := exec.LookPath("nu"); err == nil {
+		t.Skip("nu unexpectedly found in PATH")
+	}
+
+	_, _, err := ResolveShellOverride("nu")
+	if err == nil {
+		t.Fatal("expected an error for a supported but uninstalled shell")
+	}
+	if !strings.Contains(err.Error(), "not found in PATH") {
+		t.Errorf("expected a PATH-not-found error, got: %v", err)
+	}
+}
+
 func TestShellQuote(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -257,9 +484,9 @@ func TestShellQuote(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := shellQuote(tt.input)
+			result := ShellQuote(tt.input)
 			if result != tt.expected {
-				t.Errorf("shellQuote(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("ShellQuote(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -322,3 +549,37 @@ func TestBuildShellCommand_QuotedArgs(t *testing.T) {
 		t.Errorf("Expected quoted message in command, got: %s", cmd[2])
 	}
 }
+
+func TestBuildShellCommand_Fast_WithArgs(t *testing.T) {
+	cfg := &Config{
+		ProjectName:    "testproject",
+		Shell:          ShellBash,
+		ShellPath:      "/bin/bash",
+		ToolsShellInit: "if command -q mise; mise activate bash; end",
+		Fast:           true,
+	}
+
+	cmd := BuildShellCommand(cfg, []string{"npm", "install"})
+
+	if len(cmd) != 2 || cmd[0] != "npm" || cmd[1] != "install" {
+		t.Errorf("expected --fast to exec the command directly with no shell wrapper, got: %v", cmd)
+	}
+}
+
+func TestBuildShellCommand_Fast_NoArgsFallsBackToFullInit(t *testing.T) {
+	cfg := &Config{
+		ProjectName: "testproject",
+		Shell:       ShellBash,
+		ShellPath:   "/bin/bash",
+		Fast:        true,
+	}
+
+	cmd := BuildShellCommand(cfg, []string{})
+
+	if len(cmd) != 3 || cmd[0] != "/bin/bash" || cmd[1] != "-c" {
+		t.Errorf("expected --fast with no args to fall back to the full interactive init, got: %v", cmd)
+	}
+	if !strings.Contains(cmd[2], "exec bash") {
+		t.Error("expected interactive init to still exec bash")
+	}
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+func init() {
+	Register(&Atuin{})
+}
+
+// Atuin provides atuin shell-history integration. History search and sync
+// config come from the host's ~/.config/atuin, bound read-only, but the
+// history database itself is redirected to a sandbox-local path via
+// ATUIN_DB_PATH - sandbox commands must never be recorded into the host's
+// atuin history.
+type Atuin struct{}
+
+func (a *Atuin) Name() string {
+	return "atuin"
+}
+
+func (a *Atuin) Description() string {
+	return "Atuin shell history with sandbox-isolated history database"
+}
+
+func (a *Atuin) Available(homeDir string) bool {
+	_, err := exec.LookPath("atuin")
+	return err == nil
+}
+
+func (a *Atuin) Bindings(homeDir, sandboxHome string) []Binding {
+	return []Binding{
+		{
+			Source:   filepath.Join(homeDir, ".config", "atuin"),
+			ReadOnly: true,
+			Optional: true,
+		},
+	}
+}
+
+func (a *Atuin) Environment(homeDir, sandboxHome string) []EnvVar {
+	return []EnvVar{
+		// Keep the sandbox's command history out of the host's atuin database.
+		{Name: "ATUIN_DB_PATH", Value: filepath.Join(sandboxHome, ".local", "share", "atuin", "history.db")},
+	}
+}
+
+func (a *Atuin) ShellInit(shell string) string {
+	switch shell {
+	case "fish":
+		return `if command -q atuin; atuin init fish | source; end`
+	case "zsh":
+		return `if command -v atuin &>/dev/null; then eval "$(atuin init zsh)"; fi`
+	case "bash":
+		return `if command -v atuin &>/dev/null; then eval "$(atuin init bash)"; fi`
+	default:
+		return fmt.Sprintf(`if command -v atuin &>/dev/null; then eval "$(atuin init %s)"; fi`, shell)
+	}
+}
+
+func (a *Atuin) Check(homeDir string) CheckResult {
+	result := CheckBinary("atuin", "https://docs.atuin.sh/guide/installation/")
+	if !result.Available {
+		return result
+	}
+
+	result.AddConfigPaths(
+		filepath.Join(homeDir, ".config", "atuin"),
+	)
+
+	result.AddInfo("sandbox history is isolated from the host atuin database")
+
+	return result
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAtuin_ShellInit(t *testing.T) {
+	a := &Atuin{}
+
+	tests := map[string]string{
+		"bash": "atuin init bash",
+		"zsh":  "atuin init zsh",
+		"fish": "atuin init fish",
+	}
+	for shell, want := range tests {
+		if got := a.ShellInit(shell); !strings.Contains(got, want) {
+			t.Errorf("ShellInit(%q) = %q, want it to contain %q", shell, got, want)
+		}
+	}
+}
+
+func TestAtuin_Environment_IsolatesHistoryFromHost(t *testing.T) {
+	a := &Atuin{}
+
+	home := "/home/testuser"
+	sandboxHome := "/tmp/sandbox"
+	envVars := a.Environment(home, sandboxHome)
+
+	found := false
+	for _, e := range envVars {
+		if e.Name != "ATUIN_DB_PATH" {
+			continue
+		}
+		found = true
+		if strings.HasPrefix(e.Value, home) {
+			t.Errorf("ATUIN_DB_PATH = %q, should not live under the host home", e.Value)
+		}
+		if !strings.HasPrefix(e.Value, sandboxHome) {
+			t.Errorf("ATUIN_DB_PATH = %q, want it under sandboxHome %q", e.Value, sandboxHome)
+		}
+	}
+	if !found {
+		t.Error("Environment() missing ATUIN_DB_PATH")
+	}
+}
+
+func TestAtuin_Check_ConfigPaths(t *testing.T) {
+	a := &Atuin{}
+
+	result := a.Check(t.TempDir())
+
+	if result.BinaryName != "atuin" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "atuin")
+	}
+}
@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(&Cargo{})
+}
+
+// Cargo provides isolation for the Rust cargo package manager. The
+// registry's index and download cache are read-only shared from the host
+// so dependencies don't re-download every run - they're content-addressed
+// and safe to share - while the mutable registry/src (extracted crate
+// sources) and build artifacts are isolated to the sandbox.
+type Cargo struct{}
+
+func (c *Cargo) Name() string {
+	return "cargo"
+}
+
+func (c *Cargo) Description() string {
+	return "Rust package manager isolation (cargo registry cache, safe config)"
+}
+
+func (c *Cargo) Available(homeDir string) bool {
+	_, err := exec.LookPath("cargo")
+	return err == nil
+}
+
+func (c *Cargo) Bindings(homeDir, sandboxHome string) []Binding {
+	return []Binding{
+		// Sanitized cargo config - always read-only.
+		{
+			Source:   filepath.Join(sandboxHome, ".cargo-config.toml.safe"),
+			Dest:     filepath.Join(homeDir, ".cargo", "config.toml"),
+			ReadOnly: true,
+			Optional: true,
+		},
+		// Registry index - content-addressed package metadata, safe to
+		// share read-only.
+		{
+			Source:   filepath.Join(homeDir, ".cargo", "registry", "index"),
+			ReadOnly: true,
+			Optional: true,
+		},
+		// Registry download cache (.crate files) - also content-addressed
+		// and safe to share read-only.
+		{
+			Source:   filepath.Join(homeDir, ".cargo", "registry", "cache"),
+			ReadOnly: true,
+			Optional: true,
+		},
+		// Registry src - extracted crate sources, written to by cargo on
+		// every build. Isolated to the sandbox so writes never touch the
+		// host's registry.
+		{
+			Source: filepath.Join(sandboxHome, ".cargo", "registry", "src"),
+			Dest:   filepath.Join(homeDir, ".cargo", "registry", "src"),
+		},
+	}
+}
+
+func (c *Cargo) Environment(homeDir, sandboxHome string) []EnvVar {
+	return []EnvVar{
+		// Build artifacts - isolated to the sandbox so they never touch
+		// the host, and so builds for different sandboxes don't collide.
+		{Name: "CARGO_TARGET_DIR", Value: filepath.Join(sandboxHome, ".cargo", "target")},
+	}
+}
+
+func (c *Cargo) ShellInit(shell string) string {
+	return ""
+}
+
+// Setup implements ToolWithSetup to generate the sanitized cargo config.
+func (c *Cargo) Setup(homeDir, sandboxHome string) error {
+	srcPath := filepath.Join(homeDir, ".cargo", "config.toml")
+	safePath := filepath.Join(sandboxHome, ".cargo-config.toml.safe")
+
+	srcInfo, err := os.Stat(srcPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if dstInfo, err := os.Stat(safePath); err == nil && dstInfo.ModTime().After(srcInfo.ModTime()) {
+		return nil // Safe config is up to date
+	}
+
+	return generateSafeCargoConfig(srcPath, safePath)
+}
+
+// generateSafeCargoConfig copies src to dst with any "token" key dropped,
+// wherever it appears - registry credentials live under both [registry]
+// and [registries.<name>] sections as a plain "token = ..." assignment,
+// so matching on the key alone (rather than the section) catches both.
+func generateSafeCargoConfig(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var out strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if cargoConfigLineIsSensitive(line) {
+			continue
+		}
+		out.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, []byte(out.String()), 0o644)
+}
+
+// cargoConfigLineIsSensitive reports whether a cargo config.toml line
+// assigns a "token" key, the key cargo's registry credentials are stored
+// under (e.g. "token = \"...\"" in [registry] or [registries.NAME]).
+func cargoConfigLineIsSensitive(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+
+	key, _, ok := strings.Cut(trimmed, "=")
+	if !ok {
+		return false
+	}
+
+	return strings.TrimSpace(key) == "token"
+}
+
+func (c *Cargo) Check(homeDir string) CheckResult {
+	result := CheckResult{
+		BinaryName:  "cargo",
+		InstallHint: "mise install rust",
+	}
+
+	path, err := exec.LookPath("cargo")
+	if err == nil {
+		result.BinaryPath = path
+	}
+
+	cargoHome := os.Getenv("CARGO_HOME")
+	if cargoHome == "" {
+		cargoHome = filepath.Join(homeDir, ".cargo")
+	}
+	result.AddInfo("CARGO_HOME: " + cargoHome)
+	result.AddConfigPath(filepath.Join(homeDir, ".cargo", "config.toml"))
+
+	result.Available = result.BinaryPath != ""
+	if !result.Available {
+		result.AddIssue("cargo binary not found in PATH")
+	}
+
+	return result
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCargo_Bindings(t *testing.T) {
+	c := &Cargo{}
+
+	bindings := c.Bindings("/home/testuser", "/tmp/sandbox")
+
+	var foundConfig, foundIndex, foundCache, foundSrc bool
+	for _, b := range bindings {
+		switch b.Source {
+		case filepath.Join("/tmp/sandbox", ".cargo-config.toml.safe"):
+			foundConfig = true
+			if !b.ReadOnly {
+				t.Error("cargo config binding should be read-only")
+			}
+			wantDest := filepath.Join("/home/testuser", ".cargo", "config.toml")
+			if b.Dest != wantDest {
+				t.Errorf("cargo config binding Dest = %q, want %q", b.Dest, wantDest)
+			}
+		case filepath.Join("/home/testuser", ".cargo", "registry", "index"):
+			foundIndex = true
+			if !b.ReadOnly {
+				t.Error("registry index binding should be read-only")
+			}
+		case filepath.Join("/home/testuser", ".cargo", "registry", "cache"):
+			foundCache = true
+			if !b.ReadOnly {
+				t.Error("registry cache binding should be read-only")
+			}
+		case filepath.Join("/tmp/sandbox", ".cargo", "registry", "src"):
+			foundSrc = true
+			if b.ReadOnly {
+				t.Error("registry src binding should be writable")
+			}
+			wantDest := filepath.Join("/home/testuser", ".cargo", "registry", "src")
+			if b.Dest != wantDest {
+				t.Errorf("registry src binding Dest = %q, want %q", b.Dest, wantDest)
+			}
+		}
+	}
+
+	if !foundConfig {
+		t.Error("missing sanitized cargo config binding")
+	}
+	if !foundIndex {
+		t.Error("missing registry index binding")
+	}
+	if !foundCache {
+		t.Error("missing registry cache binding")
+	}
+	if !foundSrc {
+		t.Error("missing sandbox-local registry src binding")
+	}
+}
+
+func TestCargo_Environment(t *testing.T) {
+	c := &Cargo{}
+
+	envVars := c.Environment("/home/testuser", "/tmp/sandbox")
+
+	want := filepath.Join("/tmp/sandbox", ".cargo", "target")
+	found := false
+	for _, env := range envVars {
+		if env.Name == "CARGO_TARGET_DIR" {
+			found = true
+			if env.Value != want {
+				t.Errorf("CARGO_TARGET_DIR = %q, want %q", env.Value, want)
+			}
+		}
+	}
+	if !found {
+		t.Error("Environment() missing CARGO_TARGET_DIR")
+	}
+}
+
+func TestCargo_Setup_StripsTokens(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(filepath.Join(homeDir, ".cargo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	configToml := filepath.Join(homeDir, ".cargo", "config.toml")
+	content := `[registry]
+token = "super-secret-token"
+
+[registries.my-registry]
+index = "sparse+https://my-registry.example.com/"
+token = "another-secret"
+
+[source.crates-io]
+replace-with = "my-registry"
+
+[build]
+target-dir = "target"
+`
+	if err := os.WriteFile(configToml, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cargo{}
+	if err := c.Setup(homeDir, sandboxHome); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	safeConfig := filepath.Join(sandboxHome, ".cargo-config.toml.safe")
+	data, err := os.ReadFile(safeConfig)
+	if err != nil {
+		t.Fatalf("failed to read safe cargo config: %v", err)
+	}
+	safeContent := string(data)
+
+	if strings.Contains(safeContent, "super-secret-token") {
+		t.Error("safe cargo config should not contain the [registry] token")
+	}
+	if strings.Contains(safeContent, "another-secret") {
+		t.Error("safe cargo config should not contain the [registries.*] token")
+	}
+	if !strings.Contains(safeContent, "[registries.my-registry]") {
+		t.Error("safe cargo config should preserve the registries section")
+	}
+	if !strings.Contains(safeContent, "replace-with = \"my-registry\"") {
+		t.Error("safe cargo config should preserve unrelated settings")
+	}
+	if !strings.Contains(safeContent, "target-dir = \"target\"") {
+		t.Error("safe cargo config should preserve the build section")
+	}
+}
+
+func TestCargo_Setup_NoHostConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cargo{}
+	if err := c.Setup(homeDir, sandboxHome); err != nil {
+		t.Fatalf("Setup should be a no-op without a host cargo config: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".cargo-config.toml.safe")); !os.IsNotExist(err) {
+		t.Error("Setup should not create a safe config when there's no host cargo config")
+	}
+}
+
+func TestCargo_Check_ReportsCargoHome(t *testing.T) {
+	homeDir := t.TempDir()
+
+	c := &Cargo{}
+	result := c.Check(homeDir)
+
+	if result.BinaryName != "cargo" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "cargo")
+	}
+
+	want := "CARGO_HOME: " + filepath.Join(homeDir, ".cargo")
+	found := false
+	for _, info := range result.Info {
+		if info == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Check() should report the effective CARGO_HOME, got Info = %v", result.Info)
+	}
+}
+
+func TestCargoConfigLineIsSensitive(t *testing.T) {
+	cases := []struct {
+		line string
+		want bool
+	}{
+		{`token = "abc123"`, true},
+		{`  token = "abc123"`, true},
+		{`index = "sparse+https://example.com/"`, false},
+		{`replace-with = "my-registry"`, false},
+		{"# a comment", false},
+		{"", false},
+		{"[registry]", false},
+	}
+
+	for _, c := range cases {
+		if got := cargoConfigLineIsSensitive(c.line); got != c.want {
+			t.Errorf("cargoConfigLineIsSensitive(%q) = %v, want %v", c.line, got, c.want)
+		}
+	}
+}
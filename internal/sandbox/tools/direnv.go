@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func init() {
+	Register(&Direnv{})
+}
+
+// Direnv provides direnv environment loader integration. By default its
+// project's .envrc is hidden the same way .env/.env.* files are (see
+// Builder.AddProjectBindings) - AllowEnvrc opts a specific project back in
+// via [tools.direnv] allow_envrc = true.
+type Direnv struct {
+	projectDir string
+	allowEnvrc bool
+}
+
+func (d *Direnv) Name() string {
+	return "direnv"
+}
+
+func (d *Direnv) Description() string {
+	return "direnv environment loader"
+}
+
+func (d *Direnv) Available(homeDir string) bool {
+	_, err := exec.LookPath("direnv")
+	return err == nil
+}
+
+// Configure implements ToolWithConfig.
+func (d *Direnv) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
+	d.projectDir = globalCfg.ProjectDir
+	d.allowEnvrc = false
+
+	if toolCfg == nil {
+		return
+	}
+	if v, ok := toolCfg["allow_envrc"].(bool); ok {
+		d.allowEnvrc = v
+	}
+}
+
+// AllowEnvrc reports whether this project's .envrc should be exempted from
+// the .env-style hiding Builder.AddProjectBindings applies. Read by the
+// builder, since hiding .envrc happens alongside the project bind rather
+// than through this tool's own Bindings.
+func (d *Direnv) AllowEnvrc() bool {
+	return d.allowEnvrc
+}
+
+func (d *Direnv) Bindings(homeDir, sandboxHome string) []Binding {
+	return []Binding{
+		{
+			Source:   filepath.Join(homeDir, ".config", "direnv"),
+			ReadOnly: true,
+			Optional: true,
+		},
+	}
+}
+
+func (d *Direnv) Environment(homeDir, sandboxHome string) []EnvVar {
+	return nil
+}
+
+func (d *Direnv) ShellInit(shell string) string {
+	switch shell {
+	case "fish":
+		return `if command -q direnv; eval (direnv hook fish); end`
+	case "zsh":
+		return `if command -v direnv &>/dev/null; then eval "$(direnv hook zsh)"; fi`
+	case "bash":
+		return `if command -v direnv &>/dev/null; then eval "$(direnv hook bash)"; fi`
+	default:
+		return fmt.Sprintf(`if command -v direnv &>/dev/null; then eval "$(direnv hook %s)"; fi`, shell)
+	}
+}
+
+func (d *Direnv) Check(homeDir string) CheckResult {
+	result := CheckBinary("direnv", "https://direnv.net/docs/installation.html")
+	if !result.Available {
+		return result
+	}
+
+	if d.allowEnvrc {
+		result.AddIssue("allow_envrc: true (.envrc is not hidden)")
+	} else {
+		result.AddIssue("allow_envrc: false (default; .envrc is hidden like .env)")
+	}
+
+	if d.projectDir == "" {
+		return result
+	}
+
+	envrcPath := filepath.Join(d.projectDir, ".envrc")
+	if _, err := os.Stat(envrcPath); err != nil {
+		result.AddInfo("no .envrc in project")
+		return result
+	}
+
+	if envrcIsTrusted(homeDir, envrcPath) {
+		result.AddInfo(".envrc found and trusted by direnv")
+	} else {
+		result.AddInfo(".envrc found but not yet trusted - run `direnv allow` on the host")
+	}
+
+	return result
+}
+
+// envrcIsTrusted reports whether direnv's allow database has marked envrcPath
+// as trusted, mirroring direnv's own on-disk layout: an allow file named by
+// the hex sha256 of the envrc's absolute path, under
+// $XDG_DATA_HOME/direnv/allow (defaulting to ~/.local/share/direnv/allow).
+func envrcIsTrusted(homeDir, envrcPath string) bool {
+	absPath, err := filepath.Abs(envrcPath)
+	if err != nil {
+		return false
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+
+	hash := sha256.Sum256([]byte(absPath))
+	allowFile := filepath.Join(dataHome, "direnv", "allow", hex.EncodeToString(hash[:]))
+
+	_, err = os.Stat(allowFile)
+	return err == nil
+}
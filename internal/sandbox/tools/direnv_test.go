@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirenv_Configure(t *testing.T) {
+	d := &Direnv{}
+	d.Configure(GlobalConfig{ProjectDir: "/tmp/proj"}, map[string]any{"allow_envrc": true})
+
+	if !d.AllowEnvrc() {
+		t.Error("expected AllowEnvrc() to be true")
+	}
+	if d.projectDir != "/tmp/proj" {
+		t.Errorf("projectDir = %q, want /tmp/proj", d.projectDir)
+	}
+}
+
+func TestDirenv_Configure_DefaultsToBlocked(t *testing.T) {
+	d := &Direnv{}
+	d.Configure(GlobalConfig{}, nil)
+
+	if d.AllowEnvrc() {
+		t.Error("expected AllowEnvrc() to default to false")
+	}
+}
+
+func TestDirenv_ShellInit(t *testing.T) {
+	d := &Direnv{}
+
+	tests := map[string]string{
+		"bash": "direnv hook bash",
+		"zsh":  "direnv hook zsh",
+		"fish": "direnv hook fish",
+	}
+	for shell, want := range tests {
+		if got := d.ShellInit(shell); !strings.Contains(got, want) {
+			t.Errorf("ShellInit(%q) = %q, want it to contain %q", shell, got, want)
+		}
+	}
+}
+
+func TestDirenv_Check_ConfigPaths(t *testing.T) {
+	d := &Direnv{}
+
+	result := d.Check(t.TempDir())
+
+	if result.BinaryName != "direnv" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "direnv")
+	}
+}
+
+func TestEnvrcIsTrusted(t *testing.T) {
+	home := t.TempDir()
+	dir := t.TempDir()
+	envrcPath := filepath.Join(dir, ".envrc")
+	if err := os.WriteFile(envrcPath, []byte("export FOO=bar\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if envrcIsTrusted(home, envrcPath) {
+		t.Error("expected an untrusted .envrc with no allow file")
+	}
+
+	absPath, err := filepath.Abs(envrcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte(absPath))
+	allowDir := filepath.Join(home, ".local", "share", "direnv", "allow")
+	if err := os.MkdirAll(allowDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	allowFile := filepath.Join(allowDir, hex.EncodeToString(hash[:]))
+	if err := os.WriteFile(allowFile, []byte(absPath), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !envrcIsTrusted(home, envrcPath) {
+		t.Error("expected a trusted .envrc once the allow file exists")
+	}
+}
@@ -20,6 +20,38 @@ const (
 	dockerSocketName         = "docker.sock"
 )
 
+// DockerMode controls how much access the sandbox gets to the host
+// container socket.
+type DockerMode string
+
+const (
+	// DockerModeDisabled gives the sandbox no container socket access. Default.
+	DockerModeDisabled DockerMode = "disabled"
+	// DockerModeReadOnlyInfo proxies the socket but only allows GET/HEAD
+	// requests (container/image/network listings, inspect, logs). No exec,
+	// no attach, no writes of any kind.
+	DockerModeReadOnlyInfo DockerMode = "readonly-info"
+	// DockerModeProxy proxies the socket through dockerproxy's default
+	// filter: GET/HEAD plus exec/attach into existing containers, but no
+	// container/image/network creation or deletion. This is the legacy
+	// behavior from the `enabled: true` config key.
+	DockerModeProxy DockerMode = "proxy"
+	// DockerModeFull binds the raw host socket into the sandbox with no
+	// filtering at all. Equivalent to handing the sandbox the keys to the
+	// Docker group on the host - it can do anything the daemon can.
+	DockerModeFull DockerMode = "full"
+)
+
+// podmanSocketPath returns the rootless Podman API socket path under
+// $XDG_RUNTIME_DIR, or "" if XDG_RUNTIME_DIR isn't set.
+func podmanSocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return ""
+	}
+	return filepath.Join(runtimeDir, "podman", "podman.sock")
+}
+
 // macOSDockerSocketCandidates returns ordered candidate paths for the Docker
 // socket on macOS. The order reflects popularity: Docker Desktop, OrbStack
 // (symlinks /var/run/docker.sock), then Colima.
@@ -54,13 +86,22 @@ func resolveDockerSocket(goos, homeDir, userSocket string) string {
 		}
 	}
 
+	if _, err := os.Stat(defaultLinuxDockerSocket); err == nil {
+		return defaultLinuxDockerSocket
+	}
+	if podmanSocket := podmanSocketPath(); podmanSocket != "" {
+		if _, err := os.Stat(podmanSocket); err == nil {
+			return podmanSocket
+		}
+	}
+
 	return defaultLinuxDockerSocket
 }
 
-// Docker provides filtered access to the Docker socket.
-// Only read operations and exec/attach are allowed.
+// Docker provides access to the host's Docker (or rootless Podman) socket,
+// at one of several trust levels controlled by DockerMode.
 type Docker struct {
-	enabled    bool
+	mode       DockerMode
 	hostSocket string
 	proxy      *dockerproxy.Proxy
 	logger     ErrorLogger
@@ -77,10 +118,16 @@ func (d *Docker) Name() string {
 }
 
 func (d *Docker) Description() string {
-	if d.enabled {
+	switch d.mode {
+	case DockerModeReadOnlyInfo:
+		return "Docker socket proxy (read-only)"
+	case DockerModeProxy:
 		return "Docker socket proxy (read-only + exec)"
+	case DockerModeFull:
+		return "Docker socket (full, unfiltered)"
+	default:
+		return "Docker socket proxy (disabled)"
 	}
-	return "Docker socket proxy (disabled)"
 }
 
 func (d *Docker) Available(homeDir string) bool {
@@ -100,43 +147,63 @@ func (d *Docker) socketPath(sandboxHome string) string {
 
 // Configure implements ToolWithConfig.
 func (d *Docker) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
-	d.enabled = false
+	d.mode = DockerModeDisabled
 	d.hostSocket = ""
 
 	// Parse user-provided socket first.
 	var userSocket string
 	if toolCfg != nil {
-		if enabled, ok := toolCfg["enabled"]; ok {
-			if b, ok := enabled.(bool); ok {
-				d.enabled = b
-			}
-		}
 		if socket, ok := toolCfg["socket"]; ok {
 			if s, ok := socket.(string); ok && s != "" {
 				userSocket = s
 			}
 		}
+
+		if mode, ok := toolCfg["mode"]; ok {
+			if m, ok := mode.(string); ok && m != "" {
+				d.mode = DockerMode(m)
+			}
+		} else if enabled, ok := toolCfg["enabled"]; ok {
+			// Legacy config key: `enabled: true` mapped to the original
+			// read + exec proxy behavior.
+			if b, ok := enabled.(bool); ok && b {
+				d.mode = DockerModeProxy
+			}
+		}
 	}
 
 	d.hostSocket = resolveDockerSocket(runtime.GOOS, globalCfg.HomeDir, userSocket)
 }
 
 func (d *Docker) Bindings(homeDir, sandboxHome string) []Binding {
-	// Docker tool uses proxy, socket is in sandboxHome which is already bound
-	return nil
+	if d.mode != DockerModeFull {
+		// Other modes go through the proxy; the listening socket lives in
+		// sandboxHome, which is already bound.
+		return nil
+	}
+
+	return []Binding{
+		{Source: d.hostSocket, ReadOnly: false},
+	}
 }
 
 func (d *Docker) Environment(homeDir, sandboxHome string) []EnvVar {
-	if !d.enabled {
+	switch d.mode {
+	case DockerModeDisabled:
 		return nil
-	}
-
-	// The socket is created at sandboxHome/docker.sock on the host,
-	// but sandboxHome is mounted at $HOME inside the sandbox.
-	// So we return $HOME/docker.sock as the path visible inside the sandbox.
-	sandboxVisiblePath := filepath.Join(homeDir, dockerSocketName)
-	return []EnvVar{
-		{Name: "DOCKER_HOST", Value: "unix://" + sandboxVisiblePath},
+	case DockerModeFull:
+		// Bound at the same absolute path inside the sandbox (see Bindings).
+		return []EnvVar{
+			{Name: "DOCKER_HOST", Value: "unix://" + d.hostSocket},
+		}
+	default:
+		// The socket is created at sandboxHome/docker.sock on the host,
+		// but sandboxHome is mounted at $HOME inside the sandbox.
+		// So we return $HOME/docker.sock as the path visible inside the sandbox.
+		sandboxVisiblePath := filepath.Join(homeDir, dockerSocketName)
+		return []EnvVar{
+			{Name: "DOCKER_HOST", Value: "unix://" + sandboxVisiblePath},
+		}
 	}
 }
 
@@ -146,15 +213,25 @@ func (d *Docker) ShellInit(shell string) string {
 
 // Start implements ActiveTool.
 func (d *Docker) Start(ctx context.Context, homeDir, sandboxHome string) error {
-	if !d.enabled {
+	switch d.mode {
+	case DockerModeDisabled:
+		return nil
+	case DockerModeFull:
+		fmt.Fprintln(os.Stderr, "WARNING: Docker socket forwarding enabled in full mode. The sandbox has unfiltered,")
+		fmt.Fprintln(os.Stderr, "         unrestricted access to the host container runtime - this effectively breaks")
+		fmt.Fprintln(os.Stderr, "         isolation and is equivalent to root on the host. Only enable this for trusted code.")
 		return nil
+	case DockerModeReadOnlyInfo:
+		fmt.Fprintln(os.Stderr, "WARNING: Docker socket proxy enabled (read-only). The sandbox can see ALL Docker state")
+		fmt.Fprintln(os.Stderr, "         on this host (containers, images, volumes, networks), but cannot modify it.")
+	default: // DockerModeProxy
+		fmt.Fprintln(os.Stderr, "WARNING: Docker socket proxy enabled. The sandbox can access ALL existing Docker containers on this host.")
+		fmt.Fprintln(os.Stderr, "         This might allow accessing host resources. Ensure you trust the sandbox content.")
 	}
 
-	fmt.Fprintln(os.Stderr, "WARNING: Docker socket proxy enabled. The sandbox can access ALL existing Docker containers on this host.")
-	fmt.Fprintln(os.Stderr, "         This might allow accessing host resources. Ensure you trust the sandbox content.")
-
 	listenPath := d.socketPath(sandboxHome)
 	d.proxy = dockerproxy.New(d.hostSocket, listenPath)
+	d.proxy.ReadOnly = d.mode == DockerModeReadOnlyInfo
 	if d.logger != nil {
 		d.proxy.SetLogger(d.logger)
 	}
@@ -199,10 +276,15 @@ func (d *Docker) Check(homeDir string) CheckResult {
 	result.ConfigPaths = []string{socket}
 
 	// Add mode info
-	if d.enabled {
-		result.AddInfo("mode: enabled (read-only + exec)")
-	} else {
-		result.AddInfo("mode: disabled (add [tools.docker] enabled=true to config)")
+	switch d.mode {
+	case DockerModeReadOnlyInfo:
+		result.AddInfo("mode: readonly-info (GET/HEAD only, no exec)")
+	case DockerModeProxy:
+		result.AddInfo("mode: proxy (read-only + exec)")
+	case DockerModeFull:
+		result.AddIssue("mode: full - sandbox has UNFILTERED access to the host container runtime, breaking isolation")
+	default:
+		result.AddInfo("mode: disabled (add [tools.docker] mode=\"readonly-info\"|\"proxy\"|\"full\" to config)")
 	}
 
 	return result
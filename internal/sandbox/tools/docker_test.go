@@ -37,22 +37,22 @@ func TestDocker_Configure_Disabled(t *testing.T) {
 	d := &Docker{}
 	d.Configure(GlobalConfig{}, nil)
 
-	if d.enabled {
-		t.Error("expected enabled=false without config")
+	if d.mode != DockerModeDisabled {
+		t.Errorf("expected mode=disabled without config, got %q", d.mode)
 	}
 
 	d.Configure(GlobalConfig{}, map[string]any{"enabled": false})
-	if d.enabled {
-		t.Error("expected enabled=false with enabled=false")
+	if d.mode != DockerModeDisabled {
+		t.Errorf("expected mode=disabled with enabled=false, got %q", d.mode)
 	}
 }
 
-func TestDocker_Configure_Enabled(t *testing.T) {
+func TestDocker_Configure_LegacyEnabledKey(t *testing.T) {
 	d := &Docker{}
 	d.Configure(GlobalConfig{}, map[string]any{"enabled": true})
 
-	if !d.enabled {
-		t.Error("expected enabled=true")
+	if d.mode != DockerModeProxy {
+		t.Errorf("expected legacy enabled=true to map to mode=proxy, got %q", d.mode)
 	}
 
 	// Default socket should be resolved via resolveDockerSocket.
@@ -62,11 +62,22 @@ func TestDocker_Configure_Enabled(t *testing.T) {
 	}
 }
 
+func TestDocker_Configure_Mode(t *testing.T) {
+	tests := []DockerMode{DockerModeReadOnlyInfo, DockerModeProxy, DockerModeFull}
+	for _, mode := range tests {
+		d := &Docker{}
+		d.Configure(GlobalConfig{}, map[string]any{"mode": string(mode)})
+		if d.mode != mode {
+			t.Errorf("expected mode=%q, got %q", mode, d.mode)
+		}
+	}
+}
+
 func TestDocker_Configure_CustomSocket(t *testing.T) {
 	d := &Docker{}
 	d.Configure(GlobalConfig{}, map[string]any{
-		"enabled": true,
-		"socket":  "/var/run/docker.sock",
+		"mode":   "proxy",
+		"socket": "/var/run/docker.sock",
 	})
 
 	if d.hostSocket != "/var/run/docker.sock" {
@@ -75,15 +86,15 @@ func TestDocker_Configure_CustomSocket(t *testing.T) {
 }
 
 func TestDocker_Environment_Disabled(t *testing.T) {
-	d := &Docker{enabled: false}
+	d := &Docker{mode: DockerModeDisabled}
 	env := d.Environment("/home/user", "/sandbox/home")
 	if env != nil {
 		t.Errorf("expected nil environment when disabled, got %d vars", len(env))
 	}
 }
 
-func TestDocker_Environment_Enabled(t *testing.T) {
-	d := &Docker{enabled: true}
+func TestDocker_Environment_Proxy(t *testing.T) {
+	d := &Docker{mode: DockerModeProxy}
 	homeDir := "/home/user"
 	sandboxHome := "/sandbox/home"
 	env := d.Environment(homeDir, sandboxHome)
@@ -103,16 +114,45 @@ func TestDocker_Environment_Enabled(t *testing.T) {
 	}
 }
 
-func TestDocker_Bindings(t *testing.T) {
-	d := &Docker{enabled: true}
+func TestDocker_Environment_Full(t *testing.T) {
+	d := &Docker{mode: DockerModeFull, hostSocket: "/run/docker.sock"}
+	env := d.Environment("/home/user", "/sandbox/home")
+
+	if len(env) != 1 {
+		t.Fatalf("expected 1 env var, got %d", len(env))
+	}
+
+	expected := "unix:///run/docker.sock"
+	if env[0].Value != expected {
+		t.Errorf("expected %q, got %q", expected, env[0].Value)
+	}
+}
+
+func TestDocker_Bindings_Proxy(t *testing.T) {
+	d := &Docker{mode: DockerModeProxy}
 	bindings := d.Bindings("/home/user", "/sandbox/home")
 
-	// Docker tool uses proxy, not direct bindings
+	// Non-full modes use the proxy, not direct bindings
 	if bindings != nil {
 		t.Errorf("expected nil bindings, got %d", len(bindings))
 	}
 }
 
+func TestDocker_Bindings_Full(t *testing.T) {
+	d := &Docker{mode: DockerModeFull, hostSocket: "/run/docker.sock"}
+	bindings := d.Bindings("/home/user", "/sandbox/home")
+
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+	if bindings[0].Source != "/run/docker.sock" {
+		t.Errorf("expected source /run/docker.sock, got %q", bindings[0].Source)
+	}
+	if bindings[0].ReadOnly {
+		t.Error("expected full mode binding to be read-write")
+	}
+}
+
 func TestDocker_Check(t *testing.T) {
 	d := &Docker{hostSocket: "/nonexistent/docker.sock"}
 	result := d.Check("/home/user")
@@ -143,8 +183,40 @@ func TestResolveDockerSocket_UserProvided(t *testing.T) {
 
 func TestResolveDockerSocket_Linux(t *testing.T) {
 	got := resolveDockerSocket("linux", "/home/test", "")
-	if got != "/run/docker.sock" {
-		t.Errorf("expected /run/docker.sock, got %q", got)
+	// /run/docker.sock might exist on the test host; if so it takes precedence.
+	if got != "/run/docker.sock" && got != podmanSocketPath() {
+		t.Errorf("expected /run/docker.sock or the podman fallback, got %q", got)
+	}
+}
+
+func TestResolveDockerSocket_Linux_PodmanFallback(t *testing.T) {
+	if _, err := os.Stat(defaultLinuxDockerSocket); err == nil {
+		t.Skip("docker socket exists on this host, can't test the podman fallback")
+	}
+
+	tmpDir := t.TempDir()
+	origRuntimeDir, had := os.LookupEnv("XDG_RUNTIME_DIR")
+	t.Cleanup(func() {
+		if had {
+			_ = os.Setenv("XDG_RUNTIME_DIR", origRuntimeDir)
+		} else {
+			_ = os.Unsetenv("XDG_RUNTIME_DIR")
+		}
+	})
+	_ = os.Setenv("XDG_RUNTIME_DIR", tmpDir)
+
+	podmanDir := filepath.Join(tmpDir, "podman")
+	if err := os.MkdirAll(podmanDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	podmanSock := filepath.Join(podmanDir, "podman.sock")
+	if err := os.WriteFile(podmanSock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := resolveDockerSocket("linux", "/home/test", "")
+	if got != podmanSock {
+		t.Errorf("expected podman socket %q, got %q", podmanSock, got)
 	}
 }
 
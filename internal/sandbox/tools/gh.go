@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(&GitHubCLI{})
+}
+
+// GitHubCLIMode defines the level of gh CLI access in the sandbox.
+type GitHubCLIMode string
+
+const (
+	// GitHubCLIModeDisabled skips binding hosts.yml entirely. gh is usable
+	// but unauthenticated. This is the default - the token in hosts.yml is
+	// sensitive and shouldn't enter the sandbox without an explicit opt-in.
+	GitHubCLIModeDisabled GitHubCLIMode = "disabled"
+
+	// GitHubCLIModeReadOnly binds hosts.yml read-only: gh can authenticate
+	// API reads and clones with the host's token, but `gh auth login`/`gh
+	// auth refresh` fail instead of silently rewriting the host's file.
+	GitHubCLIModeReadOnly GitHubCLIMode = "readonly"
+
+	// GitHubCLIModeFull binds hosts.yml read-write, so `gh auth` subcommands
+	// work normally inside the sandbox too.
+	GitHubCLIModeFull GitHubCLIMode = "full"
+)
+
+// ValidGitHubCLIMode returns true if the given string is a valid gh mode value.
+func ValidGitHubCLIMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "disabled", "readonly", "full":
+		return true
+	default:
+		return false
+	}
+}
+
+// GitHubCLI provides configurable `gh` CLI credential access. Supports three
+// modes: disabled (default), readonly, and full. Mirrors the Git tool's
+// readonly pattern - hosts.yml is bound at its real host path, never copied
+// into the sandbox home, so the token can't be carried out via a writable
+// sandbox directory.
+type GitHubCLI struct {
+	mode GitHubCLIMode
+}
+
+func (g *GitHubCLI) Name() string {
+	return "gh"
+}
+
+func (g *GitHubCLI) Description() string {
+	switch g.mode {
+	case GitHubCLIModeFull:
+		return "GitHub CLI (full access, can rewrite auth)"
+	case GitHubCLIModeReadOnly:
+		return "GitHub CLI (read-only, auth rewrite blocked)"
+	default:
+		return "GitHub CLI (disabled, no credentials)"
+	}
+}
+
+func (g *GitHubCLI) Available(homeDir string) bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+// Configure implements ToolWithConfig.
+func (g *GitHubCLI) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
+	g.mode = GitHubCLIModeDisabled // default
+
+	if toolCfg == nil {
+		return
+	}
+
+	if modeVal, ok := toolCfg["mode"]; ok {
+		if modeStr, ok := modeVal.(string); ok {
+			switch strings.ToLower(modeStr) {
+			case "full":
+				g.mode = GitHubCLIModeFull
+			case "readonly", "read-only", "ro":
+				g.mode = GitHubCLIModeReadOnly
+			default:
+				g.mode = GitHubCLIModeDisabled
+			}
+		}
+	}
+}
+
+func (g *GitHubCLI) Bindings(homeDir, sandboxHome string) []Binding {
+	if g.mode == GitHubCLIModeDisabled {
+		return nil
+	}
+
+	// Bound at its real path (no Dest override) so the token never lands
+	// anywhere under sandboxHome where it could be copied out.
+	return []Binding{
+		{
+			Source:   filepath.Join(homeDir, ".config", "gh", "hosts.yml"),
+			ReadOnly: g.mode == GitHubCLIModeReadOnly,
+			Optional: true,
+		},
+	}
+}
+
+func (g *GitHubCLI) Environment(homeDir, sandboxHome string) []EnvVar {
+	return nil
+}
+
+func (g *GitHubCLI) ShellInit(shell string) string {
+	return ""
+}
+
+func (g *GitHubCLI) Check(homeDir string) CheckResult {
+	result := CheckBinary("gh", "https://cli.github.com/manual/installation")
+	if !result.Available {
+		return result
+	}
+
+	switch g.mode {
+	case GitHubCLIModeFull:
+		result.AddIssue("mode: full (auth can be rewritten)")
+	case GitHubCLIModeReadOnly:
+		result.AddIssue("mode: readonly (auth rewrite blocked)")
+	default:
+		result.AddIssue("mode: disabled (default, no credentials)")
+	}
+
+	hostsPath := filepath.Join(homeDir, ".config", "gh", "hosts.yml")
+	if _, err := os.Stat(hostsPath); err == nil {
+		result.AddInfo("token found in hosts.yml")
+	} else {
+		result.AddInfo("no hosts.yml found (not logged in on host)")
+	}
+
+	return result
+}
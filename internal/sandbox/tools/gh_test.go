@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitHubCLI_DefaultMode(t *testing.T) {
+	g := &GitHubCLI{}
+	g.Configure(GlobalConfig{}, nil)
+
+	if g.mode != GitHubCLIModeDisabled {
+		t.Errorf("expected default mode %q, got %q", GitHubCLIModeDisabled, g.mode)
+	}
+}
+
+func TestGitHubCLI_Configure(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]any
+		expected GitHubCLIMode
+	}{
+		{"disabled explicit", map[string]any{"mode": "disabled"}, GitHubCLIModeDisabled},
+		{"disabled unknown", map[string]any{"mode": "bogus"}, GitHubCLIModeDisabled},
+		{"disabled nil", nil, GitHubCLIModeDisabled},
+		{"readonly", map[string]any{"mode": "readonly"}, GitHubCLIModeReadOnly},
+		{"read-only", map[string]any{"mode": "read-only"}, GitHubCLIModeReadOnly},
+		{"ro", map[string]any{"mode": "ro"}, GitHubCLIModeReadOnly},
+		{"full", map[string]any{"mode": "full"}, GitHubCLIModeFull},
+		{"full uppercase", map[string]any{"mode": "FULL"}, GitHubCLIModeFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GitHubCLI{}
+			g.Configure(GlobalConfig{}, tt.config)
+
+			if g.mode != tt.expected {
+				t.Errorf("expected mode %q, got %q", tt.expected, g.mode)
+			}
+		})
+	}
+}
+
+func TestGitHubCLI_Bindings_Disabled(t *testing.T) {
+	g := &GitHubCLI{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "disabled"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+
+	if bindings != nil {
+		t.Errorf("expected nil bindings for disabled mode, got %d bindings", len(bindings))
+	}
+}
+
+func TestGitHubCLI_Bindings_ReadOnly(t *testing.T) {
+	g := &GitHubCLI{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	b := bindings[0]
+	if b.Source != "/home/user/.config/gh/hosts.yml" {
+		t.Errorf("expected source %q, got %q", "/home/user/.config/gh/hosts.yml", b.Source)
+	}
+	if b.Dest != "" {
+		t.Errorf("expected no explicit dest (bind in place), got %q", b.Dest)
+	}
+	if !b.ReadOnly {
+		t.Error("expected binding to be read-only")
+	}
+	if !b.Optional {
+		t.Error("expected binding to be optional")
+	}
+}
+
+func TestGitHubCLI_Bindings_Full(t *testing.T) {
+	g := &GitHubCLI{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "full"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	if bindings[0].ReadOnly {
+		t.Error("expected binding to be read-write in full mode")
+	}
+}
+
+func TestGitHubCLI_Check_DetectsToken(t *testing.T) {
+	home := t.TempDir()
+	ghDir := filepath.Join(home, ".config", "gh")
+	if err := os.MkdirAll(ghDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ghDir, "hosts.yml"), []byte("github.com:\n  oauth_token: abc123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &GitHubCLI{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly"})
+
+	result := g.Check(home)
+	if result.BinaryName != "gh" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "gh")
+	}
+}
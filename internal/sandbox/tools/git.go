@@ -41,11 +41,44 @@ func ValidGitMode(mode string) bool {
 	}
 }
 
+// SafeConfigMode controls how readonly mode exposes the host gitconfig.
+type SafeConfigMode string
+
+const (
+	// SafeConfigModeGenerate writes a filtered copy of ~/.gitconfig containing
+	// only user.name and user.email, and binds that over ~/.gitconfig. This is
+	// the default: it's safe because nothing beyond identity ever enters the
+	// sandbox, but includeIf/conditional includes and other config the project
+	// may rely on are silently dropped.
+	SafeConfigModeGenerate SafeConfigMode = "generate"
+
+	// SafeConfigModePassthroughReadonly binds the real ~/.gitconfig read-only
+	// instead of generating a filtered copy. includeIf and other conditional
+	// config resolve exactly as they would outside the sandbox, at the cost of
+	// exposing the full host gitconfig (credential helpers, signing config,
+	// aliases, include paths) to the sandboxed process. Read-only prevents the
+	// sandbox from rewriting it, but does not prevent it from being read.
+	SafeConfigModePassthroughReadonly SafeConfigMode = "passthrough-readonly"
+)
+
+// ValidSafeConfigMode returns true if the given string is a valid
+// safe_config_mode value.
+func ValidSafeConfigMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "generate", "passthrough-readonly":
+		return true
+	default:
+		return false
+	}
+}
+
 // Git provides configurable git configuration.
 // Supports three modes: readonly (default), readwrite, and disabled.
 type Git struct {
-	mode       GitMode
-	projectDir string
+	mode             GitMode
+	safeConfigMode   SafeConfigMode
+	projectDir       string
+	preserveSections []string
 }
 
 func (g *Git) Name() string {
@@ -63,6 +96,30 @@ func (g *Git) Description() string {
 	}
 }
 
+// gitconfigPath returns the host gitconfig file git would actually read for
+// this user, following git's own lookup precedence: ~/.gitconfig first,
+// then $XDG_CONFIG_HOME/git/config (falling back to ~/.config/git/config
+// if XDG_CONFIG_HOME is unset). Returns the ~/.gitconfig path even if
+// neither exists, so callers that check os.Stat themselves still get a
+// sensible "not found" rather than an empty string.
+func gitconfigPath(homeDir string) string {
+	legacy := filepath.Join(homeDir, ".gitconfig")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+
+	xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfigHome == "" {
+		xdgConfigHome = filepath.Join(homeDir, ".config")
+	}
+	xdgPath := filepath.Join(xdgConfigHome, "git", "config")
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath
+	}
+
+	return legacy
+}
+
 func (g *Git) Available(homeDir string) bool {
 	// Git tool is always "available" - it handles all modes including disabled
 	// Check if git binary exists
@@ -72,7 +129,8 @@ func (g *Git) Available(homeDir string) bool {
 
 // Configure implements ToolWithConfig.
 func (g *Git) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
-	g.mode = GitModeReadOnly // default
+	g.mode = GitModeReadOnly                  // default
+	g.safeConfigMode = SafeConfigModeGenerate // default
 	g.projectDir = globalCfg.ProjectDir
 
 	if toolCfg == nil {
@@ -91,6 +149,14 @@ func (g *Git) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
 			}
 		}
 	}
+
+	if modeVal, ok := toolCfg["safe_config_mode"]; ok {
+		if modeStr, ok := modeVal.(string); ok && strings.EqualFold(modeStr, string(SafeConfigModePassthroughReadonly)) {
+			g.safeConfigMode = SafeConfigModePassthroughReadonly
+		}
+	}
+
+	g.preserveSections = stringListSetting(toolCfg, "preserve_sections")
 }
 
 func (g *Git) Bindings(homeDir, sandboxHome string) []Binding {
@@ -108,17 +174,31 @@ func (g *Git) Bindings(homeDir, sandboxHome string) []Binding {
 
 // readOnlyBindings returns bindings for readonly mode (safe gitconfig + read-only .git).
 func (g *Git) readOnlyBindings(homeDir, sandboxHome string) []Binding {
-	safeGitconfig := filepath.Join(sandboxHome, ".gitconfig.safe")
-
-	bindings := []Binding{
-		{
+	var gitconfigBinding Binding
+	if g.safeConfigMode == SafeConfigModePassthroughReadonly {
+		// Bind the real gitconfig read-only so includeIf/conditional includes
+		// resolve exactly as on the host, at the cost of exposing its full
+		// contents (credential helpers, signing config, etc.) to the sandbox.
+		// No Dest override: gitconfigPath already resolves to wherever git
+		// itself would read from (~/.gitconfig, else the XDG location), and
+		// binding it at that same path is what makes the sandboxed git see it.
+		gitconfigBinding = Binding{
+			Source:   gitconfigPath(homeDir),
+			ReadOnly: true,
+			Optional: true,
+		}
+	} else {
+		safeGitconfig := filepath.Join(sandboxHome, ".gitconfig.safe")
+		gitconfigBinding = Binding{
 			Source:   safeGitconfig,
 			Dest:     filepath.Join(homeDir, ".gitconfig"),
 			ReadOnly: true,
 			Optional: true, // Safe config might not exist if Setup failed
-		},
+		}
 	}
 
+	bindings := []Binding{gitconfigBinding}
+
 	// Mount .git as read-only to prevent commits
 	if g.projectDir != "" {
 		gitDir := filepath.Join(g.projectDir, ".git")
@@ -128,6 +208,16 @@ func (g *Git) readOnlyBindings(homeDir, sandboxHome string) []Binding {
 				ReadOnly: true,
 				Optional: false, // .git must exist if we're mounting it
 			})
+
+			// A worktree's .git is a file pointing elsewhere, not the real
+			// object store - bind the worktree metadata and common dir too,
+			// read-only, or git log/status won't work at all.
+			if worktreeDir, commonDir, ok := gitWorktreeDirs(gitDir); ok {
+				bindings = append(bindings,
+					Binding{Source: worktreeDir, ReadOnly: true, Optional: false},
+					Binding{Source: commonDir, ReadOnly: true, Optional: false},
+				)
+			}
 		}
 	}
 
@@ -163,9 +253,95 @@ func (g *Git) readWriteBindings(homeDir, _ string) []Binding {
 		},
 	}
 
+	// Forward the gpg-agent socket so `git commit -S` can reach the
+	// host's already-unlocked agent instead of failing or prompting for
+	// a passphrase gpg-agent has no terminal to ask on. Modern gnupg puts
+	// the socket under a runtime directory rather than inside .gnupg, so
+	// it needs its own binding - the .gnupg bind above won't cover it.
+	if sockDir, ok := gpgAgentSocketDir(); ok {
+		bindings = append(bindings, Binding{
+			Source:   sockDir,
+			ReadOnly: false, // Agent communication is bidirectional
+			Optional: true,
+		})
+	}
+
+	// A worktree's common dir (the real object store and refs) usually
+	// lives outside the project directory, so the project bind alone
+	// doesn't cover it - bind it and the worktree metadata dir writable too.
+	if g.projectDir != "" {
+		gitDir := filepath.Join(g.projectDir, ".git")
+		if worktreeDir, commonDir, ok := gitWorktreeDirs(gitDir); ok {
+			bindings = append(bindings,
+				Binding{Source: worktreeDir, ReadOnly: false, Optional: false},
+				Binding{Source: commonDir, ReadOnly: false, Optional: false},
+			)
+		}
+	}
+
 	return bindings
 }
 
+// gitWorktreeDirs detects whether gitDirPath (a project's ".git" entry) is a
+// worktree gitdir file rather than a real .git directory, and if so resolves
+// both the worktree's private metadata directory (named by its "gitdir:"
+// line) and the main repository's common directory (named by that
+// directory's "commondir" file). Binding just gitDirPath for a worktree
+// gives read access to neither - the object store and refs live in the
+// common dir, reachable only through this chain.
+func gitWorktreeDirs(gitDirPath string) (worktreeDir, commonDir string, ok bool) {
+	info, err := os.Stat(gitDirPath)
+	if err != nil || info.IsDir() {
+		return "", "", false
+	}
+
+	content, err := os.ReadFile(gitDirPath)
+	if err != nil {
+		return "", "", false
+	}
+
+	const prefix = "gitdir:"
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, prefix) {
+		return "", "", false
+	}
+
+	pointer := strings.TrimSpace(strings.TrimPrefix(line, prefix))
+	if !filepath.IsAbs(pointer) {
+		pointer = filepath.Join(filepath.Dir(gitDirPath), pointer)
+	}
+	worktreeDir = filepath.Clean(pointer)
+
+	commondirContent, err := os.ReadFile(filepath.Join(worktreeDir, "commondir"))
+	if err != nil {
+		return "", "", false
+	}
+
+	commonPointer := strings.TrimSpace(string(commondirContent))
+	if !filepath.IsAbs(commonPointer) {
+		commonPointer = filepath.Join(worktreeDir, commonPointer)
+	}
+
+	return worktreeDir, filepath.Clean(commonPointer), true
+}
+
+// gpgAgentSocketDir returns the directory containing the host's gpg-agent
+// socket (from `gpgconf --list-dir agent-socket`), or false if gpgconf
+// isn't available or reports nothing.
+func gpgAgentSocketDir() (string, bool) {
+	out, err := exec.Command("gpgconf", "--list-dir", "agent-socket").Output()
+	if err != nil {
+		return "", false
+	}
+
+	sockPath := strings.TrimSpace(string(out))
+	if sockPath == "" {
+		return "", false
+	}
+
+	return filepath.Dir(sockPath), true
+}
+
 func (g *Git) Environment(homeDir, sandboxHome string) []EnvVar {
 	if g.mode == GitModeDisabled {
 		return nil
@@ -176,6 +352,7 @@ func (g *Git) Environment(homeDir, sandboxHome string) []EnvVar {
 		return []EnvVar{
 			{Name: "SSH_AUTH_SOCK", FromHost: true},
 			{Name: "GPG_TTY", FromHost: true},
+			{Name: "GNUPGHOME", Value: filepath.Join(homeDir, ".gnupg")},
 		}
 	}
 
@@ -188,38 +365,60 @@ func (g *Git) ShellInit(shell string) string {
 
 // Setup implements ToolWithSetup to generate the safe gitconfig.
 func (g *Git) Setup(homeDir, sandboxHome string) error {
-	// Only generate safe gitconfig for readonly mode
-	if g.mode != GitModeReadOnly {
+	// Only generate safe gitconfig for readonly mode, and only when
+	// safe_config_mode is "generate" (the default). In passthrough-readonly
+	// mode the real gitconfig is bound directly, so there's nothing to generate.
+	if g.mode != GitModeReadOnly || g.safeConfigMode == SafeConfigModePassthroughReadonly {
 		return nil
 	}
 
-	gitconfigPath := filepath.Join(homeDir, ".gitconfig")
+	srcPath := gitconfigPath(homeDir)
 	safeGitconfigPath := filepath.Join(sandboxHome, ".gitconfig.safe")
 
 	// Check if gitconfig exists
-	if _, err := os.Stat(gitconfigPath); os.IsNotExist(err) {
+	srcInfo, err := os.Stat(srcPath)
+	if os.IsNotExist(err) {
 		return nil
 	}
 
 	// Check if safe config already exists and is newer than source
-	srcInfo, _ := os.Stat(gitconfigPath)
 	dstInfo, err := os.Stat(safeGitconfigPath)
 	if err == nil && dstInfo.ModTime().After(srcInfo.ModTime()) {
 		return nil // Safe config is up to date
 	}
 
-	return generateSafeGitconfig(gitconfigPath, safeGitconfigPath)
+	return generateSafeGitconfig(srcPath, safeGitconfigPath, g.preserveSections)
+}
+
+// gitconfigAlwaysStrippedSections are section names never copied into the
+// safe gitconfig, even if listed in preserve_sections, because they can leak
+// credentials or silently redirect where git pushes/fetches.
+var gitconfigAlwaysStrippedSections = map[string]bool{
+	"credential": true,
+	"url":        true,
+}
+
+// gitconfigAlwaysStrippedKeys are setting names stripped from any preserved
+// section because they identify a private signing key.
+var gitconfigAlwaysStrippedKeys = map[string]bool{
+	"signingkey": true,
 }
 
-// generateSafeGitconfig creates a sanitized gitconfig with only safe settings.
-func generateSafeGitconfig(src, dst string) error {
+// generateSafeGitconfig creates a sanitized gitconfig containing user.name
+// and user.email, plus a verbatim copy of any sections named in
+// preserveSections (e.g. "core", "color", "init") so everyday settings like
+// the editor or default branch survive into the sandbox. credential, url,
+// and signingkey settings are always stripped regardless of preserveSections.
+func generateSafeGitconfig(src, dst string, preserveSections []string) error {
+	sections := parseGitconfig(src)
+
 	// Try to get user info from git config
 	name, _ := exec.Command("git", "config", "--global", "user.name").Output()
 	email, _ := exec.Command("git", "config", "--global", "user.email").Output()
 
-	// If git config fails, try parsing the file directly
+	// If git config fails, fall back to parsing the file directly
 	if len(name) == 0 || len(email) == 0 {
-		parsedName, parsedEmail := parseGitconfig(src)
+		parsedName, parsedEmail := gitconfigUserInfo(sections)
 		if len(name) == 0 {
 			name = []byte(parsedName)
 		}
@@ -237,9 +436,51 @@ func generateSafeGitconfig(src, dst string) error {
 		content += "\temail = " + strings.TrimSpace(string(email)) + "\n"
 	}
 
+	content += renderPreservedSections(sections, preserveSections)
+
 	return os.WriteFile(dst, []byte(content), 0o644)
 }
 
+// renderPreservedSections copies the sections named in preserveSections
+// through verbatim, dropping always-stripped sections/keys and the [user]
+// section (already handled separately above).
+func renderPreservedSections(sections []gitconfigSection, preserveSections []string) string {
+	allowed := make(map[string]bool, len(preserveSections))
+	for _, name := range preserveSections {
+		allowed[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var out strings.Builder
+	for _, section := range sections {
+		if section.Name == "user" || !allowed[section.Name] || gitconfigAlwaysStrippedSections[section.Name] {
+			continue
+		}
+
+		var lines []string
+		for _, line := range section.Lines {
+			key, _, ok := splitGitconfigLine(line)
+			if ok && gitconfigAlwaysStrippedKeys[strings.ToLower(key)] {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		if section.Subsection != "" {
+			out.WriteString("[" + section.Name + " \"" + section.Subsection + "\"]\n")
+		} else {
+			out.WriteString("[" + section.Name + "]\n")
+		}
+		for _, line := range lines {
+			out.WriteString("\t" + line + "\n")
+		}
+	}
+
+	return out.String()
+}
+
 func (g *Git) Check(homeDir string) CheckResult {
 	result := CheckBinary("git", "Install via system package manager (apt install git, pacman -S git)")
 	if !result.Available {
@@ -253,14 +494,19 @@ func (g *Git) Check(homeDir string) CheckResult {
 	case GitModeDisabled:
 		result.AddIssue("mode: disabled")
 	default:
-		result.AddIssue("mode: readonly (safe, default)")
+		if g.safeConfigMode == SafeConfigModePassthroughReadonly {
+			result.AddIssue("mode: readonly (passthrough-readonly: real gitconfig bound read-only)")
+		} else {
+			result.AddIssue("mode: readonly (safe, default)")
+		}
 	}
 
-	// Check for gitconfig
-	gitconfig := filepath.Join(homeDir, ".gitconfig")
-	result.AddConfigPath(gitconfig)
-	if len(result.ConfigPaths) == 0 {
-		result.AddIssue("no ~/.gitconfig found (will use defaults)")
+	// Check for gitconfig, at ~/.gitconfig or the XDG location
+	gitconfig := gitconfigPath(homeDir)
+	if _, err := os.Stat(gitconfig); err == nil {
+		result.AddConfigPath(gitconfig)
+	} else {
+		result.AddIssue("no ~/.gitconfig or $XDG_CONFIG_HOME/git/config found (will use defaults)")
 	}
 
 	// Check for SSH and GPG in readwrite mode
@@ -274,41 +520,89 @@ func (g *Git) Check(homeDir string) CheckResult {
 	return result
 }
 
-// parseGitconfig extracts user.name and user.email from a gitconfig file.
-func parseGitconfig(path string) (name, email string) {
+// gitconfigSection is one [name] or [name "subsection"] block from a
+// gitconfig file, in file order, with its body lines verbatim (comments and
+// blank lines dropped).
+type gitconfigSection struct {
+	Name       string // lowercased section name, e.g. "core", "url"
+	Subsection string // raw subsection, e.g. an insteadOf target; empty if none
+	Lines      []string
+}
+
+// parseGitconfig parses a gitconfig file into its sections, in file order.
+// It tolerates a missing or unreadable file by returning nil.
+func parseGitconfig(path string) []gitconfigSection {
 	file, err := os.Open(path)
 	if err != nil {
-		return "", ""
+		return nil
 	}
 	defer func() { _ = file.Close() }()
 
-	scanner := bufio.NewScanner(file)
-	inUserSection := false
+	var sections []gitconfigSection
+	var current *gitconfigSection
 
+	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
 
 		if strings.HasPrefix(line, "[") {
-			inUserSection = strings.HasPrefix(strings.ToLower(line), "[user]")
+			name, subsection := parseGitconfigSectionHeader(line)
+			sections = append(sections, gitconfigSection{Name: name, Subsection: subsection})
+			current = &sections[len(sections)-1]
 			continue
 		}
 
-		if !inUserSection {
-			continue
+		if current != nil {
+			current.Lines = append(current.Lines, line)
 		}
+	}
+
+	return sections
+}
+
+// parseGitconfigSectionHeader splits a "[name]" or `[name "subsection"]`
+// header into its lowercased section name and raw subsection.
+func parseGitconfigSectionHeader(line string) (name, subsection string) {
+	header := strings.Trim(line, "[]")
+	parts := strings.SplitN(header, "\"", 3)
+	name = strings.ToLower(strings.TrimSpace(parts[0]))
+	if len(parts) >= 2 {
+		subsection = parts[1]
+	}
+	return name, subsection
+}
 
-		if strings.HasPrefix(line, "name") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				name = strings.TrimSpace(parts[1])
+// splitGitconfigLine splits a "key = value" body line from a gitconfig
+// section into its key and value.
+func splitGitconfigLine(line string) (key, value string, ok bool) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+}
+
+// gitconfigUserInfo extracts user.name and user.email from parsed sections.
+func gitconfigUserInfo(sections []gitconfigSection) (name, email string) {
+	for _, section := range sections {
+		if section.Name != "user" {
+			continue
+		}
+		for _, line := range section.Lines {
+			key, value, ok := splitGitconfigLine(line)
+			if !ok {
+				continue
 			}
-		} else if strings.HasPrefix(line, "email") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				email = strings.TrimSpace(parts[1])
+			switch strings.ToLower(key) {
+			case "name":
+				name = value
+			case "email":
+				email = value
 			}
 		}
 	}
-
 	return name, email
 }
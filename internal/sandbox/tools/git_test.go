@@ -141,16 +141,210 @@ func TestGit_Bindings_ReadOnly_WithGitDir(t *testing.T) {
 	}
 }
 
-func TestGit_Bindings_ReadWrite(t *testing.T) {
+// setupWorktreeLayout creates a bare-ish main repo at mainDir/.git and a
+// linked worktree at worktreeProjectDir, wired together the way real git
+// worktrees are: the worktree's .git is a gitdir-pointer file, its private
+// metadata dir lives under the main repo's .git/worktrees/<name>, and that
+// metadata dir's commondir file points back at the main repo's .git.
+func setupWorktreeLayout(t *testing.T, tmpDir string) (worktreeProjectDir, worktreeMetaDir, commonDir string) {
+	t.Helper()
+
+	mainDir := filepath.Join(tmpDir, "main")
+	commonDir = filepath.Join(mainDir, ".git")
+	worktreeMetaDir = filepath.Join(commonDir, "worktrees", "feature-x")
+	worktreeProjectDir = filepath.Join(tmpDir, "feature-x")
+
+	for _, dir := range []string{commonDir, worktreeMetaDir, worktreeProjectDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitdirFile := filepath.Join(worktreeProjectDir, ".git")
+	if err := os.WriteFile(gitdirFile, []byte("gitdir: "+worktreeMetaDir+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreeMetaDir, "commondir"), []byte("../..\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return worktreeProjectDir, worktreeMetaDir, commonDir
+}
+
+func TestGit_Bindings_ReadOnly_Worktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	worktreeProjectDir, worktreeMetaDir, commonDir := setupWorktreeLayout(t, tmpDir)
+
 	g := &Git{}
-	g.Configure(GlobalConfig{}, map[string]any{"mode": "readwrite"})
+	g.Configure(GlobalConfig{ProjectDir: worktreeProjectDir}, map[string]any{"mode": "readonly"})
 
 	bindings := g.Bindings("/home/user", "/sandbox/home")
 
+	// gitconfig + .git gitdir file + worktree meta dir + common dir
 	if len(bindings) != 4 {
-		t.Fatalf("expected 4 bindings for readwrite mode, got %d", len(bindings))
+		t.Fatalf("expected 4 bindings for readonly worktree, got %d: %+v", len(bindings), bindings)
+	}
+
+	wantSources := map[string]bool{
+		filepath.Join(worktreeProjectDir, ".git"): true,
+		worktreeMetaDir: true,
+		commonDir:       true,
+	}
+	for _, b := range bindings {
+		if wantSources[b.Source] {
+			if !b.ReadOnly {
+				t.Errorf("expected %q to be read-only in readonly mode", b.Source)
+			}
+			delete(wantSources, b.Source)
+		}
+	}
+	if len(wantSources) != 0 {
+		t.Errorf("missing expected bindings: %v", wantSources)
+	}
+}
+
+func TestGit_Bindings_ReadWrite_Worktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	worktreeProjectDir, worktreeMetaDir, commonDir := setupWorktreeLayout(t, tmpDir)
+
+	g := &Git{}
+	g.Configure(GlobalConfig{ProjectDir: worktreeProjectDir}, map[string]any{"mode": "readwrite"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+
+	wantSources := map[string]bool{worktreeMetaDir: true, commonDir: true}
+	for _, b := range bindings {
+		if wantSources[b.Source] {
+			if b.ReadOnly {
+				t.Errorf("expected %q to be writable in readwrite mode", b.Source)
+			}
+			delete(wantSources, b.Source)
+		}
+	}
+	if len(wantSources) != 0 {
+		t.Errorf("missing expected worktree bindings in readwrite mode: %v", wantSources)
+	}
+}
+
+func TestGitWorktreeDirs_NotAWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.MkdirAll(gitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := gitWorktreeDirs(gitDir); ok {
+		t.Error("expected a real .git directory not to be detected as a worktree")
+	}
+}
+
+func TestGit_Configure_SafeConfigMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]any
+		expected SafeConfigMode
+	}{
+		{"default", map[string]any{"mode": "readonly"}, SafeConfigModeGenerate},
+		{"explicit generate", map[string]any{"mode": "readonly", "safe_config_mode": "generate"}, SafeConfigModeGenerate},
+		{"passthrough", map[string]any{"mode": "readonly", "safe_config_mode": "passthrough-readonly"}, SafeConfigModePassthroughReadonly},
+		{"passthrough uppercase", map[string]any{"mode": "readonly", "safe_config_mode": "PASSTHROUGH-READONLY"}, SafeConfigModePassthroughReadonly},
+		{"unknown falls back to generate", map[string]any{"mode": "readonly", "safe_config_mode": "bogus"}, SafeConfigModeGenerate},
+		{"nil config", nil, SafeConfigModeGenerate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Git{}
+			g.Configure(GlobalConfig{}, tt.config)
+
+			if g.safeConfigMode != tt.expected {
+				t.Errorf("expected safeConfigMode %q, got %q", tt.expected, g.safeConfigMode)
+			}
+		})
+	}
+}
+
+func TestGit_Bindings_ReadOnly_Generate(t *testing.T) {
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly", "safe_config_mode": "generate"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	b := bindings[0]
+	if b.Source != "/sandbox/home/.gitconfig.safe" {
+		t.Errorf("expected source to be the generated safe gitconfig, got %q", b.Source)
+	}
+	if b.Dest != "/home/user/.gitconfig" {
+		t.Errorf("expected dest %q, got %q", "/home/user/.gitconfig", b.Dest)
+	}
+	if !b.ReadOnly {
+		t.Error("expected binding to be read-only")
+	}
+}
+
+func TestGit_Bindings_ReadOnly_PassthroughReadonly(t *testing.T) {
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly", "safe_config_mode": "passthrough-readonly"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
 	}
 
+	b := bindings[0]
+	if b.Source != "/home/user/.gitconfig" {
+		t.Errorf("expected source to be the real gitconfig, got %q", b.Source)
+	}
+	if b.Dest != "" {
+		t.Errorf("expected no explicit dest (bind in place), got %q", b.Dest)
+	}
+	if !b.ReadOnly {
+		t.Error("expected binding to be read-only")
+	}
+	if !b.Optional {
+		t.Error("expected binding to be optional")
+	}
+}
+
+func TestGit_Setup_PassthroughReadonly_SkipsGeneration(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	gitconfig := filepath.Join(homeDir, ".gitconfig")
+	if err := os.WriteFile(gitconfig, []byte("[user]\n\tname = Test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly", "safe_config_mode": "passthrough-readonly"})
+
+	if err := g.Setup(homeDir, sandboxHome); err != nil {
+		t.Errorf("Setup failed: %v", err)
+	}
+
+	safeConfig := filepath.Join(sandboxHome, ".gitconfig.safe")
+	if _, err := os.Stat(safeConfig); !os.IsNotExist(err) {
+		t.Error("safe gitconfig should not be generated in passthrough-readonly mode")
+	}
+}
+
+func TestGit_Bindings_ReadWrite(t *testing.T) {
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readwrite"})
+
+	bindings := g.Bindings("/home/user", "/sandbox/home")
+
 	// Check expected bindings exist
 	expectedBindings := map[string]struct {
 		readOnly bool
@@ -161,6 +355,18 @@ func TestGit_Bindings_ReadWrite(t *testing.T) {
 		"/home/user/.gnupg":           {readOnly: true},
 	}
 
+	// The gpg-agent socket binding only appears if gpgconf is available on
+	// the machine running the test; its exact directory isn't known ahead
+	// of time, so allow (but don't require) exactly one extra binding.
+	sockDir, haveSockDir := gpgAgentSocketDir()
+	if haveSockDir {
+		expectedBindings[sockDir] = struct{ readOnly bool }{readOnly: false}
+	}
+
+	if len(bindings) != len(expectedBindings) {
+		t.Fatalf("expected %d bindings for readwrite mode, got %d", len(expectedBindings), len(bindings))
+	}
+
 	for _, b := range bindings {
 		expected, ok := expectedBindings[b.Source]
 		if !ok {
@@ -178,6 +384,38 @@ func TestGit_Bindings_ReadWrite(t *testing.T) {
 	}
 }
 
+func TestGit_Bindings_GPGAgentSocket_ReadWriteOnly(t *testing.T) {
+	if _, ok := gpgAgentSocketDir(); !ok {
+		t.Skip("gpgconf not available or reports no agent socket")
+	}
+
+	sockDir, _ := gpgAgentSocketDir()
+
+	g := &Git{}
+	for _, mode := range []string{"readonly", "disabled"} {
+		g.Configure(GlobalConfig{}, map[string]any{"mode": mode})
+		for _, b := range g.Bindings("/home/user", "/sandbox/home") {
+			if b.Source == sockDir {
+				t.Errorf("mode %q: unexpectedly exposed gpg-agent socket binding %s", mode, b.Source)
+			}
+		}
+	}
+
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readwrite"})
+	found := false
+	for _, b := range g.Bindings("/home/user", "/sandbox/home") {
+		if b.Source == sockDir {
+			found = true
+			if b.ReadOnly {
+				t.Errorf("expected gpg-agent socket binding to be read-write, got read-only")
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected readwrite mode to bind the gpg-agent socket dir %s", sockDir)
+	}
+}
+
 func TestGit_Environment_Disabled(t *testing.T) {
 	g := &Git{}
 	g.Configure(GlobalConfig{}, map[string]any{"mode": "disabled"})
@@ -206,19 +444,29 @@ func TestGit_Environment_ReadWrite(t *testing.T) {
 
 	env := g.Environment("/home/user", "/sandbox/home")
 
-	if len(env) != 2 {
-		t.Fatalf("expected 2 environment vars for readwrite mode, got %d", len(env))
+	if len(env) != 3 {
+		t.Fatalf("expected 3 environment vars for readwrite mode, got %d", len(env))
 	}
 
 	expectedVars := map[string]bool{
 		"SSH_AUTH_SOCK": true,
 		"GPG_TTY":       true,
+		"GNUPGHOME":     true,
 	}
 
 	for _, e := range env {
 		if !expectedVars[e.Name] {
 			t.Errorf("unexpected environment var: %s", e.Name)
 		}
+		if e.Name == "GNUPGHOME" {
+			if e.FromHost {
+				t.Errorf("expected GNUPGHOME to be a literal value, not FromHost")
+			}
+			if e.Value != "/home/user/.gnupg" {
+				t.Errorf("expected GNUPGHOME=/home/user/.gnupg, got %q", e.Value)
+			}
+			continue
+		}
 		if !e.FromHost {
 			t.Errorf("expected %s to have FromHost=true", e.Name)
 		}
@@ -392,6 +640,249 @@ func TestGit_Setup_ReadOnlyMode_GeneratesSafeConfig(t *testing.T) {
 	}
 }
 
+func TestGit_Setup_ReadOnlyMode_PreservesConfiguredSections(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	gitconfig := filepath.Join(homeDir, ".gitconfig")
+	content := `[user]
+	name = Test User
+	email = test@example.com
+	signingkey = ABC123
+[credential]
+	helper = store
+[core]
+	editor = vim
+[color]
+	ui = auto
+[init]
+	defaultBranch = main
+[url "https://example.com/"]
+	insteadOf = git://example.com/
+[alias]
+	co = checkout
+`
+	if err := os.WriteFile(gitconfig, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{
+		"mode":              "readonly",
+		"preserve_sections": []any{"core", "color", "init", "credential", "url"},
+	})
+
+	if err := g.Setup(homeDir, sandboxHome); err != nil {
+		t.Errorf("Setup failed: %v", err)
+	}
+
+	safeConfig := filepath.Join(sandboxHome, ".gitconfig.safe")
+	data, err := os.ReadFile(safeConfig)
+	if err != nil {
+		t.Fatalf("failed to read safe gitconfig: %v", err)
+	}
+	safeContent := string(data)
+
+	for _, want := range []string{"[core]", "editor = vim", "[color]", "ui = auto", "[init]", "defaultBranch = main"} {
+		if !strings.Contains(safeContent, want) {
+			t.Errorf("safe gitconfig missing preserved setting %q, got:\n%s", want, safeContent)
+		}
+	}
+
+	// credential and url must never be copied, even when explicitly listed
+	// in preserve_sections, since they can leak tokens or redirect remotes.
+	if strings.Contains(safeContent, "[credential]") {
+		t.Error("safe gitconfig should not contain [credential] even when preserve_sections requests it")
+	}
+	if strings.Contains(safeContent, "[url") {
+		t.Error("safe gitconfig should not contain [url ...] even when preserve_sections requests it")
+	}
+	if strings.Contains(safeContent, "signingkey") {
+		t.Error("safe gitconfig should not contain signingkey")
+	}
+	if strings.Contains(safeContent, "alias") || strings.Contains(safeContent, "checkout") {
+		t.Error("safe gitconfig should not contain sections outside preserve_sections")
+	}
+}
+
+func TestRenderPreservedSections(t *testing.T) {
+	sections := []gitconfigSection{
+		{Name: "user", Lines: []string{"name = Test User", "signingkey = ABC123"}},
+		{Name: "core", Lines: []string{"editor = vim", "signingkey = should-not-exist"}},
+		{Name: "credential", Lines: []string{"helper = store"}},
+		{Name: "url", Subsection: "https://example.com/", Lines: []string{"insteadOf = git://example.com/"}},
+		{Name: "alias", Lines: []string{"co = checkout"}},
+	}
+
+	got := renderPreservedSections(sections, []string{"core", "credential", "url"})
+
+	if !strings.Contains(got, "[core]") || !strings.Contains(got, "editor = vim") {
+		t.Errorf("expected [core] with editor to be preserved, got:\n%s", got)
+	}
+	if strings.Contains(got, "signingkey") {
+		t.Error("signingkey should be stripped even from a preserved section")
+	}
+	if strings.Contains(got, "[credential]") || strings.Contains(got, "helper") {
+		t.Error("credential should never be preserved")
+	}
+	if strings.Contains(got, "[url") {
+		t.Error("url should never be preserved")
+	}
+	if strings.Contains(got, "[user]") {
+		t.Error("user is handled separately and should not be duplicated here")
+	}
+	if strings.Contains(got, "alias") {
+		t.Error("sections not in preserveSections should not appear")
+	}
+}
+
+func TestGitconfigPath_FallsBackToXDGConfigHome(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	xdgConfigHome := filepath.Join(tmpDir, "xdgconfig")
+	gitConfigDir := filepath.Join(xdgConfigHome, "git")
+
+	if err := os.MkdirAll(gitConfigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	xdgGitconfig := filepath.Join(gitConfigDir, "config")
+	if err := os.WriteFile(xdgGitconfig, []byte("[user]\n\tname = XDG User\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+
+	// No ~/.gitconfig, so the XDG location should win.
+	if got := gitconfigPath(homeDir); got != xdgGitconfig {
+		t.Errorf("gitconfigPath() = %q, want %q", got, xdgGitconfig)
+	}
+}
+
+func TestGitconfigPath_FallsBackToDotConfigGit_WhenXDGConfigHomeUnset(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	gitConfigDir := filepath.Join(homeDir, ".config", "git")
+
+	if err := os.MkdirAll(gitConfigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dotConfigGitconfig := filepath.Join(gitConfigDir, "config")
+	if err := os.WriteFile(dotConfigGitconfig, []byte("[user]\n\tname = Dot Config User\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if got := gitconfigPath(homeDir); got != dotConfigGitconfig {
+		t.Errorf("gitconfigPath() = %q, want %q", got, dotConfigGitconfig)
+	}
+}
+
+func TestGitconfigPath_HomeGitconfigWinsOverXDG(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	xdgConfigHome := filepath.Join(tmpDir, "xdgconfig")
+	gitConfigDir := filepath.Join(xdgConfigHome, "git")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(gitConfigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	homeGitconfig := filepath.Join(homeDir, ".gitconfig")
+	if err := os.WriteFile(homeGitconfig, []byte("[user]\n\tname = Home User\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	xdgGitconfig := filepath.Join(gitConfigDir, "config")
+	if err := os.WriteFile(xdgGitconfig, []byte("[user]\n\tname = XDG User\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+
+	// Both exist: ~/.gitconfig wins per git's own precedence.
+	if got := gitconfigPath(homeDir); got != homeGitconfig {
+		t.Errorf("gitconfigPath() = %q, want %q (the ~/.gitconfig path to win)", got, homeGitconfig)
+	}
+}
+
+func TestGit_Setup_ReadOnlyMode_GeneratesSafeConfig_FromXDGLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+	xdgConfigHome := filepath.Join(tmpDir, "xdgconfig")
+	gitConfigDir := filepath.Join(xdgConfigHome, "git")
+
+	for _, dir := range []string{homeDir, sandboxHome, gitConfigDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No ~/.gitconfig - only the XDG location has one.
+	xdgGitconfig := filepath.Join(gitConfigDir, "config")
+	if err := os.WriteFile(xdgGitconfig, []byte("[user]\n\tname = XDG User\n\temail = xdg@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly"})
+
+	if err := g.Setup(homeDir, sandboxHome); err != nil {
+		t.Errorf("Setup failed: %v", err)
+	}
+
+	safeConfig := filepath.Join(sandboxHome, ".gitconfig.safe")
+	if _, err := os.Stat(safeConfig); err != nil {
+		t.Fatalf("expected safe gitconfig to be generated from the XDG location, got error: %v", err)
+	}
+}
+
+func TestGit_Bindings_ReadOnly_PassthroughReadonly_XDGLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	xdgConfigHome := filepath.Join(tmpDir, "xdgconfig")
+	gitConfigDir := filepath.Join(xdgConfigHome, "git")
+
+	if err := os.MkdirAll(gitConfigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	xdgGitconfig := filepath.Join(gitConfigDir, "config")
+	if err := os.WriteFile(xdgGitconfig, []byte("[user]\n\tname = XDG User\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+
+	g := &Git{}
+	g.Configure(GlobalConfig{}, map[string]any{"mode": "readonly", "safe_config_mode": "passthrough-readonly"})
+
+	bindings := g.Bindings(homeDir, "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	b := bindings[0]
+	if b.Source != xdgGitconfig {
+		t.Errorf("expected source to be the XDG gitconfig, got %q", b.Source)
+	}
+	if b.Dest != "" {
+		t.Errorf("expected no explicit dest (bind in place), got %q", b.Dest)
+	}
+}
+
 func TestParseGitconfig(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -473,7 +964,7 @@ func TestParseGitconfig(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			name, email := parseGitconfig(tmpFile)
+			name, email := gitconfigUserInfo(parseGitconfig(tmpFile))
 
 			if name != tt.expectedName {
 				t.Errorf("expected name %q, got %q", tt.expectedName, name)
@@ -486,7 +977,7 @@ func TestParseGitconfig(t *testing.T) {
 }
 
 func TestParseGitconfig_NonExistent(t *testing.T) {
-	name, email := parseGitconfig("/nonexistent/path/.gitconfig")
+	name, email := gitconfigUserInfo(parseGitconfig("/nonexistent/path/.gitconfig"))
 
 	if name != "" || email != "" {
 		t.Errorf("expected empty strings for non-existent file, got name=%q email=%q", name, email)
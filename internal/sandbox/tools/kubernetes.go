@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(&Kubernetes{})
+}
+
+// Kubernetes provides configurable `kubectl` access to the host's
+// kubeconfig. Disabled by default - cluster credentials (tokens, client
+// certificates) are as sensitive as any other credential this package
+// gates behind an explicit opt-in.
+type Kubernetes struct {
+	enabled bool
+	context string
+}
+
+func (k *Kubernetes) Name() string {
+	return "kubernetes"
+}
+
+func (k *Kubernetes) Description() string {
+	switch {
+	case !k.enabled:
+		return "Kubernetes CLI (disabled, no credentials)"
+	case k.context != "":
+		return fmt.Sprintf("Kubernetes CLI (context %q only)", k.context)
+	default:
+		return "Kubernetes CLI (read-only, all contexts)"
+	}
+}
+
+func (k *Kubernetes) Available(homeDir string) bool {
+	_, err := exec.LookPath("kubectl")
+	return err == nil
+}
+
+// Configure implements ToolWithConfig.
+func (k *Kubernetes) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
+	k.enabled = false // default
+	k.context = ""
+
+	if toolCfg == nil {
+		return
+	}
+
+	if v, ok := toolCfg["enabled"].(bool); ok {
+		k.enabled = v
+	}
+	if v, ok := toolCfg["context"].(string); ok {
+		k.context = v
+	}
+}
+
+func (k *Kubernetes) Bindings(homeDir, sandboxHome string) []Binding {
+	if !k.enabled {
+		return nil
+	}
+
+	kubeconfigPath := filepath.Join(homeDir, ".kube", "config")
+
+	if k.context == "" {
+		return []Binding{
+			{
+				Source:   kubeconfigPath,
+				ReadOnly: true,
+				Optional: true,
+			},
+		}
+	}
+
+	// Setup generates a filtered kubeconfig containing only the selected
+	// context/cluster/user, the same way Git's readonly mode binds a
+	// generated .gitconfig.safe over the real file instead of the host's.
+	return []Binding{
+		{
+			Source:   filteredKubeconfigPath(sandboxHome),
+			Dest:     kubeconfigPath,
+			ReadOnly: true,
+			Optional: true, // Setup may have failed to find the context
+		},
+	}
+}
+
+func (k *Kubernetes) Environment(homeDir, sandboxHome string) []EnvVar {
+	if !k.enabled {
+		return nil
+	}
+	return []EnvVar{
+		{Name: "KUBECONFIG", Value: filepath.Join(homeDir, ".kube", "config")},
+	}
+}
+
+func (k *Kubernetes) ShellInit(shell string) string {
+	return ""
+}
+
+// filteredKubeconfigPath is where Setup writes the context-scoped kubeconfig.
+func filteredKubeconfigPath(sandboxHome string) string {
+	return filepath.Join(sandboxHome, ".kube", "config.filtered")
+}
+
+// Setup implements ToolWithSetup to generate the context-scoped kubeconfig.
+func (k *Kubernetes) Setup(homeDir, sandboxHome string) error {
+	if !k.enabled || k.context == "" {
+		return nil
+	}
+
+	src := filepath.Join(homeDir, ".kube", "config")
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	dst := filteredKubeconfigPath(sandboxHome)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create kube config dir: %w", err)
+	}
+
+	return generateFilteredKubeconfig(src, dst, k.context)
+}
+
+// kubeconfigNamedEntry is the shape shared by kubeconfig's clusters,
+// contexts, and users lists: a name plus an opaque payload keyed by the
+// list's own field name (cluster/context/user).
+type kubeconfigNamedEntry struct {
+	Name    string         `yaml:"name"`
+	Cluster map[string]any `yaml:"cluster,omitempty"`
+	Context map[string]any `yaml:"context,omitempty"`
+	User    map[string]any `yaml:"user,omitempty"`
+}
+
+// kubeconfigFile mirrors the subset of kubeconfig's schema this package
+// needs to filter down to a single context.
+type kubeconfigFile struct {
+	APIVersion     string                 `yaml:"apiVersion"`
+	Kind           string                 `yaml:"kind"`
+	CurrentContext string                 `yaml:"current-context"`
+	Clusters       []kubeconfigNamedEntry `yaml:"clusters"`
+	Contexts       []kubeconfigNamedEntry `yaml:"contexts"`
+	Users          []kubeconfigNamedEntry `yaml:"users"`
+	Preferences    map[string]any         `yaml:"preferences,omitempty"`
+}
+
+// generateFilteredKubeconfig reads the kubeconfig at src and writes a copy
+// to dst containing only the cluster/user referenced by contextName, so the
+// sandbox can never read credentials for clusters outside that context.
+func generateFilteredKubeconfig(src, dst, contextName string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	var cfg kubeconfigFile
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	ctxEntry, ok := findKubeconfigEntry(cfg.Contexts, contextName)
+	if !ok {
+		return fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	clusterName, _ := ctxEntry.Context["cluster"].(string)
+	userName, _ := ctxEntry.Context["user"].(string)
+
+	clusterEntry, ok := findKubeconfigEntry(cfg.Clusters, clusterName)
+	if !ok {
+		return fmt.Errorf("cluster %q (referenced by context %q) not found in kubeconfig", clusterName, contextName)
+	}
+
+	filtered := kubeconfigFile{
+		APIVersion:     cfg.APIVersion,
+		Kind:           cfg.Kind,
+		CurrentContext: contextName,
+		Clusters:       []kubeconfigNamedEntry{clusterEntry},
+		Contexts:       []kubeconfigNamedEntry{ctxEntry},
+	}
+	if filtered.APIVersion == "" {
+		filtered.APIVersion = "v1"
+	}
+	if filtered.Kind == "" {
+		filtered.Kind = "Config"
+	}
+
+	if userName != "" {
+		if userEntry, ok := findKubeconfigEntry(cfg.Users, userName); ok {
+			filtered.Users = []kubeconfigNamedEntry{userEntry}
+		}
+	}
+
+	out, err := yaml.Marshal(&filtered)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filtered kubeconfig: %w", err)
+	}
+
+	// May embed client certificate/token material - keep it as private as
+	// the source kubeconfig.
+	return os.WriteFile(dst, out, 0o600)
+}
+
+func findKubeconfigEntry(entries []kubeconfigNamedEntry, name string) (kubeconfigNamedEntry, bool) {
+	for _, e := range entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return kubeconfigNamedEntry{}, false
+}
+
+func (k *Kubernetes) Check(homeDir string) CheckResult {
+	result := CheckBinary("kubectl", "https://kubernetes.io/docs/tasks/tools/#kubectl")
+	if !result.Available {
+		return result
+	}
+
+	if !k.enabled {
+		result.AddIssue("disabled (default, no credentials bound into sandbox)")
+		return result
+	}
+
+	if k.context != "" {
+		result.AddIssue(fmt.Sprintf("context: %q only", k.context))
+	} else {
+		result.AddIssue("all contexts bound read-only")
+	}
+
+	kubeconfigPath := filepath.Join(homeDir, ".kube", "config")
+	result.AddConfigPath(kubeconfigPath)
+
+	contexts, err := listKubeContexts(kubeconfigPath)
+	if err != nil {
+		result.AddIssue(fmt.Sprintf("failed to list contexts: %v", err))
+		return result
+	}
+	if len(contexts) == 0 {
+		result.AddInfo("no contexts found in kubeconfig")
+	} else {
+		result.AddInfo("available contexts: " + strings.Join(contexts, ", "))
+	}
+
+	return result
+}
+
+// listKubeContexts returns the context names defined in the kubeconfig at
+// path, read directly rather than shelling out to `kubectl config
+// get-contexts` so Check works even against a kubeconfig the host's default
+// KUBECONFIG env var doesn't point at.
+func listKubeContexts(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg kubeconfigFile
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cfg.Contexts))
+	for _, c := range cfg.Contexts {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging-cluster
+  cluster:
+    server: https://staging.example.com
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: staging
+  context:
+    cluster: staging-cluster
+    user: staging-user
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+users:
+- name: staging-user
+  user:
+    token: staging-token
+- name: prod-user
+  user:
+    token: prod-token
+`
+
+func TestKubernetes_DefaultDisabled(t *testing.T) {
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, nil)
+
+	if k.enabled {
+		t.Error("expected kubernetes to be disabled by default")
+	}
+	if bindings := k.Bindings("/home/user", "/sandbox/home"); bindings != nil {
+		t.Errorf("expected nil bindings when disabled, got %d", len(bindings))
+	}
+	if env := k.Environment("/home/user", "/sandbox/home"); env != nil {
+		t.Errorf("expected no environment when disabled, got %v", env)
+	}
+}
+
+func TestKubernetes_Bindings_NoContext(t *testing.T) {
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, map[string]any{"enabled": true})
+
+	bindings := k.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	b := bindings[0]
+	if b.Source != "/home/user/.kube/config" {
+		t.Errorf("source = %q, want %q", b.Source, "/home/user/.kube/config")
+	}
+	if b.Dest != "" {
+		t.Errorf("expected no explicit dest (bind in place), got %q", b.Dest)
+	}
+	if !b.ReadOnly {
+		t.Error("expected binding to be read-only")
+	}
+
+	env := k.Environment("/home/user", "/sandbox/home")
+	if len(env) != 1 || env[0].Name != "KUBECONFIG" || env[0].Value != "/home/user/.kube/config" {
+		t.Errorf("unexpected environment: %v", env)
+	}
+}
+
+func TestKubernetes_Bindings_WithContext(t *testing.T) {
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, map[string]any{"enabled": true, "context": "staging"})
+
+	bindings := k.Bindings("/home/user", "/sandbox/home")
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 binding, got %d", len(bindings))
+	}
+
+	b := bindings[0]
+	if b.Source != filteredKubeconfigPath("/sandbox/home") {
+		t.Errorf("source = %q, want the filtered kubeconfig path", b.Source)
+	}
+	if b.Dest != "/home/user/.kube/config" {
+		t.Errorf("dest = %q, want %q", b.Dest, "/home/user/.kube/config")
+	}
+	if !b.Optional {
+		t.Error("expected binding to be optional (Setup may not have run)")
+	}
+}
+
+func TestKubernetes_Setup_FiltersToSingleContext(t *testing.T) {
+	home := t.TempDir()
+	sandboxHome := t.TempDir()
+
+	kubeDir := filepath.Join(home, ".kube")
+	if err := os.MkdirAll(kubeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(kubeDir, "config"), []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, map[string]any{"enabled": true, "context": "staging"})
+
+	if err := k.Setup(home, sandboxHome); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	contexts, err := listKubeContexts(filteredKubeconfigPath(sandboxHome))
+	if err != nil {
+		t.Fatalf("failed to read filtered kubeconfig: %v", err)
+	}
+	if len(contexts) != 1 || contexts[0] != "staging" {
+		t.Errorf("expected only the staging context, got %v", contexts)
+	}
+
+	data, err := os.ReadFile(filteredKubeconfigPath(sandboxHome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, "prod-token") || strings.Contains(content, "prod-cluster") || strings.Contains(content, "prod-user") {
+		t.Error("filtered kubeconfig must not contain the other context's cluster/user")
+	}
+	if !strings.Contains(content, "staging-token") {
+		t.Error("filtered kubeconfig should retain the selected context's credentials")
+	}
+}
+
+func TestKubernetes_Setup_UnknownContext(t *testing.T) {
+	home := t.TempDir()
+	sandboxHome := t.TempDir()
+
+	kubeDir := filepath.Join(home, ".kube")
+	if err := os.MkdirAll(kubeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(kubeDir, "config"), []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, map[string]any{"enabled": true, "context": "nonexistent"})
+
+	if err := k.Setup(home, sandboxHome); err == nil {
+		t.Error("expected an error for an unknown context")
+	}
+}
+
+func TestKubernetes_Check(t *testing.T) {
+	home := t.TempDir()
+	kubeDir := filepath.Join(home, ".kube")
+	if err := os.MkdirAll(kubeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(kubeDir, "config"), []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k := &Kubernetes{}
+	k.Configure(GlobalConfig{}, map[string]any{"enabled": true})
+
+	result := k.Check(home)
+	if result.BinaryName != "kubectl" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "kubectl")
+	}
+}
@@ -15,6 +15,7 @@ func init() {
 type Mise struct {
 	writable   bool
 	persistent bool
+	disabled   bool
 }
 
 func (m *Mise) Name() string {
@@ -33,6 +34,11 @@ func (m *Mise) Available(homeDir string) bool {
 // Configure implements ToolWithConfig.
 // Parses mise-specific config from the raw map.
 func (m *Mise) Configure(globalCfg GlobalConfig, toolCfg map[string]any) {
+	m.disabled = false
+	if v, ok := toolCfg["enabled"].(bool); ok && !v {
+		m.disabled = true
+	}
+
 	// If overlays are globally disabled, don't enable writable mode
 	if !globalCfg.OverlayEnabled {
 		m.writable = false
@@ -122,6 +128,9 @@ func (m *Mise) Environment(homeDir, sandboxHome string) []EnvVar {
 }
 
 func (m *Mise) ShellInit(shell string) string {
+	if m.disabled {
+		return ""
+	}
 	switch shell {
 	case "fish":
 		return `if command -q mise; mise activate fish | source; end`
@@ -129,6 +138,8 @@ func (m *Mise) ShellInit(shell string) string {
 		return `if command -v mise &>/dev/null; then eval "$(mise activate zsh)"; fi`
 	case "bash":
 		return `if command -v mise &>/dev/null; then eval "$(mise activate bash)"; fi`
+	case "nu":
+		return `if (which mise | is-not-empty) { mise activate nu | save -f ($env.HOME + "/.cache/mise-activate.nu"); source ($env.HOME + "/.cache/mise-activate.nu") }`
 	default:
 		return fmt.Sprintf(`if command -v mise &>/dev/null; then eval "$(mise activate %s)"; fi`, shell)
 	}
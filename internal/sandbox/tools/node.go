@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(&Node{})
+}
+
+// Node provides isolation for npm: the package cache is redirected into
+// the sandbox so writes never touch the host's ~/.npm and installs don't
+// re-download on every run, and ~/.npmrc is shared as a sanitized
+// read-only copy with any auth tokens stripped - the same approach git.go
+// uses for ~/.gitconfig.
+type Node struct{}
+
+func (n *Node) Name() string {
+	return "node"
+}
+
+func (n *Node) Description() string {
+	return "Node.js/npm isolation (sandboxed cache, sanitized .npmrc)"
+}
+
+func (n *Node) Available(homeDir string) bool {
+	if _, err := exec.LookPath("node"); err == nil {
+		return true
+	}
+	_, err := exec.LookPath("npm")
+	return err == nil
+}
+
+func (n *Node) Bindings(homeDir, sandboxHome string) []Binding {
+	return []Binding{
+		// Sanitized .npmrc - always read-only.
+		{
+			Source:   filepath.Join(sandboxHome, ".npmrc.safe"),
+			Dest:     filepath.Join(homeDir, ".npmrc"),
+			ReadOnly: true,
+			Optional: true, // Safe config might not exist if Setup failed or there's no host .npmrc
+		},
+		// npm package cache - isolated to the sandbox so installs don't
+		// re-download every run, but writes never touch the host's cache.
+		{
+			Source: filepath.Join(sandboxHome, ".cache", "npm"),
+			Dest:   filepath.Join(homeDir, ".cache", "npm"),
+		},
+	}
+}
+
+func (n *Node) Environment(homeDir, sandboxHome string) []EnvVar {
+	return []EnvVar{
+		{Name: "npm_config_cache", Value: filepath.Join(sandboxHome, ".cache", "npm")},
+		// NODE_EXTRA_CA_CERTS is already set globally when proxy mode is on
+		// (see Builder.AddProxyEnvironment); node and npm both honor it.
+		{Name: "NODE_ENV", FromHost: true},
+	}
+}
+
+func (n *Node) ShellInit(shell string) string {
+	return ""
+}
+
+// Setup implements ToolWithSetup to generate the sanitized .npmrc.
+func (n *Node) Setup(homeDir, sandboxHome string) error {
+	srcPath := filepath.Join(homeDir, ".npmrc")
+	safePath := filepath.Join(sandboxHome, ".npmrc.safe")
+
+	srcInfo, err := os.Stat(srcPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if dstInfo, err := os.Stat(safePath); err == nil && dstInfo.ModTime().After(srcInfo.ModTime()) {
+		return nil // Safe config is up to date
+	}
+
+	return generateSafeNpmrc(srcPath, safePath)
+}
+
+// generateSafeNpmrc copies src to dst with any credential-bearing line
+// dropped. npm's own convention is that settings starting with "_"
+// (optionally scoped to a registry, e.g. "//registry.npmjs.org/:_authToken")
+// carry auth material, so that's what's stripped; everything else
+// (registry, proxy settings, save-exact, etc.) passes through unchanged.
+func generateSafeNpmrc(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var out strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if npmrcLineIsSensitive(line) {
+			continue
+		}
+		out.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, []byte(out.String()), 0o644)
+}
+
+// npmrcLineIsSensitive reports whether an .npmrc line sets a key that
+// carries auth material: its key, after stripping an optional
+// "//host/path:" registry scope, starts with "_" (e.g. "_authToken",
+// "_password"). Unscoped booleans like "always-auth" don't match, since
+// they don't start with an underscore.
+func npmrcLineIsSensitive(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+
+	key, _, ok := strings.Cut(trimmed, "=")
+	if !ok {
+		return false
+	}
+	key = strings.TrimSpace(key)
+	if idx := strings.LastIndex(key, ":"); idx != -1 {
+		key = key[idx+1:]
+	}
+
+	return strings.HasPrefix(key, "_")
+}
+
+func (n *Node) Check(homeDir string) CheckResult {
+	result := CheckResult{
+		BinaryName:  "node",
+		InstallHint: "mise install node",
+	}
+
+	path, err := exec.LookPath("node")
+	if err == nil {
+		result.BinaryPath = path
+	}
+
+	if _, err := exec.LookPath("npm"); err == nil {
+		result.AddInfo("npm is also available in PATH")
+	}
+
+	npmrcPath := filepath.Join(homeDir, ".npmrc")
+	result.AddConfigPath(npmrcPath)
+	if registry, ok := npmrcRegistry(npmrcPath); ok {
+		result.AddInfo("registry: " + registry)
+	} else {
+		result.AddInfo("registry: https://registry.npmjs.org/ (default)")
+	}
+
+	result.Available = result.BinaryPath != ""
+	if !result.Available {
+		result.AddIssue("node binary not found in PATH")
+	}
+
+	return result
+}
+
+// npmrcRegistry returns the "registry" setting from an .npmrc file, if set.
+func npmrcRegistry(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.TrimSpace(key) != "registry" {
+			continue
+		}
+		return strings.TrimSpace(value), true
+	}
+	return "", false
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNode_Bindings(t *testing.T) {
+	n := &Node{}
+
+	bindings := n.Bindings("/home/testuser", "/tmp/sandbox")
+
+	var foundNpmrc, foundCache bool
+	for _, b := range bindings {
+		switch b.Source {
+		case filepath.Join("/tmp/sandbox", ".npmrc.safe"):
+			foundNpmrc = true
+			if !b.ReadOnly {
+				t.Error(".npmrc binding should be read-only")
+			}
+			wantDest := filepath.Join("/home/testuser", ".npmrc")
+			if b.Dest != wantDest {
+				t.Errorf(".npmrc binding Dest = %q, want %q", b.Dest, wantDest)
+			}
+		case filepath.Join("/tmp/sandbox", ".cache", "npm"):
+			foundCache = true
+			if b.ReadOnly {
+				t.Error("npm cache binding should be writable")
+			}
+			wantDest := filepath.Join("/home/testuser", ".cache", "npm")
+			if b.Dest != wantDest {
+				t.Errorf("npm cache binding Dest = %q, want %q", b.Dest, wantDest)
+			}
+		}
+	}
+
+	if !foundNpmrc {
+		t.Error("missing sanitized .npmrc binding")
+	}
+	if !foundCache {
+		t.Error("missing sandbox-local npm cache binding")
+	}
+}
+
+func TestNode_Environment(t *testing.T) {
+	n := &Node{}
+
+	envVars := n.Environment("/home/testuser", "/tmp/sandbox")
+
+	want := filepath.Join("/tmp/sandbox", ".cache", "npm")
+	found := false
+	for _, env := range envVars {
+		if env.Name == "npm_config_cache" {
+			found = true
+			if env.Value != want {
+				t.Errorf("npm_config_cache = %q, want %q", env.Value, want)
+			}
+		}
+	}
+	if !found {
+		t.Error("Environment() missing npm_config_cache")
+	}
+}
+
+func TestNode_Setup_StripsAuthTokens(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	npmrc := filepath.Join(homeDir, ".npmrc")
+	content := `registry=https://registry.example.com/
+//registry.example.com/:_authToken=super-secret-token
+_password=another-secret
+always-auth=true
+save-exact=true
+`
+	if err := os.WriteFile(npmrc, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &Node{}
+	if err := n.Setup(homeDir, sandboxHome); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	safeNpmrc := filepath.Join(sandboxHome, ".npmrc.safe")
+	data, err := os.ReadFile(safeNpmrc)
+	if err != nil {
+		t.Fatalf("failed to read safe .npmrc: %v", err)
+	}
+	safeContent := string(data)
+
+	if strings.Contains(safeContent, "super-secret-token") {
+		t.Error("safe .npmrc should not contain the auth token")
+	}
+	if strings.Contains(safeContent, "another-secret") {
+		t.Error("safe .npmrc should not contain _password")
+	}
+	if !strings.Contains(safeContent, "registry=https://registry.example.com/") {
+		t.Error("safe .npmrc should preserve the registry setting")
+	}
+	if !strings.Contains(safeContent, "always-auth=true") {
+		t.Error("safe .npmrc should preserve always-auth (it carries no secret)")
+	}
+	if !strings.Contains(safeContent, "save-exact=true") {
+		t.Error("safe .npmrc should preserve unrelated settings")
+	}
+}
+
+func TestNode_Setup_NoHostNpmrc(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	sandboxHome := filepath.Join(tmpDir, "sandbox")
+
+	if err := os.MkdirAll(homeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &Node{}
+	if err := n.Setup(homeDir, sandboxHome); err != nil {
+		t.Fatalf("Setup should be a no-op without a host .npmrc: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(sandboxHome, ".npmrc.safe")); !os.IsNotExist(err) {
+		t.Error("Setup should not create a safe .npmrc when there's no host .npmrc")
+	}
+}
+
+func TestNode_Check_DetectsRegistry(t *testing.T) {
+	homeDir := t.TempDir()
+	npmrc := filepath.Join(homeDir, ".npmrc")
+	if err := os.WriteFile(npmrc, []byte("registry=https://registry.example.com/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &Node{}
+	result := n.Check(homeDir)
+
+	if result.BinaryName != "node" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "node")
+	}
+
+	found := false
+	for _, info := range result.Info {
+		if strings.Contains(info, "registry.example.com") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Check() should report the configured registry")
+	}
+}
+
+func TestNpmrcLineIsSensitive(t *testing.T) {
+	cases := []struct {
+		line string
+		want bool
+	}{
+		{"//registry.npmjs.org/:_authToken=abc123", true},
+		{"_auth=abc123", true},
+		{"_password=abc123", true},
+		{"always-auth=true", false},
+		{"registry=https://registry.npmjs.org/", false},
+		{"save-exact=true", false},
+		{"; a comment", false},
+		{"", false},
+	}
+
+	for _, c := range cases {
+		if got := npmrcLineIsSensitive(c.line); got != c.want {
+			t.Errorf("npmrcLineIsSensitive(%q) = %v, want %v", c.line, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+func init() {
+	Register(&Python{})
+}
+
+// Python provides isolation for the uv/pip Python package managers.
+// Config (uv's settings, pip.conf) is shared read-only from the host so
+// existing settings still apply, but the package cache is isolated to the
+// sandbox so writes never touch the host's cache and resolution doesn't
+// have to re-download on every run.
+type Python struct{}
+
+func (p *Python) Name() string {
+	return "python"
+}
+
+func (p *Python) Description() string {
+	return "Python package manager isolation (uv, pip)"
+}
+
+func (p *Python) Available(homeDir string) bool {
+	if _, err := exec.LookPath("uv"); err == nil {
+		return true
+	}
+	_, err := exec.LookPath("pip")
+	return err == nil
+}
+
+func (p *Python) Bindings(homeDir, sandboxHome string) []Binding {
+	return []Binding{
+		// uv configuration - always read-only
+		{
+			Source:   filepath.Join(homeDir, ".config", "uv"),
+			ReadOnly: true,
+			Optional: true,
+		},
+		// pip configuration - always read-only
+		{
+			Source:   filepath.Join(homeDir, ".config", "pip", "pip.conf"),
+			ReadOnly: true,
+			Optional: true,
+		},
+		// uv package cache - isolated to the sandbox so dependency
+		// resolution doesn't re-download every run, but writes never
+		// touch the host's cache
+		{
+			Source: filepath.Join(sandboxHome, ".cache", "uv"),
+			Dest:   filepath.Join(homeDir, ".cache", "uv"),
+		},
+	}
+}
+
+func (p *Python) Environment(homeDir, sandboxHome string) []EnvVar {
+	return []EnvVar{
+		{Name: "UV_CACHE_DIR", Value: filepath.Join(sandboxHome, ".cache", "uv")},
+		{Name: "PIP_CACHE_DIR", Value: filepath.Join(sandboxHome, ".cache", "uv")},
+	}
+}
+
+func (p *Python) ShellInit(shell string) string {
+	return ""
+}
+
+func (p *Python) Check(homeDir string) CheckResult {
+	result := CheckResult{
+		BinaryName:  "uv",
+		InstallHint: "mise install uv",
+	}
+
+	path, err := exec.LookPath("uv")
+	if err == nil {
+		result.BinaryPath = path
+	}
+
+	if _, err := exec.LookPath("pip"); err == nil {
+		result.AddInfo("pip is also available in PATH")
+	}
+
+	result.AddConfigPaths(
+		filepath.Join(homeDir, ".config", "uv"),
+		filepath.Join(homeDir, ".config", "pip", "pip.conf"),
+	)
+
+	result.Available = result.BinaryPath != ""
+	if !result.Available {
+		result.AddIssue("uv binary not found in PATH")
+	}
+
+	return result
+}
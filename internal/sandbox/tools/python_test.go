@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPython_Bindings(t *testing.T) {
+	p := &Python{}
+
+	bindings := p.Bindings("/home/testuser", "/tmp/sandbox")
+
+	var foundUvConfig, foundPipConfig, foundCache bool
+	for _, b := range bindings {
+		switch b.Source {
+		case filepath.Join("/home/testuser", ".config", "uv"):
+			foundUvConfig = true
+			if !b.ReadOnly {
+				t.Error("uv config binding should be read-only")
+			}
+		case filepath.Join("/home/testuser", ".config", "pip", "pip.conf"):
+			foundPipConfig = true
+			if !b.ReadOnly {
+				t.Error("pip.conf binding should be read-only")
+			}
+		case filepath.Join("/tmp/sandbox", ".cache", "uv"):
+			foundCache = true
+			if b.ReadOnly {
+				t.Error("uv cache binding should be writable")
+			}
+			wantDest := filepath.Join("/home/testuser", ".cache", "uv")
+			if b.Dest != wantDest {
+				t.Errorf("uv cache binding Dest = %q, want %q", b.Dest, wantDest)
+			}
+		}
+	}
+
+	if !foundUvConfig {
+		t.Error("missing uv config binding")
+	}
+	if !foundPipConfig {
+		t.Error("missing pip.conf binding")
+	}
+	if !foundCache {
+		t.Error("missing sandbox-local uv cache binding")
+	}
+}
+
+func TestPython_Environment(t *testing.T) {
+	p := &Python{}
+
+	envVars := p.Environment("/home/testuser", "/tmp/sandbox")
+
+	want := map[string]string{
+		"UV_CACHE_DIR":  filepath.Join("/tmp/sandbox", ".cache", "uv"),
+		"PIP_CACHE_DIR": filepath.Join("/tmp/sandbox", ".cache", "uv"),
+	}
+
+	for name, wantValue := range want {
+		found := false
+		for _, env := range envVars {
+			if env.Name == name {
+				found = true
+				if env.Value != wantValue {
+					t.Errorf("%s = %q, want %q", name, env.Value, wantValue)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Environment() missing %s", name)
+		}
+	}
+}
+
+func TestPython_Check_ConfigPaths(t *testing.T) {
+	p := &Python{}
+
+	result := p.Check(t.TempDir())
+
+	if result.BinaryName != "uv" {
+		t.Errorf("BinaryName = %q, want %q", result.BinaryName, "uv")
+	}
+}
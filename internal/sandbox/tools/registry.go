@@ -39,6 +39,86 @@ func Available(homeDir string) []Tool {
 	return available
 }
 
+// Enabled filters ts down to the tools allowed by the top-level
+// [tools] enabled/disabled lists in toolsConfig, e.g.:
+//
+//	[tools]
+//	disabled = ["oh-my-posh"]
+//
+// With neither list set, ts is returned unchanged - today's auto-detect
+// behavior. When enabled is set, it acts as an allowlist: only those
+// names pass through. disabled is then subtracted from whatever remains,
+// so it can trim a name out of an enabled allowlist or, used alone,
+// out of the full auto-detected set.
+func Enabled(ts []Tool, toolsConfig map[string]any) []Tool {
+	enabled := stringListSetting(toolsConfig, "enabled")
+	disabled := stringListSetting(toolsConfig, "disabled")
+	if len(enabled) == 0 && len(disabled) == 0 {
+		return ts
+	}
+
+	var allow map[string]bool
+	if len(enabled) > 0 {
+		allow = make(map[string]bool, len(enabled))
+		for _, name := range enabled {
+			allow[name] = true
+		}
+	}
+	deny := make(map[string]bool, len(disabled))
+	for _, name := range disabled {
+		deny[name] = true
+	}
+
+	var result []Tool
+	for _, t := range ts {
+		if allow != nil && !allow[t.Name()] {
+			continue
+		}
+		if deny[t.Name()] {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// DisabledByConfig returns the names from ts that Enabled would drop given
+// toolsConfig, for surfacing in diagnostics (e.g. `--info`) so it's clear
+// when a host-available tool was turned off deliberately rather than never
+// having been detected.
+func DisabledByConfig(ts []Tool, toolsConfig map[string]any) []string {
+	kept := make(map[string]bool)
+	for _, t := range Enabled(ts, toolsConfig) {
+		kept[t.Name()] = true
+	}
+
+	var dropped []string
+	for _, t := range ts {
+		if !kept[t.Name()] {
+			dropped = append(dropped, t.Name())
+		}
+	}
+	return dropped
+}
+
+// stringListSetting reads a []string-ish value from a top-level [tools]
+// key. TOML decodes a list as []any, so elements are checked for
+// string-ness individually, matching how each tool's Configure parses its
+// own [tools.<name>] section.
+func stringListSetting(toolsConfig map[string]any, key string) []string {
+	items, _ := toolsConfig[key].([]any)
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 // CollectCacheMounts returns all cache mounts from registered tools.
 // Uses All() instead of Available() because Docker containers provide their
 // own tool binaries — host availability is irrelevant for cache mounts.
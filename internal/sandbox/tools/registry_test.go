@@ -2,6 +2,18 @@ package tools
 
 import "testing"
 
+// fakeTool is a minimal Tool stub for exercising registry-level logic
+// (like Enabled/DisabledByConfig) independently of the real registered
+// tools, whose availability depends on what's installed on the host.
+type fakeTool struct{ name string }
+
+func (f fakeTool) Name() string                                     { return f.name }
+func (f fakeTool) Description() string                              { return f.name }
+func (f fakeTool) Available(homeDir string) bool                    { return true }
+func (f fakeTool) Bindings(homeDir, sandboxHome string) []Binding   { return nil }
+func (f fakeTool) Environment(homeDir, sandboxHome string) []EnvVar { return nil }
+func (f fakeTool) ShellInit(shell string) string                    { return "" }
+
 func TestCollectCacheMounts(t *testing.T) {
 	// CollectCacheMounts depends on host tool availability (exec.LookPath),
 	// so we only assert structural invariants rather than specific counts.
@@ -33,3 +45,65 @@ func TestAllReturnsRegisteredTools(t *testing.T) {
 		}
 	}
 }
+
+func TestEnabled_NoListsIsNoop(t *testing.T) {
+	ts := []Tool{fakeTool{"git"}, fakeTool{"oh-my-posh"}}
+
+	got := Enabled(ts, nil)
+	if len(got) != 2 {
+		t.Fatalf("Enabled() = %v, want both tools unfiltered", got)
+	}
+
+	got = Enabled(ts, map[string]any{"git": map[string]any{"mode": "readonly"}})
+	if len(got) != 2 {
+		t.Fatalf("Enabled() with an unrelated per-tool section = %v, want both tools unfiltered", got)
+	}
+}
+
+func TestEnabled_Disabled(t *testing.T) {
+	ts := []Tool{fakeTool{"git"}, fakeTool{"oh-my-posh"}}
+
+	got := Enabled(ts, map[string]any{"disabled": []any{"oh-my-posh"}})
+	if len(got) != 1 || got[0].Name() != "git" {
+		t.Errorf("Enabled() = %v, want only git", got)
+	}
+}
+
+func TestEnabled_EnabledIsAllowlist(t *testing.T) {
+	ts := []Tool{fakeTool{"git"}, fakeTool{"oh-my-posh"}, fakeTool{"mise"}}
+
+	got := Enabled(ts, map[string]any{"enabled": []any{"git", "mise"}})
+	if len(got) != 2 {
+		t.Fatalf("Enabled() = %v, want exactly git and mise", got)
+	}
+	for _, tool := range got {
+		if tool.Name() != "git" && tool.Name() != "mise" {
+			t.Errorf("Enabled() included unexpected tool %q", tool.Name())
+		}
+	}
+}
+
+func TestEnabled_DisabledTrimsEnabledAllowlist(t *testing.T) {
+	ts := []Tool{fakeTool{"git"}, fakeTool{"oh-my-posh"}, fakeTool{"mise"}}
+
+	got := Enabled(ts, map[string]any{
+		"enabled":  []any{"git", "mise"},
+		"disabled": []any{"mise"},
+	})
+	if len(got) != 1 || got[0].Name() != "git" {
+		t.Errorf("Enabled() = %v, want only git", got)
+	}
+}
+
+func TestDisabledByConfig(t *testing.T) {
+	ts := []Tool{fakeTool{"git"}, fakeTool{"oh-my-posh"}}
+
+	got := DisabledByConfig(ts, map[string]any{"disabled": []any{"oh-my-posh"}})
+	if len(got) != 1 || got[0] != "oh-my-posh" {
+		t.Errorf("DisabledByConfig() = %v, want [oh-my-posh]", got)
+	}
+
+	if got := DisabledByConfig(ts, nil); len(got) != 0 {
+		t.Errorf("DisabledByConfig() with no config = %v, want none", got)
+	}
+}
@@ -51,6 +51,11 @@ func (s *Starship) Environment(homeDir, sandboxHome string) []EnvVar {
 }
 
 func (s *Starship) ShellInit(shell string) string {
+	// No shell-specific init needed here: starship is activated by
+	// `eval "$(starship init <shell>)"` in the user's own shell rc file,
+	// which is already bound read-only. The sandbox indicator comes from
+	// the custom segment Setup() adds to starship.toml, so it shows up
+	// regardless of which shell activates starship.
 	return ""
 }
 
@@ -84,13 +84,13 @@ type ToolWithSetup interface {
 
 // CheckResult contains detailed availability information for a tool.
 type CheckResult struct {
-	Available   bool     // Whether the tool is available
-	BinaryPath  string   // Path to the tool's binary (if applicable)
-	BinaryName  string   // Name of the binary to look for
-	ConfigPaths []string // Configuration paths that exist
-	Issues      []string // Any issues or warnings
-	Info        []string // Informational messages (not problems)
-	InstallHint string   // How to install if missing
+	Available   bool     `json:"available"`              // Whether the tool is available
+	BinaryPath  string   `json:"binary_path,omitempty"`  // Path to the tool's binary (if applicable)
+	BinaryName  string   `json:"binary_name,omitempty"`  // Name of the binary to look for
+	ConfigPaths []string `json:"config_paths,omitempty"` // Configuration paths that exist
+	Issues      []string `json:"issues,omitempty"`       // Any issues or warnings
+	Info        []string `json:"info,omitempty"`         // Informational messages (not problems)
+	InstallHint string   `json:"install_hint,omitempty"` // How to install if missing
 }
 
 // ToolWithCheck extends Tool with detailed availability checking.